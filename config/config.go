@@ -3,13 +3,17 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DatabaseURL string
-	ServerPort  string
+	DatabaseURL          string
+	ServerPort           string
+	PredictionIterations int
+	PredictionWorkers    int
+	PredictionTopN       int
 }
 
 func LoadConfig() (*Config, error) {
@@ -31,9 +35,34 @@ func LoadConfig() (*Config, error) {
 		cfg.ServerPort = "8080" // Default port
 	}
 
+	// Monte Carlo prediction settings
+	if cfg.PredictionIterations, err = intFromEnv("PREDICTION_ITERATIONS", 5000); err != nil {
+		return nil, err
+	}
+	if cfg.PredictionWorkers, err = intFromEnv("PREDICTION_WORKERS", 0); err != nil { // 0 uses every CPU
+		return nil, err
+	}
+	if cfg.PredictionTopN, err = intFromEnv("PREDICTION_TOP_N", 2); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// intFromEnv reads an integer environment variable, returning fallback if it is not set.
+func intFromEnv(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &ConfigError{Message: key + " must be an integer"}
+	}
+	return n, nil
+}
+
 type ConfigError struct {
 	Message string
 }
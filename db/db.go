@@ -4,40 +4,78 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"os"
+	"strings"
 
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "modernc.org/sqlite"
 
 	"github.com/orhosko/go-backend/sqlc"
 )
 
+// Dialect identifies the SQL database behind a connection.
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// DialectOf picks the dialect from the database URL. postgres:// and
+// postgresql:// URLs select PostgreSQL, anything else is a SQLite path.
+func DialectOf(databaseURL string) Dialect {
+	if strings.HasPrefix(databaseURL, "postgres://") || strings.HasPrefix(databaseURL, "postgresql://") {
+		return Postgres
+	}
+	return SQLite
+}
+
+// driver returns the database/sql driver name for the dialect.
+func (d Dialect) driver() string {
+	if d == Postgres {
+		return "pgx"
+	}
+	return "sqlite"
+}
+
 // DB represents the database connection and sqlc queries.
 type DB struct {
-	Queries *sqlc.Queries
+	Dialect Dialect
+	Queries *sqlc.Queries // SQLite queries, nil for PostgreSQL
 	Conn    *sql.DB
 }
 
 // NewDB initializes a new database connection and sqlc queries.
 func NewDB(databaseURL string) (*DB, error) {
-	// Change "sqlite3" to "pgx" if using PostgreSQL
-	db, err := sql.Open("sqlite", databaseURL)
+	dialect := DialectOf(databaseURL)
+	db, err := sql.Open(dialect.driver(), databaseURL)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
+	// SQLite allows a single writer, and every connection to ":memory:" opens
+	// a separate empty database, so keep all work on one connection. Code
+	// running inside Repository.WithTx must use the transaction's repository.
+	if dialect == SQLite {
+		db.SetMaxOpenConns(1)
+	}
+
 	// Ping the database to ensure connection is established
 	if err = db.Ping(); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("Database connection established successfully.")
+	log.Printf("Database connection established successfully (%s).", dialect)
 
 	// Create a new Queries instance from sqlc generated code
-	queries := sqlc.New(db)
+	var queries *sqlc.Queries
+	if dialect == SQLite {
+		queries = sqlc.New(db)
+	}
 
 	return &DB{
+		Dialect: dialect,
 		Queries: queries,
 		Conn:    db,
 	}, nil
@@ -51,19 +89,3 @@ func (d *DB) Close() error {
 	}
 	return nil
 }
-
-// EnsureSchema initializes the database schema (useful for SQLite in development).
-// In a production environment, you would use a dedicated migration tool.
-func (d *DB) EnsureSchema(schemaPath string) error {
-	schema, err := os.ReadFile(schemaPath)
-	if err != nil {
-		return fmt.Errorf("failed to read schema file: %w", err)
-	}
-
-	_, err = d.Conn.Exec(string(schema))
-	if err != nil {
-		return fmt.Errorf("failed to execute schema: %w", err)
-	}
-	log.Println("Database schema ensured.")
-	return nil
-}
@@ -0,0 +1,362 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Each dialect has its own copy of the migrations under migrations/<dialect>.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationName matches migration files such as 0001_init.up.sql.
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a numbered schema change with the SQL applying and reverting
+// it.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrations returns the embedded migrations for the dialect ordered by
+// version.
+func Migrations(dialect Dialect) ([]Migration, error) {
+	files, err := fs.Glob(migrationFiles, path.Join("migrations", string(dialect), "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no migrations for dialect %q", dialect)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, file := range files {
+		name := path.Base(file)
+		match := migrationName.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", name, err)
+		}
+
+		contents, err := migrationFiles.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(contents)
+		} else {
+			m.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// MigrateUp applies every pending migration in order, each in its own
+// transaction, and returns the migrations applied.
+func (d *DB) MigrateUp(ctx context.Context) ([]Migration, error) {
+	statuses, err := d.MigrationStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.baseline(ctx, statuses); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, status := range statuses {
+		if status.Applied {
+			continue
+		}
+
+		err := d.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, status.Up); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx,
+				d.Dialect.rebind("INSERT INTO schema_migrations (version, name) VALUES (?, ?)"),
+				status.Version, status.Name)
+			return err
+		})
+		if err != nil {
+			return applied, fmt.Errorf("failed to apply migration %d_%s: %w", status.Version, status.Name, err)
+		}
+
+		log.Printf("Applied migration %d_%s", status.Version, status.Name)
+		applied = append(applied, status.Migration)
+	}
+	return applied, nil
+}
+
+// baselineVersion is the last migration the schema file of the removed
+// EnsureSchema covered: it created the tables of 0001_init and inserted the
+// seed data of 0002_seed_data.
+const baselineVersion = 2
+
+// baselineColumns are the columns of 0001_init that a database created by
+// EnsureSchema may lack, with their definitions.
+var baselineColumns = []struct{ table, column, definition string }{
+	{"season", "seed", "INTEGER NOT NULL DEFAULT 0"},
+	{"season", "ranking_rules", "TEXT NOT NULL DEFAULT 'premier-league'"},
+	{"standing", "goals_for", "INTEGER DEFAULT 0"},
+	{"standing", "goals_against", "INTEGER DEFAULT 0"},
+}
+
+// baseline marks the migrations up to baselineVersion as applied to a SQLite
+// database created by EnsureSchema, which has the tables but no applied
+// migrations, after adding any columns it lacks and the unique team names of
+// 0001_init. It fails if two teams share a name. It updates statuses to
+// match.
+func (d *DB) baseline(ctx context.Context, statuses []MigrationStatus) error {
+	if d.Dialect != SQLite {
+		return nil
+	}
+	for _, status := range statuses {
+		if status.Applied {
+			return nil
+		}
+	}
+
+	var tables int
+	err := d.Conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'season'").Scan(&tables)
+	if err != nil {
+		return fmt.Errorf("failed to look for an existing schema: %w", err)
+	}
+	if tables == 0 {
+		return nil
+	}
+
+	err = d.inTx(ctx, func(tx *sql.Tx) error {
+		if err := uniqueTeamNames(ctx, tx); err != nil {
+			return err
+		}
+
+		for _, c := range baselineColumns {
+			var n int
+			err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", c.table, c.column).Scan(&n)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+				return err
+			}
+		}
+
+		for _, status := range statuses {
+			if status.Version > baselineVersion {
+				break
+			}
+			_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES (?, ?)", status.Version, status.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to baseline existing schema: %w", err)
+	}
+
+	for i := range statuses {
+		if statuses[i].Version <= baselineVersion {
+			statuses[i].Applied = true
+			log.Printf("Baselined migration %d_%s on an existing schema", statuses[i].Version, statuses[i].Name)
+		}
+	}
+	return nil
+}
+
+// uniqueTeamNames adds the unique index on team names that EnsureSchema did
+// not create, naming the teams to rename first if any share a name.
+func uniqueTeamNames(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, "SELECT name FROM team GROUP BY name HAVING COUNT(*) > 1 ORDER BY name")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var duplicates []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		duplicates = append(duplicates, name)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("team names must be unique, rename the duplicate teams %q first", duplicates)
+	}
+
+	_, err = tx.ExecContext(ctx, "CREATE UNIQUE INDEX IF NOT EXISTS team_name_unique ON team (name)")
+	return err
+}
+
+// MigrateDown reverts the latest applied migration and returns it. It
+// returns false if no migration is applied.
+func (d *DB) MigrateDown(ctx context.Context) (Migration, bool, error) {
+	statuses, err := d.MigrationStatus(ctx)
+	if err != nil {
+		return Migration{}, false, err
+	}
+
+	for i := len(statuses) - 1; i >= 0; i-- {
+		status := statuses[i]
+		if !status.Applied {
+			continue
+		}
+
+		err := d.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, status.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, d.Dialect.rebind("DELETE FROM schema_migrations WHERE version = ?"), status.Version)
+			return err
+		})
+		if err != nil {
+			return Migration{}, false, fmt.Errorf("failed to revert migration %d_%s: %w", status.Version, status.Name, err)
+		}
+
+		log.Printf("Reverted migration %d_%s", status.Version, status.Name)
+		return status.Migration, true, nil
+	}
+	return Migration{}, false, nil
+}
+
+// MigrationStatus lists every embedded migration and whether it has been
+// applied.
+func (d *DB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := Migrations(d.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := d.Conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	appliedAt := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		at, applied := appliedAt[m.Version]
+		statuses = append(statuses, MigrationStatus{Migration: m, Applied: applied, AppliedAt: at})
+		delete(appliedAt, m.Version)
+	}
+
+	// A version applied by a newer build cannot be reverted by this one
+	for version := range appliedAt {
+		return nil, fmt.Errorf("database has migration %d applied, which this build does not know", version)
+	}
+	return statuses, nil
+}
+
+func (d *DB) ensureMigrationsTable(ctx context.Context) error {
+	_, err := d.Conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version     INTEGER     PRIMARY KEY,
+    name        TEXT        NOT NULL,
+    applied_at  TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+func (d *DB) inTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := d.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// rebind rewrites ? placeholders to the dialect's parameter syntax.
+func (d Dialect) rebind(query string) string {
+	if d != Postgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	d, err := NewDB(filepath.Join(t.TempDir(), "league.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestMigrateUp(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDB(t)
+
+	migrations, err := Migrations(d.Dialect)
+	if err != nil {
+		t.Fatalf("Migrations: %v", err)
+	}
+	applied, err := d.MigrateUp(ctx)
+	if err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if len(applied) != len(migrations) || applied[0].Version != 1 {
+		t.Errorf("MigrateUp applied %d migrations from %d, want all %d", len(applied), applied[0].Version, len(migrations))
+	}
+
+	if applied, err := d.MigrateUp(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("second MigrateUp = %d migrations, %v, want none", len(applied), err)
+	}
+}
+
+func TestMigrateDown(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDB(t)
+
+	if _, err := d.MigrateUp(ctx); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	statuses, err := d.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.AppliedAt.IsZero() {
+			t.Errorf("migration %d_%s = %+v, want applied", status.Version, status.Name, status)
+		}
+	}
+
+	// Revert every migration, latest first, then apply them all again
+	for i := len(statuses) - 1; i >= 0; i-- {
+		reverted, ok, err := d.MigrateDown(ctx)
+		if err != nil || !ok || reverted.Version != statuses[i].Version {
+			t.Fatalf("MigrateDown = %d, %t, %v, want migration %d", reverted.Version, ok, err, statuses[i].Version)
+		}
+	}
+	if _, ok, err := d.MigrateDown(ctx); ok || err != nil {
+		t.Errorf("MigrateDown with nothing applied = %t, %v, want false", ok, err)
+	}
+	if applied, err := d.MigrateUp(ctx); err != nil || len(applied) != len(statuses) {
+		t.Errorf("MigrateUp after reverting = %d migrations, %v, want %d", len(applied), err, len(statuses))
+	}
+}
+
+// TestMigrateUpBaseline upgrades a database created by the removed
+// EnsureSchema from the schema file it ran.
+func TestMigrateUpBaseline(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDB(t)
+
+	schema, err := os.ReadFile(filepath.Join("testdata", "ensure_schema.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Conn.ExecContext(ctx, string(schema)); err != nil {
+		t.Fatalf("create legacy schema: %v", err)
+	}
+
+	applied, err := d.MigrateUp(ctx)
+	if err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if len(applied) == 0 || applied[0].Version != baselineVersion+1 {
+		t.Errorf("MigrateUp applied %+v, want the migrations after %d", applied, baselineVersion)
+	}
+
+	statuses, err := d.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied {
+			t.Errorf("migration %d_%s is not applied", status.Version, status.Name)
+		}
+	}
+
+	// The existing data is kept and the seed data is not inserted again
+	for table, want := range map[string]int{"season": 1, "team": 4, "game_state": 1} {
+		var n int
+		if err := d.Conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if n != want {
+			t.Errorf("%s has %d rows, want %d", table, n, want)
+		}
+	}
+	var seed int64
+	if err := d.Conn.QueryRowContext(ctx, "SELECT seed FROM season").Scan(&seed); err != nil || seed != 0 {
+		t.Errorf("seed of the existing season = %d, %v, want 0", seed, err)
+	}
+
+	// Team names are unique, as 0001_init makes them
+	if _, err := d.Conn.ExecContext(ctx, "INSERT INTO team (name, strength, budget) VALUES ('Chelsea', 5, 0)"); err == nil {
+		t.Error("inserting a second Chelsea succeeded, want the team names unique")
+	}
+}
+
+// TestMigrateUpBaselineDuplicateNames refuses to baseline a database whose
+// teams share a name, leaving it as it was.
+func TestMigrateUpBaselineDuplicateNames(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDB(t)
+
+	schema, err := os.ReadFile(filepath.Join("testdata", "ensure_schema.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Conn.ExecContext(ctx, string(schema)+"INSERT INTO team (name, strength, budget) VALUES ('Chelsea', 5, 0);"); err != nil {
+		t.Fatalf("create legacy schema: %v", err)
+	}
+
+	if _, err := d.MigrateUp(ctx); err == nil || !strings.Contains(err.Error(), `"Chelsea"`) {
+		t.Fatalf("MigrateUp = %v, want an error naming Chelsea", err)
+	}
+	statuses, err := d.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	for _, status := range statuses {
+		if status.Applied {
+			t.Errorf("migration %d_%s is applied, want none", status.Version, status.Name)
+		}
+	}
+}
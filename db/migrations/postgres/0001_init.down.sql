@@ -0,0 +1,7 @@
+DROP TABLE teamStats;
+DROP TABLE game_state;
+DROP TABLE match_result;
+DROP TABLE match;
+DROP TABLE standing;
+DROP TABLE team;
+DROP TABLE season;
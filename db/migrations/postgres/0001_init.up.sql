@@ -0,0 +1,58 @@
+CREATE TABLE season (
+    id          BIGSERIAL   PRIMARY KEY,
+    year        BIGINT      NOT NULL,
+    seed        BIGINT      NOT NULL DEFAULT 0,
+    ranking_rules TEXT      NOT NULL DEFAULT 'premier-league',
+    is_current  BOOLEAN     DEFAULT FALSE,
+    is_complete BOOLEAN     DEFAULT FALSE
+);
+
+CREATE TABLE team (
+    id          BIGSERIAL   PRIMARY KEY,
+    name        TEXT        NOT NULL UNIQUE,
+    strength    BIGINT,
+    budget      BIGINT      DEFAULT 1000000
+);
+
+CREATE TABLE standing (
+    id          BIGSERIAL   PRIMARY KEY,
+    team_id     BIGINT      NOT NULL REFERENCES team(id),
+    season_id   BIGINT      NOT NULL REFERENCES season(id),
+    points      BIGINT      DEFAULT 0,
+    wins        BIGINT      DEFAULT 0,
+    draws       BIGINT      DEFAULT 0,
+    losses      BIGINT      DEFAULT 0,
+    goal_diff   BIGINT      DEFAULT 0,
+    goals_for   BIGINT      DEFAULT 0,
+    goals_against BIGINT    DEFAULT 0
+);
+
+CREATE TABLE match (
+    id          BIGSERIAL   PRIMARY KEY,
+    season_id   BIGINT      NOT NULL REFERENCES season(id),
+    home_id     BIGINT      NOT NULL REFERENCES team(id),
+    guest_id    BIGINT      NOT NULL REFERENCES team(id),
+    played      BOOLEAN     DEFAULT FALSE,
+    week        BIGINT      NOT NULL
+);
+
+CREATE TABLE match_result (
+    id          BIGSERIAL   PRIMARY KEY,
+    match_id    BIGINT      NOT NULL UNIQUE REFERENCES match(id),
+    home_score  BIGINT      NOT NULL,
+    guest_score BIGINT      NOT NULL,
+    winner_id   BIGINT      REFERENCES team(id)
+);
+
+CREATE TABLE game_state (
+    id          BIGSERIAL   PRIMARY KEY,
+    current_week BIGINT     DEFAULT 1,
+    season_id   BIGINT      NOT NULL REFERENCES season(id)
+);
+
+CREATE TABLE teamStats (
+    id          BIGSERIAL   PRIMARY KEY,
+    team        BIGINT      REFERENCES team(id),
+    value       BIGINT,
+    lastSeasonStanding BIGINT
+);
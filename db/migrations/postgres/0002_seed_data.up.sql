@@ -0,0 +1,12 @@
+-- Initialize first season (2025) with a random simulation seed
+INSERT INTO season (year, seed, is_current, is_complete) VALUES (2025, floor(random() * 9e18)::BIGINT, TRUE, FALSE);
+
+-- Initialize game state with week 1 and current season
+INSERT INTO game_state (current_week, season_id) 
+SELECT 1, id FROM season WHERE is_current = TRUE;
+
+-- Initialize teams with budgets
+INSERT INTO team (name, strength, budget) VALUES ('Manchester City', 10, 1000000000);
+INSERT INTO team (name, strength, budget) VALUES ('Chelsea', 7, 700000000);
+INSERT INTO team (name, strength, budget) VALUES ('Arsenal', 6, 600000000);
+INSERT INTO team (name, strength, budget) VALUES ('Liverpool', 9, 900000000);
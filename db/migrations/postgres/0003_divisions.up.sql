@@ -0,0 +1,36 @@
+-- Divisions of a season, tier 1 being the top one. relegation_places teams
+-- swap with the division below at the end of the season, plus one more
+-- decided by a playoff when playoff is set.
+CREATE TABLE division (
+    id          BIGSERIAL   PRIMARY KEY,
+    season_id   BIGINT      NOT NULL REFERENCES season(id),
+    name        TEXT        NOT NULL,
+    tier        BIGINT      NOT NULL,
+    relegation_places BIGINT NOT NULL DEFAULT 0,
+    playoff     BOOLEAN     NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE division_team (
+    division_id BIGINT      NOT NULL REFERENCES division(id),
+    team_id     BIGINT      NOT NULL REFERENCES team(id),
+    PRIMARY KEY (division_id, team_id)
+);
+
+-- Playoffs between two divisions decided when the next season starts
+CREATE TABLE playoff (
+    id          BIGSERIAL   PRIMARY KEY,
+    season_id   BIGINT      NOT NULL REFERENCES season(id),
+    division_id BIGINT      NOT NULL REFERENCES division(id),
+    upper_team_id BIGINT    NOT NULL REFERENCES team(id),
+    lower_team_id BIGINT    NOT NULL REFERENCES team(id),
+    upper_score BIGINT      NOT NULL,
+    lower_score BIGINT      NOT NULL,
+    winner_id   BIGINT      NOT NULL REFERENCES team(id)
+);
+
+ALTER TABLE match ADD COLUMN division_id BIGINT REFERENCES division(id);
+
+-- Every existing season becomes a single division of every team
+INSERT INTO division (season_id, name, tier) SELECT id, 'Division 1', 1 FROM season;
+INSERT INTO division_team (division_id, team_id) SELECT d.id, t.id FROM division d CROSS JOIN team t;
+UPDATE match SET division_id = (SELECT d.id FROM division d WHERE d.season_id = match.season_id);
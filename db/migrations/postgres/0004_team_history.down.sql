@@ -0,0 +1,8 @@
+DROP TABLE team_history;
+
+CREATE TABLE teamStats (
+    id          BIGSERIAL   PRIMARY KEY,
+    team        BIGINT      REFERENCES team(id),
+    value       BIGINT,
+    lastSeasonStanding BIGINT
+);
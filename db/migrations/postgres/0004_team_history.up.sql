@@ -0,0 +1,18 @@
+-- The unused teamStats table gives way to a row per team and season
+DROP TABLE teamStats;
+
+-- How each team changed at the end of a season: its final position across
+-- the divisions, the prize money it earned and its strength and budget
+-- before and after
+CREATE TABLE team_history (
+    id          BIGSERIAL   PRIMARY KEY,
+    team_id     BIGINT      NOT NULL REFERENCES team(id),
+    season_id   BIGINT      NOT NULL REFERENCES season(id),
+    last_season_standing BIGINT NOT NULL,
+    prize_money BIGINT      NOT NULL,
+    strength_before BIGINT  NOT NULL,
+    strength_after  BIGINT  NOT NULL,
+    budget_before   BIGINT  NOT NULL,
+    budget_after    BIGINT  NOT NULL,
+    UNIQUE (team_id, season_id)
+);
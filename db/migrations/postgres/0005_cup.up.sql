@@ -0,0 +1,30 @@
+-- The domestic cup of a season, a single-elimination bracket played
+-- alongside the league. A seeded draw keeps the strongest teams apart until
+-- the late rounds, otherwise the draw is random.
+CREATE TABLE cup (
+    id          BIGSERIAL   PRIMARY KEY,
+    season_id   BIGINT      NOT NULL UNIQUE REFERENCES season(id),
+    seeded      BOOLEAN     NOT NULL DEFAULT FALSE
+);
+
+-- Ties of a cup, numbered by slot from the top of each round's bracket. The
+-- winners of slots 2k and 2k+1 meet in slot k of the next round, so later
+-- rounds get their teams as the earlier ones are played. A tie with a single
+-- team is a bye. Drawn ties go to extra time and then penalties.
+CREATE TABLE cup_tie (
+    id          BIGSERIAL   PRIMARY KEY,
+    cup_id      BIGINT      NOT NULL REFERENCES cup(id),
+    round       BIGINT      NOT NULL,
+    slot        BIGINT      NOT NULL,
+    week        BIGINT      NOT NULL,
+    home_id     BIGINT      REFERENCES team(id),
+    guest_id    BIGINT      REFERENCES team(id),
+    home_score  BIGINT,
+    guest_score BIGINT,
+    extra_time  BOOLEAN     NOT NULL DEFAULT FALSE,
+    home_penalties  BIGINT,
+    guest_penalties BIGINT,
+    winner_id   BIGINT      REFERENCES team(id),
+    played      BOOLEAN     NOT NULL DEFAULT FALSE,
+    UNIQUE (cup_id, round, slot)
+);
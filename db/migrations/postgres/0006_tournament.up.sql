@@ -0,0 +1,46 @@
+-- A tournament of a season: a group stage drawn from pots, whose best teams
+-- go through to a two-legged knockout. It is played alongside the league.
+-- With away_goals, a tie level on aggregate goes to the side with more away
+-- goals before extra time. With separate_divisions, teams of the same
+-- division are kept in different groups.
+CREATE TABLE tournament (
+    id          BIGSERIAL   PRIMARY KEY,
+    season_id   BIGINT      NOT NULL UNIQUE REFERENCES season(id),
+    group_count BIGINT      NOT NULL,
+    qualifiers  BIGINT      NOT NULL,
+    away_goals  BOOLEAN     NOT NULL DEFAULT FALSE,
+    separate_divisions BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- The pot each team was drawn from and the group it was drawn into. Groups
+-- are numbered from 0 and take at most one team of each pot.
+CREATE TABLE tournament_team (
+    tournament_id BIGINT    NOT NULL REFERENCES tournament(id),
+    team_id       BIGINT    NOT NULL REFERENCES team(id),
+    pot           BIGINT    NOT NULL,
+    group_number  BIGINT    NOT NULL,
+    PRIMARY KEY (tournament_id, team_id)
+);
+
+-- Matches of a tournament. Round 0 is the group stage, where slot is the
+-- group. Later rounds are the knockout, numbered by slot like cup ties and
+-- played over two legs; their teams are only known once the round before
+-- is decided. The second leg holds the winner of the tie, after extra time
+-- and penalties if needed.
+CREATE TABLE tournament_match (
+    id            BIGSERIAL PRIMARY KEY,
+    tournament_id BIGINT    NOT NULL REFERENCES tournament(id),
+    round         BIGINT    NOT NULL,
+    slot          BIGINT    NOT NULL,
+    leg           BIGINT    NOT NULL,
+    week          BIGINT    NOT NULL,
+    home_id       BIGINT    REFERENCES team(id),
+    guest_id      BIGINT    REFERENCES team(id),
+    home_score    BIGINT,
+    guest_score   BIGINT,
+    extra_time    BOOLEAN   NOT NULL DEFAULT FALSE,
+    home_penalties  BIGINT,
+    guest_penalties BIGINT,
+    winner_id     BIGINT    REFERENCES team(id),
+    played        BOOLEAN   NOT NULL DEFAULT FALSE
+);
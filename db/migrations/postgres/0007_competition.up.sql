@@ -0,0 +1,49 @@
+-- The competitions of a season: its league, cup and tournament. Each holds
+-- the rules its matches are played and ranked by: the number of times teams
+-- of a league meet, and the points for a win, a draw and a loss.
+CREATE TABLE competition (
+    id          BIGSERIAL   PRIMARY KEY,
+    season_id   BIGINT      NOT NULL REFERENCES season(id),
+    format      TEXT        NOT NULL,
+    name        TEXT        NOT NULL,
+    legs        BIGINT      NOT NULL DEFAULT 2,
+    win_points  BIGINT      NOT NULL DEFAULT 3,
+    draw_points BIGINT      NOT NULL DEFAULT 1,
+    loss_points BIGINT      NOT NULL DEFAULT 0,
+    UNIQUE (season_id, format)
+);
+
+-- The teams taking part in a competition
+CREATE TABLE competition_team (
+    competition_id BIGINT   NOT NULL REFERENCES competition(id),
+    team_id        BIGINT   NOT NULL REFERENCES team(id),
+    PRIMARY KEY (competition_id, team_id)
+);
+
+-- Existing seasons keep the rules they were played by
+INSERT INTO competition (season_id, format, name, legs) SELECT id, 'league', 'League', 2 FROM season;
+INSERT INTO competition (season_id, format, name, legs) SELECT season_id, 'cup', 'Cup', 1 FROM cup;
+INSERT INTO competition (season_id, format, name, legs) SELECT season_id, 'tournament', 'Tournament', 2 FROM tournament;
+
+INSERT INTO competition_team (competition_id, team_id)
+SELECT c.id, dt.team_id
+FROM competition c
+JOIN division d ON d.season_id = c.season_id
+JOIN division_team dt ON dt.division_id = d.id
+WHERE c.format = 'league';
+INSERT INTO competition_team (competition_id, team_id)
+SELECT DISTINCT c.id, t.team_id
+FROM competition c
+JOIN cup ON cup.season_id = c.season_id
+JOIN (
+    SELECT cup_id, home_id AS team_id FROM cup_tie WHERE round = 1 AND home_id IS NOT NULL
+    UNION
+    SELECT cup_id, guest_id AS team_id FROM cup_tie WHERE round = 1 AND guest_id IS NOT NULL
+) t ON t.cup_id = cup.id
+WHERE c.format = 'cup';
+INSERT INTO competition_team (competition_id, team_id)
+SELECT c.id, tt.team_id
+FROM competition c
+JOIN tournament tr ON tr.season_id = c.season_id
+JOIN tournament_team tt ON tt.tournament_id = tr.id
+WHERE c.format = 'tournament';
@@ -0,0 +1,14 @@
+-- A competition can award bonus points to a team scoring at least
+-- bonus_goals in a match, none when it is 0
+ALTER TABLE competition ADD COLUMN bonus_goals BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE competition ADD COLUMN bonus_points BIGINT NOT NULL DEFAULT 0;
+
+-- Points taken from a team of a competition by an admin, with the reason
+CREATE TABLE point_deduction (
+    id             BIGSERIAL   PRIMARY KEY,
+    competition_id BIGINT      NOT NULL REFERENCES competition(id),
+    team_id        BIGINT      NOT NULL REFERENCES team(id),
+    points         BIGINT      NOT NULL,
+    reason         TEXT        NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,15 @@
+CREATE TABLE point_deduction (
+    id             BIGSERIAL   PRIMARY KEY,
+    competition_id BIGINT      NOT NULL REFERENCES competition(id),
+    team_id        BIGINT      NOT NULL REFERENCES team(id),
+    points         BIGINT      NOT NULL,
+    reason         TEXT        NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL
+);
+
+-- Forfeits and bans have no place without the sanction table
+INSERT INTO point_deduction (id, competition_id, team_id, points, reason, created_at)
+SELECT id, competition_id, team_id, points, reason, created_at FROM sanction WHERE kind = 'deduction';
+SELECT setval(pg_get_serial_sequence('point_deduction', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM point_deduction;
+
+DROP TABLE sanction;
@@ -0,0 +1,22 @@
+-- Sanctions taken against a team of a competition by an admin, with the
+-- reason. A deduction takes points, a forfeit loses match_id 3-0 and a ban
+-- forfeits every fixture of the team from first_week to last_week.
+CREATE TABLE sanction (
+    id             BIGSERIAL   PRIMARY KEY,
+    competition_id BIGINT      NOT NULL REFERENCES competition(id),
+    team_id        BIGINT      NOT NULL REFERENCES team(id),
+    kind           TEXT        NOT NULL,
+    points         BIGINT      NOT NULL DEFAULT 0,
+    match_id       BIGINT      REFERENCES match(id),
+    first_week     BIGINT      NOT NULL DEFAULT 0,
+    last_week      BIGINT      NOT NULL DEFAULT 0,
+    reason         TEXT        NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL
+);
+
+-- Point deductions become the first kind of sanction
+INSERT INTO sanction (id, competition_id, team_id, kind, points, reason, created_at)
+SELECT id, competition_id, team_id, 'deduction', points, reason, created_at FROM point_deduction;
+SELECT setval(pg_get_serial_sequence('sanction', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM sanction;
+
+DROP TABLE point_deduction;
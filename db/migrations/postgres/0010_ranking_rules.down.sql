@@ -0,0 +1,9 @@
+ALTER TABLE season ADD COLUMN ranking_rules TEXT NOT NULL DEFAULT 'premier-league';
+
+UPDATE season
+SET ranking_rules = COALESCE(
+    (SELECT c.ranking_rules FROM competition c WHERE c.season_id = season.id AND c.format = 'league'),
+    'premier-league'
+);
+
+ALTER TABLE competition DROP COLUMN ranking_rules;
@@ -0,0 +1,66 @@
+CREATE TABLE season (
+    id          INTEGER     PRIMARY KEY,
+    year        INTEGER     NOT NULL,
+    seed        INTEGER     NOT NULL DEFAULT 0,
+    ranking_rules TEXT      NOT NULL DEFAULT 'premier-league',
+    is_current  BOOLEAN     DEFAULT FALSE,
+    is_complete BOOLEAN     DEFAULT FALSE
+);
+
+CREATE TABLE team (
+    id          INTEGER     PRIMARY KEY,
+    name        text        NOT NULL UNIQUE,
+    strength    INTEGER,
+    budget      INTEGER     DEFAULT 1000000
+);
+
+CREATE TABLE standing (
+    id          INTEGER     PRIMARY KEY,
+    team_id     INTEGER     NOT NULL,
+    season_id   INTEGER     NOT NULL,
+    points      INTEGER     DEFAULT 0,
+    wins        INTEGER     DEFAULT 0,
+    draws       INTEGER     DEFAULT 0,
+    losses      INTEGER     DEFAULT 0,
+    goal_diff   INTEGER     DEFAULT 0,
+    goals_for   INTEGER     DEFAULT 0,
+    goals_against INTEGER   DEFAULT 0,
+    FOREIGN KEY (team_id) REFERENCES team(id),
+    FOREIGN KEY (season_id) REFERENCES season(id)
+);
+
+CREATE TABLE match (
+    id          INTEGER     PRIMARY KEY,
+    season_id   INTEGER     NOT NULL,
+    home_id     INTEGER     NOT NULL,
+    guest_id    INTEGER     NOT NULL,
+    played      BOOLEAN     DEFAULT FALSE,
+    week        INTEGER     NOT NULL,
+    FOREIGN KEY (home_id) REFERENCES team(id),
+    FOREIGN KEY (guest_id) REFERENCES team(id),
+    FOREIGN KEY (season_id) REFERENCES season(id)
+);
+
+CREATE TABLE match_result (
+    id          INTEGER     PRIMARY KEY,
+    match_id    INTEGER     NOT NULL UNIQUE,
+    home_score  INTEGER     NOT NULL,
+    guest_score INTEGER     NOT NULL,
+    winner_id   INTEGER,
+    FOREIGN KEY (match_id) REFERENCES match(id),
+    FOREIGN KEY (winner_id) REFERENCES team(id)
+);
+
+CREATE TABLE game_state (
+    id          INTEGER     PRIMARY KEY,
+    current_week INTEGER    DEFAULT 1,
+    season_id   INTEGER     NOT NULL,
+    FOREIGN KEY (season_id) REFERENCES season(id)
+);
+
+CREATE TABLE teamStats (
+    id          INTEGER     PRIMARY KEY,
+    team        team,
+    value       integer,
+    lastSeasonStanding integer
+);
@@ -0,0 +1,6 @@
+DELETE FROM match_result;
+DELETE FROM match;
+DELETE FROM standing;
+DELETE FROM game_state;
+DELETE FROM team;
+DELETE FROM season;
@@ -0,0 +1,4 @@
+ALTER TABLE match DROP COLUMN division_id;
+DROP TABLE playoff;
+DROP TABLE division_team;
+DROP TABLE division;
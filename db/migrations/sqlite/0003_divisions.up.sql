@@ -0,0 +1,44 @@
+-- Divisions of a season, tier 1 being the top one. relegation_places teams
+-- swap with the division below at the end of the season, plus one more
+-- decided by a playoff when playoff is set.
+CREATE TABLE division (
+    id          INTEGER     PRIMARY KEY,
+    season_id   INTEGER     NOT NULL,
+    name        TEXT        NOT NULL,
+    tier        INTEGER     NOT NULL,
+    relegation_places INTEGER NOT NULL DEFAULT 0,
+    playoff     BOOLEAN     NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (season_id) REFERENCES season(id)
+);
+
+CREATE TABLE division_team (
+    division_id INTEGER     NOT NULL,
+    team_id     INTEGER     NOT NULL,
+    PRIMARY KEY (division_id, team_id),
+    FOREIGN KEY (division_id) REFERENCES division(id),
+    FOREIGN KEY (team_id) REFERENCES team(id)
+);
+
+-- Playoffs between two divisions decided when the next season starts
+CREATE TABLE playoff (
+    id          INTEGER     PRIMARY KEY,
+    season_id   INTEGER     NOT NULL,
+    division_id INTEGER     NOT NULL,
+    upper_team_id INTEGER   NOT NULL,
+    lower_team_id INTEGER   NOT NULL,
+    upper_score INTEGER     NOT NULL,
+    lower_score INTEGER     NOT NULL,
+    winner_id   INTEGER     NOT NULL,
+    FOREIGN KEY (season_id) REFERENCES season(id),
+    FOREIGN KEY (division_id) REFERENCES division(id),
+    FOREIGN KEY (upper_team_id) REFERENCES team(id),
+    FOREIGN KEY (lower_team_id) REFERENCES team(id),
+    FOREIGN KEY (winner_id) REFERENCES team(id)
+);
+
+ALTER TABLE match ADD COLUMN division_id INTEGER;
+
+-- Every existing season becomes a single division of every team
+INSERT INTO division (season_id, name, tier) SELECT id, 'Division 1', 1 FROM season;
+INSERT INTO division_team (division_id, team_id) SELECT d.id, t.id FROM division d CROSS JOIN team t;
+UPDATE match SET division_id = (SELECT d.id FROM division d WHERE d.season_id = match.season_id);
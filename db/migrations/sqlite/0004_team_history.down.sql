@@ -0,0 +1,8 @@
+DROP TABLE team_history;
+
+CREATE TABLE teamStats (
+    id          INTEGER     PRIMARY KEY,
+    team        team,
+    value       integer,
+    lastSeasonStanding integer
+);
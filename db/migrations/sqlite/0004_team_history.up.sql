@@ -0,0 +1,20 @@
+-- The unused teamStats table gives way to a row per team and season
+DROP TABLE teamStats;
+
+-- How each team changed at the end of a season: its final position across
+-- the divisions, the prize money it earned and its strength and budget
+-- before and after
+CREATE TABLE team_history (
+    id          INTEGER     PRIMARY KEY,
+    team_id     INTEGER     NOT NULL,
+    season_id   INTEGER     NOT NULL,
+    last_season_standing INTEGER NOT NULL,
+    prize_money INTEGER     NOT NULL,
+    strength_before INTEGER NOT NULL,
+    strength_after  INTEGER NOT NULL,
+    budget_before   INTEGER NOT NULL,
+    budget_after    INTEGER NOT NULL,
+    UNIQUE (team_id, season_id),
+    FOREIGN KEY (team_id) REFERENCES team(id),
+    FOREIGN KEY (season_id) REFERENCES season(id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE cup_tie;
+DROP TABLE cup;
@@ -0,0 +1,35 @@
+-- The domestic cup of a season, a single-elimination bracket played
+-- alongside the league. A seeded draw keeps the strongest teams apart until
+-- the late rounds, otherwise the draw is random.
+CREATE TABLE cup (
+    id          INTEGER     PRIMARY KEY,
+    season_id   INTEGER     NOT NULL UNIQUE,
+    seeded      BOOLEAN     NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (season_id) REFERENCES season(id)
+);
+
+-- Ties of a cup, numbered by slot from the top of each round's bracket. The
+-- winners of slots 2k and 2k+1 meet in slot k of the next round, so later
+-- rounds get their teams as the earlier ones are played. A tie with a single
+-- team is a bye. Drawn ties go to extra time and then penalties.
+CREATE TABLE cup_tie (
+    id          INTEGER     PRIMARY KEY,
+    cup_id      INTEGER     NOT NULL,
+    round       INTEGER     NOT NULL,
+    slot        INTEGER     NOT NULL,
+    week        INTEGER     NOT NULL,
+    home_id     INTEGER,
+    guest_id    INTEGER,
+    home_score  INTEGER,
+    guest_score INTEGER,
+    extra_time  BOOLEAN     NOT NULL DEFAULT FALSE,
+    home_penalties  INTEGER,
+    guest_penalties INTEGER,
+    winner_id   INTEGER,
+    played      BOOLEAN     NOT NULL DEFAULT FALSE,
+    UNIQUE (cup_id, round, slot),
+    FOREIGN KEY (cup_id) REFERENCES cup(id),
+    FOREIGN KEY (home_id) REFERENCES team(id),
+    FOREIGN KEY (guest_id) REFERENCES team(id),
+    FOREIGN KEY (winner_id) REFERENCES team(id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE tournament_match;
+DROP TABLE tournament_team;
+DROP TABLE tournament;
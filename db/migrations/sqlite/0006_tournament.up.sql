@@ -0,0 +1,53 @@
+-- A tournament of a season: a group stage drawn from pots, whose best teams
+-- go through to a two-legged knockout. It is played alongside the league.
+-- With away_goals, a tie level on aggregate goes to the side with more away
+-- goals before extra time. With separate_divisions, teams of the same
+-- division are kept in different groups.
+CREATE TABLE tournament (
+    id          INTEGER     PRIMARY KEY,
+    season_id   INTEGER     NOT NULL UNIQUE,
+    group_count INTEGER     NOT NULL,
+    qualifiers  INTEGER     NOT NULL,
+    away_goals  BOOLEAN     NOT NULL DEFAULT FALSE,
+    separate_divisions BOOLEAN NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (season_id) REFERENCES season(id)
+);
+
+-- The pot each team was drawn from and the group it was drawn into. Groups
+-- are numbered from 0 and take at most one team of each pot.
+CREATE TABLE tournament_team (
+    tournament_id INTEGER   NOT NULL,
+    team_id       INTEGER   NOT NULL,
+    pot           INTEGER   NOT NULL,
+    group_number  INTEGER   NOT NULL,
+    PRIMARY KEY (tournament_id, team_id),
+    FOREIGN KEY (tournament_id) REFERENCES tournament(id),
+    FOREIGN KEY (team_id) REFERENCES team(id)
+);
+
+-- Matches of a tournament. Round 0 is the group stage, where slot is the
+-- group. Later rounds are the knockout, numbered by slot like cup ties and
+-- played over two legs; their teams are only known once the round before
+-- is decided. The second leg holds the winner of the tie, after extra time
+-- and penalties if needed.
+CREATE TABLE tournament_match (
+    id            INTEGER   PRIMARY KEY,
+    tournament_id INTEGER   NOT NULL,
+    round         INTEGER   NOT NULL,
+    slot          INTEGER   NOT NULL,
+    leg           INTEGER   NOT NULL,
+    week          INTEGER   NOT NULL,
+    home_id       INTEGER,
+    guest_id      INTEGER,
+    home_score    INTEGER,
+    guest_score   INTEGER,
+    extra_time    BOOLEAN   NOT NULL DEFAULT FALSE,
+    home_penalties  INTEGER,
+    guest_penalties INTEGER,
+    winner_id     INTEGER,
+    played        BOOLEAN   NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (tournament_id) REFERENCES tournament(id),
+    FOREIGN KEY (home_id) REFERENCES team(id),
+    FOREIGN KEY (guest_id) REFERENCES team(id),
+    FOREIGN KEY (winner_id) REFERENCES team(id)
+);
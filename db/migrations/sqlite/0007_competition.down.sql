@@ -0,0 +1,2 @@
+DROP TABLE competition_team;
+DROP TABLE competition;
@@ -0,0 +1,3 @@
+DROP TABLE point_deduction;
+ALTER TABLE competition DROP COLUMN bonus_points;
+ALTER TABLE competition DROP COLUMN bonus_goals;
@@ -0,0 +1,16 @@
+-- A competition can award bonus points to a team scoring at least
+-- bonus_goals in a match, none when it is 0
+ALTER TABLE competition ADD COLUMN bonus_goals INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE competition ADD COLUMN bonus_points INTEGER NOT NULL DEFAULT 0;
+
+-- Points taken from a team of a competition by an admin, with the reason
+CREATE TABLE point_deduction (
+    id             INTEGER   PRIMARY KEY,
+    competition_id INTEGER   NOT NULL,
+    team_id        INTEGER   NOT NULL,
+    points         INTEGER   NOT NULL,
+    reason         TEXT      NOT NULL,
+    created_at     TIMESTAMP NOT NULL,
+    FOREIGN KEY (competition_id) REFERENCES competition(id),
+    FOREIGN KEY (team_id) REFERENCES team(id)
+);
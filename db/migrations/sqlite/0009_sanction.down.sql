@@ -0,0 +1,16 @@
+CREATE TABLE point_deduction (
+    id             INTEGER   PRIMARY KEY,
+    competition_id INTEGER   NOT NULL,
+    team_id        INTEGER   NOT NULL,
+    points         INTEGER   NOT NULL,
+    reason         TEXT      NOT NULL,
+    created_at     TIMESTAMP NOT NULL,
+    FOREIGN KEY (competition_id) REFERENCES competition(id),
+    FOREIGN KEY (team_id) REFERENCES team(id)
+);
+
+-- Forfeits and bans have no place without the sanction table
+INSERT INTO point_deduction (id, competition_id, team_id, points, reason, created_at)
+SELECT id, competition_id, team_id, points, reason, created_at FROM sanction WHERE kind = 'deduction';
+
+DROP TABLE sanction;
@@ -0,0 +1,24 @@
+-- Sanctions taken against a team of a competition by an admin, with the
+-- reason. A deduction takes points, a forfeit loses match_id 3-0 and a ban
+-- forfeits every fixture of the team from first_week to last_week.
+CREATE TABLE sanction (
+    id             INTEGER   PRIMARY KEY,
+    competition_id INTEGER   NOT NULL,
+    team_id        INTEGER   NOT NULL,
+    kind           TEXT      NOT NULL,
+    points         INTEGER   NOT NULL DEFAULT 0,
+    match_id       INTEGER,
+    first_week     INTEGER   NOT NULL DEFAULT 0,
+    last_week      INTEGER   NOT NULL DEFAULT 0,
+    reason         TEXT      NOT NULL,
+    created_at     TIMESTAMP NOT NULL,
+    FOREIGN KEY (competition_id) REFERENCES competition(id),
+    FOREIGN KEY (team_id) REFERENCES team(id),
+    FOREIGN KEY (match_id) REFERENCES match(id)
+);
+
+-- Point deductions become the first kind of sanction
+INSERT INTO sanction (id, competition_id, team_id, kind, points, reason, created_at)
+SELECT id, competition_id, team_id, 'deduction', points, reason, created_at FROM point_deduction;
+
+DROP TABLE point_deduction;
@@ -0,0 +1,15 @@
+-- Each competition ranks level teams by its own tie-breaker rules, which
+-- used to be set on the season
+ALTER TABLE competition ADD COLUMN ranking_rules TEXT NOT NULL DEFAULT 'premier-league';
+
+UPDATE competition
+SET ranking_rules = (SELECT s.ranking_rules FROM season s WHERE s.id = competition.season_id);
+
+-- A season without a league yet keeps its rules for the league it will play
+INSERT INTO competition (season_id, format, name, legs, ranking_rules)
+SELECT s.id, 'league', 'League', 2, s.ranking_rules
+FROM season s
+WHERE s.ranking_rules <> 'premier-league'
+  AND NOT EXISTS (SELECT 1 FROM competition c WHERE c.season_id = s.id AND c.format = 'league');
+
+ALTER TABLE season DROP COLUMN ranking_rules;
@@ -0,0 +1,204 @@
+// Package api serves the versioned JSON API of the league under /api/v1.
+//
+// Successful responses wrap their payload as {"data": ...} and failed ones as
+// {"error": {"code": ..., "message": ...}}.
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/prediction"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+// Error codes returned in the error envelope
+const (
+	CodeBadRequest         = "bad_request"
+	CodeNotFound           = "not_found"
+	CodeNoActiveSeason     = "no_active_season"
+	CodeNoFixtures         = "no_fixtures"
+	CodeWeekNotFinished    = "week_not_finished"
+	CodeSeasonComplete     = "season_complete"
+	CodeValidation         = "validation_failed"
+	CodeTeamNameTaken      = "team_name_taken"
+	CodeTeamHasMatches     = "team_has_matches"
+	CodeSeasonStarted      = "season_in_progress"
+	CodeCupNotDrawn        = "cup_not_drawn"
+	CodeTournamentNotDrawn = "tournament_not_drawn"
+	CodeInternal           = "internal_error"
+)
+
+// RegisterRoutes registers all API routes
+func RegisterRoutes(router *gin.Engine, repo repository.Repository, sim simulation.MatchSimulator, predictor *prediction.MonteCarloPredictor) {
+	v1 := router.Group("/api/v1")
+
+	v1.GET("/seasons", handleListSeasons(repo))
+	v1.POST("/seasons", handleCreateSeason(repo, sim))
+	v1.GET("/seasons/current", handleGetCurrentSeason(repo))
+	v1.GET("/seasons/:id", handleGetSeason(repo))
+
+	v1.GET("/teams", handleListTeams(repo))
+	v1.GET("/teams/:id", handleGetTeam(repo))
+	v1.GET("/teams/:id/history", handleGetTeamHistory(repo))
+	v1.GET("/teams/:id/season", handleGetTeamSeason(repo))
+	v1.GET("/teams/:id/vs/:opponent_id", handleGetHeadToHead(repo))
+	v1.POST("/teams", handleCreateTeam(repo))
+	v1.PUT("/teams/:id", handleUpdateTeam(repo))
+	v1.DELETE("/teams/:id", handleDeleteTeam(repo))
+
+	v1.GET("/fixtures", handleListFixtures(repo))
+	v1.GET("/results", handleListResults(repo))
+	v1.GET("/standings", handleGetStandings(repo))
+	v1.GET("/divisions", handleListDivisions(repo))
+	v1.GET("/predictions", handleGetPredictions(repo, predictor))
+	v1.GET("/cup", handleGetCup(repo))
+	v1.POST("/cup", handleDrawCup(repo))
+	v1.GET("/tournament", handleGetTournament(repo))
+	v1.POST("/tournament", handleDrawTournament(repo))
+	v1.GET("/competitions", handleListCompetitions(repo))
+	v1.PUT("/competitions/:format", handleUpdateCompetition(repo))
+	v1.POST("/competitions/:format/deductions", handleAddDeduction(repo, sim))
+	v1.DELETE("/deductions/:id", handleDeleteDeduction(repo, sim))
+	v1.GET("/sanctions", handleListSanctions(repo))
+	v1.POST("/sanctions", handleAddSanction(repo, sim))
+	v1.DELETE("/sanctions/:id", handleDeleteSanction(repo, sim))
+
+	v1.POST("/simulation/generate-fixtures", handleGenerateFixtures(repo))
+	v1.POST("/simulation/play-week", handlePlayWeek(repo, sim))
+	v1.POST("/simulation/next-week", handleNextWeek(repo))
+	v1.POST("/simulation/play-all", handlePlayAll(repo, sim))
+}
+
+// ErrorEnvelope is the body of every failed response
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody is the payload of the error envelope
+type ErrorBody struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"` // invalid fields of a validation error
+}
+
+func respond(c *gin.Context, status int, data any) {
+	c.JSON(status, gin.H{"data": data})
+}
+
+func respondError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, ErrorEnvelope{Error: ErrorBody{Code: code, Message: message}})
+}
+
+// respondLeagueError maps the errors of the league package to API errors
+func respondLeagueError(c *gin.Context, err error) {
+	var validationErr *league.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.AbortWithStatusJSON(http.StatusBadRequest, ErrorEnvelope{Error: ErrorBody{
+			Code:    CodeValidation,
+			Message: "Invalid fields",
+			Fields:  validationErr.Fields,
+		}})
+	case errors.Is(err, league.ErrTeamNotFound):
+		respondError(c, http.StatusNotFound, CodeNotFound, "Team not found")
+	case errors.Is(err, league.ErrSameTeam):
+		respondError(c, http.StatusBadRequest, CodeBadRequest, "A team cannot be compared with itself")
+	case errors.Is(err, league.ErrTeamNameTaken):
+		respondError(c, http.StatusConflict, CodeTeamNameTaken, "A team with this name already exists")
+	case errors.Is(err, league.ErrTeamHasMatches):
+		respondError(c, http.StatusConflict, CodeTeamHasMatches, "Team has played matches and cannot be deleted")
+	case errors.Is(err, league.ErrSeasonInProgress):
+		respondError(c, http.StatusConflict, CodeSeasonStarted, "Teams cannot be added or removed while a season is in progress")
+	case errors.Is(err, league.ErrNoActiveSeason):
+		respondError(c, http.StatusNotFound, CodeNoActiveSeason, "No active season")
+	case errors.Is(err, league.ErrNoMatchesToPlay), errors.Is(err, league.ErrNoFixtures):
+		respondError(c, http.StatusConflict, CodeNoFixtures, "No matches to play in the current week, generate fixtures first")
+	case errors.Is(err, league.ErrWeekNotFinished):
+		respondError(c, http.StatusConflict, CodeWeekNotFinished, "Not all matches of the current week are played")
+	case errors.Is(err, league.ErrSeasonComplete):
+		respondError(c, http.StatusConflict, CodeSeasonComplete, "Season is complete")
+	case errors.Is(err, league.ErrUnknownRules):
+		respondError(c, http.StatusBadRequest, CodeBadRequest, "Unknown ranking rules")
+	case errors.Is(err, league.ErrNoCup):
+		respondError(c, http.StatusNotFound, CodeNotFound, "The season has no cup")
+	case errors.Is(err, league.ErrUnknownDraw):
+		respondError(c, http.StatusBadRequest, CodeBadRequest, "Unknown cup draw, use seeded or random")
+	case errors.Is(err, league.ErrCupLocked):
+		respondError(c, http.StatusConflict, CodeSeasonStarted, "The cup can only be drawn before the season's first match")
+	case errors.Is(err, league.ErrCupTooLong):
+		respondError(c, http.StatusConflict, CodeCupNotDrawn, "The league has fewer weeks than the cup has rounds")
+	case errors.Is(err, league.ErrNoTournament):
+		respondError(c, http.StatusNotFound, CodeNotFound, "The season has no tournament")
+	case errors.Is(err, league.ErrTournamentLocked):
+		respondError(c, http.StatusConflict, CodeSeasonStarted, "The tournament can only be drawn before the season's first match")
+	case errors.Is(err, league.ErrTournamentTooLong):
+		respondError(c, http.StatusConflict, CodeTournamentNotDrawn, "The league has fewer weeks than the tournament has matchdays")
+	case errors.Is(err, league.ErrDrawImpossible):
+		respondError(c, http.StatusConflict, CodeTournamentNotDrawn, "No draw keeps the teams of each division in different groups")
+	case errors.Is(err, league.ErrCompetitionNotFound):
+		respondError(c, http.StatusNotFound, CodeNotFound, "The season has no such competition")
+	case errors.Is(err, league.ErrCompetitionLocked):
+		respondError(c, http.StatusConflict, CodeSeasonStarted, "Competition rules can only change before the season's first match")
+	case errors.Is(err, league.ErrSanctionNotFound):
+		respondError(c, http.StatusNotFound, CodeNotFound, "Sanction not found in the current season")
+	default:
+		log.Printf("API error on %s %s: %v", c.Request.Method, c.FullPath(), err)
+		respondError(c, http.StatusInternalServerError, CodeInternal, "Internal server error")
+	}
+}
+
+// pathID parses an integer path parameter, responding with an error if it
+// is invalid
+func pathID(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid "+name)
+		return 0, false
+	}
+	return id, true
+}
+
+// querySeason returns the season selected by the season_id query parameter,
+// or the current season when it is not given
+func querySeason(c *gin.Context, repo repository.Repository) (sqlc.Season, bool) {
+	reqCtx := c.Request.Context()
+
+	value := c.Query("season_id")
+	if value == "" {
+		season, err := repo.GetCurrentSeason(reqCtx)
+		if err == sql.ErrNoRows {
+			respondLeagueError(c, league.ErrNoActiveSeason)
+			return sqlc.Season{}, false
+		}
+		if err != nil {
+			respondLeagueError(c, err)
+			return sqlc.Season{}, false
+		}
+		return season, true
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid season_id")
+		return sqlc.Season{}, false
+	}
+
+	season, err := repo.GetSeason(reqCtx, id)
+	if err == sql.ErrNoRows {
+		respondError(c, http.StatusNotFound, CodeNotFound, "Season not found")
+		return sqlc.Season{}, false
+	}
+	if err != nil {
+		respondLeagueError(c, err)
+		return sqlc.Season{}, false
+	}
+	return season, true
+}
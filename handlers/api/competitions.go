@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+)
+
+func handleListCompetitions(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		season, ok := querySeason(c, repo)
+		if !ok {
+			return
+		}
+
+		competitions, err := league.ListCompetitions(c.Request.Context(), repo, season)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		dto := make([]Competition, 0, len(competitions))
+		for _, competition := range competitions {
+			dto = append(dto, newCompetition(competition))
+		}
+		respond(c, http.StatusOK, dto)
+	}
+}
+
+// handleUpdateCompetition changes the rules of a competition of the current
+// season
+func handleUpdateCompetition(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CompetitionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid request body")
+			return
+		}
+
+		competition, err := league.UpdateCompetition(c.Request.Context(), repo, c.Param("format"), league.CompetitionInput{
+			Legs:         req.Legs,
+			WinPoints:    req.Points.Win,
+			DrawPoints:   req.Points.Draw,
+			LossPoints:   req.Points.Loss,
+			BonusGoals:   req.Points.BonusGoals,
+			BonusPoints:  req.Points.Bonus,
+			RankingRules: req.RankingRules,
+		})
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusOK, newCompetition(competition))
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+)
+
+func handleGetCup(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		season, ok := querySeason(c, repo)
+		if !ok {
+			return
+		}
+
+		cup, err := league.GetCup(c.Request.Context(), repo, season)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusOK, newCup(cup))
+	}
+}
+
+// handleDrawCup draws the cup of the current season, replacing any earlier
+// draw
+func handleDrawCup(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DrawCupRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid request body")
+			return
+		}
+
+		cup, err := league.DrawCup(c.Request.Context(), repo, req.Draw)
+		if errors.Is(err, league.ErrNotEnoughTeams) {
+			respondError(c, http.StatusConflict, CodeCupNotDrawn, "The cup needs at least 2 teams")
+			return
+		}
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusCreated, newCup(cup))
+	}
+}
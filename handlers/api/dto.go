@@ -0,0 +1,745 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/prediction"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+// Season is the API representation of a season
+type Season struct {
+	ID          int64 `json:"id"`
+	Year        int64 `json:"year"`
+	Seed        int64 `json:"seed"`
+	IsCurrent   bool  `json:"is_current"`
+	IsComplete  bool  `json:"is_complete"`
+	CurrentWeek int   `json:"current_week,omitempty"`
+}
+
+// Team is the API representation of a team
+type Team struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Strength int64  `json:"strength"`
+	Budget   int64  `json:"budget"`
+}
+
+// TeamHistory is how a team's strength and budget changed at the end of a
+// season
+type TeamHistory struct {
+	SeasonID           int64 `json:"season_id"`
+	SeasonYear         int64 `json:"season_year"`
+	LastSeasonStanding int64 `json:"last_season_standing"`
+	PrizeMoney         int64 `json:"prize_money"`
+	StrengthBefore     int64 `json:"strength_before"`
+	StrengthAfter      int64 `json:"strength_after"`
+	BudgetBefore       int64 `json:"budget_before"`
+	BudgetAfter        int64 `json:"budget_after"`
+}
+
+// TeamRef identifies a team inside another resource
+type TeamRef struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Division is a tier of a season's league with its teams
+type Division struct {
+	ID               int64     `json:"id"`
+	SeasonID         int64     `json:"season_id"`
+	Name             string    `json:"name"`
+	Tier             int64     `json:"tier"`
+	RelegationPlaces int64     `json:"relegation_places"`
+	Playoff          bool      `json:"playoff"`
+	Teams            []TeamRef `json:"teams"`
+}
+
+// DivisionRef identifies a division inside another resource
+type DivisionRef struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Tier int64  `json:"tier"`
+}
+
+// Match is a fixture, with its score once it is played
+type Match struct {
+	ID         int64   `json:"id"`
+	SeasonID   int64   `json:"season_id"`
+	Week       int64   `json:"week"`
+	HomeTeam   TeamRef `json:"home_team"`
+	GuestTeam  TeamRef `json:"guest_team"`
+	Played     bool    `json:"played"`
+	HomeScore  *int64  `json:"home_score"`
+	GuestScore *int64  `json:"guest_score"`
+}
+
+// Standing is a row of the league table
+type Standing struct {
+	Position     int         `json:"position"` // within the division
+	Team         TeamRef     `json:"team"`
+	Division     DivisionRef `json:"division,omitzero"`
+	Played       int64       `json:"played"`
+	Wins         int64       `json:"wins"`
+	Draws        int64       `json:"draws"`
+	Losses       int64       `json:"losses"`
+	GoalsFor     int64       `json:"goals_for"`
+	GoalsAgainst int64       `json:"goals_against"`
+	GoalDiff     int64       `json:"goal_diff"`
+	Points       int64       `json:"points"`
+	Deducted     int64       `json:"deducted"`        // points deducted by an admin, already taken from points
+	Notes        []string    `json:"notes,omitempty"` // sanctions against the team in the league
+}
+
+// Record counts the results of a team against an opponent
+type Record struct {
+	Played       int64 `json:"played"`
+	Wins         int64 `json:"wins"`
+	Draws        int64 `json:"draws"`
+	Losses       int64 `json:"losses"`
+	GoalsFor     int64 `json:"goals_for"`
+	GoalsAgainst int64 `json:"goals_against"`
+}
+
+// SeasonRecord is the record of a team against an opponent in one season
+type SeasonRecord struct {
+	SeasonID   int64  `json:"season_id"`
+	SeasonYear int64  `json:"season_year"`
+	Record     Record `json:"record"`
+}
+
+// Meeting is a played match between the two teams of a head-to-head
+type Meeting struct {
+	MatchID    int64   `json:"match_id"`
+	SeasonID   int64   `json:"season_id"`
+	SeasonYear int64   `json:"season_year"`
+	Week       int64   `json:"week"`
+	HomeTeam   TeamRef `json:"home_team"`
+	GuestTeam  TeamRef `json:"guest_team"`
+	HomeScore  int64   `json:"home_score"`
+	GuestScore int64   `json:"guest_score"`
+}
+
+// HeadToHead compares a team with an opponent over every season, the
+// records seen from the team
+type HeadToHead struct {
+	Team               TeamRef        `json:"team"`
+	Opponent           TeamRef        `json:"opponent"`
+	AllTime            Record         `json:"all_time"`
+	Seasons            []SeasonRecord `json:"seasons"` // oldest first
+	BiggestWin         *Meeting       `json:"biggest_win"`
+	OpponentBiggestWin *Meeting       `json:"opponent_biggest_win"`
+	LastMeetings       []Meeting      `json:"last_meetings"` // latest first
+}
+
+// TeamMatch is a fixture of a team, seen from the team
+type TeamMatch struct {
+	MatchID      int64   `json:"match_id"`
+	Week         int64   `json:"week"`
+	Opponent     TeamRef `json:"opponent"`
+	Home         bool    `json:"home"`
+	Played       bool    `json:"played"`
+	GoalsFor     *int64  `json:"goals_for"`
+	GoalsAgainst *int64  `json:"goals_against"`
+	Outcome      string  `json:"outcome,omitempty"` // W, D or L once played
+}
+
+// TeamWeek is where a team stood in its division after a week
+type TeamWeek struct {
+	Week     int64 `json:"week"`
+	Points   int64 `json:"points"`
+	Position int   `json:"position"`
+}
+
+// TeamSeason follows a team through a season
+type TeamSeason struct {
+	Team     TeamRef     `json:"team"`
+	SeasonID int64       `json:"season_id"`
+	Division DivisionRef `json:"division,omitzero"`
+	Form     []string    `json:"form"` // latest results, oldest first
+	Home     Record      `json:"home"`
+	Away     Record      `json:"away"`
+	Matches  []TeamMatch `json:"matches"`
+	Upcoming []TeamMatch `json:"upcoming"`
+	Weeks    []TeamWeek  `json:"weeks"` // points and position after each week
+}
+
+// Prediction holds a team's estimated finishing probabilities
+type Prediction struct {
+	Team  TeamRef `json:"team"`
+	Title float64 `json:"title"`
+	TopN  float64 `json:"top_n"`
+	Last  float64 `json:"last"`
+}
+
+// Predictions lists the predictions of a season
+type Predictions struct {
+	SeasonID int64        `json:"season_id"`
+	TopN     int          `json:"top_n"`
+	Teams    []Prediction `json:"teams"`
+}
+
+// Cup is the knockout bracket of a season's cup
+type Cup struct {
+	SeasonID int64      `json:"season_id"`
+	Draw     string     `json:"draw"` // seeded or random
+	Rounds   []CupRound `json:"rounds"`
+	Winner   *TeamRef   `json:"winner"` // null until the final is played
+}
+
+// CupRound is a round of the cup, played alongside a league week
+type CupRound struct {
+	Round int64    `json:"round"`
+	Name  string   `json:"name"`
+	Week  int64    `json:"week"`
+	Ties  []CupTie `json:"ties"`
+}
+
+// CupTie is a match of the cup. Teams are null until they are known and the
+// guest team of a bye stays null.
+type CupTie struct {
+	ID             int64    `json:"id"`
+	HomeTeam       *TeamRef `json:"home_team"`
+	GuestTeam      *TeamRef `json:"guest_team"`
+	Bye            bool     `json:"bye"`
+	Played         bool     `json:"played"`
+	HomeScore      *int64   `json:"home_score"`
+	GuestScore     *int64   `json:"guest_score"`
+	ExtraTime      bool     `json:"extra_time"`
+	HomePenalties  *int64   `json:"home_penalties"`
+	GuestPenalties *int64   `json:"guest_penalties"`
+	Winner         *TeamRef `json:"winner"`
+}
+
+// Tournament is a season's tournament: a group stage and then a two-legged
+// knockout between the best placed teams of each group
+type Tournament struct {
+	SeasonID int64             `json:"season_id"`
+	Format   TournamentFormat  `json:"format"`
+	Groups   []TournamentGroup `json:"groups"`
+	Knockout []TournamentRound `json:"knockout"`
+	Winner   *TeamRef          `json:"winner"` // null until the final is played
+}
+
+// TournamentFormat is how a tournament is drawn and decided
+type TournamentFormat struct {
+	Groups            int64 `json:"groups"`
+	Qualifiers        int64 `json:"qualifiers"` // teams of each group going through
+	AwayGoals         bool  `json:"away_goals"`
+	SeparateDivisions bool  `json:"separate_divisions"`
+}
+
+// TournamentGroup is a group of the tournament with its table, best placed
+// team first
+type TournamentGroup struct {
+	Name    string            `json:"name"`
+	Table   []Standing        `json:"table"`
+	Matches []TournamentMatch `json:"matches"`
+}
+
+// TournamentRound is a knockout round of the tournament
+type TournamentRound struct {
+	Round int64           `json:"round"`
+	Name  string          `json:"name"`
+	Ties  []TournamentTie `json:"ties"`
+}
+
+// TournamentTie is a two-legged knockout tie, the better placed side at home
+// in the second leg
+type TournamentTie struct {
+	FirstLeg  TournamentMatch `json:"first_leg"`
+	SecondLeg TournamentMatch `json:"second_leg"`
+	Winner    *TeamRef        `json:"winner"`
+}
+
+// TournamentMatch is a match of the tournament. Teams of a knockout match
+// are null until they are known.
+type TournamentMatch struct {
+	ID             int64    `json:"id"`
+	Week           int64    `json:"week"`
+	HomeTeam       *TeamRef `json:"home_team"`
+	GuestTeam      *TeamRef `json:"guest_team"`
+	Played         bool     `json:"played"`
+	HomeScore      *int64   `json:"home_score"`
+	GuestScore     *int64   `json:"guest_score"`
+	ExtraTime      bool     `json:"extra_time"`
+	HomePenalties  *int64   `json:"home_penalties"`
+	GuestPenalties *int64   `json:"guest_penalties"`
+}
+
+// Competition is a competition of a season and the rules it is played by
+type Competition struct {
+	SeasonID     int64            `json:"season_id"`
+	Format       string           `json:"format"` // league, cup or tournament
+	Name         string           `json:"name"`
+	Legs         int64            `json:"legs"` // times the teams of a league meet, or legs of a knockout tie
+	Points       Points           `json:"points"`
+	RankingRules string           `json:"ranking_rules"` // tie-breakers between teams level on points
+	Teams        []TeamRef        `json:"teams"`         // empty until fixtures are generated or the draw is made
+	Deductions   []PointDeduction `json:"deductions"`    // also listed among the sanctions
+}
+
+// Points are what a win, a draw and a loss are worth, and the bonus for
+// scoring at least bonus_goals in a match
+type Points struct {
+	Win        int64 `json:"win"`
+	Draw       int64 `json:"draw"`
+	Loss       int64 `json:"loss"`
+	BonusGoals int64 `json:"bonus_goals"` // 0 for no bonus
+	Bonus      int64 `json:"bonus"`
+}
+
+// PointDeduction is points taken from a team of a competition by an admin
+type PointDeduction struct {
+	ID        int64     `json:"id"`
+	Team      TeamRef   `json:"team"`
+	Points    int64     `json:"points"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Sanction is a sanction taken against a team of a competition by an admin:
+// a point deduction, a forfeit losing one league match 3-0 or a ban
+// forfeiting every league match over a range of weeks
+type Sanction struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`   // deduction, forfeit or ban
+	Format    string    `json:"format"` // format of the competition
+	Team      TeamRef   `json:"team"`
+	Points    int64     `json:"points,omitempty"`   // deducted
+	MatchID   *int64    `json:"match_id,omitempty"` // forfeited
+	FirstWeek int64     `json:"first_week,omitempty"`
+	LastWeek  int64     `json:"last_week,omitempty"`
+	Summary   string    `json:"summary"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PlayWeekResult reports the matches played by a play-week action
+type PlayWeekResult struct {
+	Played int    `json:"played"`
+	Season Season `json:"season"`
+}
+
+// TeamRequest is the body of a request creating or updating a team. The
+// budget defaults to the league's default budget when omitted.
+type TeamRequest struct {
+	Name     string `json:"name"`
+	Strength int64  `json:"strength"`
+	Budget   *int64 `json:"budget,omitempty"`
+}
+
+// DrawCupRequest is the body of a request drawing the current season's cup
+type DrawCupRequest struct {
+	Draw string `json:"draw"` // seeded or random
+}
+
+// DrawTournamentRequest is the body of a request drawing the current
+// season's tournament
+type DrawTournamentRequest struct {
+	Groups            int64 `json:"groups"`
+	Qualifiers        int64 `json:"qualifiers"`
+	AwayGoals         bool  `json:"away_goals"`
+	SeparateDivisions bool  `json:"separate_divisions"`
+}
+
+// CompetitionRequest is the body of a request changing the rules of a
+// competition of the current season. Only a league can change its legs, and
+// omitted ranking rules are kept.
+type CompetitionRequest struct {
+	Legs         int64  `json:"legs"`
+	Points       Points `json:"points"`
+	RankingRules string `json:"ranking_rules,omitempty"`
+}
+
+// DeductionRequest is the body of a request deducting points from a team of
+// a competition of the current season. It predates SanctionRequest, which
+// takes the same deduction with kind deduction.
+type DeductionRequest struct {
+	TeamID int64  `json:"team_id"`
+	Points int64  `json:"points"`
+	Reason string `json:"reason"`
+}
+
+// SanctionRequest is the body of a request taking a sanction against a team
+// in the current season. Points apply to a deduction, match_id to a forfeit
+// and the weeks to a ban. Forfeits and bans apply to the league, deductions
+// to the competition of format, the league if omitted.
+type SanctionRequest struct {
+	Kind      string `json:"kind"` // deduction, forfeit or ban
+	Format    string `json:"format,omitempty"`
+	TeamID    int64  `json:"team_id"`
+	Points    int64  `json:"points,omitempty"`
+	MatchID   int64  `json:"match_id,omitempty"`
+	FirstWeek int64  `json:"first_week,omitempty"`
+	LastWeek  int64  `json:"last_week,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// CreateSeasonRequest is the body of a request to start a new season. Both
+// fields are optional: a random seed is drawn and the league keeps the
+// ranking rules of the previous one.
+type CreateSeasonRequest struct {
+	Seed  *int64 `json:"seed,omitempty"`
+	Rules string `json:"rules,omitempty"`
+}
+
+func newSeason(ctx context.Context, repo repository.Repository, season sqlc.Season) Season {
+	dto := Season{
+		ID:         season.ID,
+		Year:       season.Year,
+		Seed:       season.Seed,
+		IsCurrent:  season.IsCurrent.Bool,
+		IsComplete: season.IsComplete.Bool,
+	}
+
+	// Only seasons still being played have a current week
+	if week, err := repo.GetCurrentWeek(ctx, season.ID); err == nil {
+		dto.CurrentWeek = week
+	}
+	return dto
+}
+
+func newTeam(team sqlc.Team) Team {
+	return Team{
+		ID:       team.ID,
+		Name:     team.Name,
+		Strength: team.Strength.Int64,
+		Budget:   team.Budget.Int64,
+	}
+}
+
+func newTeamHistory(entry sqlc.ListTeamHistoryRow) TeamHistory {
+	return TeamHistory{
+		SeasonID:           entry.SeasonID,
+		SeasonYear:         entry.SeasonYear,
+		LastSeasonStanding: entry.LastSeasonStanding,
+		PrizeMoney:         entry.PrizeMoney,
+		StrengthBefore:     entry.StrengthBefore,
+		StrengthAfter:      entry.StrengthAfter,
+		BudgetBefore:       entry.BudgetBefore,
+		BudgetAfter:        entry.BudgetAfter,
+	}
+}
+
+func newRecord(record league.Record) Record {
+	return Record{
+		Played:       record.Played,
+		Wins:         record.Wins,
+		Draws:        record.Draws,
+		Losses:       record.Losses,
+		GoalsFor:     record.GoalsFor,
+		GoalsAgainst: record.GoalsAgainst,
+	}
+}
+
+func newHeadToHead(h2h league.HeadToHead) HeadToHead {
+	names := map[int64]string{h2h.Team.ID: h2h.Team.Name, h2h.Opponent.ID: h2h.Opponent.Name}
+	meeting := func(m sqlc.ListHeadToHeadMatchesRow) Meeting {
+		return Meeting{
+			MatchID:    m.ID,
+			SeasonID:   m.SeasonID,
+			SeasonYear: m.SeasonYear,
+			Week:       m.Week,
+			HomeTeam:   TeamRef{ID: m.HomeID, Name: names[m.HomeID]},
+			GuestTeam:  TeamRef{ID: m.GuestID, Name: names[m.GuestID]},
+			HomeScore:  m.HomeScore,
+			GuestScore: m.GuestScore,
+		}
+	}
+
+	dto := HeadToHead{
+		Team:         TeamRef{ID: h2h.Team.ID, Name: h2h.Team.Name},
+		Opponent:     TeamRef{ID: h2h.Opponent.ID, Name: h2h.Opponent.Name},
+		AllTime:      newRecord(h2h.AllTime),
+		Seasons:      []SeasonRecord{},
+		LastMeetings: []Meeting{},
+	}
+	for _, season := range h2h.Seasons {
+		dto.Seasons = append(dto.Seasons, SeasonRecord{
+			SeasonID:   season.SeasonID,
+			SeasonYear: season.SeasonYear,
+			Record:     newRecord(season.Record),
+		})
+	}
+	if h2h.BiggestWin != nil {
+		win := meeting(*h2h.BiggestWin)
+		dto.BiggestWin = &win
+	}
+	if h2h.OpponentBiggestWin != nil {
+		win := meeting(*h2h.OpponentBiggestWin)
+		dto.OpponentBiggestWin = &win
+	}
+	for _, m := range h2h.LastMeetings {
+		dto.LastMeetings = append(dto.LastMeetings, meeting(m))
+	}
+	return dto
+}
+
+func newTeamSeason(ts league.TeamSeason) TeamSeason {
+	teamMatch := func(m league.TeamMatch) TeamMatch {
+		dto := TeamMatch{
+			MatchID:  m.ID,
+			Week:     m.Week,
+			Opponent: TeamRef{ID: m.OpponentID, Name: m.OpponentName},
+			Home:     m.Home,
+			Played:   m.Played,
+			Outcome:  m.Outcome,
+		}
+		if m.Played {
+			dto.GoalsFor, dto.GoalsAgainst = &m.GoalsFor, &m.GoalsAgainst
+		}
+		return dto
+	}
+
+	dto := TeamSeason{
+		Team:     TeamRef{ID: ts.Team.ID, Name: ts.Team.Name},
+		SeasonID: ts.Season.ID,
+		Form:     append([]string{}, ts.Form...),
+		Home:     newRecord(ts.Home),
+		Away:     newRecord(ts.Away),
+		Matches:  []TeamMatch{},
+		Upcoming: []TeamMatch{},
+		Weeks:    []TeamWeek{},
+	}
+	if ts.Division.ID != 0 {
+		dto.Division = DivisionRef{ID: ts.Division.ID, Name: ts.Division.Name, Tier: ts.Division.Tier}
+	}
+	for _, m := range ts.Matches {
+		dto.Matches = append(dto.Matches, teamMatch(m))
+	}
+	for _, m := range ts.Upcoming {
+		dto.Upcoming = append(dto.Upcoming, teamMatch(m))
+	}
+	for _, week := range ts.Weeks {
+		dto.Weeks = append(dto.Weeks, TeamWeek{Week: week.Week, Points: week.Points, Position: week.Position})
+	}
+	return dto
+}
+
+func newMatch(match sqlc.GetMatchesBySeasonRow) Match {
+	dto := Match{
+		ID:        match.ID,
+		SeasonID:  match.SeasonID,
+		Week:      match.Week,
+		HomeTeam:  TeamRef{ID: match.HomeID, Name: match.HomeTeamName},
+		GuestTeam: TeamRef{ID: match.GuestID, Name: match.GuestTeamName},
+		Played:    match.Played.Bool,
+	}
+	if match.HomeScore.Valid && match.GuestScore.Valid {
+		dto.HomeScore = &match.HomeScore.Int64
+		dto.GuestScore = &match.GuestScore.Int64
+	}
+	return dto
+}
+
+func newStanding(position int, entry standings.Entry) Standing {
+	return Standing{
+		Position:     position,
+		Team:         TeamRef{ID: entry.Team.ID, Name: entry.Team.Name},
+		Played:       entry.Played,
+		Wins:         entry.Wins,
+		Draws:        entry.Draws,
+		Losses:       entry.Losses,
+		GoalsFor:     entry.GoalsFor,
+		GoalsAgainst: entry.GoalsAgainst,
+		GoalDiff:     entry.GoalDiff(),
+		Points:       entry.Points,
+		Deducted:     entry.Deducted,
+	}
+}
+
+func newDivision(division sqlc.Division) Division {
+	return Division{
+		ID:               division.ID,
+		SeasonID:         division.SeasonID,
+		Name:             division.Name,
+		Tier:             division.Tier,
+		RelegationPlaces: division.RelegationPlaces,
+		Playoff:          division.Playoff,
+		Teams:            []TeamRef{},
+	}
+}
+
+func newPrediction(p prediction.TeamProbability) Prediction {
+	return Prediction{
+		Team:  TeamRef{ID: p.TeamID, Name: p.TeamName},
+		Title: p.Title,
+		TopN:  p.TopN,
+		Last:  p.Last,
+	}
+}
+
+func newCup(cup league.Cup) Cup {
+	dto := Cup{
+		SeasonID: cup.Cup.SeasonID,
+		Draw:     cup.Draw(),
+		Rounds:   make([]CupRound, 0, len(cup.Rounds)),
+	}
+	if cup.WinnerID != 0 {
+		dto.Winner = &TeamRef{ID: cup.WinnerID, Name: cup.WinnerName}
+	}
+
+	for _, round := range cup.Rounds {
+		item := CupRound{Round: round.Round, Name: round.Name, Week: round.Week, Ties: make([]CupTie, 0, len(round.Ties))}
+		for _, tie := range round.Ties {
+			item.Ties = append(item.Ties, newCupTie(tie))
+		}
+		dto.Rounds = append(dto.Rounds, item)
+	}
+	return dto
+}
+
+func newCupTie(tie sqlc.ListCupTiesRow) CupTie {
+	dto := CupTie{
+		ID:             tie.ID,
+		HomeTeam:       teamRef(tie.HomeID, tie.HomeTeamName),
+		GuestTeam:      teamRef(tie.GuestID, tie.GuestTeamName),
+		Bye:            league.Bye(tie),
+		Played:         tie.Played,
+		HomeScore:      nullable(tie.HomeScore),
+		GuestScore:     nullable(tie.GuestScore),
+		ExtraTime:      tie.ExtraTime,
+		HomePenalties:  nullable(tie.HomePenalties),
+		GuestPenalties: nullable(tie.GuestPenalties),
+	}
+	switch {
+	case !tie.WinnerID.Valid:
+	case tie.WinnerID == tie.HomeID:
+		dto.Winner = dto.HomeTeam
+	default:
+		dto.Winner = dto.GuestTeam
+	}
+	return dto
+}
+
+func newTournament(tournament league.Tournament) Tournament {
+	dto := Tournament{
+		SeasonID: tournament.Tournament.SeasonID,
+		Format: TournamentFormat{
+			Groups:            tournament.Tournament.GroupCount,
+			Qualifiers:        tournament.Tournament.Qualifiers,
+			AwayGoals:         tournament.Tournament.AwayGoals,
+			SeparateDivisions: tournament.Tournament.SeparateDivisions,
+		},
+		Groups:   make([]TournamentGroup, 0, len(tournament.Groups)),
+		Knockout: make([]TournamentRound, 0, len(tournament.Rounds)),
+	}
+	if tournament.WinnerID != 0 {
+		dto.Winner = &TeamRef{ID: tournament.WinnerID, Name: tournament.WinnerName}
+	}
+
+	for _, group := range tournament.Groups {
+		item := TournamentGroup{
+			Name:    group.Name,
+			Table:   make([]Standing, 0, len(group.Table)),
+			Matches: make([]TournamentMatch, 0, len(group.Matches)),
+		}
+		for i, entry := range group.Table {
+			item.Table = append(item.Table, newStanding(i+1, entry))
+		}
+		for _, match := range group.Matches {
+			item.Matches = append(item.Matches, newTournamentMatch(match))
+		}
+		dto.Groups = append(dto.Groups, item)
+	}
+	for _, round := range tournament.Rounds {
+		item := TournamentRound{Round: round.Round, Name: round.Name, Ties: make([]TournamentTie, 0, len(round.Ties))}
+		for _, tie := range round.Ties {
+			tieDTO := TournamentTie{FirstLeg: newTournamentMatch(tie.First), SecondLeg: newTournamentMatch(tie.Second)}
+			if tie.Second.WinnerID.Valid {
+				tieDTO.Winner = tieDTO.SecondLeg.GuestTeam
+				if tie.Second.WinnerID == tie.Second.HomeID {
+					tieDTO.Winner = tieDTO.SecondLeg.HomeTeam
+				}
+			}
+			item.Ties = append(item.Ties, tieDTO)
+		}
+		dto.Knockout = append(dto.Knockout, item)
+	}
+	return dto
+}
+
+func newTournamentMatch(match sqlc.ListTournamentMatchesRow) TournamentMatch {
+	return TournamentMatch{
+		ID:             match.ID,
+		Week:           match.Week,
+		HomeTeam:       teamRef(match.HomeID, match.HomeTeamName),
+		GuestTeam:      teamRef(match.GuestID, match.GuestTeamName),
+		Played:         match.Played,
+		HomeScore:      nullable(match.HomeScore),
+		GuestScore:     nullable(match.GuestScore),
+		ExtraTime:      match.ExtraTime,
+		HomePenalties:  nullable(match.HomePenalties),
+		GuestPenalties: nullable(match.GuestPenalties),
+	}
+}
+
+func newCompetition(competition league.Competition) Competition {
+	dto := Competition{
+		SeasonID: competition.Competition.SeasonID,
+		Format:   competition.Competition.Format,
+		Name:     competition.Competition.Name,
+		Legs:     competition.Competition.Legs,
+		Points: Points{
+			Win:        competition.Competition.WinPoints,
+			Draw:       competition.Competition.DrawPoints,
+			Loss:       competition.Competition.LossPoints,
+			BonusGoals: competition.Competition.BonusGoals,
+			Bonus:      competition.Competition.BonusPoints,
+		},
+		RankingRules: competition.Competition.RankingRules,
+		Teams:        make([]TeamRef, 0, len(competition.Teams)),
+		Deductions:   make([]PointDeduction, 0, len(competition.Deductions)),
+	}
+	for _, team := range competition.Teams {
+		dto.Teams = append(dto.Teams, TeamRef{ID: team.TeamID, Name: team.TeamName})
+	}
+	for _, deduction := range competition.Deductions {
+		dto.Deductions = append(dto.Deductions, PointDeduction{
+			ID:        deduction.ID,
+			Team:      TeamRef{ID: deduction.TeamID, Name: deduction.TeamName},
+			Points:    deduction.Points,
+			Reason:    deduction.Reason,
+			CreatedAt: deduction.CreatedAt,
+		})
+	}
+	return dto
+}
+
+func newSanction(sanction sqlc.ListSanctionsRow) Sanction {
+	return Sanction{
+		ID:        sanction.ID,
+		Kind:      sanction.Kind,
+		Format:    sanction.Format,
+		Team:      TeamRef{ID: sanction.TeamID, Name: sanction.TeamName},
+		Points:    sanction.Points,
+		MatchID:   nullable(sanction.MatchID),
+		FirstWeek: sanction.FirstWeek,
+		LastWeek:  sanction.LastWeek,
+		Summary:   league.SanctionSummary(sanction),
+		Reason:    sanction.Reason,
+		CreatedAt: sanction.CreatedAt,
+	}
+}
+
+// teamRef returns the team with the given ID, or nil if it is not known yet
+func teamRef(id sql.NullInt64, name sql.NullString) *TeamRef {
+	if !id.Valid {
+		return nil
+	}
+	return &TeamRef{ID: id.Int64, Name: name.String}
+}
+
+// nullable returns a pointer to a nullable value, nil if it is null
+func nullable(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int64
+}
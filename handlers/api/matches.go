@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/repository"
+)
+
+// handleListFixtures lists every match of a season, optionally filtered by
+// the week query parameter
+func handleListFixtures(repo repository.Repository) gin.HandlerFunc {
+	return listMatches(repo, false)
+}
+
+// handleListResults lists the played matches of a season with their scores
+func handleListResults(repo repository.Repository) gin.HandlerFunc {
+	return listMatches(repo, true)
+}
+
+func listMatches(repo repository.Repository, playedOnly bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		season, ok := querySeason(c, repo)
+		if !ok {
+			return
+		}
+
+		var week int64
+		if value := c.Query("week"); value != "" {
+			var err error
+			week, err = strconv.ParseInt(value, 10, 64)
+			if err != nil || week < 1 {
+				respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid week")
+				return
+			}
+		}
+
+		matches, err := repo.GetMatchesBySeason(c.Request.Context(), season.ID)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		data := make([]Match, 0, len(matches))
+		for _, match := range matches {
+			if week != 0 && match.Week != week {
+				continue
+			}
+			if playedOnly && !match.Played.Bool {
+				continue
+			}
+			data = append(data, newMatch(match))
+		}
+		respond(c, http.StatusOK, data)
+	}
+}
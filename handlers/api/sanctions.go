@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+)
+
+func handleListSanctions(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		season, ok := querySeason(c, repo)
+		if !ok {
+			return
+		}
+
+		sanctions, err := league.ListSanctions(c.Request.Context(), repo, season.ID)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		dto := make([]Sanction, 0, len(sanctions))
+		for _, sanction := range sanctions {
+			dto = append(dto, newSanction(sanction))
+		}
+		respond(c, http.StatusOK, dto)
+	}
+}
+
+// handleAddSanction takes a sanction against a team in the current season,
+// replacing the results of the played matches it forfeits
+func handleAddSanction(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req SanctionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid request body")
+			return
+		}
+
+		sanction, err := league.AddSanction(c.Request.Context(), repo, sim, league.SanctionInput{
+			Kind:      req.Kind,
+			Format:    req.Format,
+			TeamID:    req.TeamID,
+			Points:    req.Points,
+			MatchID:   req.MatchID,
+			FirstWeek: req.FirstWeek,
+			LastWeek:  req.LastWeek,
+			Reason:    req.Reason,
+		})
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusCreated, newSanction(sanction))
+	}
+}
+
+// handleDeleteSanction lifts a sanction of the current season, playing
+// again the matches it forfeited
+func handleDeleteSanction(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := league.DeleteSanction(c.Request.Context(), repo, sim, id); err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// handleAddDeduction deducts points from a team of a competition of the
+// current season, responding with the competition and its deductions. It
+// predates handleAddSanction and is kept for the clients of v1
+func handleAddDeduction(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DeductionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid request body")
+			return
+		}
+
+		competition, err := league.AddPointDeduction(c.Request.Context(), repo, sim, c.Param("format"), league.SanctionInput{
+			TeamID: req.TeamID,
+			Points: req.Points,
+			Reason: req.Reason,
+		})
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusCreated, newCompetition(competition))
+	}
+}
+
+// handleDeleteDeduction gives back the points of a deduction of the current
+// season, other sanctions being lifted by handleDeleteSanction
+func handleDeleteDeduction(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := league.DeletePointDeduction(c.Request.Context(), repo, sim, id); err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
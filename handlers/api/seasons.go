@@ -0,0 +1,98 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+)
+
+func handleListSeasons(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		seasons, err := repo.ListSeasons(reqCtx)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		data := make([]Season, 0, len(seasons))
+		for _, season := range seasons {
+			data = append(data, newSeason(reqCtx, repo, season))
+		}
+		respond(c, http.StatusOK, data)
+	}
+}
+
+func handleGetCurrentSeason(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		season, err := repo.GetCurrentSeason(reqCtx)
+		if err == sql.ErrNoRows {
+			err = league.ErrNoActiveSeason
+		}
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusOK, newSeason(reqCtx, repo, season))
+	}
+}
+
+func handleGetSeason(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		id, ok := pathID(c, "id")
+		if !ok {
+			return
+		}
+
+		season, err := repo.GetSeason(reqCtx, id)
+		if err == sql.ErrNoRows {
+			respondError(c, http.StatusNotFound, CodeNotFound, "Season not found")
+			return
+		}
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusOK, newSeason(reqCtx, repo, season))
+	}
+}
+
+// handleCreateSeason starts the season following the current one
+func handleCreateSeason(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		// The body is optional
+		var req CreateSeasonRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid request body")
+			return
+		}
+
+		seed := league.NewSeed()
+		if req.Seed != nil {
+			seed = *req.Seed
+		}
+
+		season, err := league.StartNewSeason(reqCtx, repo, sim, seed, req.Rules)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusCreated, newSeason(reqCtx, repo, season))
+	}
+}
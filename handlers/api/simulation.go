@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+)
+
+// The simulation actions act on the current season and respond with its
+// updated state.
+
+func handleGenerateFixtures(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		season, err := league.EnsureFixtures(reqCtx, repo)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusOK, newSeason(reqCtx, repo, season))
+	}
+}
+
+func handlePlayWeek(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		played, err := league.PlayWeek(reqCtx, repo, sim)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		season, err := repo.GetCurrentSeason(reqCtx)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusOK, PlayWeekResult{
+			Played: played,
+			Season: newSeason(reqCtx, repo, season),
+		})
+	}
+}
+
+func handleNextWeek(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		if err := league.NextWeek(reqCtx, repo); err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respondCurrentSeason(c, repo)
+	}
+}
+
+func handlePlayAll(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := league.PlayAll(c.Request.Context(), repo, sim); err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respondCurrentSeason(c, repo)
+	}
+}
+
+func respondCurrentSeason(c *gin.Context, repo repository.Repository) {
+	reqCtx := c.Request.Context()
+
+	season, err := repo.GetCurrentSeason(reqCtx)
+	if err != nil {
+		respondLeagueError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, newSeason(reqCtx, repo, season))
+}
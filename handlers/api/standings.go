@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/prediction"
+	"github.com/orhosko/go-backend/repository"
+)
+
+func handleGetStandings(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		season, ok := querySeason(c, repo)
+		if !ok {
+			return
+		}
+
+		tables, err := league.Tables(c.Request.Context(), repo, season)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+		sanctions, err := league.ListSanctions(c.Request.Context(), repo, season.ID)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+		notes := league.SanctionNotes(sanctions, league.FormatLeague)
+
+		// Divisions are listed from the top tier down
+		data := []Standing{}
+		for _, table := range tables {
+			for i, entry := range table.Entries {
+				standing := newStanding(i+1, entry)
+				standing.Notes = notes[entry.Team.ID]
+				if table.Division.ID != 0 {
+					standing.Division = DivisionRef{ID: table.Division.ID, Name: table.Division.Name, Tier: table.Division.Tier}
+				}
+				data = append(data, standing)
+			}
+		}
+		respond(c, http.StatusOK, data)
+	}
+}
+
+func handleListDivisions(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		season, ok := querySeason(c, repo)
+		if !ok {
+			return
+		}
+		reqCtx := c.Request.Context()
+
+		divisions, err := repo.ListDivisions(reqCtx, season.ID)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+		members, err := repo.ListDivisionTeams(reqCtx, season.ID)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+		teams, err := repo.ListTeams(reqCtx)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		names := make(map[int64]string, len(teams))
+		for _, team := range teams {
+			names[team.ID] = team.Name
+		}
+
+		data := make([]Division, 0, len(divisions))
+		for _, division := range divisions {
+			item := newDivision(division)
+			for _, member := range members {
+				if member.DivisionID == division.ID {
+					item.Teams = append(item.Teams, TeamRef{ID: member.TeamID, Name: names[member.TeamID]})
+				}
+			}
+			data = append(data, item)
+		}
+		respond(c, http.StatusOK, data)
+	}
+}
+
+func handleGetPredictions(repo repository.Repository, predictor *prediction.MonteCarloPredictor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		season, ok := querySeason(c, repo)
+		if !ok {
+			return
+		}
+
+		probabilities, err := league.Predictions(c.Request.Context(), repo, predictor, season)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		data := Predictions{
+			SeasonID: season.ID,
+			TopN:     predictor.TopN,
+			Teams:    make([]Prediction, 0, len(probabilities)),
+		}
+		for _, p := range probabilities {
+			data.Teams = append(data.Teams, newPrediction(p))
+		}
+		respond(c, http.StatusOK, data)
+	}
+}
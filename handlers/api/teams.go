@@ -0,0 +1,198 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+)
+
+func handleListTeams(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teams, err := repo.ListTeams(c.Request.Context())
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		data := make([]Team, 0, len(teams))
+		for _, team := range teams {
+			data = append(data, newTeam(team))
+		}
+		respond(c, http.StatusOK, data)
+	}
+}
+
+func handleGetTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "id")
+		if !ok {
+			return
+		}
+
+		team, err := repo.GetTeam(c.Request.Context(), id)
+		if err == sql.ErrNoRows {
+			respondError(c, http.StatusNotFound, CodeNotFound, "Team not found")
+			return
+		}
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusOK, newTeam(team))
+	}
+}
+
+// handleGetTeamHistory lists how a team changed after each season, oldest
+// season first
+func handleGetTeamHistory(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "id")
+		if !ok {
+			return
+		}
+		reqCtx := c.Request.Context()
+
+		if _, err := repo.GetTeam(reqCtx, id); err == sql.ErrNoRows {
+			respondError(c, http.StatusNotFound, CodeNotFound, "Team not found")
+			return
+		} else if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		history, err := repo.ListTeamHistory(reqCtx)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		data := []TeamHistory{}
+		for _, entry := range history {
+			if entry.TeamID == id {
+				data = append(data, newTeamHistory(entry))
+			}
+		}
+		respond(c, http.StatusOK, data)
+	}
+}
+
+// handleGetHeadToHead compares a team with an opponent over every season
+func handleGetHeadToHead(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "id")
+		if !ok {
+			return
+		}
+		opponentID, ok := pathID(c, "opponent_id")
+		if !ok {
+			return
+		}
+
+		h2h, err := league.CompareTeams(c.Request.Context(), repo, id, opponentID)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+		respond(c, http.StatusOK, newHeadToHead(h2h))
+	}
+}
+
+// handleGetTeamSeason follows a team through the season selected by the
+// season_id query parameter, the current one by default
+func handleGetTeamSeason(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "id")
+		if !ok {
+			return
+		}
+		season, ok := querySeason(c, repo)
+		if !ok {
+			return
+		}
+
+		ts, err := league.GetTeamSeason(c.Request.Context(), repo, id, season)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+		respond(c, http.StatusOK, newTeamSeason(ts))
+	}
+}
+
+func handleCreateTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		in, ok := bindTeam(c)
+		if !ok {
+			return
+		}
+
+		team, err := league.CreateTeam(c.Request.Context(), repo, in)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusCreated, newTeam(team))
+	}
+}
+
+func handleUpdateTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "id")
+		if !ok {
+			return
+		}
+
+		in, ok := bindTeam(c)
+		if !ok {
+			return
+		}
+
+		team, err := league.UpdateTeam(c.Request.Context(), repo, id, in)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusOK, newTeam(team))
+	}
+}
+
+func handleDeleteTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := pathID(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := league.DeleteTeam(c.Request.Context(), repo, id); err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// bindTeam parses the body of a team request
+func bindTeam(c *gin.Context) (league.TeamInput, bool) {
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid request body")
+		return league.TeamInput{}, false
+	}
+
+	in := league.TeamInput{
+		Name:     req.Name,
+		Strength: req.Strength,
+		Budget:   league.DefaultBudget,
+	}
+	if req.Budget != nil {
+		in.Budget = *req.Budget
+	}
+	return in, true
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/repository/repotest"
+)
+
+// newTestRouter serves the API from a seeded in-memory repository.
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	repo := repository.NewMemoryRepository()
+	if err := repotest.Seed(context.Background(), repo); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router, repo, nil, nil)
+	return router
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTeamsLifecycle(t *testing.T) {
+	router := newTestRouter(t)
+
+	w := serve(router, http.MethodPost, "/api/v1/teams", `{"name": "Brighton", "strength": 5}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /teams = %d %s, want 201", w.Code, w.Body)
+	}
+	var created struct{ Data Team }
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode created team: %v", err)
+	}
+
+	w = serve(router, http.MethodGet, "/api/v1/teams", "")
+	var listed struct{ Data []Team }
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("decode teams: %v", err)
+	}
+	if len(listed.Data) != 5 {
+		t.Errorf("GET /teams returned %d teams, want 5", len(listed.Data))
+	}
+
+	w = serve(router, http.MethodPost, "/api/v1/teams", `{"name": "brighton", "strength": 5}`)
+	assertError(t, w, http.StatusConflict, CodeTeamNameTaken)
+
+	w = serve(router, http.MethodDelete, "/api/v1/teams/"+strconv.FormatInt(created.Data.ID, 10), "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE /teams/:id = %d %s, want 204", w.Code, w.Body)
+	}
+
+	w = serve(router, http.MethodGet, "/api/v1/teams/"+strconv.FormatInt(created.Data.ID, 10), "")
+	assertError(t, w, http.StatusNotFound, CodeNotFound)
+}
+
+func assertError(t *testing.T, w *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d %s, want %d", w.Code, w.Body, status)
+	}
+	var envelope ErrorEnvelope
+	if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("decode error envelope: %v", err)
+	}
+	if envelope.Error.Code != code {
+		t.Errorf("error code = %q, want %q", envelope.Error.Code, code)
+	}
+}
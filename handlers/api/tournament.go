@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+)
+
+func handleGetTournament(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		season, ok := querySeason(c, repo)
+		if !ok {
+			return
+		}
+
+		tournament, err := league.GetTournament(c.Request.Context(), repo, season)
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusOK, newTournament(tournament))
+	}
+}
+
+// handleDrawTournament draws the tournament of the current season, replacing
+// any earlier draw
+func handleDrawTournament(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DrawTournamentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, CodeBadRequest, "Invalid request body")
+			return
+		}
+
+		tournament, err := league.DrawTournament(c.Request.Context(), repo, league.TournamentInput{
+			Groups:            req.Groups,
+			Qualifiers:        req.Qualifiers,
+			AwayGoals:         req.AwayGoals,
+			SeparateDivisions: req.SeparateDivisions,
+		})
+		if errors.Is(err, league.ErrNotEnoughTeams) {
+			respondError(c, http.StatusConflict, CodeTournamentNotDrawn, "The tournament needs at least 2 teams")
+			return
+		}
+		if err != nil {
+			respondLeagueError(c, err)
+			return
+		}
+
+		respond(c, http.StatusCreated, newTournament(tournament))
+	}
+}
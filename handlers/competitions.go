@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/templates"
+)
+
+// RegisterCompetitionRoutes registers all competition related routes
+func RegisterCompetitionRoutes(router *gin.Engine, repo repository.Repository) {
+	router.GET("/competitions", handleCompetitions(repo))
+	router.POST("/competitions/:format", handleUpdateCompetition(repo))
+}
+
+func handleCompetitions(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		season, readOnly, ok := pageSeason(c, repo)
+		if !ok {
+			return
+		}
+
+		competitions, err := league.ListCompetitions(reqCtx, repo, season)
+		if err != nil {
+			log.Printf("Failed to fetch competitions: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch competitions"})
+			return
+		}
+		results, err := repo.GetResultsBySeason(reqCtx, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch results"})
+			return
+		}
+
+		data := templates.CompetitionsPageData{Season: season, ReadOnly: readOnly, Locked: len(results) > 0, RankingOptions: rankingOptions()}
+		for _, competition := range competitions {
+			data.Competitions = append(data.Competitions, templates.CompetitionDisplay{
+				Competition: competition.Competition,
+				Teams:       competition.Teams,
+			})
+		}
+
+		c.Status(http.StatusOK)
+		templates.Competitions(data).Render(reqCtx, c.Writer)
+	}
+}
+
+func handleUpdateCompetition(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		in, ok := parseCompetitionForm(c)
+		if !ok {
+			return
+		}
+
+		_, err := league.UpdateCompetition(c.Request.Context(), repo, c.Param("format"), in)
+		var validationErr *league.ValidationError
+		switch {
+		case err == nil:
+			c.Redirect(http.StatusSeeOther, "/competitions")
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+		case errors.Is(err, league.ErrCompetitionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Competition not found"})
+		case errors.Is(err, league.ErrNoActiveSeason):
+			c.JSON(http.StatusConflict, gin.H{"error": "No active season"})
+		case errors.Is(err, league.ErrCompetitionLocked):
+			c.JSON(http.StatusConflict, gin.H{"error": "Competition rules can only change before the season's first match"})
+		default:
+			log.Printf("Failed to update competition: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update competition"})
+		}
+	}
+}
+
+// parseCompetitionForm parses the submitted competition rules
+func parseCompetitionForm(c *gin.Context) (league.CompetitionInput, bool) {
+	legs, legsErr := strconv.ParseInt(c.PostForm("legs"), 10, 64)
+	win, winErr := strconv.ParseInt(c.PostForm("win_points"), 10, 64)
+	draw, drawErr := strconv.ParseInt(c.PostForm("draw_points"), 10, 64)
+	loss, lossErr := strconv.ParseInt(c.PostForm("loss_points"), 10, 64)
+	bonusGoals, bonusGoalsErr := strconv.ParseInt(c.PostForm("bonus_goals"), 10, 64)
+	bonus, bonusErr := strconv.ParseInt(c.PostForm("bonus_points"), 10, 64)
+	if legsErr != nil || winErr != nil || drawErr != nil || lossErr != nil || bonusGoalsErr != nil || bonusErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Legs and points must be whole numbers"})
+		return league.CompetitionInput{}, false
+	}
+
+	return league.CompetitionInput{
+		Legs:         legs,
+		WinPoints:    win,
+		DrawPoints:   draw,
+		LossPoints:   loss,
+		BonusGoals:   bonusGoals,
+		BonusPoints:  bonus,
+		RankingRules: c.PostForm("ranking_rules"),
+	}, true
+}
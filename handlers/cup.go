@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/templates"
+)
+
+// RegisterCupRoutes registers all cup related routes
+func RegisterCupRoutes(router *gin.Engine, repo repository.Repository) {
+	router.GET("/cup", handleCup(repo))
+	router.POST("/cup", handleDrawCup(repo))
+}
+
+func handleCup(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		season, readOnly, ok := pageSeason(c, repo)
+		if !ok {
+			return
+		}
+
+		cup, err := league.GetCup(reqCtx, repo, season)
+		if err != nil && !errors.Is(err, league.ErrNoCup) {
+			log.Printf("Failed to fetch cup: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cup"})
+			return
+		}
+		hasCup := err == nil
+
+		results, err := repo.GetResultsBySeason(reqCtx, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch results"})
+			return
+		}
+
+		data := templates.CupPageData{
+			Season:   season,
+			ReadOnly: readOnly,
+			Locked:   len(results) > 0,
+			Winner:   cup.WinnerName,
+		}
+		if hasCup {
+			data.Draw = cup.Draw()
+		}
+		for _, round := range cup.Rounds {
+			data.Rounds = append(data.Rounds, cupRound(round))
+		}
+
+		c.Status(http.StatusOK)
+		templates.CupPage(data).Render(reqCtx, c.Writer)
+	}
+}
+
+func handleDrawCup(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, err := league.DrawCup(c.Request.Context(), repo, c.PostForm("draw"))
+		switch {
+		case err == nil:
+			c.Redirect(http.StatusSeeOther, "/cup")
+		case errors.Is(err, league.ErrUnknownDraw):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown cup draw"})
+		case errors.Is(err, league.ErrNoActiveSeason):
+			c.JSON(http.StatusConflict, gin.H{"error": "No active season"})
+		case errors.Is(err, league.ErrCupLocked):
+			c.JSON(http.StatusConflict, gin.H{"error": "The cup can only be drawn before the season's first match"})
+		case errors.Is(err, league.ErrNotEnoughTeams):
+			c.JSON(http.StatusConflict, gin.H{"error": "The cup needs at least 2 teams"})
+		case errors.Is(err, league.ErrCupTooLong):
+			c.JSON(http.StatusConflict, gin.H{"error": "The league has fewer weeks than the cup has rounds"})
+		default:
+			log.Printf("Failed to draw cup: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to draw cup"})
+		}
+	}
+}
+
+// weekCupRound returns the cup round played in a week of a season, or nil if
+// there is none.
+func weekCupRound(ctx context.Context, repo repository.Repository, season sqlc.Season, week int) (*templates.CupRoundDisplay, error) {
+	cup, err := league.GetCup(ctx, repo, season)
+	if errors.Is(err, league.ErrNoCup) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, round := range cup.Rounds {
+		if round.Week == int64(week) {
+			display := cupRound(round)
+			return &display, nil
+		}
+	}
+	return nil, nil
+}
+
+// cupRound converts a round of the cup bracket for the templates
+func cupRound(round league.CupRound) templates.CupRoundDisplay {
+	display := templates.CupRoundDisplay{Name: round.Name, Week: round.Week}
+	for _, tie := range round.Ties {
+		display.Ties = append(display.Ties, cupTie(tie))
+	}
+	return display
+}
+
+func cupTie(tie sqlc.ListCupTiesRow) templates.CupTieDisplay {
+	display := templates.CupTieDisplay{
+		HomeTeamName:  tie.HomeTeamName.String,
+		GuestTeamName: tie.GuestTeamName.String,
+		Bye:           league.Bye(tie),
+		Played:        tie.Played,
+		HomeScore:     tie.HomeScore.Int64,
+		GuestScore:    tie.GuestScore.Int64,
+	}
+	if tie.Played {
+		display.HomeWon = tie.WinnerID == tie.HomeID
+		display.GuestWon = tie.WinnerID == tie.GuestID
+	}
+	switch {
+	case tie.HomePenalties.Valid:
+		display.Decided = fmt.Sprintf("a.e.t., %d-%d on penalties", tie.HomePenalties.Int64, tie.GuestPenalties.Int64)
+	case tie.ExtraTime:
+		display.Decided = "a.e.t."
+	}
+	return display
+}
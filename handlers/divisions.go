@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/templates"
+)
+
+// RegisterDivisionRoutes registers all division related routes
+func RegisterDivisionRoutes(router *gin.Engine, repo repository.Repository) {
+	router.GET("/divisions", handleDivisions(repo))
+	router.POST("/divisions", handleCreateDivision(repo))
+	router.POST("/divisions/:id", handleUpdateDivision(repo))
+	router.POST("/divisions/:id/delete", handleDeleteDivision(repo))
+	router.POST("/divisions/:id/teams", handleMoveTeam(repo))
+}
+
+func handleDivisions(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		season, err := league.CurrentSeason(reqCtx, repo)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
+			return
+		}
+
+		// Generating the fixtures puts every team in a division
+		if _, err := league.EnsureFixtures(reqCtx, repo); err != nil && !errors.Is(err, league.ErrNotEnoughTeams) {
+			log.Printf("Failed to generate fixtures: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate fixtures"})
+			return
+		}
+
+		divisions, err := repo.ListDivisions(reqCtx, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch divisions"})
+			return
+		}
+		members, err := repo.ListDivisionTeams(reqCtx, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch division teams"})
+			return
+		}
+		teams, err := repo.ListTeams(reqCtx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams"})
+			return
+		}
+		results, err := repo.GetResultsBySeason(reqCtx, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch results"})
+			return
+		}
+
+		teamsByID := make(map[int64]sqlc.Team, len(teams))
+		for _, team := range teams {
+			teamsByID[team.ID] = team
+		}
+
+		data := templates.DivisionsPageData{Season: season, Locked: len(results) > 0}
+		for _, division := range divisions {
+			entry := templates.DivisionTeams{Division: division}
+			for _, member := range members {
+				if member.DivisionID == division.ID {
+					entry.Teams = append(entry.Teams, teamsByID[member.TeamID])
+				}
+			}
+			data.Divisions = append(data.Divisions, entry)
+		}
+
+		c.Status(http.StatusOK)
+		templates.Divisions(data).Render(reqCtx, c.Writer)
+	}
+}
+
+func handleCreateDivision(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		in, ok := parseDivisionForm(c)
+		if !ok {
+			return
+		}
+
+		_, err := league.CreateDivision(c.Request.Context(), repo, in)
+		if !divisionError(c, err) {
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/divisions")
+	}
+}
+
+func handleUpdateDivision(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		divisionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid division ID"})
+			return
+		}
+
+		in, ok := parseDivisionForm(c)
+		if !ok {
+			return
+		}
+
+		_, err = league.UpdateDivision(c.Request.Context(), repo, divisionID, in)
+		if !divisionError(c, err) {
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/divisions")
+	}
+}
+
+func handleDeleteDivision(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		divisionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid division ID"})
+			return
+		}
+
+		err = league.DeleteDivision(c.Request.Context(), repo, divisionID)
+		if !divisionError(c, err) {
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/divisions")
+	}
+}
+
+func handleMoveTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		divisionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid division ID"})
+			return
+		}
+
+		teamID, err := strconv.ParseInt(c.PostForm("team_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+
+		err = league.MoveTeam(c.Request.Context(), repo, divisionID, teamID)
+		if !divisionError(c, err) {
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/divisions")
+	}
+}
+
+// parseDivisionForm parses the submitted division
+func parseDivisionForm(c *gin.Context) (league.DivisionInput, bool) {
+	places, err := strconv.ParseInt(c.PostForm("relegation_places"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Relegation places must be a whole number"})
+		return league.DivisionInput{}, false
+	}
+
+	return league.DivisionInput{
+		Name:             c.PostForm("name"),
+		RelegationPlaces: places,
+		Playoff:          c.PostForm("playoff") == "true",
+	}, true
+}
+
+// divisionError responds to an error changing the divisions and reports
+// whether there was none.
+func divisionError(c *gin.Context, err error) bool {
+	var validationErr *league.ValidationError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+	case errors.Is(err, league.ErrDivisionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Division not found"})
+	case errors.Is(err, league.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+	case errors.Is(err, league.ErrNoActiveSeason):
+		c.JSON(http.StatusConflict, gin.H{"error": "No active season"})
+	case errors.Is(err, league.ErrDivisionsLocked):
+		c.JSON(http.StatusConflict, gin.H{"error": "Divisions can only change before the season's first match"})
+	case errors.Is(err, league.ErrDivisionNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": "Move the division's teams out before deleting it"})
+	case errors.Is(err, league.ErrLastDivision):
+		c.JSON(http.StatusConflict, gin.H{"error": "A season needs at least one division"})
+	default:
+		log.Printf("Failed to change divisions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to change divisions"})
+	}
+	return false
+}
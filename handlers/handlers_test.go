@@ -0,0 +1,1641 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	database "github.com/orhosko/go-backend/db"
+	"github.com/orhosko/go-backend/handlers/api"
+	"github.com/orhosko/go-backend/prediction"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testSeed replaces the random seed of the seeded season so every run plays
+// the same matches.
+const testSeed = 42
+
+// testServer is the full router backed by a migrated SQLite database in a
+// temporary directory.
+type testServer struct {
+	t      *testing.T
+	router *gin.Engine
+	repo   repository.Repository
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+
+	dbConn, err := database.NewDB(filepath.Join(t.TempDir(), "league.db"))
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { dbConn.Close() })
+
+	ctx := context.Background()
+	if _, err := dbConn.MigrateUp(ctx); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if _, err := dbConn.Conn.ExecContext(ctx, "UPDATE season SET seed = ?", testSeed); err != nil {
+		t.Fatalf("pin season seed: %v", err)
+	}
+
+	repo := repository.NewSQLCRepository(dbConn.Queries, dbConn.Conn)
+	sim := simulation.NewPoissonSimulator()
+	predictor := prediction.NewMonteCarloPredictor(sim, 200, 2, 2)
+
+	return &testServer{t: t, router: NewRouter(repo, sim, predictor), repo: repo}
+}
+
+func (s *testServer) do(req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// get requests a page and fails the test unless it is served.
+func (s *testServer) get(path string) string {
+	s.t.Helper()
+	w := s.do(httptest.NewRequest(http.MethodGet, path, nil))
+	if w.Code != http.StatusOK {
+		s.t.Fatalf("GET %s = %d %s", path, w.Code, w.Body)
+	}
+	return w.Body.String()
+}
+
+// post submits a form and returns the response.
+func (s *testServer) post(path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return s.do(req)
+}
+
+// action submits a form to an endpoint that redirects when it succeeds.
+func (s *testServer) action(path string, form url.Values) {
+	s.t.Helper()
+	if w := s.post(path, form); w.Code != http.StatusSeeOther {
+		s.t.Fatalf("POST %s = %d %s, want 303", path, w.Code, w.Body)
+	}
+}
+
+// standings returns the league table served by the API.
+func (s *testServer) standings() []api.Standing {
+	s.t.Helper()
+	var body struct{ Data []api.Standing }
+	if err := json.Unmarshal([]byte(s.get("/api/v1/standings")), &body); err != nil {
+		s.t.Fatalf("decode standings: %v", err)
+	}
+	return body.Data
+}
+
+// currentWeek returns the week of the current season.
+func (s *testServer) currentWeek() int {
+	s.t.Helper()
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		s.t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	week, err := s.repo.GetCurrentWeek(ctx, season.ID)
+	if err != nil {
+		s.t.Fatalf("GetCurrentWeek: %v", err)
+	}
+	return week
+}
+
+// assertStandingsInvariants checks the table against the rules every
+// standings table obeys, and the cached standings against the table.
+func (s *testServer) assertStandingsInvariants(wantPlayed int64) {
+	s.t.Helper()
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		s.t.Fatalf("GetCurrentSeason: %v", err)
+	}
+
+	table := s.standings()
+	var goalDiff, wins, losses int64
+	for _, row := range table {
+		if row.Points != 3*row.Wins+row.Draws-row.Deducted {
+			s.t.Errorf("%s has %d points from %d wins, %d draws and %d deducted", row.Team.Name, row.Points, row.Wins, row.Draws, row.Deducted)
+		}
+		if row.Played != row.Wins+row.Draws+row.Losses {
+			s.t.Errorf("%s played %d matches but has %d results", row.Team.Name, row.Played, row.Wins+row.Draws+row.Losses)
+		}
+		if row.GoalDiff != row.GoalsFor-row.GoalsAgainst {
+			s.t.Errorf("%s has goal difference %d from %d-%d", row.Team.Name, row.GoalDiff, row.GoalsFor, row.GoalsAgainst)
+		}
+		if wantPlayed >= 0 && row.Played != wantPlayed {
+			s.t.Errorf("%s played %d matches, want %d", row.Team.Name, row.Played, wantPlayed)
+		}
+		goalDiff += row.GoalDiff
+		wins += row.Wins
+		losses += row.Losses
+
+		// Standings are cached once the season has results
+		cached, err := s.repo.GetStanding(ctx, row.Team.ID, season.ID)
+		if errors.Is(err, sql.ErrNoRows) && row.Played == 0 {
+			continue
+		}
+		if err != nil {
+			s.t.Errorf("cached standing of %s: %v", row.Team.Name, err)
+			continue
+		}
+		if cached.Points.Int64 != row.Points || cached.GoalDiff.Int64 != row.GoalDiff || cached.Wins.Int64 != row.Wins {
+			s.t.Errorf("cached standing of %s = %+v, want %+v", row.Team.Name, cached, row)
+		}
+	}
+	if goalDiff != 0 {
+		s.t.Errorf("goal differences sum to %d, want 0", goalDiff)
+	}
+	if wins != losses {
+		s.t.Errorf("%d wins but %d losses", wins, losses)
+	}
+}
+
+func TestFullSeason(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/") // the home page generates the fixtures
+	n := int64(len(s.standings()))
+
+	// Play the first half week by week
+	for week := 1; int64(week) < n; week++ {
+		if got := s.currentWeek(); got != week {
+			t.Fatalf("current week = %d, want %d", got, week)
+		}
+		s.action("/play-week", nil)
+		s.assertStandingsInvariants(int64(week))
+		s.action("/next-week", nil)
+	}
+
+	// A played week cannot be played again
+	s.action("/play-week", nil)
+	before := s.standings()
+	if w := s.post("/play-week", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("POST /play-week on a played week = %d, want 400", w.Code)
+	}
+	if after := s.standings(); !sameTable(before, after) {
+		t.Errorf("replaying the week changed the table from %+v to %+v", before, after)
+	}
+
+	// Finish the season in one go
+	s.action("/play-all", nil)
+	s.assertStandingsInvariants(2 * (n - 1))
+	if got, want := s.currentWeek(), int(2*(n-1)); got != want {
+		t.Errorf("current week after play-all = %d, want %d", got, want)
+	}
+
+	w := s.post("/next-week", nil)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Season is complete") {
+		t.Errorf("POST /next-week after the last week = %d %s, want the season complete message", w.Code, w.Body)
+	}
+	if page := s.get("/"); !strings.Contains(page, "Start New Season") {
+		t.Error("home page does not show the season as complete")
+	}
+}
+
+func TestNextWeekNeedsPlayedMatches(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+
+	w := s.post("/next-week", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("POST /next-week before playing = %d, want 400", w.Code)
+	}
+	if got := s.currentWeek(); got != 1 {
+		t.Errorf("current week = %d, want 1", got)
+	}
+}
+
+func TestEditMatch(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	s.action("/play-week", nil)
+
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	matches, err := s.repo.GetMatchesByWeek(ctx, 1, season.ID)
+	if err != nil || len(matches) == 0 {
+		t.Fatalf("GetMatchesByWeek = %v, %v", matches, err)
+	}
+	match := matches[0]
+
+	s.action("/matches/"+strconv.FormatInt(match.ID, 10)+"/edit", url.Values{
+		"home_score":  {"7"},
+		"guest_score": {"0"},
+	})
+
+	result, err := s.repo.GetMatchResult(ctx, match.ID)
+	if err != nil {
+		t.Fatalf("GetMatchResult: %v", err)
+	}
+	if result.HomeScore != 7 || result.GuestScore != 0 || result.WinnerID.Int64 != match.HomeID {
+		t.Errorf("edited result = %+v, want a 7-0 home win", result)
+	}
+	s.assertStandingsInvariants(1)
+
+	for _, row := range s.standings() {
+		if row.Team.ID == match.HomeID && (row.GoalsFor != 7 || row.Points != 3) {
+			t.Errorf("%s standing = %+v, want 7 goals and 3 points", row.Team.Name, row)
+		}
+	}
+
+	if w := s.post("/matches/"+strconv.FormatInt(match.ID, 10)+"/edit", url.Values{"home_score": {"x"}, "guest_score": {"0"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("editing with an invalid score = %d, want 400", w.Code)
+	}
+	if w := s.post("/matches/"+strconv.FormatInt(match.ID, 10)+"/edit", url.Values{"home_score": {"-1"}, "guest_score": {"0"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("editing with a negative score = %d, want 400", w.Code)
+	}
+	if w := s.post("/matches/9999/edit", url.Values{"home_score": {"1"}, "guest_score": {"0"}}); w.Code != http.StatusNotFound {
+		t.Errorf("editing a missing match = %d, want 404", w.Code)
+	}
+
+	// A match forfeited under a sanction keeps the score it was awarded
+	s.action("/sanctions", url.Values{"kind": {"forfeit"}, "team_id": {fmt.Sprint(match.GuestID)}, "match_id": {fmt.Sprint(match.ID)}, "reason": {"Fielded an ineligible player"}})
+	if w := s.post("/matches/"+strconv.FormatInt(match.ID, 10)+"/edit", url.Values{"home_score": {"0"}, "guest_score": {"1"}}); w.Code != http.StatusConflict {
+		t.Errorf("editing a forfeited match = %d, want 409", w.Code)
+	}
+	if result, err := s.repo.GetMatchResult(ctx, match.ID); err != nil || result.HomeScore != 3 || result.GuestScore != 0 {
+		t.Errorf("forfeited result = %+v (%v), want 3-0", result, err)
+	}
+}
+
+// rollbackToken returns the confirmation token offered by the rollback page.
+func (s *testServer) rollbackToken(seasonID int64, week int) string {
+	s.t.Helper()
+	page := s.get(fmt.Sprintf("/seasons/%d/rollback?week=%d", seasonID, week))
+	match := regexp.MustCompile(`name="token" value="([0-9a-f]+)"`).FindStringSubmatch(page)
+	if match == nil {
+		s.t.Fatalf("no confirmation token on the rollback page of season %d", seasonID)
+	}
+	return match[1]
+}
+
+func TestRollbackSeason(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	s.action("/play-all", nil)
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+	s.action("/play-week", nil)
+
+	ctx := context.Background()
+	seasons, err := s.repo.ListSeasons(ctx)
+	if err != nil || len(seasons) != 2 {
+		t.Fatalf("ListSeasons = %+v, %v", seasons, err)
+	}
+	first, next := seasons[0], seasons[1]
+	nextResults, err := s.repo.GetResultsBySeason(ctx, next.ID)
+	if err != nil {
+		t.Fatalf("GetResultsBySeason: %v", err)
+	}
+
+	// The rollback needs the token of the page
+	token := s.rollbackToken(first.ID, 3)
+	form := url.Values{"week": {"3"}}
+	if w := s.post(fmt.Sprintf("/seasons/%d/rollback", first.ID), form); w.Code != http.StatusBadRequest {
+		t.Errorf("rollback without a token = %d, want 400", w.Code)
+	}
+	form.Set("token", "0123456789abcdef")
+	if w := s.post(fmt.Sprintf("/seasons/%d/rollback", first.ID), form); w.Code != http.StatusBadRequest {
+		t.Errorf("rollback with a wrong token = %d, want 400", w.Code)
+	}
+	if got := s.currentWeek(); got != 1 {
+		t.Fatalf("a refused rollback changed the current week to %d", got)
+	}
+
+	form.Set("token", token)
+	s.action(fmt.Sprintf("/seasons/%d/rollback", first.ID), form)
+
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	if season.ID != first.ID || season.IsComplete.Bool {
+		t.Errorf("current season after rollback = %+v, want the incomplete season %d", season, first.ID)
+	}
+	if got := s.currentWeek(); got != 3 {
+		t.Errorf("current week after rollback = %d, want 3", got)
+	}
+	s.assertStandingsInvariants(2)
+
+	// The token is used up once the season changes
+	if w := s.post(fmt.Sprintf("/seasons/%d/rollback", first.ID), form); w.Code != http.StatusBadRequest {
+		t.Errorf("reusing the token = %d, want 400", w.Code)
+	}
+
+	// The other season keeps its history
+	results, err := s.repo.GetResultsBySeason(ctx, next.ID)
+	if err != nil {
+		t.Fatalf("GetResultsBySeason: %v", err)
+	}
+	if len(results) != len(nextResults) {
+		t.Errorf("the other season has %d results after rollback, want %d", len(results), len(nextResults))
+	}
+
+	// The season can be played again and is followed by a new year
+	s.action("/play-all", nil)
+	s.assertStandingsInvariants(int64(2 * (len(s.standings()) - 1)))
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+	season, err = s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	if season.Year != next.Year+1 {
+		t.Errorf("new season after rollback is %d, want %d", season.Year, next.Year+1)
+	}
+
+	for path, want := range map[string]int{
+		"/seasons/x/rollback":           http.StatusBadRequest,
+		"/seasons/9999/rollback":        http.StatusNotFound,
+		"/seasons/1/rollback?week=0":    http.StatusBadRequest,
+		"/seasons/1/rollback?week=9999": http.StatusBadRequest,
+	} {
+		if w := s.do(httptest.NewRequest(http.MethodGet, path, nil)); w.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestReplaySeason(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+
+	// Play the season week by week the first time
+	totalWeeks := 2 * (len(s.standings()) - 1)
+	for week := 1; week < totalWeeks; week++ {
+		s.action("/play-week", nil)
+		s.action("/next-week", nil)
+	}
+	s.action("/play-week", nil)
+
+	scores := func() map[int64]string {
+		t.Helper()
+		results, err := s.repo.GetResultsBySeason(ctx, season.ID)
+		if err != nil {
+			t.Fatalf("GetResultsBySeason: %v", err)
+		}
+		scores := make(map[int64]string, len(results))
+		for _, result := range results {
+			scores[result.ID] = fmt.Sprintf("%d-%d", result.HomeScore, result.GuestScore)
+		}
+		return scores
+	}
+	played := scores()
+	if len(played) != totalWeeks*len(s.standings())/2 {
+		t.Fatalf("played %d matches, want %d", len(played), totalWeeks*len(s.standings())/2)
+	}
+
+	form := url.Values{"week": {"1"}, "token": {s.rollbackToken(season.ID, 1)}}
+	s.action(fmt.Sprintf("/seasons/%d/rollback", season.ID), form)
+	if replayed := scores(); len(replayed) != 0 {
+		t.Fatalf("%d results left after rolling back to week 1", len(replayed))
+	}
+
+	// Replaying in one go draws every match from the same seed
+	s.action("/play-all", nil)
+	replayed := scores()
+	if len(replayed) != len(played) {
+		t.Fatalf("replayed %d matches, want %d", len(replayed), len(played))
+	}
+	for id, score := range played {
+		if replayed[id] != score {
+			t.Errorf("match %d replayed as %s, want %s", id, replayed[id], score)
+		}
+	}
+}
+
+// TestReplaySeasonAfterProgression replays a season whose teams have
+// developed since, which must not change its scores.
+func TestReplaySeasonAfterProgression(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+
+	scores := func() map[int64]string {
+		t.Helper()
+		results, err := s.repo.GetResultsBySeason(ctx, season.ID)
+		if err != nil {
+			t.Fatalf("GetResultsBySeason: %v", err)
+		}
+		scores := make(map[int64]string, len(results))
+		for _, result := range results {
+			scores[result.ID] = fmt.Sprintf("%d-%d", result.HomeScore, result.GuestScore)
+		}
+		return scores
+	}
+	strengths := func() map[int64]int64 {
+		t.Helper()
+		teams, err := s.repo.ListTeams(ctx)
+		if err != nil {
+			t.Fatalf("ListTeams: %v", err)
+		}
+		strengths := make(map[int64]int64, len(teams))
+		for _, team := range teams {
+			strengths[team.ID] = team.Strength.Int64
+		}
+		return strengths
+	}
+
+	before := strengths()
+	s.action("/play-all", nil)
+	played := scores()
+
+	// Starting the next season lets the teams develop
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+	if maps.Equal(strengths(), before) {
+		t.Fatal("no team's strength changed at the end of the season")
+	}
+
+	form := url.Values{"week": {"1"}, "token": {s.rollbackToken(season.ID, 1)}}
+	s.action(fmt.Sprintf("/seasons/%d/rollback", season.ID), form)
+	s.action("/play-all", nil)
+	replayed := scores()
+	if len(replayed) != len(played) {
+		t.Fatalf("replayed %d matches, want %d", len(replayed), len(played))
+	}
+	for id, score := range played {
+		if replayed[id] != score {
+			t.Errorf("match %d replayed as %s, want %s", id, replayed[id], score)
+		}
+	}
+}
+
+func TestSeasonHistory(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	s.action("/play-all", nil)
+	final := s.standings()
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+
+	seasons := s.get("/seasons")
+	if !strings.Contains(seasons, "Champion: "+final[0].Team.Name) || !strings.Contains(seasons, "Runner-up: "+final[1].Team.Name) {
+		t.Errorf("seasons page does not name %s and %s as champion and runner-up", final[0].Team.Name, final[1].Team.Name)
+	}
+
+	// Past seasons are shown read-only
+	for _, path := range []string{"/standings?season=1", "/matches?season=1", "/teams?season=1"} {
+		page := s.get(path)
+		if !strings.Contains(page, "read-only") {
+			t.Errorf("%s is not marked read-only", path)
+		}
+		for _, control := range []string{`action="/play-week"`, `action="/start-new-season"`, "/edit", "Add Team"} {
+			if strings.Contains(page, control) {
+				t.Errorf("%s shows the control %s", path, control)
+			}
+		}
+	}
+	if page := s.get("/matches"); strings.Contains(page, "read-only") {
+		t.Error("the current season is marked read-only")
+	}
+
+	for path, want := range map[string]int{
+		"/standings?season=x":  http.StatusBadRequest,
+		"/matches?season=9999": http.StatusNotFound,
+		"/seasons/x":           http.StatusBadRequest,
+		"/seasons/9999":        http.StatusNotFound,
+	} {
+		if w := s.do(httptest.NewRequest(http.MethodGet, path, nil)); w.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestDivisions(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	s.get("/divisions")
+	for _, name := range []string{"Leeds United", "Everton"} {
+		s.action("/teams", url.Values{"name": {name}, "strength": {"5"}, "budget": {"100000000"}})
+	}
+
+	// Split the six teams into two divisions, one relegated and one playoff
+	// place between them
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	s.action("/divisions", url.Values{"name": {"Division 2"}, "relegation_places": {"0"}})
+	divisions, err := s.repo.ListDivisions(ctx, season.ID)
+	if err != nil || len(divisions) != 2 {
+		t.Fatalf("ListDivisions = %+v, %v", divisions, err)
+	}
+	top, second := divisions[0], divisions[1]
+	s.action(fmt.Sprintf("/divisions/%d", top.ID), url.Values{"name": {"Division 1"}, "relegation_places": {"1"}, "playoff": {"true"}})
+	teams, err := s.repo.ListTeams(ctx)
+	if err != nil {
+		t.Fatalf("ListTeams: %v", err)
+	}
+	for _, team := range teams[3:] {
+		s.action(fmt.Sprintf("/divisions/%d/teams", second.ID), url.Values{"team_id": {fmt.Sprint(team.ID)}})
+	}
+
+	for _, tt := range []struct {
+		path string
+		form url.Values
+		want int
+	}{
+		{fmt.Sprintf("/divisions/%d/delete", second.ID), nil, http.StatusConflict},
+		{"/divisions/9999", url.Values{"name": {"Missing"}, "relegation_places": {"0"}}, http.StatusNotFound},
+		{"/divisions/9999/teams", url.Values{"team_id": {fmt.Sprint(teams[0].ID)}}, http.StatusNotFound},
+		{"/divisions", url.Values{"name": {""}, "relegation_places": {"1"}}, http.StatusBadRequest},
+		{"/divisions", url.Values{"name": {"Division 3"}, "relegation_places": {"-1"}}, http.StatusBadRequest},
+	} {
+		if w := s.post(tt.path, tt.form); w.Code != tt.want {
+			t.Errorf("POST %s %v = %d, want %d", tt.path, tt.form, w.Code, tt.want)
+		}
+	}
+
+	// Each division plays a double round-robin of its own
+	s.action("/play-all", nil)
+	matches, err := s.repo.GetMatchesBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetMatchesBySeason: %v", err)
+	}
+	if len(matches) != 2*6 {
+		t.Errorf("%d matches, want 6 in each division", len(matches))
+	}
+	members, err := s.repo.ListDivisionTeams(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ListDivisionTeams: %v", err)
+	}
+	divisionOf := map[int64]int64{}
+	for _, member := range members {
+		divisionOf[member.TeamID] = member.DivisionID
+	}
+	for _, match := range matches {
+		if divisionOf[match.HomeID] != match.DivisionID.Int64 || divisionOf[match.GuestID] != match.DivisionID.Int64 {
+			t.Errorf("match %d between %d and %d is not played inside division %d", match.ID, match.HomeID, match.GuestID, match.DivisionID.Int64)
+		}
+	}
+	if w := s.post(fmt.Sprintf("/divisions/%d/teams", top.ID), url.Values{"team_id": {fmt.Sprint(teams[3].ID)}}); w.Code != http.StatusConflict {
+		t.Errorf("moving a team after the first match = %d, want 409", w.Code)
+	}
+
+	var tables [2][]api.Standing
+	for _, row := range s.standings() {
+		tables[row.Division.Tier-1] = append(tables[row.Division.Tier-1], row)
+	}
+	relegated, promoted := tables[0][2].Team.ID, tables[1][0].Team.ID
+
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+
+	// The playoff between the second of each division decides the last place
+	playoffs, err := s.repo.ListPlayoffsBySeason(ctx, season.ID)
+	if err != nil || len(playoffs) != 1 {
+		t.Fatalf("ListPlayoffsBySeason = %+v, %v", playoffs, err)
+	}
+	playoff := playoffs[0]
+	if playoff.UpperTeamID != tables[0][1].Team.ID || playoff.LowerTeamID != tables[1][1].Team.ID {
+		t.Errorf("playoff between %s and %s, want %s and %s", playoff.UpperTeamName, playoff.LowerTeamName, tables[0][1].Team.Name, tables[1][1].Team.Name)
+	}
+	loser := playoff.UpperTeamID
+	if playoff.WinnerID == loser {
+		loser = playoff.LowerTeamID
+	}
+
+	next, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	nextDivisions, err := s.repo.ListDivisions(ctx, next.ID)
+	if err != nil || len(nextDivisions) != 2 {
+		t.Fatalf("ListDivisions = %+v, %v", nextDivisions, err)
+	}
+	if nextDivisions[0].RelegationPlaces != 1 || !nextDivisions[0].Playoff {
+		t.Errorf("the rules of the top division were not carried over: %+v", nextDivisions[0])
+	}
+	members, err = s.repo.ListDivisionTeams(ctx, next.ID)
+	if err != nil {
+		t.Fatalf("ListDivisionTeams: %v", err)
+	}
+	divisionOf = map[int64]int64{}
+	for _, member := range members {
+		divisionOf[member.TeamID] = member.DivisionID
+	}
+	for teamID, want := range map[int64]int64{
+		tables[0][0].Team.ID: nextDivisions[0].ID,
+		relegated:            nextDivisions[1].ID,
+		promoted:             nextDivisions[0].ID,
+		playoff.WinnerID:     nextDivisions[0].ID,
+		loser:                nextDivisions[1].ID,
+		tables[1][2].Team.ID: nextDivisions[1].ID,
+	} {
+		if divisionOf[teamID] != want {
+			t.Errorf("team %d plays in division %d, want %d", teamID, divisionOf[teamID], want)
+		}
+	}
+
+	page := s.get(fmt.Sprintf("/seasons/%d", season.ID))
+	if !strings.Contains(page, "Playoffs") || !strings.Contains(page, "zone-relegation") || !strings.Contains(page, "zone-promotion") {
+		t.Error("season page does not show the playoff and the promotion and relegation zones")
+	}
+}
+
+func TestTeamProgression(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	s.action("/play-all", nil)
+	final := s.standings()
+	var teams struct{ Data []api.Team }
+	if err := json.Unmarshal([]byte(s.get("/api/v1/teams")), &teams); err != nil {
+		t.Fatalf("decode teams: %v", err)
+	}
+	budgets := map[int64]int64{}
+	for _, team := range teams.Data {
+		budgets[team.ID] = team.Budget
+	}
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+
+	var lastPrize int64
+	for i, row := range final {
+		var body struct{ Data []api.TeamHistory }
+		path := fmt.Sprintf("/api/v1/teams/%d/history", row.Team.ID)
+		if err := json.Unmarshal([]byte(s.get(path)), &body); err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+		if len(body.Data) != 1 {
+			t.Fatalf("%s has %d entries, want 1", path, len(body.Data))
+		}
+		entry := body.Data[0]
+		if entry.LastSeasonStanding != int64(i+1) {
+			t.Errorf("%s finished %d, want %d", row.Team.Name, entry.LastSeasonStanding, i+1)
+		}
+		budget := budgets[row.Team.ID]
+		if entry.BudgetBefore != budget || entry.BudgetAfter != budget+entry.PrizeMoney {
+			t.Errorf("%s budget went from %d to %d with %d prize money, want from %d",
+				row.Team.Name, entry.BudgetBefore, entry.BudgetAfter, entry.PrizeMoney, budget)
+		}
+		if entry.PrizeMoney <= 0 || (i > 0 && entry.PrizeMoney >= lastPrize) {
+			t.Errorf("%s earned %d, want less than the %d of the team above it", row.Team.Name, entry.PrizeMoney, lastPrize)
+		}
+		lastPrize = entry.PrizeMoney
+		if entry.StrengthAfter < 1 || entry.StrengthAfter > 10 {
+			t.Errorf("%s strength %d is out of bounds", row.Team.Name, entry.StrengthAfter)
+		}
+	}
+
+	// Starting another season with nothing played records nothing new
+	s.action("/start-new-season", url.Values{"seed": {"8"}})
+	history, err := s.repo.ListTeamHistory(context.Background())
+	if err != nil {
+		t.Fatalf("ListTeamHistory: %v", err)
+	}
+	if len(history) != len(final) {
+		t.Errorf("%d history entries, want %d", len(history), len(final))
+	}
+	if page := s.get("/teams"); !strings.Contains(page, "Season History") {
+		t.Error("teams page does not show the season history")
+	}
+	if w := s.do(httptest.NewRequest(http.MethodGet, "/api/v1/teams/9999/history", nil)); w.Code != http.StatusNotFound {
+		t.Errorf("history of a missing team = %d, want 404", w.Code)
+	}
+}
+
+func TestHeadToHead(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	s.get("/")
+	s.action("/play-all", nil)
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+	s.action("/play-all", nil)
+
+	teams, err := s.repo.ListTeams(ctx)
+	if err != nil {
+		t.Fatalf("ListTeams: %v", err)
+	}
+	team, opponent := teams[0], teams[1]
+
+	// Count the meetings of the two teams season by season
+	seasons, err := s.repo.ListSeasons(ctx)
+	if err != nil {
+		t.Fatalf("ListSeasons: %v", err)
+	}
+	var want api.Record
+	var meetings int
+	var biggestMargin int64
+	for _, season := range seasons {
+		results, err := s.repo.GetResultsBySeason(ctx, season.ID)
+		if err != nil {
+			t.Fatalf("GetResultsBySeason: %v", err)
+		}
+		for _, r := range results {
+			scored, conceded := r.HomeScore, r.GuestScore
+			switch {
+			case r.HomeID == team.ID && r.GuestID == opponent.ID:
+			case r.HomeID == opponent.ID && r.GuestID == team.ID:
+				scored, conceded = r.GuestScore, r.HomeScore
+			default:
+				continue
+			}
+			meetings++
+			want.Played++
+			want.GoalsFor += scored
+			want.GoalsAgainst += conceded
+			switch {
+			case scored > conceded:
+				want.Wins++
+				biggestMargin = max(biggestMargin, scored-conceded)
+			case scored == conceded:
+				want.Draws++
+			default:
+				want.Losses++
+			}
+		}
+	}
+
+	var body struct{ Data api.HeadToHead }
+	path := fmt.Sprintf("/api/v1/teams/%d/vs/%d", team.ID, opponent.ID)
+	if err := json.Unmarshal([]byte(s.get(path)), &body); err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+	h2h := body.Data
+	if h2h.AllTime != want {
+		t.Errorf("all-time record = %+v, want %+v", h2h.AllTime, want)
+	}
+	var sum api.Record
+	for _, season := range h2h.Seasons {
+		sum.Played += season.Record.Played
+		sum.Wins += season.Record.Wins
+		sum.GoalsFor += season.Record.GoalsFor
+	}
+	if len(h2h.Seasons) != 2 || sum.Played != want.Played || sum.Wins != want.Wins || sum.GoalsFor != want.GoalsFor {
+		t.Errorf("season records %+v do not add up to the all-time record", h2h.Seasons)
+	}
+	if len(h2h.LastMeetings) != min(meetings, 5) {
+		t.Errorf("%d last meetings, want %d", len(h2h.LastMeetings), min(meetings, 5))
+	}
+	if len(h2h.LastMeetings) > 1 && h2h.LastMeetings[0].SeasonYear < h2h.LastMeetings[len(h2h.LastMeetings)-1].SeasonYear {
+		t.Error("last meetings are not listed latest first")
+	}
+	if win := h2h.BiggestWin; (win == nil) != (want.Wins == 0) {
+		t.Errorf("biggest win = %+v with %d wins", win, want.Wins)
+	} else if win != nil {
+		margin := win.HomeScore - win.GuestScore
+		if win.GuestTeam.ID == team.ID {
+			margin = -margin
+		}
+		if margin != biggestMargin {
+			t.Errorf("biggest win by %d goals, want %d", margin, biggestMargin)
+		}
+	}
+
+	// Seen from the opponent the record is mirrored
+	path = fmt.Sprintf("/api/v1/teams/%d/vs/%d", opponent.ID, team.ID)
+	if err := json.Unmarshal([]byte(s.get(path)), &body); err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+	if got := body.Data.AllTime; got.Wins != want.Losses || got.Losses != want.Wins || got.GoalsFor != want.GoalsAgainst {
+		t.Errorf("mirrored record = %+v, want the opposite of %+v", got, want)
+	}
+
+	page := s.get(fmt.Sprintf("/teams/%d/vs/%d", team.ID, opponent.ID))
+	if !strings.Contains(page, "All time") || !strings.Contains(page, "Last Meetings") {
+		t.Error("head-to-head page does not show the all-time record and the last meetings")
+	}
+	w := s.do(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/teams/compare?team=%d&opponent=%d", team.ID, opponent.ID), nil))
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != fmt.Sprintf("/teams/%d/vs/%d", team.ID, opponent.ID) {
+		t.Errorf("compare = %d to %q, want a redirect to the head-to-head page", w.Code, w.Header().Get("Location"))
+	}
+	for path, want := range map[string]int{
+		fmt.Sprintf("/teams/%d/vs/%d", team.ID, team.ID):        http.StatusBadRequest,
+		fmt.Sprintf("/teams/%d/vs/9999", team.ID):               http.StatusNotFound,
+		fmt.Sprintf("/api/v1/teams/%d/vs/%d", team.ID, team.ID): http.StatusBadRequest,
+		fmt.Sprintf("/api/v1/teams/9999/vs/%d", team.ID):        http.StatusNotFound,
+		"/api/v1/teams/1/vs/x":                                  http.StatusBadRequest,
+	} {
+		if w := s.do(httptest.NewRequest(http.MethodGet, path, nil)); w.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+func TestTeamPage(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	s.action("/play-week", nil)
+	for range 2 {
+		s.action("/next-week", nil)
+		s.action("/play-week", nil)
+	}
+
+	for i, row := range s.standings() {
+		var body struct{ Data api.TeamSeason }
+		path := fmt.Sprintf("/api/v1/teams/%d/season", row.Team.ID)
+		if err := json.Unmarshal([]byte(s.get(path)), &body); err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+		ts := body.Data
+
+		// Four teams play a double round-robin, one match a week
+		if len(ts.Matches) != 6 || len(ts.Upcoming) != 3 || len(ts.Form) != 3 {
+			t.Errorf("%s has %d matches, %d upcoming and form %v, want 6, 3 and 3 results",
+				row.Team.Name, len(ts.Matches), len(ts.Upcoming), ts.Form)
+		}
+		if ts.Home.Played+ts.Away.Played != row.Played || ts.Home.Wins+ts.Away.Wins != row.Wins ||
+			ts.Home.GoalsFor+ts.Away.GoalsFor != row.GoalsFor {
+			t.Errorf("%s home %+v and away %+v do not add up to its standing %+v", row.Team.Name, ts.Home, ts.Away, row)
+		}
+		if len(ts.Weeks) != 3 {
+			t.Fatalf("%s has %d weeks, want 3", row.Team.Name, len(ts.Weeks))
+		}
+		last := ts.Weeks[2]
+		if last.Points != row.Points || last.Position != i+1 {
+			t.Errorf("%s after week 3 = %d points in position %d, want %d in position %d",
+				row.Team.Name, last.Points, last.Position, row.Points, i+1)
+		}
+		for _, m := range ts.Upcoming {
+			if m.Played || m.Week <= 3 {
+				t.Errorf("%s upcoming match %+v was played", row.Team.Name, m)
+			}
+		}
+	}
+
+	page := s.get("/teams/1")
+	for _, want := range []string{"Form", "Points by Week", "Fixtures and Results", "Upcoming Opponents"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("team page does not show %s", want)
+		}
+	}
+	for path, want := range map[string]int{
+		"/teams/9999":                        http.StatusNotFound,
+		"/teams/x":                           http.StatusBadRequest,
+		"/teams/1?season=9999":               http.StatusNotFound,
+		"/api/v1/teams/9999/season":          http.StatusNotFound,
+		"/api/v1/teams/1/season?season_id=x": http.StatusBadRequest,
+	} {
+		if w := s.do(httptest.NewRequest(http.MethodGet, path, nil)); w.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, w.Code, want)
+		}
+	}
+}
+
+// cup returns the cup of a season served by the API.
+func (s *testServer) cup(seasonID int64) api.Cup {
+	s.t.Helper()
+	var body struct{ Data api.Cup }
+	if err := json.Unmarshal([]byte(s.get(fmt.Sprintf("/api/v1/cup?season_id=%d", seasonID))), &body); err != nil {
+		s.t.Fatalf("decode cup: %v", err)
+	}
+	return body.Data
+}
+
+func TestCup(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+
+	if w := s.do(httptest.NewRequest(http.MethodGet, "/api/v1/cup", nil)); w.Code != http.StatusNotFound {
+		t.Errorf("GET /api/v1/cup before the draw = %d, want 404", w.Code)
+	}
+	drawCup := func(draw string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/cup", strings.NewReader(`{"draw":"`+draw+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return s.do(req)
+	}
+	if w := drawCup("knockout"); w.Code != http.StatusBadRequest {
+		t.Errorf("drawing an unknown draw = %d, want 400", w.Code)
+	}
+	if w := drawCup("random"); w.Code != http.StatusCreated {
+		t.Fatalf("POST /api/v1/cup = %d %s", w.Code, w.Body)
+	}
+	s.action("/cup", url.Values{"draw": {"seeded"}})
+
+	// Four teams play semi-finals and a final, the two strongest teams
+	// kept apart
+	cup := s.cup(season.ID)
+	if cup.Draw != "seeded" || len(cup.Rounds) != 2 || cup.Winner != nil {
+		t.Fatalf("cup = %+v, want an unplayed seeded cup of two rounds", cup)
+	}
+	semis, final := cup.Rounds[0], cup.Rounds[1]
+	if semis.Name != "Semi-finals" || len(semis.Ties) != 2 || final.Name != "Final" || len(final.Ties) != 1 {
+		t.Fatalf("rounds = %+v, want two semi-finals and a final", cup.Rounds)
+	}
+	if semis.Week >= final.Week || final.Week >= 6 {
+		t.Errorf("semi-finals in week %d and final in week %d, want the final before the last league week", semis.Week, final.Week)
+	}
+	if final.Ties[0].HomeTeam != nil || final.Ties[0].GuestTeam != nil {
+		t.Errorf("final teams known before the semi-finals: %+v", final.Ties[0])
+	}
+	for _, tie := range semis.Ties {
+		if tie.HomeTeam == nil || tie.GuestTeam == nil {
+			t.Fatalf("semi-final %+v is missing a team", tie)
+		}
+		names := tie.HomeTeam.Name + " " + tie.GuestTeam.Name
+		if strings.Contains(names, "Manchester City") && strings.Contains(names, "Liverpool") {
+			t.Errorf("the top seeds meet in the semi-finals: %+v", tie)
+		}
+	}
+
+	s.action("/play-all", nil)
+	if w := drawCup("seeded"); w.Code != http.StatusConflict {
+		t.Errorf("drawing a started cup = %d, want 409", w.Code)
+	}
+
+	// The semi-final winners meet in the final
+	cup = s.cup(season.ID)
+	final = cup.Rounds[1]
+	for i, tie := range cup.Rounds[0].Ties {
+		finalist := final.Ties[0].HomeTeam
+		if i == 1 {
+			finalist = final.Ties[0].GuestTeam
+		}
+		if !tie.Played || tie.Winner == nil || finalist == nil || *tie.Winner != *finalist {
+			t.Errorf("semi-final %+v does not send its winner to the final %+v", tie, final.Ties[0])
+		}
+	}
+	tie := final.Ties[0]
+	if !tie.Played || cup.Winner == nil || tie.Winner == nil || *cup.Winner != *tie.Winner {
+		t.Fatalf("cup after the season = %+v, want the final's winner", cup)
+	}
+	if tie.HomePenalties == nil && *tie.HomeScore == *tie.GuestScore {
+		t.Errorf("final %+v was drawn without penalties", tie)
+	}
+	if page := s.get("/cup"); !strings.Contains(page, cup.Winner.Name+" won the cup") {
+		t.Error("cup page does not show the winner")
+	}
+
+	// Rolling back to the final's week replays it
+	form := url.Values{"week": {strconv.FormatInt(final.Week, 10)}, "token": {s.rollbackToken(season.ID, int(final.Week))}}
+	s.action(fmt.Sprintf("/seasons/%d/rollback", season.ID), form)
+	cup = s.cup(season.ID)
+	if cup.Winner != nil || cup.Rounds[1].Ties[0].Played || !cup.Rounds[0].Ties[0].Played {
+		t.Errorf("cup after rolling back to the final = %+v, want only the final undone", cup)
+	}
+
+	// The next season draws its cup the same way
+	s.action("/play-all", nil)
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+	next, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	if cup := s.cup(next.ID); cup.Draw != "seeded" || len(cup.Rounds) != 2 || cup.Rounds[0].Ties[0].Played {
+		t.Errorf("next season's cup = %+v, want a new seeded draw", cup)
+	}
+}
+
+// tournament returns the tournament of a season served by the API.
+func (s *testServer) tournament(seasonID int64) api.Tournament {
+	s.t.Helper()
+	var body struct{ Data api.Tournament }
+	if err := json.Unmarshal([]byte(s.get(fmt.Sprintf("/api/v1/tournament?season_id=%d", seasonID))), &body); err != nil {
+		s.t.Fatalf("decode tournament: %v", err)
+	}
+	return body.Data
+}
+
+func TestTournament(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+
+	if w := s.do(httptest.NewRequest(http.MethodGet, "/api/v1/tournament", nil)); w.Code != http.StatusNotFound {
+		t.Errorf("GET /api/v1/tournament before the draw = %d, want 404", w.Code)
+	}
+	drawTournament := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tournament", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return s.do(req)
+	}
+	for _, body := range []string{`{"groups":3,"qualifiers":1}`, `{"groups":1,"qualifiers":3}`, `{"groups":2,"qualifiers":0}`} {
+		if w := drawTournament(body); w.Code != http.StatusBadRequest {
+			t.Errorf("drawing %s = %d, want 400", body, w.Code)
+		}
+	}
+	if w := drawTournament(`{"groups":1,"qualifiers":4}`); w.Code != http.StatusConflict {
+		t.Errorf("drawing more matchdays than weeks = %d, want 409", w.Code)
+	}
+	if w := drawTournament(`{"groups":1,"qualifiers":2}`); w.Code != http.StatusCreated {
+		t.Fatalf("POST /api/v1/tournament = %d %s", w.Code, w.Body)
+	}
+	s.action("/tournament", url.Values{"groups": {"2"}, "qualifiers": {"1"}, "away_goals": {"true"}})
+
+	// Two groups of two send their winners to a two-legged final, the two
+	// strongest teams drawn from the first pot into different groups
+	tournament := s.tournament(season.ID)
+	if !tournament.Format.AwayGoals || len(tournament.Groups) != 2 || len(tournament.Knockout) != 1 || tournament.Winner != nil {
+		t.Fatalf("tournament = %+v, want two groups and a final", tournament)
+	}
+	for _, group := range tournament.Groups {
+		if len(group.Table) != 2 || len(group.Matches) != 1 {
+			t.Fatalf("%s = %+v, want two teams playing once", group.Name, group)
+		}
+		names := group.Table[0].Team.Name + " " + group.Table[1].Team.Name
+		if strings.Contains(names, "Manchester City") && strings.Contains(names, "Liverpool") {
+			t.Errorf("the first pot's teams share %s", group.Name)
+		}
+	}
+	final := tournament.Knockout[0]
+	if final.Name != "Final" || len(final.Ties) != 1 || final.Ties[0].FirstLeg.HomeTeam != nil {
+		t.Fatalf("final = %+v, want it waiting for the group winners", final)
+	}
+	if tie := final.Ties[0]; tie.FirstLeg.Week <= tournament.Groups[0].Matches[0].Week || tie.SecondLeg.Week >= 6 {
+		t.Errorf("final legs in weeks %d and %d, want them after the groups and before the last league week", tie.FirstLeg.Week, tie.SecondLeg.Week)
+	}
+
+	s.action("/play-all", nil)
+	if w := drawTournament(`{"groups":2,"qualifiers":1}`); w.Code != http.StatusConflict {
+		t.Errorf("drawing a started tournament = %d, want 409", w.Code)
+	}
+
+	// The group winners meet in the final, decided on aggregate, then on
+	// away goals and then on penalties
+	tournament = s.tournament(season.ID)
+	tie := tournament.Knockout[0].Ties[0]
+	first, second := tie.FirstLeg, tie.SecondLeg
+	if first.HomeTeam == nil || second.GuestTeam == nil || *first.HomeTeam != *second.GuestTeam || *first.GuestTeam != *second.HomeTeam {
+		t.Fatalf("final legs %+v and %+v do not swap home sides", first, second)
+	}
+	for _, group := range tournament.Groups {
+		winner := group.Table[0].Team
+		if winner.ID != first.HomeTeam.ID && winner.ID != first.GuestTeam.ID {
+			t.Errorf("%s winner %s is not in the final", group.Name, winner.Name)
+		}
+	}
+	if !second.Played || tie.Winner == nil || tournament.Winner == nil || *tournament.Winner != *tie.Winner {
+		t.Fatalf("tournament after the season = %+v, want the final's winner", tournament)
+	}
+	home, guest := *second.HomeScore+*first.GuestScore, *second.GuestScore+*first.HomeScore
+	want := tie.Winner.ID == second.HomeTeam.ID
+	switch {
+	case home != guest:
+		want = home > guest
+	case *first.GuestScore != *second.GuestScore:
+		want = *first.GuestScore > *second.GuestScore
+	case second.HomePenalties == nil:
+		t.Errorf("final %+v was level without penalties", tie)
+	}
+	if want != (tie.Winner.ID == second.HomeTeam.ID) {
+		t.Errorf("final %+v went to the wrong team", tie)
+	}
+	if page := s.get("/tournament"); !strings.Contains(page, tournament.Winner.Name+" won the tournament") {
+		t.Error("tournament page does not show the winner")
+	}
+
+	// Rolling back to the second leg's week replays only the second leg
+	form := url.Values{"week": {strconv.FormatInt(second.Week, 10)}, "token": {s.rollbackToken(season.ID, int(second.Week))}}
+	s.action(fmt.Sprintf("/seasons/%d/rollback", season.ID), form)
+	tournament = s.tournament(season.ID)
+	if tie := tournament.Knockout[0].Ties[0]; tournament.Winner != nil || tie.SecondLeg.Played || !tie.FirstLeg.Played {
+		t.Errorf("tournament after rolling back to the second leg = %+v, want only it undone", tournament)
+	}
+
+	// The next season draws its tournament in the same format
+	s.action("/play-all", nil)
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+	next, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	if tournament := s.tournament(next.ID); !tournament.Format.AwayGoals || len(tournament.Groups) != 2 || tournament.Groups[0].Matches[0].Played {
+		t.Errorf("next season's tournament = %+v, want a new draw in the same format", tournament)
+	}
+}
+
+// competitions returns the competitions of a season served by the API.
+func (s *testServer) competitions(seasonID int64) []api.Competition {
+	s.t.Helper()
+	var body struct{ Data []api.Competition }
+	if err := json.Unmarshal([]byte(s.get(fmt.Sprintf("/api/v1/competitions?season_id=%d", seasonID))), &body); err != nil {
+		s.t.Fatalf("decode competitions: %v", err)
+	}
+	return body.Data
+}
+
+func (s *testServer) sanctions(seasonID int64) []api.Sanction {
+	s.t.Helper()
+	var body struct{ Data []api.Sanction }
+	if err := json.Unmarshal([]byte(s.get(fmt.Sprintf("/api/v1/sanctions?season_id=%d", seasonID))), &body); err != nil {
+		s.t.Fatalf("decode sanctions: %v", err)
+	}
+	return body.Data
+}
+
+func TestCompetitions(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+
+	// The league is a double round-robin of every team with three points
+	// for a win
+	competitions := s.competitions(season.ID)
+	if len(competitions) != 1 {
+		t.Fatalf("competitions = %+v, want only the league", competitions)
+	}
+	if league := competitions[0]; league.Format != "league" || league.Legs != 2 || league.Points != (api.Points{Win: 3, Draw: 1}) || len(league.Teams) != 4 {
+		t.Errorf("league = %+v, want two legs, 3-1-0 points and every team", league)
+	}
+
+	updateCompetition := func(format, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/competitions/"+format, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return s.do(req)
+	}
+	for _, body := range []string{`{"legs":0,"points":{"win":3,"draw":1}}`, `{"legs":5,"points":{"win":3,"draw":1}}`, `{"legs":2,"points":{"win":1,"draw":2}}`, `{"legs":2,"points":{"win":3,"draw":1,"loss":-1}}`, `{"legs":2,"points":{"win":3,"draw":1},"ranking_rules":"bundesliga"}`} {
+		if w := updateCompetition("league", body); w.Code != http.StatusBadRequest {
+			t.Errorf("updating the league with %s = %d, want 400", body, w.Code)
+		}
+	}
+	if w := updateCompetition("cup", `{"legs":1,"points":{"win":3,"draw":1}}`); w.Code != http.StatusNotFound {
+		t.Errorf("updating the cup before it is drawn = %d, want 404", w.Code)
+	}
+
+	// The cup enters every team and only plays single legs
+	s.action("/cup", url.Values{"draw": {"seeded"}})
+	if w := updateCompetition("cup", `{"legs":2,"points":{"win":3,"draw":1}}`); w.Code != http.StatusBadRequest {
+		t.Errorf("giving the cup two legs = %d, want 400", w.Code)
+	}
+	if w := updateCompetition("cup", `{"legs":1,"points":{"win":3,"draw":1},"ranking_rules":"la-liga"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("giving the cup tie-breakers = %d, want 400", w.Code)
+	}
+
+	// Playing the league once with two points for a win and La Liga
+	// tie-breakers shortens the season to three weeks, the cup drawn again
+	// over them
+	s.action("/competitions/league", url.Values{"legs": {"1"}, "win_points": {"2"}, "draw_points": {"1"}, "loss_points": {"0"}, "bonus_goals": {"0"}, "bonus_points": {"0"}, "ranking_rules": {"la-liga"}})
+	competitions = s.competitions(season.ID)
+	if len(competitions) != 2 || competitions[1].Format != "cup" || len(competitions[1].Teams) != 4 {
+		t.Fatalf("competitions = %+v, want the league and the cup", competitions)
+	}
+	if league := competitions[0]; league.Legs != 1 || league.Points != (api.Points{Win: 2, Draw: 1}) || league.RankingRules != "la-liga" {
+		t.Errorf("league = %+v, want one leg, 2-1-0 points and la-liga rules", league)
+	}
+	if cup := competitions[1]; cup.RankingRules != "premier-league" {
+		t.Errorf("cup = %+v, want the default rules", cup)
+	}
+	if cup := s.cup(season.ID); len(cup.Rounds) != 2 || cup.Rounds[1].Week >= 3 {
+		t.Errorf("cup = %+v, want its final before the third week", cup)
+	}
+	if page := s.get("/competitions"); !strings.Contains(page, "Teams meet once, 2-1-0 points for a win, draw and loss, level teams ranked by La Liga rules") {
+		t.Error("competitions page does not show the league's rules")
+	}
+
+	s.action("/play-all", nil)
+	if got := s.currentWeek(); got != 3 {
+		t.Errorf("season ended in week %d, want 3", got)
+	}
+	for _, row := range s.standings() {
+		if row.Played != 3 || row.Points != 2*row.Wins+row.Draws {
+			t.Errorf("%s has %d points from %d matches, want two points a win", row.Team.Name, row.Points, row.Played)
+		}
+		cached, err := s.repo.GetStanding(ctx, row.Team.ID, season.ID)
+		if err != nil || cached.Points.Int64 != row.Points {
+			t.Errorf("cached standing of %s = %+v (%v), want %d points", row.Team.Name, cached, err, row.Points)
+		}
+	}
+	if w := updateCompetition("league", `{"legs":2,"points":{"win":3,"draw":1}}`); w.Code != http.StatusConflict {
+		t.Errorf("updating a started league = %d, want 409", w.Code)
+	}
+
+	// The next season is played by the same rules
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+	next, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	if league := s.competitions(next.ID)[0]; league.Legs != 1 || league.Points.Win != 2 || league.RankingRules != "la-liga" {
+		t.Errorf("next season's league = %+v, want the previous season's rules", league)
+	}
+	s.action("/play-all", nil)
+	if got := s.currentWeek(); got != 3 {
+		t.Errorf("next season ended in week %d, want 3", got)
+	}
+
+	// Starting a season with other tie-breakers changes only those
+	s.action("/start-new-season", url.Values{"seed": {"8"}, "rules": {"serie-a"}})
+	third, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	if league := s.competitions(third.ID)[0]; league.Legs != 1 || league.Points.Win != 2 || league.RankingRules != "serie-a" {
+		t.Errorf("third season's league = %+v, want the previous rules with serie-a tie-breakers", league)
+	}
+}
+
+func TestSanctions(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+
+	// A bonus point for scoring three or more, which needs the goals
+	updateCompetition := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/competitions/league", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return s.do(req)
+	}
+	for _, body := range []string{`{"legs":2,"points":{"win":3,"draw":1,"bonus":1}}`, `{"legs":2,"points":{"win":3,"draw":1,"bonus_goals":-1}}`} {
+		if w := updateCompetition(body); w.Code != http.StatusBadRequest {
+			t.Errorf("updating the league with %s = %d, want 400", body, w.Code)
+		}
+	}
+	if w := updateCompetition(`{"legs":2,"points":{"win":3,"draw":1,"bonus_goals":3,"bonus":1}}`); w.Code != http.StatusOK {
+		t.Fatalf("adding a bonus = %d %s, want 200", w.Code, w.Body)
+	}
+	if page := s.get("/competitions"); !strings.Contains(page, "1 bonus for scoring 3 or more") {
+		t.Error("competitions page does not show the bonus")
+	}
+
+	s.action("/play-week", nil)
+	results, err := s.repo.GetResultsBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetResultsBySeason: %v", err)
+	}
+	bonus := map[int64]int64{}
+	for _, result := range results {
+		if result.HomeScore >= 3 {
+			bonus[result.HomeID]++
+		}
+		if result.GuestScore >= 3 {
+			bonus[result.GuestID]++
+		}
+	}
+	before := map[int64]int64{}
+	for _, row := range s.standings() {
+		if row.Points != 3*row.Wins+row.Draws+bonus[row.Team.ID] {
+			t.Errorf("%s has %d points from %d wins, %d draws and %d bonus", row.Team.Name, row.Points, row.Wins, row.Draws, bonus[row.Team.ID])
+		}
+		before[row.Team.ID] = row.Points
+	}
+
+	// Deductions need a team of the competition, points and a reason
+	teams, err := s.repo.ListTeams(ctx)
+	if err != nil {
+		t.Fatalf("ListTeams: %v", err)
+	}
+	team := teams[0]
+	deduction := func(format, teamID, points, reason string) url.Values {
+		return url.Values{"kind": {"deduction"}, "format": {format}, "team_id": {teamID}, "points": {points}, "reason": {reason}}
+	}
+	for _, form := range []url.Values{
+		deduction("league", "9999", "3", "Financial breach"),
+		deduction("league", fmt.Sprint(team.ID), "0", "Financial breach"),
+		deduction("league", fmt.Sprint(team.ID), "3", "  "),
+		{"kind": {"fine"}, "team_id": {fmt.Sprint(team.ID)}, "reason": {"Financial breach"}},
+	} {
+		if w := s.post("/sanctions", form); w.Code != http.StatusBadRequest {
+			t.Errorf("deducting %v = %d, want 400", form, w.Code)
+		}
+	}
+	if w := s.post("/sanctions", deduction("tournament", fmt.Sprint(team.ID), "3", "Financial breach")); w.Code != http.StatusNotFound {
+		t.Errorf("deducting from an undrawn tournament = %d, want 404", w.Code)
+	}
+
+	// Deductions count at once, in the middle of the season
+	s.action("/sanctions", deduction("league", fmt.Sprint(team.ID), "4", "Financial breach"))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/sanctions", strings.NewReader(fmt.Sprintf(`{"kind":"deduction","team_id":%d,"points":2,"reason":"Fielded an ineligible player"}`, team.ID)))
+	req.Header.Set("Content-Type", "application/json")
+	w := s.do(req)
+	var body struct{ Data api.Sanction }
+	if w.Code != http.StatusCreated || json.Unmarshal(w.Body.Bytes(), &body) != nil {
+		t.Fatalf("POST /api/v1/sanctions = %d %s, want 201", w.Code, w.Body)
+	}
+	if body.Data.Format != "league" || body.Data.Points != 2 || body.Data.Team.ID != team.ID || body.Data.Summary != "2 points deducted" {
+		t.Errorf("sanction = %+v, want 2 points deducted in the league", body.Data)
+	}
+	sanctions := s.sanctions(season.ID)
+	if len(sanctions) != 2 || sanctions[0].Points != 4 || sanctions[1].Reason != "Fielded an ineligible player" {
+		t.Errorf("sanctions = %+v, want 4 then 2 points", sanctions)
+	}
+	for _, row := range s.standings() {
+		want, deducted := before[row.Team.ID], int64(0)
+		if row.Team.ID == team.ID {
+			want, deducted = want-6, 6
+		}
+		if row.Points != want || row.Deducted != deducted {
+			t.Errorf("%s has %d points with %d deducted, want %d with %d", row.Team.Name, row.Points, row.Deducted, want, deducted)
+		}
+		if row.Team.ID == team.ID && (len(row.Notes) != 2 || row.Notes[0] != "4 points deducted: Financial breach") {
+			t.Errorf("notes of %s = %q, want both deductions", row.Team.Name, row.Notes)
+		}
+		cached, err := s.repo.GetStanding(ctx, row.Team.ID, season.ID)
+		if err != nil || cached.Points.Int64 != row.Points {
+			t.Errorf("cached standing of %s = %+v (%v), want %d points", row.Team.Name, cached, err, row.Points)
+		}
+	}
+	if page := s.get("/sanctions"); !strings.Contains(page, "Financial breach") {
+		t.Error("sanctions page does not list the deduction")
+	}
+	if page := s.get("/"); !strings.Contains(page, "4 points deducted: Financial breach") {
+		t.Error("league table does not note the deduction")
+	}
+
+	// Lifting a deduction gives the points back
+	deleteSanction := func(id int64) int {
+		return s.do(httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/sanctions/%d", id), nil)).Code
+	}
+	if code := deleteSanction(sanctions[0].ID); code != http.StatusNoContent {
+		t.Fatalf("DELETE /api/v1/sanctions/%d = %d, want 204", sanctions[0].ID, code)
+	}
+	if code := deleteSanction(sanctions[0].ID); code != http.StatusNotFound {
+		t.Errorf("deleting a deleted sanction = %d, want 404", code)
+	}
+	for _, row := range s.standings() {
+		if row.Team.ID == team.ID && (row.Points != before[team.ID]-2 || row.Deducted != 2) {
+			t.Errorf("%s has %d points with %d deducted after the removal, want %d with 2", row.Team.Name, row.Points, row.Deducted, before[team.ID]-2)
+		}
+	}
+
+	// Sanctions stay with their season, which is archived once it ends
+	s.action("/play-all", nil)
+	s.action("/start-new-season", url.Values{"seed": {"7"}})
+	next, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	if league := s.competitions(next.ID)[0]; league.Points.BonusGoals != 3 {
+		t.Errorf("next season's league = %+v, want the bonus", league)
+	}
+	if sanctions := s.sanctions(next.ID); len(sanctions) != 0 {
+		t.Errorf("next season's sanctions = %+v, want none", sanctions)
+	}
+	if code := deleteSanction(sanctions[1].ID); code != http.StatusNotFound {
+		t.Errorf("deleting a sanction of an archived season = %d, want 404", code)
+	}
+}
+
+func TestForfeitsAndBans(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	week1, err := s.repo.GetMatchesByWeek(ctx, 1, season.ID)
+	if err != nil || len(week1) < 2 {
+		t.Fatalf("GetMatchesByWeek = %+v, %v, want two matches", week1, err)
+	}
+	forfeited, banned := week1[0], week1[1]
+	score := func(matchID int64) string {
+		result, err := s.repo.GetMatchResult(ctx, matchID)
+		if err != nil {
+			t.Fatalf("GetMatchResult(%d): %v", matchID, err)
+		}
+		return fmt.Sprintf("%d-%d", result.HomeScore, result.GuestScore)
+	}
+
+	// A forfeit needs a match of the team, a ban weeks of the season
+	for _, form := range []url.Values{
+		{"kind": {"forfeit"}, "team_id": {fmt.Sprint(banned.HomeID)}, "match_id": {fmt.Sprint(forfeited.ID)}, "reason": {"Crowd trouble"}},
+		{"kind": {"ban"}, "team_id": {fmt.Sprint(banned.HomeID)}, "first_week": {"2"}, "last_week": {"1"}, "reason": {"Crowd trouble"}},
+		{"kind": {"ban"}, "team_id": {fmt.Sprint(banned.HomeID)}, "first_week": {"1"}, "last_week": {"99"}, "reason": {"Crowd trouble"}},
+	} {
+		if w := s.post("/sanctions", form); w.Code != http.StatusBadRequest {
+			t.Errorf("sanctioning %v = %d, want 400", form, w.Code)
+		}
+	}
+
+	// A match forfeited before it is played is awarded 3-0 to the opponent
+	s.action("/sanctions", url.Values{"kind": {"forfeit"}, "team_id": {fmt.Sprint(forfeited.HomeID)}, "match_id": {fmt.Sprint(forfeited.ID)}, "reason": {"Fielded an ineligible player"}})
+	s.action("/play-week", nil)
+	s.assertStandingsInvariants(1)
+	if got := score(forfeited.ID); got != "0-3" {
+		t.Errorf("forfeited match = %s, want 0-3", got)
+	}
+	simulated := score(banned.ID)
+	s.action("/next-week", nil)
+	s.action("/play-week", nil)
+
+	// A ban forfeits the played matches of its weeks at once
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/sanctions", strings.NewReader(fmt.Sprintf(`{"kind":"ban","team_id":%d,"first_week":1,"last_week":2,"reason":"Crowd trouble"}`, banned.GuestID)))
+	req.Header.Set("Content-Type", "application/json")
+	w := s.do(req)
+	var body struct{ Data api.Sanction }
+	if w.Code != http.StatusCreated || json.Unmarshal(w.Body.Bytes(), &body) != nil {
+		t.Fatalf("POST /api/v1/sanctions = %d %s, want 201", w.Code, w.Body)
+	}
+	if got := score(banned.ID); got != "3-0" {
+		t.Errorf("match of the banned team = %s, want 3-0", got)
+	}
+	for week := int64(1); week <= 2; week++ {
+		matches, err := s.repo.GetMatchesByWeek(ctx, week, season.ID)
+		if err != nil {
+			t.Fatalf("GetMatchesByWeek: %v", err)
+		}
+		for _, match := range matches {
+			if match.HomeID != banned.GuestID && match.GuestID != banned.GuestID {
+				continue
+			}
+			if got := score(match.ID); (match.HomeID == banned.GuestID && got != "0-3") || (match.GuestID == banned.GuestID && got != "3-0") {
+				t.Errorf("week %d match of the banned team = %s, want it forfeited", week, got)
+			}
+		}
+	}
+	s.assertStandingsInvariants(2)
+	for _, row := range s.standings() {
+		if row.Team.ID == banned.GuestID && (len(row.Notes) != 1 || row.Notes[0] != "Banned from the fixtures of weeks 1 to 2: Crowd trouble") {
+			t.Errorf("notes of %s = %q, want the ban", row.Team.Name, row.Notes)
+		}
+	}
+	if page := s.get("/sanctions"); !strings.Contains(page, "Forfeited the week 1 match") || !strings.Contains(page, "Banned from the fixtures of weeks 1 to 2") {
+		t.Error("sanctions page does not list the forfeit and the ban")
+	}
+
+	// Lifting the ban plays its matches again as if it had never been taken
+	s.action(fmt.Sprintf("/sanctions/%d/delete", body.Data.ID), nil)
+	if got := score(banned.ID); got != simulated {
+		t.Errorf("match after lifting the ban = %s, want %s", got, simulated)
+	}
+	s.assertStandingsInvariants(2)
+	if w := s.post(fmt.Sprintf("/sanctions/%d/delete", body.Data.ID), nil); w.Code != http.StatusNotFound {
+		t.Errorf("lifting a lifted ban = %d, want 404", w.Code)
+	}
+}
+
+func TestDeductionEndpoints(t *testing.T) {
+	s := newTestServer(t)
+	s.get("/")
+	ctx := context.Background()
+	season, err := s.repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	week1, err := s.repo.GetMatchesByWeek(ctx, 1, season.ID)
+	if err != nil || len(week1) == 0 {
+		t.Fatalf("GetMatchesByWeek = %+v, %v, want a match", week1, err)
+	}
+	team := week1[0].HomeID
+	addDeduction := func(format, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/competitions/"+format+"/deductions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return s.do(req)
+	}
+	deleteDeduction := func(id int64) int {
+		return s.do(httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/deductions/%d", id), nil)).Code
+	}
+
+	// The v1 deduction endpoints take and lift sanctions of kind deduction
+	if w := addDeduction("league", fmt.Sprintf(`{"team_id":%d,"points":0,"reason":"Financial breach"}`, team)); w.Code != http.StatusBadRequest {
+		t.Errorf("deducting no points = %d, want 400", w.Code)
+	}
+	if w := addDeduction("tournament", fmt.Sprintf(`{"team_id":%d,"points":3,"reason":"Financial breach"}`, team)); w.Code != http.StatusNotFound {
+		t.Errorf("deducting from an undrawn tournament = %d, want 404", w.Code)
+	}
+	w := addDeduction("league", fmt.Sprintf(`{"team_id":%d,"points":3,"reason":"Financial breach"}`, team))
+	var body struct{ Data api.Competition }
+	if w.Code != http.StatusCreated || json.Unmarshal(w.Body.Bytes(), &body) != nil {
+		t.Fatalf("POST /api/v1/competitions/league/deductions = %d %s, want 201", w.Code, w.Body)
+	}
+	deductions := body.Data.Deductions
+	if body.Data.Format != "league" || len(deductions) != 1 || deductions[0].Team.ID != team || deductions[0].Points != 3 {
+		t.Fatalf("league = %+v, want the deduction", body.Data)
+	}
+	if sanctions := s.sanctions(season.ID); len(sanctions) != 1 || sanctions[0].ID != deductions[0].ID || sanctions[0].Kind != "deduction" {
+		t.Errorf("sanctions = %+v, want the deduction", sanctions)
+	}
+	if league := s.competitions(season.ID)[0]; len(league.Deductions) != 1 {
+		t.Errorf("league = %+v, want the deduction", league)
+	}
+
+	// Other sanctions are not deductions
+	s.action("/sanctions", url.Values{"kind": {"forfeit"}, "team_id": {fmt.Sprint(team)}, "match_id": {fmt.Sprint(week1[0].ID)}, "reason": {"Crowd trouble"}})
+	forfeit := s.sanctions(season.ID)[1]
+	if code := deleteDeduction(forfeit.ID); code != http.StatusNotFound {
+		t.Errorf("deleting a forfeit as a deduction = %d, want 404", code)
+	}
+	if code := deleteDeduction(deductions[0].ID); code != http.StatusNoContent {
+		t.Fatalf("DELETE /api/v1/deductions/%d = %d, want 204", deductions[0].ID, code)
+	}
+	if sanctions := s.sanctions(season.ID); len(sanctions) != 1 || sanctions[0].ID != forfeit.ID {
+		t.Errorf("sanctions = %+v, want the forfeit only", sanctions)
+	}
+}
+
+// TestGoldenPages compares the rendered pages with the files in
+// testdata/golden. Run `go test ./handlers -update` to accept changes.
+func TestGoldenPages(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup []string // actions posted before rendering
+		path  string
+	}{
+		{name: "home_week1", path: "/"},
+		{name: "home_midseason", setup: []string{"/play-week", "/next-week", "/play-week"}, path: "/"},
+		{name: "home_complete", setup: []string{"/play-all"}, path: "/"},
+		{name: "standings_midseason", setup: []string{"/play-week", "/next-week", "/play-week"}, path: "/standings"},
+		{name: "matches_midseason", setup: []string{"/play-week", "/next-week", "/play-week"}, path: "/matches"},
+		{name: "teams", path: "/teams"},
+		{name: "team_new", path: "/teams/new"},
+		{name: "team_edit", path: "/teams/1/edit"},
+		{name: "team_midseason", setup: []string{"/play-week", "/next-week", "/play-week"}, path: "/teams/1"},
+		{name: "head_to_head", setup: []string{"/play-all", "/start-new-season", "/play-all"}, path: "/teams/1/vs/2"},
+		{name: "seasons", setup: []string{"/play-all", "/start-new-season"}, path: "/seasons"},
+		{name: "season_archive", setup: []string{"/play-all", "/start-new-season"}, path: "/seasons/1"},
+		{name: "standings_archive", setup: []string{"/play-all", "/start-new-season"}, path: "/standings?season=1"},
+		{name: "cup_midseason", setup: []string{"/cup", "/play-week", "/next-week", "/play-week"}, path: "/cup"},
+		{name: "tournament_complete", setup: []string{"/tournament", "/play-all"}, path: "/tournament"},
+		{name: "season_rollback", setup: []string{"/play-week", "/next-week", "/play-week"}, path: "/seasons/1/rollback?week=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.action("/generate-fixtures", nil)
+			for _, path := range tt.setup {
+				// Only /start-new-season reads the seed, which draws lots
+				// between teams level on everything, only /cup the draw and
+				// only /tournament the format
+				s.action(path, url.Values{"seed": {"7"}, "draw": {"seeded"}, "groups": {"2"}, "qualifiers": {"1"}, "away_goals": {"true"}})
+			}
+			assertGolden(t, tt.name, s.get(tt.path))
+		})
+	}
+}
+
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".html")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file: %v (run with -update to create it)", err)
+	}
+	if !bytes.Equal([]byte(got), want) {
+		t.Errorf("%s differs from %s (run with -update to accept)\n%s", name, path, firstDifference(string(want), got))
+	}
+}
+
+// firstDifference shows both pages around the first byte where they differ,
+// as templ renders most pages on a single line.
+func firstDifference(want, got string) string {
+	i := 0
+	for i < len(want) && i < len(got) && want[i] == got[i] {
+		i++
+	}
+	excerpt := func(page string) string {
+		return page[max(0, i-80):min(len(page), i+80)]
+	}
+	return fmt.Sprintf("at byte %d:\n- %s\n+ %s", i, excerpt(want), excerpt(got))
+}
+
+func sameTable(a, b []api.Standing) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !reflect.DeepEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
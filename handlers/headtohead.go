@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/templates"
+)
+
+func handleHeadToHead(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		teamID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+		opponentID, err := strconv.ParseInt(c.Param("opponent_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid opponent ID"})
+			return
+		}
+
+		h2h, err := league.CompareTeams(reqCtx, repo, teamID, opponentID)
+		switch {
+		case errors.Is(err, league.ErrSameTeam):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A team cannot be compared with itself"})
+			return
+		case errors.Is(err, league.ErrTeamNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		case err != nil:
+			log.Printf("Failed to compare teams %d and %d: %v", teamID, opponentID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare teams"})
+			return
+		}
+
+		teams, err := repo.ListTeams(reqCtx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams"})
+			return
+		}
+
+		data := templates.HeadToHeadPageData{
+			Team:     h2h.Team,
+			Opponent: h2h.Opponent,
+			Teams:    teams,
+			Records: []templates.HeadToHeadRecord{
+				headToHeadRecord("All time", 0, h2h.AllTime),
+			},
+			BiggestWin:         headToHeadMeeting(h2h, h2h.BiggestWin),
+			OpponentBiggestWin: headToHeadMeeting(h2h, h2h.OpponentBiggestWin),
+		}
+		for i := len(h2h.Seasons) - 1; i >= 0; i-- {
+			season := h2h.Seasons[i]
+			label := fmt.Sprintf("Season %d", season.SeasonYear)
+			data.Records = append(data.Records, headToHeadRecord(label, season.SeasonID, season.Record))
+		}
+		for i := range h2h.LastMeetings {
+			data.LastMeetings = append(data.LastMeetings, *headToHeadMeeting(h2h, &h2h.LastMeetings[i]))
+		}
+
+		c.Status(http.StatusOK)
+		templates.HeadToHead(data).Render(reqCtx, c.Writer)
+	}
+}
+
+// handleCompareTeams sends the team picker's choice to its head-to-head page
+func handleCompareTeams() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teamID, err := strconv.ParseInt(c.Query("team"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+		opponentID, err := strconv.ParseInt(c.Query("opponent"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid opponent ID"})
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, fmt.Sprintf("/teams/%d/vs/%d", teamID, opponentID))
+	}
+}
+
+func headToHeadRecord(label string, seasonID int64, record league.Record) templates.HeadToHeadRecord {
+	return templates.HeadToHeadRecord{
+		Label:        label,
+		SeasonID:     seasonID,
+		Played:       record.Played,
+		Wins:         record.Wins,
+		Draws:        record.Draws,
+		Losses:       record.Losses,
+		GoalsFor:     record.GoalsFor,
+		GoalsAgainst: record.GoalsAgainst,
+	}
+}
+
+// headToHeadMeeting names the teams of a meeting for the templates, keeping
+// nil for a missing one
+func headToHeadMeeting(h2h league.HeadToHead, m *sqlc.ListHeadToHeadMatchesRow) *templates.HeadToHeadMeeting {
+	if m == nil {
+		return nil
+	}
+	names := map[int64]string{h2h.Team.ID: h2h.Team.Name, h2h.Opponent.ID: h2h.Opponent.Name}
+	return &templates.HeadToHeadMeeting{
+		SeasonYear: m.SeasonYear,
+		Week:       m.Week,
+		Match: templates.MatchDisplay{
+			HomeTeamName:  names[m.HomeID],
+			GuestTeamName: names[m.GuestID],
+			HomeScore:     m.HomeScore,
+			GuestScore:    m.GuestScore,
+		},
+	}
+}
@@ -4,43 +4,30 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
-	"sort"
 
 	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/prediction"
 	"github.com/orhosko/go-backend/repository"
-	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
 	"github.com/orhosko/go-backend/templates"
 )
 
 // RegisterHomeRoutes registers all home related routes
-func RegisterHomeRoutes(router *gin.Engine, repo repository.Repository) {
-	router.GET("/", handleHome(repo))
+func RegisterHomeRoutes(router *gin.Engine, repo repository.Repository, predictor *prediction.MonteCarloPredictor) {
+	router.GET("/", handleHome(repo, predictor))
 }
 
-func handleHome(repo repository.Repository) gin.HandlerFunc {
+func handleHome(repo repository.Repository, predictor *prediction.MonteCarloPredictor) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		reqCtx := c.Request.Context()
 
-		// Get current season
-		currentSeason, err := repo.GetCurrentSeason(reqCtx)
+		// Start the initial season and its fixtures on the first visit
+		currentSeason, err := league.EnsureFixtures(reqCtx, repo)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				// If no season exists, create one starting from 2025
-				currentSeason, err = repo.CreateNewSeason(reqCtx, 2025)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create initial season"})
-					return
-				}
-				// Set it as the current season
-				err = repo.SetCurrentSeason(reqCtx, currentSeason.ID)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set current season"})
-					return
-				}
-			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
-				return
-			}
+			log.Printf("Failed to generate fixtures: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate fixtures"})
+			return
 		}
 
 		// Get current week from the database, default to 1 if not set
@@ -49,80 +36,16 @@ func handleHome(repo repository.Repository) gin.HandlerFunc {
 			currentWeek = 1 // Default to week 1 if not set
 		}
 
-		// Check if fixtures exist for the current week
-		matches, err := repo.GetMatchesByWeek(reqCtx, int64(currentWeek), currentSeason.ID)
-		if err == sql.ErrNoRows || len(matches) == 0 {
-			// No fixtures exist, generate them
-			err = generateRoundRobinFixtures(repo, reqCtx)
-			if err != nil {
-				log.Printf("Failed to generate fixtures: %v", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate fixtures"})
-				return
-			}
-		} else if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check fixtures"})
-			return
-		}
-
-		// Get team standings
-		teams, err := repo.ListTeams(reqCtx)
+		// Compute the league table from the played matches
+		divisions, err := buildDivisionTables(reqCtx, repo, currentSeason)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch standings"})
 			return
 		}
 
-		// Get standings for each team
-		var leagueTable []templates.TeamStanding
-		for _, team := range teams {
-			standing, err := repo.GetStanding(reqCtx, team.ID, currentSeason.ID)
-			if err != nil {
-				if err == sql.ErrNoRows {
-					// If no standing exists, create one with zeros
-					err = repo.CreateStanding(reqCtx, sqlc.CreateStandingParams{
-						TeamID:   team.ID,
-						SeasonID: currentSeason.ID,
-						Points:   sql.NullInt64{Int64: 0, Valid: true},
-						Wins:     sql.NullInt64{Int64: 0, Valid: true},
-						Draws:    sql.NullInt64{Int64: 0, Valid: true},
-						Losses:   sql.NullInt64{Int64: 0, Valid: true},
-						GoalDiff: sql.NullInt64{Int64: 0, Valid: true},
-					})
-					if err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create standing"})
-						return
-					}
-					standing = sqlc.Standing{
-						TeamID:   team.ID,
-						SeasonID: currentSeason.ID,
-						Points:   sql.NullInt64{Int64: 0, Valid: true},
-						Wins:     sql.NullInt64{Int64: 0, Valid: true},
-						Draws:    sql.NullInt64{Int64: 0, Valid: true},
-						Losses:   sql.NullInt64{Int64: 0, Valid: true},
-						GoalDiff: sql.NullInt64{Int64: 0, Valid: true},
-					}
-				} else {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch standings"})
-					return
-				}
-			}
-
-			leagueTable = append(leagueTable, templates.TeamStanding{
-				Team:     team,
-				Standing: standing,
-			})
-		}
-
-		// Sort league table by points (descending), then goal difference
-		sort.Slice(leagueTable, func(i, j int) bool {
-			if leagueTable[i].Standing.Points.Int64 == leagueTable[j].Standing.Points.Int64 {
-				return leagueTable[i].Standing.GoalDiff.Int64 > leagueTable[j].Standing.GoalDiff.Int64
-			}
-			return leagueTable[i].Standing.Points.Int64 > leagueTable[j].Standing.Points.Int64
-		})
-
 		// Get match results for current week (if any exist)
 		var matchResults []templates.MatchDisplay
-		matches, err = repo.GetMatchesByWeek(reqCtx, int64(currentWeek), currentSeason.ID)
+		matches, err := repo.GetMatchesByWeek(reqCtx, int64(currentWeek), currentSeason.ID)
 		if err != nil && err != sql.ErrNoRows {
 			log.Printf("Failed to fetch matches: %v", err)
 		} else if err != sql.ErrNoRows {
@@ -157,8 +80,24 @@ func handleHome(repo repository.Repository) gin.HandlerFunc {
 			}
 		}
 
+		// Get the cup round of the current week (if the season has a cup)
+		cupRound, err := weekCupRound(reqCtx, repo, currentSeason, currentWeek)
+		if err != nil {
+			log.Printf("Failed to fetch cup: %v", err)
+		}
+		tournamentMatches, err := weekTournamentMatches(reqCtx, repo, currentSeason, currentWeek)
+		if err != nil {
+			log.Printf("Failed to fetch tournament: %v", err)
+		}
+
+		// Get the tie-breakers of the league
+		rules, err := standings.LeagueRules(reqCtx, repo, currentSeason.ID)
+		if err != nil {
+			log.Printf("Failed to fetch ranking rules: %v", err)
+		}
+
 		// Calculate championship predictions
-		predictions, err := calculateChampionshipPredictions(reqCtx, repo, currentSeason)
+		predictions, err := calculateChampionshipPredictions(reqCtx, repo, predictor, currentSeason)
 		if err != nil {
 			log.Printf("Failed to calculate predictions: %v", err)
 			predictions = []TeamPrediction{} // Use empty predictions if calculation fails
@@ -170,11 +109,17 @@ func handleHome(repo repository.Repository) gin.HandlerFunc {
 			templatePredictions = append(templatePredictions, templates.TeamPrediction{
 				TeamName:    pred.TeamName,
 				Probability: pred.Probability,
+				TopN:        pred.TopN,
+				Last:        pred.Last,
 			})
 		}
 
 		// Check if season is complete
-		totalWeeks := 2 * (len(teams) - 1)
+		totalWeeks, err := league.TotalWeeks(reqCtx, repo, currentSeason.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count weeks"})
+			return
+		}
 
 		// Get all unplayed matches for the season
 		unplayedMatchesForSeason, err := repo.GetUnplayedMatchesByWeek(reqCtx, int64(totalWeeks), currentSeason.ID)
@@ -193,12 +138,19 @@ func handleHome(repo repository.Repository) gin.HandlerFunc {
 
 		standingData := templates.StandingsPageData{
 			CurrentWeek:             currentWeek,
+			SeasonID:                currentSeason.ID,
 			CurrentYear:             int(currentSeason.Year),
-			LeagueTable:             leagueTable,
+			Seed:                    currentSeason.Seed,
+			PredictionTopN:          predictor.TopN,
+			RankingRules:            rules.Name,
+			RankingOptions:          rankingOptions(),
+			Divisions:               divisions,
 			MatchResults:            matchResults,
 			ChampionshipPredictions: templatePredictions,
 			Fixtures:                fixtures,
 			IsSeasonComplete:        isSeasonComplete,
+			Cup:                     cupRound,
+			Tournament:              tournamentMatches,
 		}
 
 		component := templates.Index(standingData)
@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
 	"github.com/orhosko/go-backend/repository"
 	"github.com/orhosko/go-backend/sqlc"
 	"github.com/orhosko/go-backend/templates"
@@ -39,91 +42,22 @@ func handleEditMatch(repo repository.Repository) gin.HandlerFunc {
 			return
 		}
 
-		reqCtx := c.Request.Context()
-
-		// Get current season
-		currentSeason, err := repo.GetCurrentSeason(reqCtx)
-		if err != nil {
-			c.String(http.StatusInternalServerError, "Failed to get current season")
-			return
-		}
-
-		// Get current week
-		currentWeek, err := repo.GetCurrentWeek(reqCtx, currentSeason.ID)
-		if err != nil {
-			c.String(http.StatusInternalServerError, "Failed to get current week")
-			return
-		}
-
-		// Search for the match in all weeks up to current week
-		var match sqlc.GetMatchesByWeekRow
-		matchFound := false
-
-		for week := 1; week <= currentWeek && !matchFound; week++ {
-			matches, err := repo.GetMatchesByWeek(reqCtx, int64(week), currentSeason.ID)
-			if err != nil {
-				c.String(http.StatusInternalServerError, "Failed to fetch matches")
-				return
-			}
-
-			for _, m := range matches {
-				if m.ID == matchID {
-					match = m
-					matchFound = true
-					break
-				}
-			}
-		}
-
-		if !matchFound {
+		err = league.EditResult(c.Request.Context(), repo, matchID, homeScore, guestScore)
+		var validationErr *league.ValidationError
+		switch {
+		case err == nil:
+			// Redirect back to matches page
+			c.Redirect(http.StatusSeeOther, "/matches")
+		case errors.As(err, &validationErr):
+			c.String(http.StatusBadRequest, validationErr.Error())
+		case errors.Is(err, league.ErrNoActiveSeason), errors.Is(err, league.ErrMatchNotFound):
 			c.String(http.StatusNotFound, "Match not found")
-			return
-		}
-
-		// Determine the winner
-		var winnerID sql.NullInt64
-		if homeScore > guestScore {
-			winnerID = sql.NullInt64{Int64: match.HomeID, Valid: true}
-		} else if guestScore > homeScore {
-			winnerID = sql.NullInt64{Int64: match.GuestID, Valid: true}
-		}
-
-		// Update the match result
-		err = repo.SaveResult(reqCtx, sqlc.SaveResultParams{
-			MatchID:    matchID,
-			HomeScore:  homeScore,
-			GuestScore: guestScore,
-			WinnerID:   winnerID,
-		})
-		if err != nil {
+		case errors.Is(err, league.ErrMatchForfeited):
+			c.String(http.StatusConflict, "Match is forfeited under a sanction and keeps its score")
+		default:
+			log.Printf("Failed to edit match %d: %v", matchID, err)
 			c.String(http.StatusInternalServerError, "Failed to update match result")
-			return
 		}
-
-		// Mark match as played if not already
-		if !match.Played.Bool {
-			err = repo.MarkMatchAsPlayed(reqCtx, matchID)
-			if err != nil {
-				c.String(http.StatusInternalServerError, "Failed to mark match as played")
-				return
-			}
-		}
-
-		// Update standings for both teams
-		err = recalculateTeamStanding(reqCtx, repo, currentSeason.ID, match.HomeID)
-		if err != nil {
-			c.String(http.StatusInternalServerError, "Failed to update home team standing")
-			return
-		}
-
-		err = recalculateTeamStanding(reqCtx, repo, currentSeason.ID, match.GuestID)
-		if err != nil {
-			c.String(http.StatusInternalServerError, "Failed to update guest team standing")
-			return
-		}
-
-		// Redirect back to matches page
-		c.Redirect(http.StatusSeeOther, "/matches")
 	}
 }
 
@@ -131,26 +65,9 @@ func handleMatches(repo repository.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		reqCtx := c.Request.Context()
 
-		// Get current season
-		currentSeason, err := repo.GetCurrentSeason(reqCtx)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				// If no season exists, create one starting from 2025
-				currentSeason, err = repo.CreateNewSeason(reqCtx, 2025)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create initial season"})
-					return
-				}
-				// Set it as the current season
-				err = repo.SetCurrentSeason(reqCtx, currentSeason.ID)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set current season"})
-					return
-				}
-			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
-				return
-			}
+		currentSeason, readOnly, ok := pageSeason(c, repo)
+		if !ok {
+			return
 		}
 
 		// Get current week
@@ -212,6 +129,7 @@ func handleMatches(repo repository.Repository) gin.HandlerFunc {
 			Matches:       matchesByWeek,
 			CurrentSeason: currentSeason,
 			CurrentWeek:   currentWeek,
+			ReadOnly:      readOnly,
 		})
 
 		c.Status(http.StatusOK)
@@ -0,0 +1,860 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/handlers/api"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/openapi"
+	"github.com/orhosko/go-backend/standings"
+)
+
+// RegisterOpenAPIRoutes registers the route serving the OpenAPI document
+func RegisterOpenAPIRoutes(router *gin.Engine) {
+	router.GET("/api/openapi.json", handleOpenAPI())
+}
+
+func handleOpenAPI() gin.HandlerFunc {
+	// The document only depends on the code, so build it once
+	spec := sync.OnceValue(OpenAPISpec)
+
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, spec())
+	}
+}
+
+// ErrorMessage is the JSON error returned by the page and form routes
+type ErrorMessage struct {
+	Error string `json:"error"`
+}
+
+// MessageResponse is the JSON confirmation returned by some form routes
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// RecalculateResponse is returned after the standings are recalculated
+type RecalculateResponse struct {
+	Message        string  `json:"message"`
+	DriftedTeamIDs []int64 `json:"drifted_team_ids"`
+}
+
+// SeasonForm is the form starting a new season
+type SeasonForm struct {
+	Seed  int64  `json:"seed,omitempty"`
+	Rules string `json:"rules,omitempty"`
+}
+
+// RollbackForm is the form rolling a season back to a week
+type RollbackForm struct {
+	Week  int    `json:"week"`
+	Token string `json:"token"`
+}
+
+// DivisionForm is the form creating or editing a division
+type DivisionForm struct {
+	Name             string `json:"name"`
+	RelegationPlaces int64  `json:"relegation_places"`
+	Playoff          bool   `json:"playoff,omitempty"`
+}
+
+// CupForm is the form drawing the current season's cup
+type CupForm struct {
+	Draw string `json:"draw"`
+}
+
+// TournamentForm is the form drawing the current season's tournament
+type TournamentForm struct {
+	Groups            int64 `json:"groups"`
+	Qualifiers        int64 `json:"qualifiers"`
+	AwayGoals         bool  `json:"away_goals,omitempty"`
+	SeparateDivisions bool  `json:"separate_divisions,omitempty"`
+}
+
+// CompetitionForm is the form changing the rules of a competition
+type CompetitionForm struct {
+	Legs        int64 `json:"legs"`
+	WinPoints   int64 `json:"win_points"`
+	DrawPoints  int64 `json:"draw_points"`
+	LossPoints  int64 `json:"loss_points"`
+	BonusGoals  int64 `json:"bonus_goals"`
+	BonusPoints int64 `json:"bonus_points"`
+}
+
+// SanctionForm is the form taking a sanction against a team. Points apply
+// to a deduction, match_id to a forfeit and the weeks to a ban.
+type SanctionForm struct {
+	Kind      string `json:"kind"`
+	Format    string `json:"format,omitempty"`
+	TeamID    int64  `json:"team_id"`
+	Points    int64  `json:"points,omitempty"`
+	MatchID   int64  `json:"match_id,omitempty"`
+	FirstWeek int64  `json:"first_week,omitempty"`
+	LastWeek  int64  `json:"last_week,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// MoveTeamForm is the form moving a team to another division
+type MoveTeamForm struct {
+	TeamID int64 `json:"team_id"`
+}
+
+// MatchScoreForm is the form correcting the score of a match, neither score
+// below 0
+type MatchScoreForm struct {
+	HomeScore  int64 `json:"home_score"`
+	GuestScore int64 `json:"guest_score"`
+}
+
+// TeamForm is the form creating or editing a team
+type TeamForm struct {
+	Name     string `json:"name"`
+	Strength int64  `json:"strength"`
+	Budget   int64  `json:"budget"`
+}
+
+// OpenAPISpec describes every route registered by NewRouter. Keep it in sync
+// when adding routes, the tests fail for any route missing here.
+func OpenAPISpec() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "Football league simulator",
+		Description: "Server-rendered pages, form actions and the JSON API of the league.",
+		Version:     "1.0.0",
+	})
+	s := specBuilder{doc: doc}
+
+	// Pages
+	doc.Add(http.MethodGet, "/", &openapi.Operation{
+		OperationID: "homePage",
+		Summary:     "League table, results, fixtures and predictions of the current season",
+		Tags:        []string{"pages"},
+		Responses:   s.page(),
+	})
+	// Pages taking ?season= show that season read-only
+	seasonParam := s.query("season", "Season ID, defaults to the current season", &openapi.Schema{Type: "integer", Format: "int64"})
+	seasonPage := s.page()
+	seasonPage["400"] = s.json("Invalid season ID", doc.SchemaOf(ErrorMessage{}))
+	seasonPage["404"] = s.json("Season not found", doc.SchemaOf(ErrorMessage{}))
+	doc.Add(http.MethodGet, "/teams", &openapi.Operation{
+		OperationID: "teamsPage",
+		Summary:     "Teams and their standings",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{seasonParam},
+		Responses:   seasonPage,
+	})
+	doc.Add(http.MethodGet, "/teams/new", &openapi.Operation{
+		OperationID: "newTeamPage",
+		Summary:     "Form creating a team",
+		Tags:        []string{"pages"},
+		Responses:   s.page(),
+	})
+	editTeamPage := s.page()
+	editTeamPage["400"] = s.json("Invalid team ID", doc.SchemaOf(ErrorMessage{}))
+	editTeamPage["404"] = s.json("Team not found", doc.SchemaOf(ErrorMessage{}))
+	doc.Add(http.MethodGet, "/teams/:id/edit", &openapi.Operation{
+		OperationID: "editTeamPage",
+		Summary:     "Form editing a team",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID")},
+		Responses:   editTeamPage,
+	})
+	teamPage := s.page()
+	teamPage["400"] = s.json("Invalid team or season ID", doc.SchemaOf(ErrorMessage{}))
+	teamPage["404"] = s.json("Team or season not found", doc.SchemaOf(ErrorMessage{}))
+	doc.Add(http.MethodGet, "/teams/:id", &openapi.Operation{
+		OperationID: "teamPage",
+		Summary:     "A team's fixtures, results, form and position week by week in a season",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID"), seasonParam},
+		Responses:   teamPage,
+	})
+	headToHeadPage := s.page()
+	headToHeadPage["400"] = s.json("Invalid team IDs or the same team twice", doc.SchemaOf(ErrorMessage{}))
+	headToHeadPage["404"] = s.json("Team not found", doc.SchemaOf(ErrorMessage{}))
+	doc.Add(http.MethodGet, "/teams/:id/vs/:opponent_id", &openapi.Operation{
+		OperationID: "headToHeadPage",
+		Summary:     "All-time and per-season head-to-head record of two teams",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID"), s.pathID("opponent_id", "Opponent team ID")},
+		Responses:   headToHeadPage,
+	})
+	teamIDSchema := &openapi.Schema{Type: "integer", Format: "int64"}
+	doc.Add(http.MethodGet, "/teams/compare", &openapi.Operation{
+		OperationID: "compareTeams",
+		Summary:     "Redirect the team picker to the head-to-head page of the chosen teams",
+		Tags:        []string{"pages"},
+		Parameters: []openapi.Parameter{
+			s.query("team", "Team ID", teamIDSchema),
+			s.query("opponent", "Opponent team ID", teamIDSchema),
+		},
+		Responses: map[string]openapi.Response{
+			"303": {Description: "Redirect to the head-to-head page"},
+			"400": s.json("Invalid team ID", doc.SchemaOf(ErrorMessage{})),
+		},
+	})
+	doc.Add(http.MethodGet, "/matches", &openapi.Operation{
+		OperationID: "matchesPage",
+		Summary:     "Matches of the current season",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{seasonParam},
+		Responses:   seasonPage,
+	})
+	doc.Add(http.MethodGet, "/standings", &openapi.Operation{
+		OperationID: "standingsPage",
+		Summary:     "League table of the current season",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{seasonParam},
+		Responses:   seasonPage,
+	})
+	doc.Add(http.MethodGet, "/seasons", &openapi.Operation{
+		OperationID: "seasonsPage",
+		Summary:     "Every season with its champion, runner-up and table",
+		Tags:        []string{"pages"},
+		Responses:   s.page(),
+	})
+	doc.Add(http.MethodGet, "/seasons/:id", &openapi.Operation{
+		OperationID: "seasonPage",
+		Summary:     "Table and matches of a season",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Season ID")},
+		Responses:   seasonPage,
+	})
+	doc.Add(http.MethodGet, "/divisions", &openapi.Operation{
+		OperationID: "divisionsPage",
+		Summary:     "Divisions of the current season with their teams and relegation rules",
+		Tags:        []string{"pages"},
+		Responses:   s.page(),
+	})
+	doc.Add(http.MethodGet, "/cup", &openapi.Operation{
+		OperationID: "cupPage",
+		Summary:     "Knockout bracket of a season's cup",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{seasonParam},
+		Responses:   s.page(),
+	})
+	doc.Add(http.MethodGet, "/competitions", &openapi.Operation{
+		OperationID: "competitionsPage",
+		Summary:     "Competitions of a season and the rules they are played by",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{seasonParam},
+		Responses:   s.page(),
+	})
+	doc.Add(http.MethodGet, "/sanctions", &openapi.Operation{
+		OperationID: "sanctionsPage",
+		Summary:     "Ledger of the point deductions, forfeits and fixture bans of a season",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{seasonParam},
+		Responses:   s.page(),
+	})
+	doc.Add(http.MethodGet, "/tournament", &openapi.Operation{
+		OperationID: "tournamentPage",
+		Summary:     "Groups and two-legged knockout of a season's tournament",
+		Tags:        []string{"pages"},
+		Parameters:  []openapi.Parameter{seasonParam},
+		Responses:   s.page(),
+	})
+	doc.Add(http.MethodGet, "/seasons/:id/rollback", &openapi.Operation{
+		OperationID: "rollbackSeasonPage",
+		Summary:     "Confirm rolling a season back to a week, with the token the rollback needs",
+		Tags:        []string{"pages"},
+		Parameters: []openapi.Parameter{
+			s.pathID("id", "Season ID"),
+			s.query("week", "First week whose results are undone, defaults to 1", &openapi.Schema{Type: "integer"}),
+		},
+		Responses: seasonPage,
+	})
+
+	// Form actions
+	doc.Add(http.MethodPost, "/generate-fixtures", &openapi.Operation{
+		OperationID: "generateFixtures",
+		Summary:     "Generate the fixtures of the current season",
+		Tags:        []string{"actions"},
+		Responses:   s.redirect(),
+	})
+	doc.Add(http.MethodPost, "/play-week", &openapi.Operation{
+		OperationID: "playWeek",
+		Summary:     "Play the unplayed matches of the current week",
+		Tags:        []string{"actions"},
+		Responses:   s.redirect(http.StatusBadRequest),
+	})
+	nextWeek := s.redirect(http.StatusBadRequest)
+	nextWeek["200"] = s.json("Season is complete", doc.SchemaOf(MessageResponse{}))
+	doc.Add(http.MethodPost, "/next-week", &openapi.Operation{
+		OperationID: "nextWeek",
+		Summary:     "Move to the next week once every match of the current week is played",
+		Tags:        []string{"actions"},
+		Responses:   nextWeek,
+	})
+	doc.Add(http.MethodPost, "/play-all", &openapi.Operation{
+		OperationID: "playAll",
+		Summary:     "Play every remaining week of the current season",
+		Tags:        []string{"actions"},
+		Responses:   s.redirect(http.StatusBadRequest),
+	})
+	doc.Add(http.MethodPost, "/seasons/:id/rollback", &openapi.Operation{
+		OperationID: "rollbackSeason",
+		Summary:     "Undo the results of a season from a week onward and make it the current season",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Season ID")},
+		RequestBody: s.form(RollbackForm{}, true),
+		Responses:   s.redirect(http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/start-new-season", &openapi.Operation{
+		OperationID: "startNewSeason",
+		Summary:     "Start the season following the current one",
+		Tags:        []string{"actions"},
+		RequestBody: s.form(SeasonForm{}, false),
+		Responses:   s.redirect(http.StatusBadRequest),
+	})
+	doc.Add(http.MethodPost, "/teams", &openapi.Operation{
+		OperationID: "createTeam",
+		Summary:     "Create a team, only before the first match or after the last match of the current season",
+		Tags:        []string{"actions"},
+		RequestBody: s.form(TeamForm{}, true),
+		Responses:   s.teamForm(),
+	})
+	updateTeam := s.teamForm()
+	updateTeam["404"] = s.json("Team not found", doc.SchemaOf(ErrorMessage{}))
+	doc.Add(http.MethodPost, "/teams/:id", &openapi.Operation{
+		OperationID: "updateTeam",
+		Summary:     "Update the name, strength and budget of a team",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID")},
+		RequestBody: s.form(TeamForm{}, true),
+		Responses:   updateTeam,
+	})
+	doc.Add(http.MethodPost, "/teams/:id/delete", &openapi.Operation{
+		OperationID: "deleteTeam",
+		Summary:     "Delete a team without played matches, only outside of a season",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID")},
+		Responses: map[string]openapi.Response{
+			"303": {Description: "Redirect to the teams page"},
+			"400": s.json("Invalid team ID", doc.SchemaOf(ErrorMessage{})),
+			"404": s.json("Team not found", doc.SchemaOf(ErrorMessage{})),
+			"409": s.json("Team has matches or a season is in progress", doc.SchemaOf(ErrorMessage{})),
+			"500": s.json("Internal error", doc.SchemaOf(ErrorMessage{})),
+		},
+	})
+	divisionAction := func(errorStatuses ...int) map[string]openapi.Response {
+		responses := s.redirect(errorStatuses...)
+		responses["303"] = openapi.Response{Description: "Redirect to the divisions page"}
+		return responses
+	}
+	doc.Add(http.MethodPost, "/divisions", &openapi.Operation{
+		OperationID: "createDivision",
+		Summary:     "Add a division below the others, only before the current season's first match",
+		Tags:        []string{"actions"},
+		RequestBody: s.form(DivisionForm{}, true),
+		Responses:   divisionAction(http.StatusBadRequest, http.StatusConflict),
+	})
+	doc.Add(http.MethodPost, "/divisions/:id", &openapi.Operation{
+		OperationID: "updateDivision",
+		Summary:     "Rename a division or change its relegation places and playoff",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Division ID")},
+		RequestBody: s.form(DivisionForm{}, true),
+		Responses:   divisionAction(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+	doc.Add(http.MethodPost, "/divisions/:id/delete", &openapi.Operation{
+		OperationID: "deleteDivision",
+		Summary:     "Delete an empty division, moving the divisions below it up a tier",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Division ID")},
+		Responses:   divisionAction(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+	doc.Add(http.MethodPost, "/divisions/:id/teams", &openapi.Operation{
+		OperationID: "moveTeam",
+		Summary:     "Move a team to a division and rebuild the fixtures",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Division ID")},
+		RequestBody: s.form(MoveTeamForm{}, true),
+		Responses:   divisionAction(http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+	formats := []string{league.FormatLeague, league.FormatCup, league.FormatTournament}
+	format := openapi.Parameter{
+		Name:        "format",
+		In:          "path",
+		Description: "Competition format",
+		Required:    true,
+		Schema:      &openapi.Schema{Type: "string", Enum: formats},
+	}
+	doc.Add(http.MethodPost, "/competitions/:format", &openapi.Operation{
+		OperationID: "updateCompetition",
+		Summary:     "Change the legs and points of a competition, only before the current season's first match",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{format},
+		RequestBody: s.form(CompetitionForm{}, true),
+		Responses: map[string]openapi.Response{
+			"303": {Description: "Redirect to the competitions page"},
+			"400": s.json("Invalid legs or points", doc.SchemaOf(ErrorMessage{})),
+			"404": s.json("The season has no such competition", doc.SchemaOf(ErrorMessage{})),
+			"409": s.json("No active season or the season has started", doc.SchemaOf(ErrorMessage{})),
+			"500": s.json("Internal error", doc.SchemaOf(ErrorMessage{})),
+		},
+	})
+	doc.Add(http.MethodPost, "/sanctions", &openapi.Operation{
+		OperationID: "addSanction",
+		Summary:     "Deduct points from a team, forfeit one of its league matches or ban it from its league fixtures in the current season",
+		Tags:        []string{"actions"},
+		RequestBody: s.form(SanctionForm{}, true),
+		Responses: map[string]openapi.Response{
+			"303": {Description: "Redirect to the sanctions page"},
+			"400": s.json("Invalid kind, team, points, match, weeks or reason", doc.SchemaOf(ErrorMessage{})),
+			"404": s.json("The season has no such competition", doc.SchemaOf(ErrorMessage{})),
+			"409": s.json("No active season", doc.SchemaOf(ErrorMessage{})),
+			"500": s.json("Internal error", doc.SchemaOf(ErrorMessage{})),
+		},
+	})
+	doc.Add(http.MethodPost, "/sanctions/:id/delete", &openapi.Operation{
+		OperationID: "deleteSanction",
+		Summary:     "Lift a sanction of the current season, playing again the matches it forfeited",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Sanction ID")},
+		Responses: map[string]openapi.Response{
+			"303": {Description: "Redirect to the sanctions page"},
+			"400": s.json("Invalid sanction ID", doc.SchemaOf(ErrorMessage{})),
+			"404": s.json("Sanction not found in the current season", doc.SchemaOf(ErrorMessage{})),
+			"409": s.json("No active season", doc.SchemaOf(ErrorMessage{})),
+			"500": s.json("Internal error", doc.SchemaOf(ErrorMessage{})),
+		},
+	})
+	doc.Add(http.MethodPost, "/cup", &openapi.Operation{
+		OperationID: "drawCup",
+		Summary:     "Draw or redraw the current season's cup, only before its first match",
+		Tags:        []string{"actions"},
+		RequestBody: s.form(CupForm{}, true),
+		Responses: map[string]openapi.Response{
+			"303": {Description: "Redirect to the cup page"},
+			"400": s.json("Unknown draw", doc.SchemaOf(ErrorMessage{})),
+			"409": s.json("No active season, the season has started or the cup does not fit it", doc.SchemaOf(ErrorMessage{})),
+			"500": s.json("Internal error", doc.SchemaOf(ErrorMessage{})),
+		},
+	})
+	doc.Add(http.MethodPost, "/tournament", &openapi.Operation{
+		OperationID: "drawTournament",
+		Summary:     "Draw or redraw the current season's tournament, only before its first match",
+		Tags:        []string{"actions"},
+		RequestBody: s.form(TournamentForm{}, true),
+		Responses: map[string]openapi.Response{
+			"303": {Description: "Redirect to the tournament page"},
+			"400": s.json("Invalid format", doc.SchemaOf(ErrorMessage{})),
+			"409": s.json("No active season, the season has started or the tournament cannot be drawn in it", doc.SchemaOf(ErrorMessage{})),
+			"500": s.json("Internal error", doc.SchemaOf(ErrorMessage{})),
+		},
+	})
+	doc.Add(http.MethodPost, "/matches/:id/edit", &openapi.Operation{
+		OperationID: "editMatch",
+		Summary:     "Correct the score of a match",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Match ID")},
+		RequestBody: s.form(MatchScoreForm{}, true),
+		Responses: map[string]openapi.Response{
+			"303": {Description: "Redirect to the matches page"},
+			"400": s.text("Invalid match ID or score"),
+			"404": s.text("Match not found"),
+			"409": s.text("Match is forfeited under a sanction"),
+			"500": s.text("Internal error"),
+		},
+	})
+	doc.Add(http.MethodPost, "/standings/recalculate", &openapi.Operation{
+		OperationID: "recalculateStandings",
+		Summary:     "Rebuild the cached standings from the match results",
+		Tags:        []string{"actions"},
+		Parameters:  []openapi.Parameter{s.query("seasonId", "Season ID, the current season by default", &openapi.Schema{Type: "integer", Format: "int64"})},
+		Responses: map[string]openapi.Response{
+			"200": s.json("Teams whose cached standing had drifted", doc.SchemaOf(RecalculateResponse{})),
+			"400": s.json("Invalid season ID", doc.SchemaOf(ErrorMessage{})),
+			"500": s.json("Internal error", doc.SchemaOf(ErrorMessage{})),
+		},
+	})
+	doc.Add(http.MethodPost, "/standings/team/:teamId", &openapi.Operation{
+		OperationID: "refreshTeamStanding",
+		Summary:     "Rebuild the cached standing of a team",
+		Tags:        []string{"actions"},
+		Parameters: []openapi.Parameter{
+			s.pathID("teamId", "Team ID"),
+			s.query("seasonId", "Season ID, the current season by default", &openapi.Schema{Type: "integer", Format: "int64"}),
+		},
+		Responses: map[string]openapi.Response{
+			"200": s.json("Standing updated", doc.SchemaOf(MessageResponse{})),
+			"400": s.json("Invalid team or season ID", doc.SchemaOf(ErrorMessage{})),
+			"404": s.json("Team not found", doc.SchemaOf(ErrorMessage{})),
+			"500": s.json("Internal error", doc.SchemaOf(ErrorMessage{})),
+		},
+	})
+
+	// JSON API
+	doc.Add(http.MethodGet, "/api/openapi.json", &openapi.Operation{
+		OperationID: "openapi",
+		Summary:     "This document",
+		Tags:        []string{"api"},
+		Responses: map[string]openapi.Response{
+			"200": s.json("OpenAPI document", &openapi.Schema{Type: "object"}),
+		},
+	})
+
+	seasonID := s.query("season_id", "Season ID, the current season by default", &openapi.Schema{Type: "integer", Format: "int64"})
+	week := s.query("week", "Only matches of this week", &openapi.Schema{Type: "integer", Format: "int64"})
+
+	doc.Add(http.MethodGet, "/api/v1/seasons", &openapi.Operation{
+		OperationID: "listSeasons",
+		Summary:     "List every season",
+		Tags:        []string{"seasons"},
+		Responses:   s.api(http.StatusOK, []api.Season{}),
+	})
+	doc.Add(http.MethodPost, "/api/v1/seasons", &openapi.Operation{
+		OperationID: "createSeason",
+		Summary:     "Start the season following the current one",
+		Tags:        []string{"seasons"},
+		RequestBody: s.body(api.CreateSeasonRequest{}),
+		Responses:   s.api(http.StatusCreated, api.Season{}, http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/seasons/current", &openapi.Operation{
+		OperationID: "getCurrentSeason",
+		Summary:     "Get the current season",
+		Tags:        []string{"seasons"},
+		Responses:   s.api(http.StatusOK, api.Season{}, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/seasons/:id", &openapi.Operation{
+		OperationID: "getSeason",
+		Summary:     "Get a season",
+		Tags:        []string{"seasons"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Season ID")},
+		Responses:   s.api(http.StatusOK, api.Season{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/teams", &openapi.Operation{
+		OperationID: "listTeams",
+		Summary:     "List every team",
+		Tags:        []string{"teams"},
+		Responses:   s.api(http.StatusOK, []api.Team{}),
+	})
+	doc.Add(http.MethodGet, "/api/v1/teams/:id", &openapi.Operation{
+		OperationID: "getTeam",
+		Summary:     "Get a team",
+		Tags:        []string{"teams"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID")},
+		Responses:   s.api(http.StatusOK, api.Team{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/teams/:id/season", &openapi.Operation{
+		OperationID: "getTeamSeason",
+		Summary:     "Follow a team through a season: fixtures, form, home and away records and its position after each week",
+		Tags:        []string{"teams"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID"), seasonID},
+		Responses:   s.api(http.StatusOK, api.TeamSeason{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/teams/:id/history", &openapi.Operation{
+		OperationID: "getTeamHistory",
+		Summary:     "List how a team's strength and budget changed after each season",
+		Tags:        []string{"teams"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID")},
+		Responses:   s.api(http.StatusOK, []api.TeamHistory{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/teams/:id/vs/:opponent_id", &openapi.Operation{
+		OperationID: "getHeadToHead",
+		Summary:     "Compare a team with an opponent over every season they met",
+		Tags:        []string{"teams"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID"), s.pathID("opponent_id", "Opponent team ID")},
+		Responses:   s.api(http.StatusOK, api.HeadToHead{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/teams", &openapi.Operation{
+		OperationID: "apiCreateTeam",
+		Summary:     "Create a team, only before the first match or after the last match of the current season",
+		Tags:        []string{"teams"},
+		RequestBody: s.body(api.TeamRequest{}),
+		Responses:   s.api(http.StatusCreated, api.Team{}, http.StatusBadRequest, http.StatusConflict),
+	})
+	doc.Add(http.MethodPut, "/api/v1/teams/:id", &openapi.Operation{
+		OperationID: "apiUpdateTeam",
+		Summary:     "Update the name, strength and budget of a team",
+		Tags:        []string{"teams"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID")},
+		RequestBody: s.body(api.TeamRequest{}),
+		Responses:   s.api(http.StatusOK, api.Team{}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/teams/:id", &openapi.Operation{
+		OperationID: "apiDeleteTeam",
+		Summary:     "Delete a team without played matches, only outside of a season",
+		Tags:        []string{"teams"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Team ID")},
+		Responses:   s.apiNoContent("Team deleted", http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+	doc.Add(http.MethodGet, "/api/v1/fixtures", &openapi.Operation{
+		OperationID: "listFixtures",
+		Summary:     "List the matches of a season",
+		Tags:        []string{"matches"},
+		Parameters:  []openapi.Parameter{seasonID, week},
+		Responses:   s.api(http.StatusOK, []api.Match{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/results", &openapi.Operation{
+		OperationID: "listResults",
+		Summary:     "List the played matches of a season",
+		Tags:        []string{"matches"},
+		Parameters:  []openapi.Parameter{seasonID, week},
+		Responses:   s.api(http.StatusOK, []api.Match{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/standings", &openapi.Operation{
+		OperationID: "getStandings",
+		Summary:     "Get the table of every division of a season, from the top tier down",
+		Tags:        []string{"standings"},
+		Parameters:  []openapi.Parameter{seasonID},
+		Responses:   s.api(http.StatusOK, []api.Standing{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/divisions", &openapi.Operation{
+		OperationID: "listDivisions",
+		Summary:     "List the divisions of a season with their teams",
+		Tags:        []string{"standings"},
+		Parameters:  []openapi.Parameter{seasonID},
+		Responses:   s.api(http.StatusOK, []api.Division{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/predictions", &openapi.Operation{
+		OperationID: "getPredictions",
+		Summary:     "Estimate the finishing probabilities of every team",
+		Tags:        []string{"standings"},
+		Parameters:  []openapi.Parameter{seasonID},
+		Responses:   s.api(http.StatusOK, api.Predictions{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/cup", &openapi.Operation{
+		OperationID: "getCup",
+		Summary:     "Get the knockout bracket of a season's cup",
+		Tags:        []string{"cup"},
+		Parameters:  []openapi.Parameter{seasonID},
+		Responses:   s.api(http.StatusOK, api.Cup{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/cup", &openapi.Operation{
+		OperationID: "apiDrawCup",
+		Summary:     "Draw or redraw the current season's cup, only before its first match",
+		Tags:        []string{"cup"},
+		RequestBody: s.body(api.DrawCupRequest{}),
+		Responses:   s.api(http.StatusCreated, api.Cup{}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+	doc.Add(http.MethodGet, "/api/v1/tournament", &openapi.Operation{
+		OperationID: "getTournament",
+		Summary:     "Get the groups and knockout of a season's tournament",
+		Tags:        []string{"tournament"},
+		Parameters:  []openapi.Parameter{seasonID},
+		Responses:   s.api(http.StatusOK, api.Tournament{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/tournament", &openapi.Operation{
+		OperationID: "apiDrawTournament",
+		Summary:     "Draw or redraw the current season's tournament, only before its first match",
+		Tags:        []string{"tournament"},
+		RequestBody: s.body(api.DrawTournamentRequest{}),
+		Responses:   s.api(http.StatusCreated, api.Tournament{}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+	doc.Add(http.MethodGet, "/api/v1/competitions", &openapi.Operation{
+		OperationID: "listCompetitions",
+		Summary:     "List the competitions of a season with their rules and teams, the league first",
+		Tags:        []string{"competitions"},
+		Parameters:  []openapi.Parameter{seasonID},
+		Responses:   s.api(http.StatusOK, []api.Competition{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPut, "/api/v1/competitions/:format", &openapi.Operation{
+		OperationID: "apiUpdateCompetition",
+		Summary:     "Change the legs and points of a competition, only before the current season's first match",
+		Tags:        []string{"competitions"},
+		Parameters:  []openapi.Parameter{format},
+		RequestBody: s.body(api.CompetitionRequest{}),
+		Responses:   s.api(http.StatusOK, api.Competition{}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	})
+	doc.Add(http.MethodPost, "/api/v1/competitions/:format/deductions", &openapi.Operation{
+		OperationID: "apiAddDeduction",
+		Summary:     "Deduct points from a team of a competition of the current season, like a sanction of kind deduction",
+		Tags:        []string{"competitions"},
+		Parameters:  []openapi.Parameter{format},
+		RequestBody: s.body(api.DeductionRequest{}),
+		Responses:   s.api(http.StatusCreated, api.Competition{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/deductions/:id", &openapi.Operation{
+		OperationID: "apiDeleteDeduction",
+		Summary:     "Give back the points of a deduction of the current season, like lifting the sanction",
+		Tags:        []string{"competitions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Sanction ID of the deduction")},
+		Responses:   s.apiNoContent("Deduction deleted", http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/sanctions", &openapi.Operation{
+		OperationID: "listSanctions",
+		Summary:     "List the sanctions of a season in the order they were taken",
+		Tags:        []string{"sanctions"},
+		Parameters:  []openapi.Parameter{seasonID},
+		Responses:   s.api(http.StatusOK, []api.Sanction{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/sanctions", &openapi.Operation{
+		OperationID: "apiAddSanction",
+		Summary:     "Take a sanction against a team at any time of the current season, awarding the played matches it forfeits 3-0",
+		Tags:        []string{"sanctions"},
+		RequestBody: s.body(api.SanctionRequest{}),
+		Responses:   s.api(http.StatusCreated, api.Sanction{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/sanctions/:id", &openapi.Operation{
+		OperationID: "apiDeleteSanction",
+		Summary:     "Lift a sanction of the current season, playing again the matches it forfeited",
+		Tags:        []string{"sanctions"},
+		Parameters:  []openapi.Parameter{s.pathID("id", "Sanction ID")},
+		Responses:   s.apiNoContent("Sanction lifted", http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/simulation/generate-fixtures", &openapi.Operation{
+		OperationID: "apiGenerateFixtures",
+		Summary:     "Generate the fixtures of the current season",
+		Tags:        []string{"simulation"},
+		Responses:   s.api(http.StatusOK, api.Season{}),
+	})
+	doc.Add(http.MethodPost, "/api/v1/simulation/play-week", &openapi.Operation{
+		OperationID: "apiPlayWeek",
+		Summary:     "Play the unplayed matches of the current week",
+		Tags:        []string{"simulation"},
+		Responses:   s.api(http.StatusOK, api.PlayWeekResult{}, http.StatusNotFound, http.StatusConflict),
+	})
+	doc.Add(http.MethodPost, "/api/v1/simulation/next-week", &openapi.Operation{
+		OperationID: "apiNextWeek",
+		Summary:     "Move to the next week once every match of the current week is played",
+		Tags:        []string{"simulation"},
+		Responses:   s.api(http.StatusOK, api.Season{}, http.StatusNotFound, http.StatusConflict),
+	})
+	doc.Add(http.MethodPost, "/api/v1/simulation/play-all", &openapi.Operation{
+		OperationID: "apiPlayAll",
+		Summary:     "Play every remaining week of the current season",
+		Tags:        []string{"simulation"},
+		Responses:   s.api(http.StatusOK, api.Season{}, http.StatusNotFound),
+	})
+
+	// Both ways of starting a season accept the ranking rule presets
+	var rules []string
+	for _, preset := range standings.Presets {
+		rules = append(rules, preset.Name)
+	}
+	doc.Components.Schemas["SeasonForm"].Properties["rules"].Enum = rules
+	doc.Components.Schemas["CreateSeasonRequest"].Properties["rules"].Enum = rules
+
+	draws := []string{league.CupDrawSeeded, league.CupDrawRandom}
+	doc.Components.Schemas["CupForm"].Properties["draw"].Enum = draws
+	doc.Components.Schemas["DrawCupRequest"].Properties["draw"].Enum = draws
+
+	doc.Components.Schemas["Competition"].Properties["format"].Enum = formats
+
+	return doc
+}
+
+// specBuilder holds the shorthands used to describe the routes
+type specBuilder struct {
+	doc *openapi.Document
+}
+
+func (s specBuilder) page() map[string]openapi.Response {
+	return map[string]openapi.Response{
+		"200": {
+			Description: "HTML page",
+			Content:     map[string]openapi.MediaType{"text/html": {Schema: &openapi.Schema{Type: "string"}}},
+		},
+		"500": s.json("Internal error", s.doc.SchemaOf(ErrorMessage{})),
+	}
+}
+
+// redirect describes a form action redirecting back to the home page
+func (s specBuilder) redirect(errorStatuses ...int) map[string]openapi.Response {
+	responses := map[string]openapi.Response{
+		"303": {Description: "Redirect to the home page"},
+		"500": s.json("Internal error", s.doc.SchemaOf(ErrorMessage{})),
+	}
+	for _, status := range errorStatuses {
+		responses[statusKey(status)] = s.json(http.StatusText(status), s.doc.SchemaOf(ErrorMessage{}))
+	}
+	return responses
+}
+
+// teamForm describes the responses of the team create and edit forms, which
+// are shown again with their errors when the team cannot be saved
+func (s specBuilder) teamForm() map[string]openapi.Response {
+	form := openapi.Response{
+		Description: "The form with the reasons the team cannot be saved",
+		Content:     map[string]openapi.MediaType{"text/html": {Schema: &openapi.Schema{Type: "string"}}},
+	}
+	return map[string]openapi.Response{
+		"303": {Description: "Redirect to the teams page"},
+		"400": form,
+		"409": form,
+		"500": s.json("Internal error", s.doc.SchemaOf(ErrorMessage{})),
+	}
+}
+
+// api describes an API response wrapped in the data envelope, along with
+// the error envelope for every error status
+func (s specBuilder) api(status int, data any, errorStatuses ...int) map[string]openapi.Response {
+	responses := s.apiErrors(errorStatuses...)
+	responses[statusKey(status)] = s.json(http.StatusText(status), &openapi.Schema{
+		Type:       "object",
+		Properties: map[string]*openapi.Schema{"data": s.doc.SchemaOf(data)},
+		Required:   []string{"data"},
+	})
+	return responses
+}
+
+// apiNoContent describes an API action responding without a body
+func (s specBuilder) apiNoContent(description string, errorStatuses ...int) map[string]openapi.Response {
+	responses := s.apiErrors(errorStatuses...)
+	responses[statusKey(http.StatusNoContent)] = openapi.Response{Description: description}
+	return responses
+}
+
+func (s specBuilder) apiErrors(errorStatuses ...int) map[string]openapi.Response {
+	responses := map[string]openapi.Response{
+		"500": s.json("Internal error", s.doc.SchemaOf(api.ErrorEnvelope{})),
+	}
+	for _, status := range errorStatuses {
+		responses[statusKey(status)] = s.json(http.StatusText(status), s.doc.SchemaOf(api.ErrorEnvelope{}))
+	}
+	return responses
+}
+
+func (s specBuilder) json(description string, schema *openapi.Schema) openapi.Response {
+	return openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+func (s specBuilder) text(description string) openapi.Response {
+	return openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: "string"}}},
+	}
+}
+
+func (s specBuilder) body(v any) *openapi.RequestBody {
+	return &openapi.RequestBody{
+		Content: map[string]openapi.MediaType{"application/json": {Schema: s.doc.SchemaOf(v)}},
+	}
+}
+
+func (s specBuilder) form(v any, required bool) *openapi.RequestBody {
+	return &openapi.RequestBody{
+		Required: required,
+		Content:  map[string]openapi.MediaType{"application/x-www-form-urlencoded": {Schema: s.doc.SchemaOf(v)}},
+	}
+}
+
+func (s specBuilder) pathID(name, description string) openapi.Parameter {
+	return openapi.Parameter{
+		Name:        name,
+		In:          "path",
+		Description: description,
+		Required:    true,
+		Schema:      &openapi.Schema{Type: "integer", Format: "int64"},
+	}
+}
+
+func (s specBuilder) query(name, description string, schema *openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      schema,
+	}
+}
+
+func statusKey(status int) string {
+	return strconv.Itoa(status)
+}
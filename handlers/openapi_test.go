@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/openapi"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	// Registering routes does not touch the repository
+	return NewRouter(nil, nil, nil)
+}
+
+func TestOpenAPISpecCoversRegisteredRoutes(t *testing.T) {
+	router := newTestRouter()
+	spec := OpenAPISpec()
+
+	registered := map[[2]string]bool{}
+	for _, route := range router.Routes() {
+		// Static files are served by the file server, not described by the API
+		if strings.HasPrefix(route.Path, "/static/") {
+			continue
+		}
+
+		registered[[2]string{strings.ToLower(route.Method), openapi.Path(route.Path)}] = true
+		if !spec.Has(route.Method, route.Path) {
+			t.Errorf("%s %s is registered but missing from the OpenAPI spec", route.Method, route.Path)
+		}
+	}
+
+	for _, route := range spec.Routes() {
+		if !registered[route] {
+			t.Errorf("%s %s is in the OpenAPI spec but not registered", strings.ToUpper(route[0]), route[1])
+		}
+	}
+}
+
+func TestOpenAPISpecDeclaresPathParameters(t *testing.T) {
+	placeholder := regexp.MustCompile(`\{([^}]+)\}`)
+
+	for path, item := range OpenAPISpec().Paths {
+		for method, op := range item {
+			declared := map[string]bool{}
+			for _, param := range op.Parameters {
+				if param.In == "path" {
+					declared[param.Name] = true
+				}
+			}
+
+			for _, match := range placeholder.FindAllStringSubmatch(path, -1) {
+				if !declared[match[1]] {
+					t.Errorf("%s %s does not declare path parameter %q", method, path, match[1])
+				}
+				delete(declared, match[1])
+			}
+			for name := range declared {
+				t.Errorf("%s %s declares unknown path parameter %q", method, path, name)
+			}
+		}
+	}
+}
+
+func TestOpenAPISpecIsServed(t *testing.T) {
+	router := newTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/openapi.json returned %d", w.Code)
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %q, want %q", doc.OpenAPI, openapi.Version)
+	}
+	if _, ok := doc.Components.Schemas["Season"]; !ok {
+		t.Errorf("schema Season missing from components")
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/handlers/api"
+	"github.com/orhosko/go-backend/prediction"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+)
+
+// NewRouter creates the router serving the pages, the JSON API and the
+// static files
+func NewRouter(repo repository.Repository, sim simulation.MatchSimulator, predictor *prediction.MonteCarloPredictor) *gin.Engine {
+	router := gin.Default()
+
+	// Serve static files
+	router.Static("/static", "./static")
+
+	// Register all routes
+	RegisterHomeRoutes(router, repo, predictor)
+	RegisterTeamRoutes(router, repo)
+	RegisterFixtureRoutes(router, repo, sim)
+	RegisterSeasonRoutes(router, repo, sim)
+	RegisterDivisionRoutes(router, repo)
+	RegisterCompetitionRoutes(router, repo)
+	RegisterSanctionRoutes(router, repo, sim)
+	RegisterCupRoutes(router, repo)
+	RegisterTournamentRoutes(router, repo)
+	RegisterMatchRoutes(router, repo)
+	RegisterStandingsRoutes(router, repo, predictor)
+	RegisterOpenAPIRoutes(router)
+	api.RegisterRoutes(router, repo, sim, predictor)
+
+	return router
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/templates"
+)
+
+// RegisterSanctionRoutes registers all sanction related routes
+func RegisterSanctionRoutes(router *gin.Engine, repo repository.Repository, sim simulation.MatchSimulator) {
+	router.GET("/sanctions", handleSanctions(repo))
+	router.POST("/sanctions", handleAddSanction(repo, sim))
+	router.POST("/sanctions/:id/delete", handleDeleteSanction(repo, sim))
+}
+
+func handleSanctions(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		season, readOnly, ok := pageSeason(c, repo)
+		if !ok {
+			return
+		}
+
+		sanctions, err := league.ListSanctions(reqCtx, repo, season.ID)
+		if err != nil {
+			log.Printf("Failed to fetch sanctions: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sanctions"})
+			return
+		}
+		competitions, err := league.ListCompetitions(reqCtx, repo, season)
+		if err != nil {
+			log.Printf("Failed to fetch competitions: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch competitions"})
+			return
+		}
+		matches, err := repo.GetMatchesBySeason(reqCtx, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches"})
+			return
+		}
+		weeks, err := league.TotalWeeks(reqCtx, repo, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count weeks"})
+			return
+		}
+
+		// Points can only be deducted in the competitions awarding them, and
+		// the league's teams are the ones sanctions are taken against
+		data := templates.SanctionsPageData{Season: season, ReadOnly: readOnly, Teams: competitions[0].Teams, Matches: matches, Weeks: weeks}
+		names := map[string]string{}
+		for _, competition := range competitions {
+			names[competition.Competition.Format] = competition.Competition.Name
+			if competition.Competition.ID != 0 && competition.Competition.Format != league.FormatCup {
+				data.Competitions = append(data.Competitions, competition.Competition)
+			}
+		}
+		for _, sanction := range sanctions {
+			data.Sanctions = append(data.Sanctions, templates.SanctionDisplay{
+				Sanction:    sanction,
+				Summary:     league.SanctionSummary(sanction),
+				Competition: names[sanction.Format],
+			})
+		}
+
+		c.Status(http.StatusOK)
+		templates.Sanctions(data).Render(reqCtx, c.Writer)
+	}
+}
+
+func handleAddSanction(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		in, ok := parseSanctionForm(c)
+		if !ok {
+			return
+		}
+
+		_, err := league.AddSanction(c.Request.Context(), repo, sim, in)
+		var validationErr *league.ValidationError
+		switch {
+		case err == nil:
+			c.Redirect(http.StatusSeeOther, "/sanctions")
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+		case errors.Is(err, league.ErrCompetitionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Competition not found"})
+		case errors.Is(err, league.ErrNoActiveSeason):
+			c.JSON(http.StatusConflict, gin.H{"error": "No active season"})
+		default:
+			log.Printf("Failed to take sanction: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to take sanction"})
+		}
+	}
+}
+
+func handleDeleteSanction(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sanction ID"})
+			return
+		}
+
+		err = league.DeleteSanction(c.Request.Context(), repo, sim, id)
+		switch {
+		case err == nil:
+			c.Redirect(http.StatusSeeOther, "/sanctions")
+		case errors.Is(err, league.ErrSanctionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sanction not found"})
+		case errors.Is(err, league.ErrNoActiveSeason):
+			c.JSON(http.StatusConflict, gin.H{"error": "No active season"})
+		default:
+			log.Printf("Failed to lift sanction: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to lift sanction"})
+		}
+	}
+}
+
+// parseSanctionForm parses a submitted sanction. Only the fields of its kind
+// are submitted, the others being left empty.
+func parseSanctionForm(c *gin.Context) (league.SanctionInput, bool) {
+	teamID, teamErr := strconv.ParseInt(c.PostForm("team_id"), 10, 64)
+	points, pointsErr := parseOptionalInt(c.PostForm("points"))
+	matchID, matchErr := parseOptionalInt(c.PostForm("match_id"))
+	firstWeek, firstErr := parseOptionalInt(c.PostForm("first_week"))
+	lastWeek, lastErr := parseOptionalInt(c.PostForm("last_week"))
+	if teamErr != nil || pointsErr != nil || matchErr != nil || firstErr != nil || lastErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Team, points, match and weeks must be whole numbers"})
+		return league.SanctionInput{}, false
+	}
+
+	return league.SanctionInput{
+		Kind:      c.PostForm("kind"),
+		Format:    c.PostForm("format"),
+		TeamID:    teamID,
+		Points:    points,
+		MatchID:   matchID,
+		FirstWeek: firstWeek,
+		LastWeek:  lastWeek,
+		Reason:    c.PostForm("reason"),
+	}, true
+}
@@ -1,83 +1,263 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"log"
 	"net/http"
+	"slices"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
 	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/templates"
 )
 
 // RegisterSeasonRoutes registers all season related routes
-func RegisterSeasonRoutes(router *gin.Engine, repo repository.Repository) {
-	router.POST("/reset-to-2025", handleResetToYear(repo))
-	router.POST("/start-new-season", handleStartNewSeason(repo))
+func RegisterSeasonRoutes(router *gin.Engine, repo repository.Repository, sim simulation.MatchSimulator) {
+	router.GET("/seasons", handleSeasons(repo))
+	router.GET("/seasons/:id", handleSeason(repo))
+	router.GET("/seasons/:id/rollback", handleRollbackPage(repo))
+	router.POST("/seasons/:id/rollback", handleRollback(repo))
+	router.POST("/start-new-season", handleStartNewSeason(repo, sim))
 }
 
-func handleResetToYear(repo repository.Repository) gin.HandlerFunc {
+func handleSeasons(repo repository.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		reqCtx := c.Request.Context()
 
-		err := repo.ResetToYear(reqCtx, 2025)
+		seasons, err := repo.ListSeasons(reqCtx)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset to 2025"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seasons"})
 			return
 		}
 
-		// Generate fixtures for the reset season
-		err = generateRoundRobinFixtures(repo, reqCtx)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate fixtures"})
-			return
+		// Show the latest season first
+		var summaries []templates.SeasonSummary
+		for _, season := range slices.Backward(seasons) {
+			summary, err := summarizeSeason(reqCtx, repo, season)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch standings"})
+				return
+			}
+			summaries = append(summaries, summary)
 		}
 
-		c.Redirect(http.StatusSeeOther, "/")
+		c.Status(http.StatusOK)
+		templates.Seasons(templates.SeasonsPageData{Seasons: summaries}).Render(reqCtx, c.Writer)
 	}
 }
 
-func handleStartNewSeason(repo repository.Repository) gin.HandlerFunc {
+func handleSeason(repo repository.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		reqCtx := c.Request.Context()
 
-		// Get current season
-		currentSeason, err := repo.GetCurrentSeason(reqCtx)
-		if err != nil && err != sql.ErrNoRows {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
+		seasonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+
+		season, err := repo.GetSeason(reqCtx, seasonID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch season"})
+			return
+		}
+
+		summary, err := summarizeSeason(reqCtx, repo, season)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch standings"})
+			return
+		}
+
+		matches, err := repo.GetMatchesBySeason(reqCtx, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches"})
 			return
 		}
 
-		var newYear int64
-		if err == sql.ErrNoRows {
-			newYear = 2025 // Start with 2025 if no season exists
-		} else {
-			newYear = currentSeason.Year + 1
+		// Group the matches by week, which they are ordered by
+		var weeks []templates.SeasonWeek
+		for _, match := range matches {
+			if len(weeks) == 0 || weeks[len(weeks)-1].Week != match.Week {
+				weeks = append(weeks, templates.SeasonWeek{Week: match.Week})
+			}
+			week := &weeks[len(weeks)-1]
+
+			if match.Played.Bool && match.HomeScore.Valid {
+				week.Results = append(week.Results, templates.MatchDisplay{
+					HomeTeamName:  match.HomeTeamName,
+					GuestTeamName: match.GuestTeamName,
+					HomeScore:     match.HomeScore.Int64,
+					GuestScore:    match.GuestScore.Int64,
+				})
+			} else {
+				week.Fixtures = append(week.Fixtures, templates.MatchFixture{
+					HomeTeamName:  match.HomeTeamName,
+					GuestTeamName: match.GuestTeamName,
+				})
+			}
+		}
+
+		c.Status(http.StatusOK)
+		templates.Season(templates.SeasonPageData{Summary: summary, Weeks: weeks}).Render(reqCtx, c.Writer)
+	}
+}
+
+// summarizeSeason returns the tables of a season and, once it is complete,
+// its champion, runner-up and the playoffs played at its end
+func summarizeSeason(ctx context.Context, repo repository.Repository, season sqlc.Season) (templates.SeasonSummary, error) {
+	divisions, err := buildDivisionTables(ctx, repo, season)
+	if err != nil {
+		return templates.SeasonSummary{}, err
+	}
+
+	currentWeek, err := repo.GetCurrentWeek(ctx, season.ID)
+	if err != nil {
+		currentWeek = 1 // Default to week 1 if not set
+	}
+
+	summary := templates.SeasonSummary{
+		Season:      season,
+		CurrentWeek: currentWeek,
+		Divisions:   divisions,
+	}
+
+	// The champion tops the first division
+	if season.IsComplete.Bool && len(divisions) > 0 {
+		table := divisions[0].Table
+		if len(table) > 0 {
+			summary.Champion = table[0].Team.Name
+		}
+		if len(table) > 1 {
+			summary.RunnerUp = table[1].Team.Name
+		}
+	}
+
+	playoffs, err := repo.ListPlayoffsBySeason(ctx, season.ID)
+	if err != nil {
+		return templates.SeasonSummary{}, err
+	}
+	for _, playoff := range playoffs {
+		display := templates.PlayoffDisplay{
+			UpperTeamName: playoff.UpperTeamName,
+			LowerTeamName: playoff.LowerTeamName,
+			UpperScore:    playoff.UpperScore,
+			LowerScore:    playoff.LowerScore,
+			WinnerName:    playoff.UpperTeamName,
+		}
+		if playoff.WinnerID == playoff.LowerTeamID {
+			display.WinnerName = playoff.LowerTeamName
+		}
+		if division, err := repo.GetDivision(ctx, playoff.DivisionID); err == nil {
+			display.Division = division.Name
 		}
+		summary.Playoffs = append(summary.Playoffs, display)
+	}
+	return summary, nil
+}
 
-		// Create new season
-		newSeason, err := repo.CreateNewSeason(reqCtx, newYear)
+func handleRollbackPage(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		seasonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create new season"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+			return
+		}
+
+		// Default to replaying the whole season
+		week := 1
+		if value := c.Query("week"); value != "" {
+			week, err = strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week"})
+				return
+			}
+		}
+
+		plan, err := league.PlanRollback(c.Request.Context(), repo, seasonID, week)
+		if !rollbackError(c, err) {
 			return
 		}
 
-		// Set it as the current season
-		err = repo.SetCurrentSeason(reqCtx, newSeason.ID)
+		c.Status(http.StatusOK)
+		templates.SeasonRollback(templates.RollbackPageData{
+			Season:     plan.Season,
+			Week:       plan.Week,
+			TotalWeeks: plan.TotalWeeks,
+			Results:    plan.Results,
+			Token:      plan.Token,
+		}).Render(c.Request.Context(), c.Writer)
+	}
+}
+
+func handleRollback(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		seasonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set current season"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
 			return
 		}
 
-		// Initialize game state for the new season
-		err = repo.InitializeGameState(reqCtx, newSeason.ID)
+		week, err := strconv.Atoi(c.PostForm("week"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize game state"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week"})
 			return
 		}
 
-		// Generate fixtures for the new season
-		err = generateRoundRobinFixtures(repo, reqCtx)
+		err = league.RollbackSeason(c.Request.Context(), repo, seasonID, week, c.PostForm("token"))
+		if !rollbackError(c, err) {
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/")
+	}
+}
+
+// rollbackError responds to an error planning or applying a rollback and
+// reports whether there was none.
+func rollbackError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, league.ErrSeasonNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+	case errors.Is(err, league.ErrInvalidWeek):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid week"})
+	case errors.Is(err, league.ErrInvalidToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or outdated confirmation token"})
+	default:
+		log.Printf("Failed to roll back season: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to roll back season"})
+	}
+	return false
+}
+
+func handleStartNewSeason(repo repository.Repository, sim simulation.MatchSimulator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Use the requested seed so a season can be replayed, otherwise pick one
+		seed, err := parseSeed(c.PostForm("seed"))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate fixtures"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seed"})
+			return
+		}
+
+		_, err = league.StartNewSeason(c.Request.Context(), repo, sim, seed, c.PostForm("rules"))
+		switch {
+		case errors.Is(err, league.ErrUnknownRules):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown ranking rules"})
+			return
+		case err != nil:
+			log.Printf("Failed to start new season: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start new season"})
 			return
 		}
 
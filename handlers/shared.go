@@ -3,91 +3,110 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
 
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
 	"github.com/orhosko/go-backend/repository"
 	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+	"github.com/orhosko/go-backend/templates"
 )
 
-// recalculateTeamStanding recalculates the standing for a specific team in a season
-func recalculateTeamStanding(ctx context.Context, repo repository.Repository, seasonID, teamID int64) error {
-	// Get all teams to calculate total weeks
-	teams, err := repo.ListTeams(ctx)
+// buildDivisionTables returns the table of each division of a season for
+// the templates, noting the sanctions against each team
+func buildDivisionTables(ctx context.Context, repo repository.Repository, season sqlc.Season) ([]templates.DivisionTable, error) {
+	tables, err := league.Tables(ctx, repo, season)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	sanctions, err := league.ListSanctions(ctx, repo, season.ID)
+	if err != nil {
+		return nil, err
+	}
+	notes := league.SanctionNotes(sanctions, league.FormatLeague)
 
-	// Calculate total weeks in the season (each team plays against every other team twice)
-	totalWeeks := 2 * (len(teams) - 1)
+	divisions := make([]templates.DivisionTable, 0, len(tables))
+	for _, table := range tables {
+		division := templates.DivisionTable{Name: table.Division.Name}
+		for i, entry := range table.Entries {
+			division.Table = append(division.Table, templates.TeamStanding{
+				Team:     entry.Team,
+				Standing: entry.Standing(season.ID),
+				Zone:     table.Zone(i),
+				Notes:    notes[entry.Team.ID],
+			})
+		}
+		divisions = append(divisions, division)
+	}
+	return divisions, nil
+}
 
-	// Initialize standings data
-	stats := struct {
-		points   int64
-		wins     int64
-		draws    int64
-		losses   int64
-		goalDiff int64
-	}{}
+// pageSeason returns the season a page shows, the one selected by the season
+// query parameter or else the current season, and whether the page is
+// read-only because it shows another season than the current one. It
+// responds with an error and returns false if the season cannot be loaded.
+func pageSeason(c *gin.Context, repo repository.Repository) (sqlc.Season, bool, bool) {
+	reqCtx := c.Request.Context()
 
-	// Get matches from all weeks
-	for week := 1; week <= totalWeeks; week++ {
-		matches, err := repo.GetMatchesByWeek(ctx, int64(week), seasonID)
-		if err != nil {
-			return err
-		}
+	currentSeason, err := league.CurrentSeason(reqCtx, repo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
+		return sqlc.Season{}, false, false
+	}
+
+	value := c.Query("season")
+	if value == "" {
+		return currentSeason, false, true
+	}
+
+	seasonID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return sqlc.Season{}, false, false
+	}
 
-		// Calculate standings based on matches
-		for _, match := range matches {
-			if !match.Played.Bool {
-				continue
-			}
+	season, err := repo.GetSeason(reqCtx, seasonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return sqlc.Season{}, false, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch season"})
+		return sqlc.Season{}, false, false
+	}
 
-			// Skip if team is not involved in this match
-			if match.HomeID != teamID && match.GuestID != teamID {
-				continue
-			}
+	return season, season.ID != currentSeason.ID, true
+}
 
-			result, err := repo.GetMatchResult(ctx, match.ID)
-			if err != nil {
-				if err == sql.ErrNoRows {
-					continue
-				}
-				return err
-			}
+// parseSeed parses an optional seed form value, falling back to a random seed
+// when the value is empty
+func parseSeed(value string) (int64, error) {
+	if value == "" {
+		return league.NewSeed(), nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
 
-			if match.HomeID == teamID {
-				stats.goalDiff += result.HomeScore - result.GuestScore
-				if result.HomeScore == result.GuestScore {
-					stats.draws++
-					stats.points++
-				} else if result.HomeScore > result.GuestScore {
-					stats.wins++
-					stats.points += 3
-				} else {
-					stats.losses++
-				}
-			} else if match.GuestID == teamID {
-				stats.goalDiff += result.GuestScore - result.HomeScore
-				if result.HomeScore == result.GuestScore {
-					stats.draws++
-					stats.points++
-				} else if result.HomeScore < result.GuestScore {
-					stats.wins++
-					stats.points += 3
-				} else {
-					stats.losses++
-				}
-			}
-		}
+// parseOptionalInt parses an optional whole number form value, falling back
+// to 0 when the value is empty
+func parseOptionalInt(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
 	}
+	return strconv.ParseInt(value, 10, 64)
+}
 
-	// Update team standing
-	return repo.UpdateStanding(ctx, sqlc.UpdateStandingParams{
-		TeamID:   teamID,
-		SeasonID: seasonID,
-		Points:   sql.NullInt64{Int64: stats.points, Valid: true},
-		Wins:     sql.NullInt64{Int64: stats.wins, Valid: true},
-		Draws:    sql.NullInt64{Int64: stats.draws, Valid: true},
-		Losses:   sql.NullInt64{Int64: stats.losses, Valid: true},
-		GoalDiff: sql.NullInt64{Int64: stats.goalDiff, Valid: true},
-	})
+// rankingOptions lists the tie-breaker presets for the templates
+func rankingOptions() []templates.RankingOption {
+	var options []templates.RankingOption
+	for _, rules := range standings.Presets {
+		options = append(options, templates.RankingOption{
+			Name:  rules.Name,
+			Label: rules.Label,
+		})
+	}
+	return options
 }
@@ -3,44 +3,31 @@ package handlers
 import (
 	"database/sql"
 	"net/http"
-	"sort"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/prediction"
 	"github.com/orhosko/go-backend/repository"
 	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
 	"github.com/orhosko/go-backend/templates"
 )
 
 // RegisterStandingsRoutes registers all standings related routes
-func RegisterStandingsRoutes(router *gin.Engine, repo repository.Repository) {
-	router.GET("/standings", handleGetStandings(repo))
+func RegisterStandingsRoutes(router *gin.Engine, repo repository.Repository, predictor *prediction.MonteCarloPredictor) {
+	router.GET("/standings", handleGetStandings(repo, predictor))
 	router.POST("/standings/recalculate", handleRecalculateStandings(repo))
 	router.POST("/standings/team/:teamId", handleUpdateTeamStanding(repo))
 }
 
-func handleGetStandings(repo repository.Repository) gin.HandlerFunc {
+func handleGetStandings(repo repository.Repository, predictor *prediction.MonteCarloPredictor) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		reqCtx := c.Request.Context()
 
-		// Get current season
-		currentSeason, err := repo.GetCurrentSeason(reqCtx)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				currentSeason, err = repo.CreateNewSeason(reqCtx, 2025)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create initial season"})
-					return
-				}
-				err = repo.SetCurrentSeason(reqCtx, currentSeason.ID)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set current season"})
-					return
-				}
-			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
-				return
-			}
+		currentSeason, readOnly, ok := pageSeason(c, repo)
+		if !ok {
+			return
 		}
 
 		// Get current week
@@ -49,40 +36,13 @@ func handleGetStandings(repo repository.Repository) gin.HandlerFunc {
 			currentWeek = 1 // Default to week 1 if not set
 		}
 
-		// Get all teams
-		teams, err := repo.ListTeams(reqCtx)
+		// Compute the league table from the played matches
+		divisions, err := buildDivisionTables(reqCtx, repo, currentSeason)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch standings"})
 			return
 		}
 
-		// Get standings for each team
-		var standings []templates.TeamStanding
-		for _, team := range teams {
-			standing, err := repo.GetStanding(reqCtx, team.ID, currentSeason.ID)
-			if err != nil && err != sql.ErrNoRows {
-				continue
-			}
-			if err == sql.ErrNoRows {
-				standing = sqlc.Standing{
-					TeamID:   team.ID,
-					SeasonID: currentSeason.ID,
-					Points:   sql.NullInt64{Int64: 0, Valid: true},
-					Wins:     sql.NullInt64{Int64: 0, Valid: true},
-					Draws:    sql.NullInt64{Int64: 0, Valid: true},
-					Losses:   sql.NullInt64{Int64: 0, Valid: true},
-					GoalDiff: sql.NullInt64{Int64: 0, Valid: true},
-				}
-			}
-			standings = append(standings, templates.TeamStanding{
-				Team:     team,
-				Standing: standing,
-			})
-		}
-
-		// Sort standings by points and goal difference
-		sortStandings(standings)
-
 		// Get match results for current week
 		var matchResults []templates.MatchDisplay
 		matches, err := repo.GetMatchesByWeek(reqCtx, int64(currentWeek), currentSeason.ID)
@@ -124,8 +84,27 @@ func handleGetStandings(repo repository.Repository) gin.HandlerFunc {
 			}
 		}
 
+		// Get the cup round of the current week
+		cupRound, err := weekCupRound(reqCtx, repo, currentSeason, currentWeek)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cup"})
+			return
+		}
+		tournamentMatches, err := weekTournamentMatches(reqCtx, repo, currentSeason, currentWeek)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tournament"})
+			return
+		}
+
+		// Get the tie-breakers of the league
+		rules, err := standings.LeagueRules(reqCtx, repo, currentSeason.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ranking rules"})
+			return
+		}
+
 		// Calculate championship predictions
-		predictions, err := calculateChampionshipPredictions(reqCtx, repo, currentSeason)
+		predictions, err := calculateChampionshipPredictions(reqCtx, repo, predictor, currentSeason)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate predictions"})
 			return
@@ -137,37 +116,41 @@ func handleGetStandings(repo repository.Repository) gin.HandlerFunc {
 			templatePredictions = append(templatePredictions, templates.TeamPrediction{
 				TeamName:    pred.TeamName,
 				Probability: pred.Probability,
+				TopN:        pred.TopN,
+				Last:        pred.Last,
 			})
 		}
 
 		// Check if season is complete
-		totalWeeks := 2 * (len(teams) - 1)
+		totalWeeks, err := league.TotalWeeks(reqCtx, repo, currentSeason.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count weeks"})
+			return
+		}
 		isSeasonComplete := currentWeek >= totalWeeks
 
 		component := templates.Index(templates.StandingsPageData{
 			CurrentWeek:             currentWeek,
+			SeasonID:                currentSeason.ID,
 			CurrentYear:             int(currentSeason.Year),
-			LeagueTable:             standings,
+			Seed:                    currentSeason.Seed,
+			PredictionTopN:          predictor.TopN,
+			RankingRules:            rules.Name,
+			RankingOptions:          rankingOptions(),
+			Divisions:               divisions,
 			MatchResults:            matchResults,
 			ChampionshipPredictions: templatePredictions,
 			Fixtures:                fixtures,
 			IsSeasonComplete:        isSeasonComplete,
+			ReadOnly:                readOnly,
+			Cup:                     cupRound,
+			Tournament:              tournamentMatches,
 		})
 
 		component.Render(reqCtx, c.Writer)
 	}
 }
 
-// sortStandings sorts the standings by points (descending) and goal difference (descending)
-func sortStandings(standings []templates.TeamStanding) {
-	sort.Slice(standings, func(i, j int) bool {
-		if standings[i].Standing.Points.Int64 != standings[j].Standing.Points.Int64 {
-			return standings[i].Standing.Points.Int64 > standings[j].Standing.Points.Int64
-		}
-		return standings[i].Standing.GoalDiff.Int64 > standings[j].Standing.GoalDiff.Int64
-	})
-}
-
 func handleRecalculateStandings(repo repository.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		reqCtx := c.Request.Context()
@@ -192,23 +175,21 @@ func handleRecalculateStandings(repo repository.Repository) gin.HandlerFunc {
 			currentSeason.ID = seasonIDInt
 		}
 
-		// Get all teams in the season
-		teams, err := repo.ListTeams(reqCtx)
+		// Find teams whose cached standing no longer matches their results
+		drifted, err := standings.Verify(reqCtx, repo, currentSeason.ID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify standings"})
 			return
 		}
 
-		// Recalculate standings for each team
-		for _, team := range teams {
-			err = recalculateTeamStanding(reqCtx, repo, currentSeason.ID, team.ID)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update standings for team " + team.Name})
-				return
-			}
+		// Rebuild the cached standings from the match results
+		err = standings.Refresh(reqCtx, repo, currentSeason.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update standings"})
+			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Standings recalculated successfully"})
+		c.JSON(http.StatusOK, gin.H{"message": "Standings recalculated successfully", "drifted_team_ids": drifted})
 	}
 }
 
@@ -242,8 +223,20 @@ func handleUpdateTeamStanding(repo repository.Repository) gin.HandlerFunc {
 			currentSeason.ID = seasonIDInt
 		}
 
-		// Recalculate standings for the specified team
-		err = recalculateTeamStanding(reqCtx, repo, currentSeason.ID, teamID)
+		// Make sure the team exists
+		_, err = repo.GetTeam(reqCtx, teamID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team"})
+			return
+		}
+
+		// Standings are derived from every result of the season, so the
+		// team's row is rebuilt together with the rest of the table
+		err = standings.Refresh(reqCtx, repo, currentSeason.ID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team standing"})
 			return
@@ -2,87 +2,64 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
 	"github.com/orhosko/go-backend/repository"
 	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
 	"github.com/orhosko/go-backend/templates"
 )
 
 // RegisterTeamRoutes registers all team related routes
 func RegisterTeamRoutes(router *gin.Engine, repo repository.Repository) {
 	router.GET("/teams", handleTeams(repo))
+	router.GET("/teams/new", handleNewTeam())
+	router.GET("/teams/compare", handleCompareTeams())
+	router.GET("/teams/:id", handleTeam(repo))
+	router.GET("/teams/:id/vs/:opponent_id", handleHeadToHead(repo))
+	router.POST("/teams", handleCreateTeam(repo))
+	router.GET("/teams/:id/edit", handleEditTeam(repo))
+	router.POST("/teams/:id", handleUpdateTeam(repo))
+	router.POST("/teams/:id/delete", handleDeleteTeam(repo))
 }
 
 func handleTeams(repo repository.Repository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		reqCtx := c.Request.Context()
 
-		// Get current season
-		currentSeason, err := repo.GetCurrentSeason(reqCtx)
+		currentSeason, readOnly, ok := pageSeason(c, repo)
+		if !ok {
+			return
+		}
+
+		// Compute the league table from the played matches
+		entries, err := standings.Table(reqCtx, repo, currentSeason)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				// If no season exists, create one starting from 2025
-				currentSeason, err = repo.CreateNewSeason(reqCtx, 2025)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create initial season"})
-					return
-				}
-				// Set it as the current season
-				err = repo.SetCurrentSeason(reqCtx, currentSeason.ID)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set current season"})
-					return
-				}
-			} else {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current season"})
-				return
-			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch standings"})
+			return
 		}
 
-		// Get all teams
-		teams, err := repo.ListTeams(reqCtx)
+		history, err := repo.ListTeamHistory(reqCtx)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team history"})
 			return
 		}
+		historyByTeam := map[int64][]sqlc.ListTeamHistoryRow{}
+		for _, entry := range history {
+			historyByTeam[entry.TeamID] = append(historyByTeam[entry.TeamID], entry)
+		}
 
 		// Prepare team details
 		var teamsData []templates.TeamDetailData
-		for _, team := range teams {
-			// Get team's standing
-			standing, err := repo.GetStanding(reqCtx, team.ID, currentSeason.ID)
-			if err != nil {
-				if err == sql.ErrNoRows {
-					// If no standing exists, create one with zeros
-					err = repo.CreateStanding(reqCtx, sqlc.CreateStandingParams{
-						TeamID:   team.ID,
-						SeasonID: currentSeason.ID,
-						Points:   sql.NullInt64{Int64: 0, Valid: true},
-						Wins:     sql.NullInt64{Int64: 0, Valid: true},
-						Draws:    sql.NullInt64{Int64: 0, Valid: true},
-						Losses:   sql.NullInt64{Int64: 0, Valid: true},
-						GoalDiff: sql.NullInt64{Int64: 0, Valid: true},
-					})
-					if err != nil {
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create standing"})
-						return
-					}
-					standing = sqlc.Standing{
-						TeamID:   team.ID,
-						SeasonID: currentSeason.ID,
-						Points:   sql.NullInt64{Int64: 0, Valid: true},
-						Wins:     sql.NullInt64{Int64: 0, Valid: true},
-						Draws:    sql.NullInt64{Int64: 0, Valid: true},
-						Losses:   sql.NullInt64{Int64: 0, Valid: true},
-						GoalDiff: sql.NullInt64{Int64: 0, Valid: true},
-					}
-				} else {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch standings"})
-					return
-				}
-			}
+		for _, entry := range entries {
+			team := entry.Team
+			standing := entry.Standing(currentSeason.ID)
 
 			// Calculate total matches
 			totalMatches := standing.Wins.Int64 + standing.Draws.Int64 + standing.Losses.Int64
@@ -95,39 +72,317 @@ func handleTeams(repo repository.Repository) gin.HandlerFunc {
 				lossPercentage = float64(standing.Losses.Int64) / float64(totalMatches) * 100
 			}
 
-			// Calculate goals
-			var goalsScored, goalsConceded int64
-			if standing.GoalDiff.Valid {
-				goalsConceded = 0                     // Initialize with a default value
-				goalsScored = standing.GoalDiff.Int64 // This is just an approximation
-				if goalsScored < 0 {
-					goalsConceded = -goalsScored
-					goalsScored = 0
-				}
-			}
-
 			teamData := templates.TeamDetailData{
 				Team:     team,
 				Standing: standing,
 				Stats: templates.TeamStats{
 					TotalMatches:   int(totalMatches),
-					GoalsScored:    goalsScored,
-					GoalsConceded:  goalsConceded,
+					GoalsScored:    standing.GoalsFor.Int64,
+					GoalsConceded:  standing.GoalsAgainst.Int64,
 					WinPercentage:  winPercentage,
 					DrawPercentage: drawPercentage,
 					LossPercentage: lossPercentage,
 				},
+				History: historyByTeam[team.ID],
 			}
 			teamsData = append(teamsData, teamData)
 		}
 
+		rosterLocked, err := league.RosterLocked(reqCtx, repo)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check season"})
+			return
+		}
+
 		// Render the teams page
 		teamsPage := templates.Teams(templates.TeamsPageData{
 			Teams:         teamsData,
 			CurrentSeason: currentSeason,
+			RosterLocked:  rosterLocked,
+			ReadOnly:      readOnly,
 		})
 
 		c.Status(http.StatusOK)
 		teamsPage.Render(reqCtx, c.Writer)
 	}
 }
+
+func handleNewTeam() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		renderTeamForm(c, http.StatusOK, newTeamForm(0, league.TeamInput{
+			Strength: (league.MinStrength + league.MaxStrength) / 2,
+			Budget:   league.DefaultBudget,
+		}))
+	}
+}
+
+func handleCreateTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		form := teamFormFromRequest(c, 0)
+		in, ok := parseTeamForm(c, form)
+		if !ok {
+			return
+		}
+
+		_, err := league.CreateTeam(c.Request.Context(), repo, in)
+		if err != nil {
+			renderTeamFormError(c, form, err)
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/teams")
+	}
+}
+
+func handleEditTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teamID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+
+		team, err := repo.GetTeam(c.Request.Context(), teamID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team"})
+			return
+		}
+
+		renderTeamForm(c, http.StatusOK, newTeamForm(team.ID, league.TeamInput{
+			Name:     team.Name,
+			Strength: team.Strength.Int64,
+			Budget:   team.Budget.Int64,
+		}))
+	}
+}
+
+func handleUpdateTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teamID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+
+		form := teamFormFromRequest(c, teamID)
+		in, ok := parseTeamForm(c, form)
+		if !ok {
+			return
+		}
+
+		_, err = league.UpdateTeam(c.Request.Context(), repo, teamID, in)
+		if err != nil {
+			renderTeamFormError(c, form, err)
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/teams")
+	}
+}
+
+func handleDeleteTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teamID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+
+		err = league.DeleteTeam(c.Request.Context(), repo, teamID)
+		switch {
+		case errors.Is(err, league.ErrTeamNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		case errors.Is(err, league.ErrTeamHasMatches):
+			c.JSON(http.StatusConflict, gin.H{"error": "Team has played matches and cannot be deleted"})
+			return
+		case errors.Is(err, league.ErrSeasonInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": "Teams cannot be removed while a season is in progress"})
+			return
+		case err != nil:
+			log.Printf("Failed to delete team %d: %v", teamID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete team"})
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/teams")
+	}
+}
+
+// newTeamForm fills the team form with the values of a team
+func newTeamForm(teamID int64, in league.TeamInput) templates.TeamFormData {
+	return templates.TeamFormData{
+		TeamID:      teamID,
+		Name:        in.Name,
+		Strength:    fmt.Sprint(in.Strength),
+		Budget:      fmt.Sprint(in.Budget),
+		MinStrength: league.MinStrength,
+		MaxStrength: league.MaxStrength,
+	}
+}
+
+// teamFormFromRequest fills the team form with the submitted values so they
+// can be shown again if they are invalid
+func teamFormFromRequest(c *gin.Context, teamID int64) templates.TeamFormData {
+	return templates.TeamFormData{
+		TeamID:      teamID,
+		Name:        c.PostForm("name"),
+		Strength:    c.PostForm("strength"),
+		Budget:      c.PostForm("budget"),
+		MinStrength: league.MinStrength,
+		MaxStrength: league.MaxStrength,
+	}
+}
+
+// parseTeamForm parses the submitted team, showing the form again with the
+// errors of any field that is not a number
+func parseTeamForm(c *gin.Context, form templates.TeamFormData) (league.TeamInput, bool) {
+	in := league.TeamInput{Name: form.Name}
+	errs := map[string]string{}
+
+	var err error
+	if in.Strength, err = strconv.ParseInt(form.Strength, 10, 64); err != nil {
+		errs["strength"] = "Strength must be a whole number"
+	}
+	if in.Budget, err = strconv.ParseInt(form.Budget, 10, 64); err != nil {
+		errs["budget"] = "Budget must be a whole number"
+	}
+
+	if len(errs) > 0 {
+		form.Errors = errs
+		renderTeamForm(c, http.StatusBadRequest, form)
+		return league.TeamInput{}, false
+	}
+	return in, true
+}
+
+// renderTeamFormError shows the team form again with the reason it could not
+// be saved
+func renderTeamFormError(c *gin.Context, form templates.TeamFormData, err error) {
+	var validationErr *league.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		form.Errors = validationErr.Fields
+		renderTeamForm(c, http.StatusBadRequest, form)
+	case errors.Is(err, league.ErrTeamNameTaken):
+		form.Errors = map[string]string{"name": "A team with this name already exists"}
+		renderTeamForm(c, http.StatusConflict, form)
+	case errors.Is(err, league.ErrSeasonInProgress):
+		form.Error = "Teams cannot be added while a season is in progress. Finish the season first."
+		renderTeamForm(c, http.StatusConflict, form)
+	case errors.Is(err, league.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+	default:
+		log.Printf("Failed to save team: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save team"})
+	}
+}
+
+func renderTeamForm(c *gin.Context, status int, form templates.TeamFormData) {
+	c.Status(status)
+	templates.TeamForm(form).Render(c.Request.Context(), c.Writer)
+}
+
+func handleTeam(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		teamID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+
+		season, readOnly, ok := pageSeason(c, repo)
+		if !ok {
+			return
+		}
+
+		ts, err := league.GetTeamSeason(reqCtx, repo, teamID, season)
+		if errors.Is(err, league.ErrTeamNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to fetch season of team %d: %v", teamID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team"})
+			return
+		}
+
+		total := ts.Home
+		total.Played += ts.Away.Played
+		total.Wins += ts.Away.Wins
+		total.Draws += ts.Away.Draws
+		total.Losses += ts.Away.Losses
+		total.GoalsFor += ts.Away.GoalsFor
+		total.GoalsAgainst += ts.Away.GoalsAgainst
+
+		data := templates.TeamPageData{
+			Team:     ts.Team,
+			Season:   season,
+			ReadOnly: readOnly,
+			Form:     ts.Form,
+			Splits: []templates.TeamRecord{
+				teamRecord("Home", ts.Home),
+				teamRecord("Away", ts.Away),
+				teamRecord("Total", total),
+			},
+			Weeks: map[int64]templates.TeamWeekDisplay{},
+		}
+		if ts.Division.ID != 0 {
+			data.Division = ts.Division.Name
+		}
+		for _, m := range ts.Matches {
+			data.Fixtures = append(data.Fixtures, teamFixture(m))
+		}
+		for _, m := range ts.Upcoming {
+			data.Upcoming = append(data.Upcoming, teamFixture(m))
+		}
+
+		// Scale the points bars to the most points
+		var most int64
+		for _, week := range ts.Weeks {
+			most = max(most, week.Points)
+		}
+		for _, week := range ts.Weeks {
+			display := templates.TeamWeekDisplay{Week: week.Week, Points: week.Points, Position: week.Position}
+			if most > 0 && week.Points > 0 {
+				display.Height = float64(week.Points) / float64(most) * 100
+			}
+			data.Weeks[week.Week] = display
+			data.Chart = append(data.Chart, display)
+		}
+
+		c.Status(http.StatusOK)
+		templates.TeamPage(data).Render(reqCtx, c.Writer)
+	}
+}
+
+func teamRecord(label string, record league.Record) templates.TeamRecord {
+	return templates.TeamRecord{
+		Label:        label,
+		Played:       record.Played,
+		Wins:         record.Wins,
+		Draws:        record.Draws,
+		Losses:       record.Losses,
+		GoalsFor:     record.GoalsFor,
+		GoalsAgainst: record.GoalsAgainst,
+	}
+}
+
+func teamFixture(m league.TeamMatch) templates.TeamFixture {
+	return templates.TeamFixture{
+		Week:         m.Week,
+		OpponentID:   m.OpponentID,
+		OpponentName: m.OpponentName,
+		Home:         m.Home,
+		Played:       m.Played,
+		GoalsFor:     m.GoalsFor,
+		GoalsAgainst: m.GoalsAgainst,
+		Outcome:      m.Outcome,
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/league"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/templates"
+)
+
+// RegisterTournamentRoutes registers all tournament related routes
+func RegisterTournamentRoutes(router *gin.Engine, repo repository.Repository) {
+	router.GET("/tournament", handleTournament(repo))
+	router.POST("/tournament", handleDrawTournament(repo))
+}
+
+func handleTournament(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqCtx := c.Request.Context()
+
+		season, readOnly, ok := pageSeason(c, repo)
+		if !ok {
+			return
+		}
+
+		tournament, err := league.GetTournament(reqCtx, repo, season)
+		if err != nil && !errors.Is(err, league.ErrNoTournament) {
+			log.Printf("Failed to fetch tournament: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tournament"})
+			return
+		}
+		hasTournament := err == nil
+
+		sanctions, err := league.ListSanctions(reqCtx, repo, season.ID)
+		if err != nil {
+			log.Printf("Failed to fetch sanctions: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sanctions"})
+			return
+		}
+		notes := league.SanctionNotes(sanctions, league.FormatTournament)
+
+		results, err := repo.GetResultsBySeason(reqCtx, season.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch results"})
+			return
+		}
+
+		// An undrawn tournament suggests two groups of which two teams go
+		// through
+		data := templates.TournamentPageData{
+			Season:     season,
+			ReadOnly:   readOnly,
+			Locked:     len(results) > 0,
+			Groups:     2,
+			Qualifiers: 2,
+			AwayGoals:  true,
+			Winner:     tournament.WinnerName,
+		}
+		if hasTournament {
+			data.Drawn = len(tournament.Groups) > 0
+			data.Groups = tournament.Tournament.GroupCount
+			data.Qualifiers = tournament.Tournament.Qualifiers
+			data.AwayGoals = tournament.Tournament.AwayGoals
+			data.SeparateDivisions = tournament.Tournament.SeparateDivisions
+		}
+		for _, group := range tournament.Groups {
+			display := templates.TournamentGroupDisplay{Name: group.Name}
+			for i, entry := range group.Table {
+				standing := templates.TeamStanding{Team: entry.Team, Standing: entry.Standing(season.ID), Notes: notes[entry.Team.ID]}
+				if int64(i) < tournament.Tournament.Qualifiers {
+					standing.Zone = "promotion"
+				}
+				display.Table = append(display.Table, standing)
+			}
+			for _, match := range group.Matches {
+				display.Matches = append(display.Matches, tournamentMatch(match))
+			}
+			data.GroupStage = append(data.GroupStage, display)
+		}
+		for _, round := range tournament.Rounds {
+			display := templates.TournamentRoundDisplay{Name: round.Name}
+			for _, tie := range round.Ties {
+				display.Ties = append(display.Ties, tournamentTie(tie))
+			}
+			data.Rounds = append(data.Rounds, display)
+		}
+
+		c.Status(http.StatusOK)
+		templates.TournamentPage(data).Render(reqCtx, c.Writer)
+	}
+}
+
+func handleDrawTournament(repo repository.Repository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		groups, groupsErr := strconv.ParseInt(c.PostForm("groups"), 10, 64)
+		qualifiers, qualifiersErr := strconv.ParseInt(c.PostForm("qualifiers"), 10, 64)
+		if groupsErr != nil || qualifiersErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Groups and qualifiers must be whole numbers"})
+			return
+		}
+
+		_, err := league.DrawTournament(c.Request.Context(), repo, league.TournamentInput{
+			Groups:            groups,
+			Qualifiers:        qualifiers,
+			AwayGoals:         c.PostForm("away_goals") == "true",
+			SeparateDivisions: c.PostForm("separate_divisions") == "true",
+		})
+		var validationErr *league.ValidationError
+		switch {
+		case err == nil:
+			c.Redirect(http.StatusSeeOther, "/tournament")
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": validationErr.Error()})
+		case errors.Is(err, league.ErrNoActiveSeason):
+			c.JSON(http.StatusConflict, gin.H{"error": "No active season"})
+		case errors.Is(err, league.ErrTournamentLocked):
+			c.JSON(http.StatusConflict, gin.H{"error": "The tournament can only be drawn before the season's first match"})
+		case errors.Is(err, league.ErrNotEnoughTeams):
+			c.JSON(http.StatusConflict, gin.H{"error": "The tournament needs at least 2 teams"})
+		case errors.Is(err, league.ErrTournamentTooLong):
+			c.JSON(http.StatusConflict, gin.H{"error": "The league has fewer weeks than the tournament has matchdays"})
+		case errors.Is(err, league.ErrDrawImpossible):
+			c.JSON(http.StatusConflict, gin.H{"error": "No draw keeps the teams of each division in different groups"})
+		default:
+			log.Printf("Failed to draw tournament: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to draw tournament"})
+		}
+	}
+}
+
+// weekTournamentMatches returns the tournament matches played in a week of
+// a season.
+func weekTournamentMatches(ctx context.Context, repo repository.Repository, season sqlc.Season, week int) ([]templates.CupTieDisplay, error) {
+	matches, err := league.TournamentWeek(ctx, repo, season, week)
+	if err != nil {
+		return nil, err
+	}
+
+	var display []templates.CupTieDisplay
+	for _, match := range matches {
+		display = append(display, tournamentMatch(match))
+	}
+	return display, nil
+}
+
+// tournamentMatch converts a tournament match for the templates
+func tournamentMatch(match sqlc.ListTournamentMatchesRow) templates.CupTieDisplay {
+	return cupTie(sqlc.ListCupTiesRow{
+		HomeID:         match.HomeID,
+		GuestID:        match.GuestID,
+		HomeScore:      match.HomeScore,
+		GuestScore:     match.GuestScore,
+		ExtraTime:      match.ExtraTime,
+		HomePenalties:  match.HomePenalties,
+		GuestPenalties: match.GuestPenalties,
+		WinnerID:       match.WinnerID,
+		Played:         match.Played,
+		HomeTeamName:   match.HomeTeamName,
+		GuestTeamName:  match.GuestTeamName,
+	})
+}
+
+func tournamentTie(tie league.TournamentTie) templates.TournamentTieDisplay {
+	display := templates.TournamentTieDisplay{
+		First:      tournamentMatch(tie.First),
+		FirstWeek:  tie.First.Week,
+		Second:     tournamentMatch(tie.Second),
+		SecondWeek: tie.Second.Week,
+	}
+	if tie.Second.Played {
+		home, guest := tie.Aggregate()
+		display.Aggregate = fmt.Sprintf("Aggregate %d-%d", home, guest)
+		if home == guest && !tie.Second.HomePenalties.Valid {
+			display.Aggregate += ", won on away goals"
+		}
+	}
+	return display
+}
@@ -0,0 +1,347 @@
+package league
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+// Formats of a competition. A season has at most one competition of each.
+const (
+	FormatLeague     = standings.LeagueFormat
+	FormatCup        = "cup"
+	FormatTournament = "tournament"
+)
+
+// MaxLegs bounds how many times the teams of a league meet.
+const MaxLegs = 4
+
+var (
+	ErrCompetitionNotFound = errors.New("competition not found")
+	ErrCompetitionLocked   = errors.New("competition rules can only change before the season's first match")
+)
+
+// competitionDefaults are the name and legs of each format. Every format
+// starts with three points for a win and one for a draw.
+var competitionDefaults = map[string]struct {
+	name string
+	legs int64
+}{
+	FormatLeague:     {"League", 2},
+	FormatCup:        {"Cup", 1},
+	FormatTournament: {"Tournament", 2},
+}
+
+// CompetitionInput holds the editable rules of a competition. A team
+// scoring at least BonusGoals in a match earns BonusPoints more, unless
+// BonusGoals is 0. Level teams are ranked by the tie-breaker rules named
+// RankingRules, an empty name keeping the competition's.
+type CompetitionInput struct {
+	Legs         int64
+	WinPoints    int64
+	DrawPoints   int64
+	LossPoints   int64
+	BonusGoals   int64
+	BonusPoints  int64
+	RankingRules string
+}
+
+// validate checks the rules for a competition, returning a *ValidationError
+// if any is invalid. Only a league can change its legs, and a cup, which
+// ranks no one on points, cannot change its points or tie-breakers.
+func (in CompetitionInput) validate(competition sqlc.Competition) error {
+	fields := map[string]string{}
+
+	switch {
+	case competition.Format != FormatLeague && in.Legs != competition.Legs:
+		fields["legs"] = fmt.Sprintf("Only a league can change its legs, the %s has %d", competition.Name, competition.Legs)
+	case in.Legs < 1 || in.Legs > MaxLegs:
+		fields["legs"] = fmt.Sprintf("Legs must be between 1 and %d", MaxLegs)
+	}
+
+	cup := competition.Format == FormatCup
+	switch {
+	case cup && (in.WinPoints != competition.WinPoints || in.DrawPoints != competition.DrawPoints || in.LossPoints != competition.LossPoints):
+		fields["points"] = "A knockout cup awards no points"
+	case in.LossPoints < 0:
+		fields["points"] = "Points must not be negative"
+	case in.WinPoints < in.DrawPoints || in.DrawPoints < in.LossPoints:
+		fields["points"] = "A win must be worth at least a draw and a draw at least a loss"
+	}
+
+	switch {
+	case cup && (in.BonusGoals != competition.BonusGoals || in.BonusPoints != competition.BonusPoints):
+		fields["bonus"] = "A knockout cup awards no bonus points"
+	case in.BonusGoals < 0 || in.BonusPoints < 0:
+		fields["bonus"] = "Bonus goals and points must not be negative"
+	case in.BonusGoals == 0 && in.BonusPoints > 0:
+		fields["bonus"] = "Bonus points need the goals a team must score for them"
+	}
+
+	switch {
+	case cup && in.RankingRules != competition.RankingRules:
+		fields["ranking_rules"] = "A knockout cup ranks no one"
+	case !validRules(in.RankingRules):
+		fields["ranking_rules"] = fmt.Sprintf("Unknown ranking rules %q", in.RankingRules)
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// Competition is a competition of a season with the teams taking part and
+// the points deducted from them.
+type Competition struct {
+	Competition sqlc.Competition
+	Teams       []sqlc.ListCompetitionTeamsRow // by name
+	Deductions  []sqlc.ListSanctionsRow        // in the order they were taken
+}
+
+// ListCompetitions returns the competitions of a season, its league first.
+// A season whose fixtures are not generated yet plays its league by the
+// default rules until they are.
+func ListCompetitions(ctx context.Context, repo repository.Repository, season sqlc.Season) ([]Competition, error) {
+	rows, err := repo.ListCompetitions(ctx, season.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch competitions: %w", err)
+	}
+
+	competitions := []Competition{{Competition: defaultCompetition(season.ID, FormatLeague)}}
+	for _, row := range rows {
+		if row.Format == FormatLeague {
+			competitions[0].Competition = row
+			continue
+		}
+		competitions = append(competitions, Competition{Competition: row})
+	}
+
+	sanctions, err := repo.ListSanctions(ctx, season.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sanctions: %w", err)
+	}
+
+	for i := range competitions {
+		if competitions[i].Competition.ID == 0 {
+			continue
+		}
+		competitions[i].Teams, err = repo.ListCompetitionTeams(ctx, competitions[i].Competition.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch competition teams: %w", err)
+		}
+		for _, sanction := range sanctions {
+			if sanction.Kind == SanctionDeduction && sanction.CompetitionID == competitions[i].Competition.ID {
+				competitions[i].Deductions = append(competitions[i].Deductions, sanction)
+			}
+		}
+	}
+	return competitions, nil
+}
+
+// UpdateCompetition changes the rules of a competition of the current
+// season, given by its format. Rules can only change before the season's
+// first match, and a league changing its legs gets new fixtures, the cup
+// and tournament being drawn again over its new weeks. Sanctions are taken
+// apart with AddSanction at any time.
+func UpdateCompetition(ctx context.Context, repo repository.Repository, format string, in CompetitionInput) (Competition, error) {
+	var season sqlc.Season
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		var err error
+		season, err = activeSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		var competition sqlc.Competition
+		if format == FormatLeague {
+			competition, err = seasonCompetition(ctx, tx, season.ID, format)
+		} else {
+			competition, err = getCompetition(ctx, tx, season.ID, format)
+		}
+		if err != nil {
+			return err
+		}
+		if in.RankingRules == "" {
+			in.RankingRules = competition.RankingRules
+		}
+		if err := in.validate(competition); err != nil {
+			return err
+		}
+
+		results, err := tx.GetResultsBySeason(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch results: %w", err)
+		}
+		if len(results) > 0 {
+			return ErrCompetitionLocked
+		}
+
+		err = tx.UpdateCompetitionRules(ctx, sqlc.UpdateCompetitionRulesParams{
+			ID:           competition.ID,
+			Legs:         in.Legs,
+			WinPoints:    in.WinPoints,
+			DrawPoints:   in.DrawPoints,
+			LossPoints:   in.LossPoints,
+			BonusGoals:   in.BonusGoals,
+			BonusPoints:  in.BonusPoints,
+			RankingRules: in.RankingRules,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update competition: %w", err)
+		}
+		if in.Legs == competition.Legs {
+			return nil
+		}
+
+		weeks, err := tx.GetSeasonWeeks(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count weeks: %w", err)
+		}
+		if weeks == 0 {
+			return nil
+		}
+		return regenerateFixtures(ctx, tx, &season)
+	})
+	if err != nil {
+		return Competition{}, err
+	}
+	return findCompetition(ctx, repo, season, format)
+}
+
+// findCompetition returns the competition of a format of a season with its
+// teams.
+func findCompetition(ctx context.Context, repo repository.Repository, season sqlc.Season, format string) (Competition, error) {
+	competitions, err := ListCompetitions(ctx, repo, season)
+	if err != nil {
+		return Competition{}, err
+	}
+	for _, competition := range competitions {
+		if competition.Competition.Format == format {
+			return competition, nil
+		}
+	}
+	return Competition{}, ErrCompetitionNotFound
+}
+
+// defaultCompetition returns a competition of a season with the default
+// rules of its format, not stored yet.
+func defaultCompetition(seasonID int64, format string) sqlc.Competition {
+	defaults := competitionDefaults[format]
+	return sqlc.Competition{
+		SeasonID:     seasonID,
+		Format:       format,
+		Name:         defaults.name,
+		Legs:         defaults.legs,
+		WinPoints:    standings.DefaultPoints.Win,
+		DrawPoints:   standings.DefaultPoints.Draw,
+		LossPoints:   standings.DefaultPoints.Loss,
+		RankingRules: standings.PremierLeague.Name,
+	}
+}
+
+// validRules reports whether name is one of the tie-breaker presets.
+func validRules(name string) bool {
+	_, err := standings.RulesByName(name)
+	return err == nil
+}
+
+// getCompetition returns the competition of a format of a season, or
+// ErrCompetitionNotFound if the season has none.
+func getCompetition(ctx context.Context, repo repository.Repository, seasonID int64, format string) (sqlc.Competition, error) {
+	competition, err := repo.GetCompetitionBySeason(ctx, sqlc.GetCompetitionBySeasonParams{SeasonID: seasonID, Format: format})
+	if err == sql.ErrNoRows {
+		return sqlc.Competition{}, ErrCompetitionNotFound
+	}
+	if err != nil {
+		return sqlc.Competition{}, fmt.Errorf("failed to fetch competition: %w", err)
+	}
+	return competition, nil
+}
+
+// competitionRules returns the competition of a format of a season, or one
+// with the default rules if the season has none yet.
+func competitionRules(ctx context.Context, repo repository.Repository, seasonID int64, format string) (sqlc.Competition, error) {
+	competition, err := getCompetition(ctx, repo, seasonID, format)
+	if errors.Is(err, ErrCompetitionNotFound) {
+		return defaultCompetition(seasonID, format), nil
+	}
+	return competition, err
+}
+
+// seasonCompetition returns the competition of a format of a season,
+// creating it with the default rules if the season has none yet.
+func seasonCompetition(ctx context.Context, repo repository.Repository, seasonID int64, format string) (sqlc.Competition, error) {
+	competition, err := getCompetition(ctx, repo, seasonID, format)
+	if !errors.Is(err, ErrCompetitionNotFound) {
+		return competition, err
+	}
+
+	competition = defaultCompetition(seasonID, format)
+	competition, err = repo.CreateCompetition(ctx, sqlc.CreateCompetitionParams{
+		SeasonID:     competition.SeasonID,
+		Format:       competition.Format,
+		Name:         competition.Name,
+		Legs:         competition.Legs,
+		WinPoints:    competition.WinPoints,
+		DrawPoints:   competition.DrawPoints,
+		LossPoints:   competition.LossPoints,
+		BonusGoals:   competition.BonusGoals,
+		BonusPoints:  competition.BonusPoints,
+		RankingRules: competition.RankingRules,
+	})
+	if err != nil {
+		return sqlc.Competition{}, fmt.Errorf("failed to create competition: %w", err)
+	}
+	return competition, nil
+}
+
+// copyCompetitions gives a new season the competitions of the previous one,
+// played by the same rules. Their teams are entered by the draws, and
+// sanctions stay with the season they were taken in.
+func copyCompetitions(ctx context.Context, repo repository.Repository, previous, season sqlc.Season) error {
+	competitions, err := repo.ListCompetitions(ctx, previous.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch competitions: %w", err)
+	}
+	for _, competition := range competitions {
+		_, err := repo.CreateCompetition(ctx, sqlc.CreateCompetitionParams{
+			SeasonID:     season.ID,
+			Format:       competition.Format,
+			Name:         competition.Name,
+			Legs:         competition.Legs,
+			WinPoints:    competition.WinPoints,
+			DrawPoints:   competition.DrawPoints,
+			LossPoints:   competition.LossPoints,
+			BonusGoals:   competition.BonusGoals,
+			BonusPoints:  competition.BonusPoints,
+			RankingRules: competition.RankingRules,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create competition: %w", err)
+		}
+	}
+	return nil
+}
+
+// setCompetitionTeams replaces the teams taking part in a competition.
+func setCompetitionTeams(ctx context.Context, repo repository.Repository, competition sqlc.Competition, teams []sqlc.Team) error {
+	if err := repo.DeleteCompetitionTeams(ctx, competition.ID); err != nil {
+		return fmt.Errorf("failed to delete competition teams: %w", err)
+	}
+	for _, team := range teams {
+		err := repo.CreateCompetitionTeam(ctx, sqlc.CreateCompetitionTeamParams{
+			CompetitionID: competition.ID,
+			TeamID:        team.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add competition team: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package league
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/bits"
+	"slices"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+// Cup draws
+const (
+	// CupDrawSeeded keeps the strongest teams apart until the late rounds
+	CupDrawSeeded = "seeded"
+	// CupDrawRandom places every team in the bracket at random
+	CupDrawRandom = "random"
+)
+
+var (
+	ErrNoCup       = errors.New("the season has no cup")
+	ErrUnknownDraw = errors.New("unknown cup draw")
+	ErrCupLocked   = errors.New("the cup can only be drawn before the season's first match")
+	ErrCupTooLong  = errors.New("the league has fewer weeks than the cup has rounds")
+)
+
+// cupStream offsets the random streams of the cup from the match IDs, the
+// playoffs and the teams' development. The draw uses the stream itself and
+// each tie the stream minus its ID.
+const cupStream = -2 << 40
+
+// Cup is the bracket of a season's cup.
+type Cup struct {
+	Cup    sqlc.Cup
+	Rounds []CupRound // first round first
+	// WinnerID and WinnerName are the team that won the final, zero until
+	// it is played
+	WinnerID   int64
+	WinnerName string
+}
+
+// Draw returns how the cup was drawn, CupDrawSeeded or CupDrawRandom.
+func (c Cup) Draw() string {
+	if c.Cup.Seeded {
+		return CupDrawSeeded
+	}
+	return CupDrawRandom
+}
+
+// CupRound is a round of a cup bracket, played alongside a league week.
+type CupRound struct {
+	Round int64
+	Name  string
+	Week  int64
+	Ties  []sqlc.ListCupTiesRow // by slot
+}
+
+// Bye reports whether a tie sends its only team through without a match.
+// Only the first round has byes, a tie of a later round with a single team
+// waits for its other team.
+func Bye(tie sqlc.ListCupTiesRow) bool {
+	return tie.Round == 1 && tie.HomeID.Valid != tie.GuestID.Valid
+}
+
+// RoundName names a round of a cup with the given number of rounds, such as
+// "Final" or "Round of 16".
+func RoundName(round, rounds int64) string {
+	switch rounds - round {
+	case 0:
+		return "Final"
+	case 1:
+		return "Semi-finals"
+	case 2:
+		return "Quarter-finals"
+	}
+	return fmt.Sprintf("Round of %d", 1<<(rounds-round+1))
+}
+
+// GetCup returns the bracket of a season's cup, or ErrNoCup if it has none.
+func GetCup(ctx context.Context, repo repository.Repository, season sqlc.Season) (Cup, error) {
+	cup, err := repo.GetCupBySeason(ctx, season.ID)
+	if err == sql.ErrNoRows {
+		return Cup{}, ErrNoCup
+	}
+	if err != nil {
+		return Cup{}, fmt.Errorf("failed to fetch cup: %w", err)
+	}
+
+	ties, err := repo.ListCupTies(ctx, cup.ID)
+	if err != nil {
+		return Cup{}, fmt.Errorf("failed to fetch cup ties: %w", err)
+	}
+
+	bracket := Cup{Cup: cup}
+	var rounds int64
+	for _, tie := range ties {
+		rounds = max(rounds, tie.Round)
+	}
+	for _, tie := range ties {
+		if len(bracket.Rounds) == 0 || bracket.Rounds[len(bracket.Rounds)-1].Round != tie.Round {
+			bracket.Rounds = append(bracket.Rounds, CupRound{
+				Round: tie.Round,
+				Name:  RoundName(tie.Round, rounds),
+				Week:  tie.Week,
+			})
+		}
+		round := &bracket.Rounds[len(bracket.Rounds)-1]
+		round.Ties = append(round.Ties, tie)
+
+		if tie.Round == rounds && tie.Played {
+			bracket.WinnerID = tie.WinnerID.Int64
+			bracket.WinnerName = tie.HomeTeamName.String
+			if tie.WinnerID == tie.GuestID {
+				bracket.WinnerName = tie.GuestTeamName.String
+			}
+		}
+	}
+	return bracket, nil
+}
+
+// DrawCup draws the cup of the current season, replacing any earlier draw.
+// The cup can only be drawn before the season's first match. Its rounds are
+// spread over the weeks of the league, generating the league's fixtures if
+// needed.
+func DrawCup(ctx context.Context, repo repository.Repository, draw string) (Cup, error) {
+	if draw != CupDrawSeeded && draw != CupDrawRandom {
+		return Cup{}, ErrUnknownDraw
+	}
+
+	var season sqlc.Season
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		var err error
+		season, err = activeSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		results, err := tx.GetResultsBySeason(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch results: %w", err)
+		}
+		if len(results) > 0 {
+			return ErrCupLocked
+		}
+
+		weeks, err := tx.GetSeasonWeeks(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count weeks: %w", err)
+		}
+		if weeks == 0 {
+			if err := GenerateRoundRobinFixtures(ctx, tx); err != nil {
+				return err
+			}
+		}
+
+		previous, err := tx.GetCupBySeason(ctx, season.ID)
+		if err == nil {
+			err = tx.DeleteCup(ctx, previous.ID)
+		}
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to delete cup: %w", err)
+		}
+
+		return newCup(ctx, tx, season, draw == CupDrawSeeded)
+	})
+	if err != nil {
+		return Cup{}, err
+	}
+
+	return GetCup(ctx, repo, season)
+}
+
+// newCup creates the cup of a season and draws its bracket.
+func newCup(ctx context.Context, repo repository.Repository, season sqlc.Season, seeded bool) error {
+	cup, err := repo.CreateCup(ctx, sqlc.CreateCupParams{SeasonID: season.ID, Seeded: seeded})
+	if err != nil {
+		return fmt.Errorf("failed to create cup: %w", err)
+	}
+	return drawCup(ctx, repo, season, cup)
+}
+
+// redrawCup draws the cup of a season again, if it has one, after its
+// teams changed.
+func redrawCup(ctx context.Context, repo repository.Repository, season sqlc.Season) error {
+	cup, err := repo.GetCupBySeason(ctx, season.ID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch cup: %w", err)
+	}
+
+	if err := repo.DeleteCupTies(ctx, cup.ID); err != nil {
+		return fmt.Errorf("failed to delete cup ties: %w", err)
+	}
+
+	// A league left with a single team, or with too few weeks for the
+	// rounds, has no cup ties
+	err = drawCup(ctx, repo, season, cup)
+	if errors.Is(err, ErrNotEnoughTeams) || errors.Is(err, ErrCupTooLong) {
+		return nil
+	}
+	return err
+}
+
+// deleteCupTies deletes the ties of a season's cup, if it has one.
+func deleteCupTies(ctx context.Context, repo repository.Repository, seasonID int64) error {
+	cup, err := repo.GetCupBySeason(ctx, seasonID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch cup: %w", err)
+	}
+
+	if err := repo.DeleteCupTies(ctx, cup.ID); err != nil {
+		return fmt.Errorf("failed to delete cup ties: %w", err)
+	}
+	return nil
+}
+
+// drawCup creates the ties of a cup's bracket. Every team of the season
+// enters, the bracket is padded to a power of two with byes and its rounds
+// are spread evenly over the league's weeks, the final coming before the
+// league's last week. A seeded draw ranks the teams by division and then
+// strength and places them so the top seeds only meet late, giving the
+// byes to the top seeds. A random draw places the teams in the same
+// pattern after shuffling them with the season seed.
+func drawCup(ctx context.Context, repo repository.Repository, season sqlc.Season, cup sqlc.Cup) error {
+	_, byDivision, err := divisionTeams(ctx, repo, season)
+	if err != nil {
+		return err
+	}
+
+	teams := seededTeams(byDivision)
+	if len(teams) < 2 {
+		return ErrNotEnoughTeams
+	}
+	competition, err := seasonCompetition(ctx, repo, season.ID, FormatCup)
+	if err != nil {
+		return err
+	}
+	if err := setCompetitionTeams(ctx, repo, competition, teams); err != nil {
+		return err
+	}
+	if !cup.Seeded {
+		rng := simulation.StreamRand(season.Seed, cupStream)
+		rng.Shuffle(len(teams), func(i, j int) { teams[i], teams[j] = teams[j], teams[i] })
+	}
+
+	totalWeeks, err := TotalWeeks(ctx, repo, season.ID)
+	if err != nil {
+		return err
+	}
+	rounds := bits.Len(uint(len(teams) - 1))
+	if totalWeeks < rounds+1 {
+		return ErrCupTooLong
+	}
+
+	size := 1 << rounds
+	order := bracketOrder(size)
+	for round := 1; round <= rounds; round++ {
+		week := int64((round*totalWeeks + rounds) / (rounds + 1))
+		for slot := 0; slot < size>>round; slot++ {
+			tie := sqlc.CreateCupTieParams{
+				CupID: cup.ID,
+				Round: int64(round),
+				Slot:  int64(slot),
+				Week:  week,
+			}
+			// Only the first round is drawn, the later ones wait for its
+			// winners
+			if round == 1 {
+				tie.HomeID = seedTeam(teams, order[2*slot])
+				tie.GuestID = seedTeam(teams, order[2*slot+1])
+			}
+			if err := repo.CreateCupTie(ctx, tie); err != nil {
+				return fmt.Errorf("failed to create cup tie: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// seededTeams ranks the teams of a season by division, from the top tier
+// down, and then by strength.
+func seededTeams(byDivision [][]sqlc.Team) []sqlc.Team {
+	var teams []sqlc.Team
+	for _, division := range byDivision {
+		division = slices.Clone(division)
+		slices.SortStableFunc(division, func(a, b sqlc.Team) int {
+			return cmp.Compare(b.Strength.Int64, a.Strength.Int64)
+		})
+		teams = append(teams, division...)
+	}
+	return teams
+}
+
+// bracketOrder returns the 0-based seeds of a bracket of size places from
+// top to bottom, such that seeds 0 and 1 can only meet in the final, the
+// top four only in the semi-finals and so on. Neighbouring places meet in
+// the first round, the better seed first.
+func bracketOrder(size int) []int {
+	order := []int{0}
+	for len(order) < size {
+		next := make([]int, 0, 2*len(order))
+		for _, seed := range order {
+			next = append(next, seed, 2*len(order)-1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// seedTeam returns the team of a seed, or no team for a bye.
+func seedTeam(teams []sqlc.Team, seed int) sql.NullInt64 {
+	if seed >= len(teams) {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: teams[seed].ID, Valid: true}
+}
+
+// playCupWeek plays the cup ties of a week and sends their winners to the
+// next round, returning the number of ties played. A tie with a single team
+// is a bye the team goes through without playing. Ties are seeded from the
+// season seed and the tie ID like league matches.
+func playCupWeek(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, season sqlc.Season, week int) (int, error) {
+	cup, err := repo.GetCupBySeason(ctx, season.ID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch cup: %w", err)
+	}
+
+	ties, err := repo.ListCupTies(ctx, cup.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch cup ties: %w", err)
+	}
+	strength, err := seasonStrengths(ctx, repo, season.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	played := 0
+	for i := range ties {
+		tie := &ties[i]
+		if tie.Week != int64(week) || tie.Played || (!tie.HomeID.Valid && !tie.GuestID.Valid) {
+			continue
+		}
+
+		result := sqlc.SaveCupTieResultParams{ID: tie.ID, WinnerID: tie.HomeID}
+		switch {
+		case !tie.GuestID.Valid:
+		case !tie.HomeID.Valid:
+			result.WinnerID = tie.GuestID
+		default:
+			log.Printf("Playing cup tie: Home(%s) vs Guest(%s)", tie.HomeTeamName.String, tie.GuestTeamName.String)
+			knockout := simulation.SimulateKnockout(sim,
+				simulation.StreamRand(season.Seed, cupStream-tie.ID),
+				simulation.RatingFromStrength(strength[tie.HomeID.Int64]),
+				simulation.RatingFromStrength(strength[tie.GuestID.Int64]),
+			)
+			log.Printf("Score: %s %d-%d %s", tie.HomeTeamName.String, knockout.HomeScore, knockout.GuestScore, tie.GuestTeamName.String)
+
+			result.HomeScore = sql.NullInt64{Int64: knockout.HomeScore, Valid: true}
+			result.GuestScore = sql.NullInt64{Int64: knockout.GuestScore, Valid: true}
+			result.ExtraTime = knockout.ExtraTime
+			if knockout.Penalties {
+				result.HomePenalties = sql.NullInt64{Int64: knockout.HomePenalties, Valid: true}
+				result.GuestPenalties = sql.NullInt64{Int64: knockout.GuestPenalties, Valid: true}
+			}
+			if !knockout.HomeWins() {
+				result.WinnerID = tie.GuestID
+			}
+			played++
+		}
+		if err := repo.SaveCupTieResult(ctx, result); err != nil {
+			return 0, fmt.Errorf("failed to save cup tie result: %w", err)
+		}
+
+		// The winner takes its place in the next round, at home when it
+		// comes from the upper tie
+		next := slices.IndexFunc(ties, func(t sqlc.ListCupTiesRow) bool {
+			return t.Round == tie.Round+1 && t.Slot == tie.Slot/2
+		})
+		if next < 0 {
+			continue
+		}
+		if tie.Slot%2 == 0 {
+			ties[next].HomeID = result.WinnerID
+		} else {
+			ties[next].GuestID = result.WinnerID
+		}
+		err := repo.SetCupTieTeams(ctx, sqlc.SetCupTieTeamsParams{
+			ID:      ties[next].ID,
+			HomeID:  ties[next].HomeID,
+			GuestID: ties[next].GuestID,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to advance cup winner: %w", err)
+		}
+	}
+	return played, nil
+}
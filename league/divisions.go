@@ -0,0 +1,502 @@
+package league
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+const (
+	// DefaultDivisionName names the division a league starts with
+	DefaultDivisionName = "Division 1"
+	// MaxDivisionNameLength bounds the length of a division name
+	MaxDivisionNameLength = 50
+)
+
+var (
+	ErrDivisionNotFound = errors.New("division not found")
+	ErrDivisionsLocked  = errors.New("divisions can only change before the season's first match")
+	ErrDivisionNotEmpty = errors.New("division still has teams")
+	ErrLastDivision     = errors.New("a season needs at least one division")
+)
+
+// Table zones of a DivisionTable
+const (
+	ZonePromotion         = "promotion"
+	ZonePromotionPlayoff  = "promotion-playoff"
+	ZoneRelegationPlayoff = "relegation-playoff"
+	ZoneRelegation        = "relegation"
+)
+
+// DivisionInput holds the editable fields of a division.
+type DivisionInput struct {
+	Name             string
+	RelegationPlaces int64
+	Playoff          bool
+}
+
+// Validate checks the fields of a division, returning a *ValidationError if
+// any is invalid.
+func (in DivisionInput) Validate() error {
+	fields := map[string]string{}
+
+	switch {
+	case in.Name == "":
+		fields["name"] = "is required"
+	case len(in.Name) > MaxDivisionNameLength:
+		fields["name"] = fmt.Sprintf("must be at most %d characters", MaxDivisionNameLength)
+	}
+
+	if in.RelegationPlaces < 0 {
+		fields["relegation_places"] = "must not be negative"
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// DivisionTable is the ranked table of one division of a season.
+type DivisionTable struct {
+	Division sqlc.Division
+	Entries  []standings.Entry
+
+	// Promoted and Relegated count the places swapped with the divisions
+	// above and below, the playoff places come on top of them.
+	Promoted          int
+	PromotionPlayoff  bool
+	Relegated         int
+	RelegationPlayoff bool
+}
+
+// Zone returns the promotion or relegation zone of the team at a 0-based
+// position of the table, or "" if it has none.
+func (t DivisionTable) Zone(position int) string {
+	fromBottom := len(t.Entries) - 1 - position
+	switch {
+	case position < t.Promoted:
+		return ZonePromotion
+	case t.PromotionPlayoff && position == t.Promoted:
+		return ZonePromotionPlayoff
+	case fromBottom < t.Relegated:
+		return ZoneRelegation
+	case t.RelegationPlayoff && fromBottom == t.Relegated:
+		return ZoneRelegationPlayoff
+	}
+	return ""
+}
+
+// Tables returns the tables of a season's divisions from the top division
+// down. A season without divisions yet has a single table of every team.
+func Tables(ctx context.Context, repo repository.Repository, season sqlc.Season) ([]DivisionTable, error) {
+	// Tie-breakers only compare teams on their own results and the matches
+	// between them, so splitting the season's table by division gives each
+	// division's table
+	entries, err := standings.Table(ctx, repo, season)
+	if err != nil {
+		return nil, err
+	}
+
+	divisions, err := repo.ListDivisions(ctx, season.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch divisions: %w", err)
+	}
+	if len(divisions) == 0 {
+		return []DivisionTable{{
+			Division: sqlc.Division{SeasonID: season.ID, Name: DefaultDivisionName, Tier: 1},
+			Entries:  entries,
+		}}, nil
+	}
+
+	members, err := repo.ListDivisionTeams(ctx, season.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch division teams: %w", err)
+	}
+	divisionOf := make(map[int64]int64, len(members))
+	for _, member := range members {
+		divisionOf[member.TeamID] = member.DivisionID
+	}
+
+	tables := make([]DivisionTable, len(divisions))
+	for i, division := range divisions {
+		table := DivisionTable{Division: division}
+		for _, entry := range entries {
+			if divisionOf[entry.Team.ID] == division.ID {
+				table.Entries = append(table.Entries, entry)
+			}
+		}
+		if i > 0 {
+			table.Promoted = int(divisions[i-1].RelegationPlaces)
+			table.PromotionPlayoff = divisions[i-1].Playoff
+		}
+		if i < len(divisions)-1 {
+			table.Relegated = int(division.RelegationPlaces)
+			table.RelegationPlayoff = division.Playoff
+		}
+		tables[i] = table
+	}
+	return tables, nil
+}
+
+// divisionTeams returns the divisions of a season from the top down with
+// their teams ordered by name. A season without divisions gets the default
+// one, and teams in no division join the bottom one.
+func divisionTeams(ctx context.Context, repo repository.Repository, season sqlc.Season) ([]sqlc.Division, [][]sqlc.Team, error) {
+	divisions, err := repo.ListDivisions(ctx, season.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch divisions: %w", err)
+	}
+	if len(divisions) == 0 {
+		division, err := repo.CreateDivision(ctx, sqlc.CreateDivisionParams{
+			SeasonID: season.ID,
+			Name:     DefaultDivisionName,
+			Tier:     1,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create division: %w", err)
+		}
+		divisions = append(divisions, division)
+	}
+
+	members, err := repo.ListDivisionTeams(ctx, season.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch division teams: %w", err)
+	}
+	divisionOf := make(map[int64]int64, len(members))
+	for _, member := range members {
+		divisionOf[member.TeamID] = member.DivisionID
+	}
+
+	teams, err := repo.ListTeams(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch teams: %w", err)
+	}
+
+	bottom := divisions[len(divisions)-1]
+	byDivision := make([][]sqlc.Team, len(divisions))
+	for _, team := range teams {
+		divisionID, ok := divisionOf[team.ID]
+		if !ok {
+			if err := repo.SetTeamDivision(ctx, season.ID, bottom.ID, team.ID); err != nil {
+				return nil, nil, fmt.Errorf("failed to add team to division: %w", err)
+			}
+			divisionID = bottom.ID
+		}
+		for i, division := range divisions {
+			if division.ID == divisionID {
+				byDivision[i] = append(byDivision[i], team)
+			}
+		}
+	}
+	return divisions, byDivision, nil
+}
+
+// seedDivisions creates the divisions of a new season from the final tables
+// of the previous one. If the previous season is complete, teams in the
+// promotion and relegation places swap divisions and playoffs decide the
+// extra places, otherwise every team keeps its division. Teams new to the
+// league join the bottom division.
+func seedDivisions(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, previous, next sqlc.Season) error {
+	tables, err := Tables(ctx, repo, previous)
+	if err != nil {
+		return err
+	}
+
+	// Every team starts in the division it played in, then the places are
+	// settled from the top division down, so a team promoted out of a
+	// division cannot also be relegated from it
+	tierOf := map[int64]int{}
+	for i, table := range tables {
+		for _, entry := range table.Entries {
+			tierOf[entry.Team.ID] = i
+		}
+	}
+
+	for i := 0; previous.IsComplete.Bool && i+1 < len(tables); i++ {
+		upper, lower := tables[i], tables[i+1]
+
+		var candidates []standings.Entry // staying teams of the upper division, worst first
+		for j := len(upper.Entries) - 1; j >= 0; j-- {
+			if tierOf[upper.Entries[j].Team.ID] == i {
+				candidates = append(candidates, upper.Entries[j])
+			}
+		}
+
+		places := min(int(upper.Division.RelegationPlaces), len(candidates), len(lower.Entries))
+		for j := 0; j < places; j++ {
+			tierOf[candidates[j].Team.ID] = i + 1
+			tierOf[lower.Entries[j].Team.ID] = i
+		}
+
+		if !upper.Division.Playoff || places >= len(candidates) || places >= len(lower.Entries) {
+			continue
+		}
+		winner, err := playPlayoff(ctx, repo, sim, previous, upper.Division, candidates[places].Team, lower.Entries[places].Team)
+		if err != nil {
+			return err
+		}
+		if winner == lower.Entries[places].Team.ID {
+			tierOf[candidates[places].Team.ID] = i + 1
+			tierOf[lower.Entries[places].Team.ID] = i
+		}
+	}
+
+	divisions := make([]sqlc.Division, len(tables))
+	for i, table := range tables {
+		divisions[i], err = repo.CreateDivision(ctx, sqlc.CreateDivisionParams{
+			SeasonID:         next.ID,
+			Name:             table.Division.Name,
+			Tier:             int64(i + 1),
+			RelegationPlaces: table.Division.RelegationPlaces,
+			Playoff:          table.Division.Playoff,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create division: %w", err)
+		}
+	}
+
+	teams, err := repo.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch teams: %w", err)
+	}
+	for _, team := range teams {
+		tier, ok := tierOf[team.ID]
+		if !ok {
+			tier = len(divisions) - 1
+		}
+		if err := repo.SetTeamDivision(ctx, next.ID, divisions[tier].ID, team.ID); err != nil {
+			return fmt.Errorf("failed to add team to division: %w", err)
+		}
+	}
+	return nil
+}
+
+// playPlayoff plays the one-off playoff between the upper division's best
+// team outside the relegation places and the lower division's best team
+// outside the promotion places, and returns the winner. The upper team plays
+// at home and keeps its place on a draw.
+func playPlayoff(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, season sqlc.Season, division sqlc.Division, upper, lower sqlc.Team) (int64, error) {
+	// Negative streams never meet a match ID, so the playoff gets its own
+	// scores for the season seed
+	result := sim.Simulate(
+		simulation.StreamRand(season.Seed, -division.ID),
+		simulation.RatingFromStrength(upper.Strength.Int64),
+		simulation.RatingFromStrength(lower.Strength.Int64),
+	)
+
+	winner := upper.ID
+	if result.GuestScore > result.HomeScore {
+		winner = lower.ID
+	}
+
+	err := repo.CreatePlayoff(ctx, sqlc.CreatePlayoffParams{
+		SeasonID:    season.ID,
+		DivisionID:  division.ID,
+		UpperTeamID: upper.ID,
+		LowerTeamID: lower.ID,
+		UpperScore:  result.HomeScore,
+		LowerScore:  result.GuestScore,
+		WinnerID:    winner,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to save playoff: %w", err)
+	}
+	return winner, nil
+}
+
+// CreateDivision adds a division below the current season's divisions.
+func CreateDivision(ctx context.Context, repo repository.Repository, in DivisionInput) (sqlc.Division, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if err := in.Validate(); err != nil {
+		return sqlc.Division{}, err
+	}
+
+	var division sqlc.Division
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		season, err := divisionSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		divisions, _, err := divisionTeams(ctx, tx, season)
+		if err != nil {
+			return err
+		}
+
+		division, err = tx.CreateDivision(ctx, sqlc.CreateDivisionParams{
+			SeasonID:         season.ID,
+			Name:             in.Name,
+			Tier:             divisions[len(divisions)-1].Tier + 1,
+			RelegationPlaces: in.RelegationPlaces,
+			Playoff:          in.Playoff,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create division: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return sqlc.Division{}, err
+	}
+
+	return division, nil
+}
+
+// UpdateDivision changes the name and the promotion and relegation rules of
+// a division of the current season.
+func UpdateDivision(ctx context.Context, repo repository.Repository, id int64, in DivisionInput) (sqlc.Division, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if err := in.Validate(); err != nil {
+		return sqlc.Division{}, err
+	}
+
+	var division sqlc.Division
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		season, err := divisionSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		division, err = getDivision(ctx, tx, season, id)
+		if err != nil {
+			return err
+		}
+
+		division.Name = in.Name
+		division.RelegationPlaces = in.RelegationPlaces
+		division.Playoff = in.Playoff
+		err = tx.UpdateDivision(ctx, sqlc.UpdateDivisionParams{
+			ID:               division.ID,
+			Name:             division.Name,
+			Tier:             division.Tier,
+			RelegationPlaces: division.RelegationPlaces,
+			Playoff:          division.Playoff,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update division: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return sqlc.Division{}, err
+	}
+
+	return division, nil
+}
+
+// DeleteDivision removes an empty division of the current season and moves
+// the divisions below it up a tier.
+func DeleteDivision(ctx context.Context, repo repository.Repository, id int64) error {
+	return repo.WithTx(ctx, func(tx repository.Repository) error {
+		season, err := divisionSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		division, err := getDivision(ctx, tx, season, id)
+		if err != nil {
+			return err
+		}
+
+		divisions, teams, err := divisionTeams(ctx, tx, season)
+		if err != nil {
+			return err
+		}
+		if len(divisions) == 1 {
+			return ErrLastDivision
+		}
+
+		for i, d := range divisions {
+			switch {
+			case d.ID == division.ID:
+				if len(teams[i]) > 0 {
+					return ErrDivisionNotEmpty
+				}
+				if err := tx.DeleteDivision(ctx, d.ID); err != nil {
+					return fmt.Errorf("failed to delete division: %w", err)
+				}
+			case d.Tier > division.Tier:
+				err := tx.UpdateDivision(ctx, sqlc.UpdateDivisionParams{
+					ID:               d.ID,
+					Name:             d.Name,
+					Tier:             d.Tier - 1,
+					RelegationPlaces: d.RelegationPlaces,
+					Playoff:          d.Playoff,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to update division: %w", err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// MoveTeam moves a team to another division of the current season and
+// rebuilds the season's fixtures if they are already generated.
+func MoveTeam(ctx context.Context, repo repository.Repository, divisionID, teamID int64) error {
+	return repo.WithTx(ctx, func(tx repository.Repository) error {
+		season, err := divisionSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := getDivision(ctx, tx, season, divisionID); err != nil {
+			return err
+		}
+		if _, err := getTeam(ctx, tx, teamID); err != nil {
+			return err
+		}
+
+		if err := tx.SetTeamDivision(ctx, season.ID, divisionID, teamID); err != nil {
+			return fmt.Errorf("failed to move team: %w", err)
+		}
+
+		weeks, err := tx.GetSeasonWeeks(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count weeks: %w", err)
+		}
+		if weeks == 0 {
+			return nil
+		}
+		return regenerateFixtures(ctx, tx, &season)
+	})
+}
+
+// divisionSeason returns the current season if its divisions can change,
+// which is only the case before its first match.
+func divisionSeason(ctx context.Context, repo repository.Repository) (sqlc.Season, error) {
+	season, err := activeSeason(ctx, repo)
+	if err != nil {
+		return sqlc.Season{}, err
+	}
+
+	results, err := repo.GetResultsBySeason(ctx, season.ID)
+	if err != nil {
+		return sqlc.Season{}, fmt.Errorf("failed to fetch results: %w", err)
+	}
+	if len(results) > 0 {
+		return sqlc.Season{}, ErrDivisionsLocked
+	}
+	return season, nil
+}
+
+// getDivision returns a division of the given season.
+func getDivision(ctx context.Context, repo repository.Repository, season sqlc.Season, id int64) (sqlc.Division, error) {
+	division, err := repo.GetDivision(ctx, id)
+	if err == sql.ErrNoRows || (err == nil && division.SeasonID != season.ID) {
+		return sqlc.Division{}, ErrDivisionNotFound
+	}
+	if err != nil {
+		return sqlc.Division{}, fmt.Errorf("failed to fetch division: %w", err)
+	}
+	return division, nil
+}
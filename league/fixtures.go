@@ -0,0 +1,166 @@
+package league
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+// ErrNotEnoughTeams is returned when generating fixtures for fewer than two
+// teams.
+var ErrNotEnoughTeams = errors.New("need at least 2 teams to generate fixtures")
+
+// EnsureFixtures generates the fixtures of the current season unless the
+// current week already has matches, creating the initial season if needed.
+func EnsureFixtures(ctx context.Context, repo repository.Repository) (sqlc.Season, error) {
+	currentSeason, err := CurrentSeason(ctx, repo)
+	if err != nil {
+		return sqlc.Season{}, err
+	}
+
+	// Get current week
+	currentWeek, err := repo.GetCurrentWeek(ctx, currentSeason.ID)
+	if err != nil {
+		currentWeek = 1 // Default to week 1 if not set
+	}
+
+	// Check if there are any existing matches for this week
+	matches, err := repo.GetMatchesByWeek(ctx, int64(currentWeek), currentSeason.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return sqlc.Season{}, fmt.Errorf("failed to check existing fixtures: %w", err)
+	}
+
+	// Only generate new fixtures if none exist for the current week
+	if err == sql.ErrNoRows || len(matches) == 0 {
+		if err := GenerateRoundRobinFixtures(ctx, repo); err != nil {
+			return sqlc.Season{}, err
+		}
+	}
+
+	return currentSeason, nil
+}
+
+// GenerateRoundRobinFixtures generates a complete season of fixtures where each team
+// plays against every other team of its division as many times as the league
+// has legs, alternating home and away
+func GenerateRoundRobinFixtures(ctx context.Context, repo repository.Repository) error {
+	// Get current season
+	currentSeason, err := repo.GetCurrentSeason(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch current season: %w", err)
+	}
+
+	divisions, teams, err := divisionTeams(ctx, repo, currentSeason)
+	if err != nil {
+		return err
+	}
+
+	// Every team of the season plays in its league
+	competition, err := seasonCompetition(ctx, repo, currentSeason.ID, FormatLeague)
+	if err != nil {
+		return err
+	}
+	if err := setCompetitionTeams(ctx, repo, competition, slices.Concat(teams...)); err != nil {
+		return err
+	}
+
+	// Divisions play their weeks side by side
+	rounds := make([][][]sqlc.CreateFixtureParams, len(divisions))
+	totalRounds := 0
+	for i, division := range divisions {
+		rounds[i] = roundRobin(teams[i], int(competition.Legs), division.ID, currentSeason.ID)
+		totalRounds = max(totalRounds, len(rounds[i]))
+	}
+	if totalRounds == 0 {
+		return ErrNotEnoughTeams
+	}
+
+	for round := 0; round < totalRounds; round++ {
+		for i := range divisions {
+			if round >= len(rounds[i]) {
+				continue
+			}
+			for _, fixture := range rounds[i][round] {
+				if err := repo.CreateFixture(ctx, fixture); err != nil {
+					return fmt.Errorf("failed to create fixture: %w", err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// roundRobinRounds returns the number of rounds in which n teams meet each
+// other legs times. An odd number of teams plays with a bye every round.
+func roundRobinRounds(n, legs int) int {
+	if n < 2 {
+		return 0
+	}
+	if n%2 != 0 {
+		n++
+	}
+	return legs * (n - 1)
+}
+
+// roundRobin returns the fixtures of a round-robin between teams in which
+// every pair of teams meets legs times, swapping home and away from one leg
+// to the next, grouped by week. Fewer than two teams play no fixtures.
+func roundRobin(teams []sqlc.Team, legs int, divisionID, seasonID int64) [][]sqlc.CreateFixtureParams {
+	n := len(teams)
+	if n < 2 {
+		return nil
+	}
+
+	// If odd number of teams, add a "bye" team
+	teams = slices.Clone(teams)
+	if n%2 != 0 {
+		teams = append(teams, sqlc.Team{ID: -1}) // Dummy team for odd number of teams
+		n++
+	}
+
+	// Each leg takes n-1 rounds
+	rounds := make([][]sqlc.CreateFixtureParams, roundRobinRounds(n, legs))
+
+	// In the first leg each team plays every other team once
+	for round := 1; round <= n-1; round++ {
+		for i := 0; i < n/2; i++ {
+			home, guest := teams[i], teams[n-1-i]
+
+			// Skip matches involving the dummy team
+			if home.ID == -1 || guest.ID == -1 {
+				continue
+			}
+
+			// The later legs repeat it, reversing home and away every leg
+			for leg := 0; leg < legs; leg++ {
+				fixture := sqlc.CreateFixtureParams{
+					HomeID:     home.ID,
+					GuestID:    guest.ID,
+					Played:     sql.NullBool{Bool: false, Valid: true},
+					Week:       int64(round + leg*(n-1)),
+					SeasonID:   seasonID,
+					DivisionID: sql.NullInt64{Int64: divisionID, Valid: true},
+				}
+				if leg%2 != 0 {
+					fixture.HomeID, fixture.GuestID = guest.ID, home.ID
+				}
+				rounds[fixture.Week-1] = append(rounds[fixture.Week-1], fixture)
+			}
+		}
+
+		// Rotate teams for next round (keep first team fixed, rotate others clockwise)
+		lastTeam := teams[n-1]
+		for i := n - 1; i > 1; i-- {
+			teams[i] = teams[i-1]
+		}
+		teams[1] = lastTeam
+	}
+
+	return rounds
+}
@@ -0,0 +1,118 @@
+package league
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+// LastMeetingsShown is how many of the latest meetings a head-to-head lists
+const LastMeetingsShown = 5
+
+var ErrSameTeam = errors.New("a team cannot be compared with itself")
+
+// Record counts the results of a team against an opponent.
+type Record struct {
+	Played       int64
+	Wins         int64
+	Draws        int64
+	Losses       int64
+	GoalsFor     int64
+	GoalsAgainst int64
+}
+
+// SeasonRecord is the record of a team against an opponent in one season.
+type SeasonRecord struct {
+	SeasonID   int64
+	SeasonYear int64
+	Record
+}
+
+// HeadToHead compares two teams over every season they met, seen from the
+// first one.
+type HeadToHead struct {
+	Team     sqlc.Team
+	Opponent sqlc.Team
+	AllTime  Record
+	Seasons  []SeasonRecord // oldest first
+	// BiggestWin and OpponentBiggestWin are the widest wins of each team,
+	// nil if it never won
+	BiggestWin         *sqlc.ListHeadToHeadMatchesRow
+	OpponentBiggestWin *sqlc.ListHeadToHeadMatchesRow
+	LastMeetings       []sqlc.ListHeadToHeadMatchesRow // latest first
+}
+
+// CompareTeams builds the head-to-head record of two teams from their played
+// matches across every season.
+func CompareTeams(ctx context.Context, repo repository.Repository, teamID, opponentID int64) (HeadToHead, error) {
+	if teamID == opponentID {
+		return HeadToHead{}, ErrSameTeam
+	}
+
+	team, err := getTeam(ctx, repo, teamID)
+	if err != nil {
+		return HeadToHead{}, err
+	}
+	opponent, err := getTeam(ctx, repo, opponentID)
+	if err != nil {
+		return HeadToHead{}, err
+	}
+	h2h := HeadToHead{Team: team, Opponent: opponent}
+
+	seasons, err := repo.ListHeadToHeadSeasons(ctx, teamID, opponentID)
+	if err != nil {
+		return HeadToHead{}, fmt.Errorf("failed to fetch head-to-head record: %w", err)
+	}
+	for _, row := range seasons {
+		record := Record{
+			Played:       row.Played,
+			Wins:         row.Wins,
+			Draws:        row.Draws,
+			Losses:       row.Losses,
+			GoalsFor:     row.GoalsFor,
+			GoalsAgainst: row.GoalsAgainst,
+		}
+		h2h.Seasons = append(h2h.Seasons, SeasonRecord{SeasonID: row.SeasonID, SeasonYear: row.SeasonYear, Record: record})
+
+		h2h.AllTime.Played += record.Played
+		h2h.AllTime.Wins += record.Wins
+		h2h.AllTime.Draws += record.Draws
+		h2h.AllTime.Losses += record.Losses
+		h2h.AllTime.GoalsFor += record.GoalsFor
+		h2h.AllTime.GoalsAgainst += record.GoalsAgainst
+	}
+
+	meetings, err := repo.ListHeadToHeadMatches(ctx, teamID, opponentID)
+	if err != nil {
+		return HeadToHead{}, fmt.Errorf("failed to fetch head-to-head matches: %w", err)
+	}
+	h2h.LastMeetings = meetings[:min(len(meetings), LastMeetingsShown)]
+	h2h.BiggestWin = biggestWin(meetings, teamID)
+	h2h.OpponentBiggestWin = biggestWin(meetings, opponentID)
+
+	return h2h, nil
+}
+
+// biggestWin returns the meeting the team won by the most goals, preferring
+// the one it scored more in and then the latest one.
+func biggestWin(meetings []sqlc.ListHeadToHeadMatchesRow, teamID int64) *sqlc.ListHeadToHeadMatchesRow {
+	var best *sqlc.ListHeadToHeadMatchesRow
+	var bestMargin, bestScored int64
+	for i, m := range meetings {
+		scored, conceded := m.HomeScore, m.GuestScore
+		if m.GuestID == teamID {
+			scored, conceded = m.GuestScore, m.HomeScore
+		}
+		margin := scored - conceded
+		if margin <= 0 {
+			continue
+		}
+		if best == nil || margin > bestMargin || (margin == bestMargin && scored > bestScored) {
+			best, bestMargin, bestScored = &meetings[i], margin, scored
+		}
+	}
+	return best
+}
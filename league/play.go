@@ -0,0 +1,318 @@
+package league
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+var (
+	ErrMatchNotFound  = errors.New("match not found in the current season")
+	ErrMatchForfeited = errors.New("match is forfeited under a sanction")
+)
+
+// PlayWeek plays every unplayed match of the current week and returns the
+// number of matches played.
+func PlayWeek(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator) (int, error) {
+	currentSeason, err := activeSeason(ctx, repo)
+	if err != nil {
+		return 0, err
+	}
+
+	currentWeek, err := repo.GetCurrentWeek(ctx, currentSeason.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch current week: %w", err)
+	}
+
+	played, err := playWeek(ctx, repo, sim, currentSeason, currentWeek)
+	if err != nil {
+		return 0, fmt.Errorf("failed to play week %d: %w", currentWeek, err)
+	}
+	if played == 0 {
+		return 0, ErrNoMatchesToPlay
+	}
+
+	return played, nil
+}
+
+// NextWeek moves the current season to its next week once every match of
+// the current week is played. It returns ErrSeasonComplete after the last
+// week.
+func NextWeek(ctx context.Context, repo repository.Repository) error {
+	currentSeason, err := activeSeason(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	currentWeek, err := repo.GetCurrentWeek(ctx, currentSeason.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch current week: %w", err)
+	}
+
+	// Check if there are any matches for the current week
+	matches, err := repo.GetMatchesByWeek(ctx, int64(currentWeek), currentSeason.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check matches status: %w", err)
+	}
+	if err == sql.ErrNoRows || len(matches) == 0 {
+		return ErrNoFixtures
+	}
+
+	allPlayed, err := repo.GetAllMatchesPlayedForWeek(ctx, int64(currentWeek), currentSeason.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check matches status: %w", err)
+	}
+	if !allPlayed {
+		return ErrWeekNotFinished
+	}
+
+	// Check if this is the last week
+	totalWeeks, err := TotalWeeks(ctx, repo, currentSeason.ID)
+	if err != nil {
+		return err
+	}
+	if currentWeek >= totalWeeks {
+		return ErrSeasonComplete
+	}
+
+	if err := repo.IncrementWeek(ctx, currentSeason.ID); err != nil {
+		return fmt.Errorf("failed to increment week: %w", err)
+	}
+	return nil
+}
+
+// PlayAll plays every remaining week of the current season, committing each
+// week together with the move to the next one.
+func PlayAll(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator) error {
+	currentSeason, err := activeSeason(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	totalWeeks, err := TotalWeeks(ctx, repo, currentSeason.ID)
+	if err != nil {
+		return err
+	}
+
+	currentWeek, err := repo.GetCurrentWeek(ctx, currentSeason.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch current week: %w", err)
+	}
+
+	for week := currentWeek; week <= totalWeeks; week++ {
+		err = repo.WithTx(ctx, func(tx repository.Repository) error {
+			if _, err := playWeek(ctx, tx, sim, currentSeason, week); err != nil {
+				return err
+			}
+
+			// Move to next week if not the last week
+			if week < totalWeeks {
+				if err := tx.IncrementWeek(ctx, currentSeason.ID); err != nil {
+					return fmt.Errorf("failed to increment week: %w", err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to play week %d: %w", week, err)
+		}
+	}
+
+	return nil
+}
+
+// playWeek plays every unplayed match, cup tie and tournament match of a week
+// in a single transaction, so either all results and standings of the week
+// are stored or none are. It returns the number of them played.
+func playWeek(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, season sqlc.Season, week int) (int, error) {
+	var played int
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		matches, err := tx.GetUnplayedMatchesByWeek(ctx, int64(week), season.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to fetch matches: %w", err)
+		}
+		sanctions, err := tx.ListSanctions(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sanctions: %w", err)
+		}
+		strength, err := seasonStrengths(ctx, tx, season.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			log.Printf("Playing match: Home(%s) vs Guest(%s)", match.HomeTeamName, match.GuestTeamName)
+
+			if err := playMatch(ctx, tx, sim, season, match, strength, sanctions); err != nil {
+				return fmt.Errorf("match %d: %w", match.ID, err)
+			}
+		}
+
+		// Cup ties of the week are played alongside the league
+		ties, err := playCupWeek(ctx, tx, sim, season, week)
+		if err != nil {
+			return err
+		}
+		tournamentMatches, err := playTournamentWeek(ctx, tx, sim, season, week)
+		if err != nil {
+			return err
+		}
+
+		// Bring the cached standings in line with the new results
+		if err := standings.Refresh(ctx, tx, season.ID); err != nil {
+			return fmt.Errorf("failed to update standings: %w", err)
+		}
+
+		played = len(matches) + ties + tournamentMatches
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return played, nil
+}
+
+// playMatch simulates a single fixture and stores its result. The match is
+// seeded from the season seed and the match ID, and played with the
+// strength from seasonStrengths, so the same season always produces the
+// same scores. A match forfeited under the season's sanctions is awarded
+// instead of simulated.
+func playMatch(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, season sqlc.Season, match sqlc.GetUnplayedMatchesByWeekRow, strength map[int64]int64, sanctions []sqlc.ListSanctionsRow) error {
+	homeScore, guestScore, forfeited := forfeit(sanctions, sqlc.Match{
+		ID:      match.ID,
+		HomeID:  match.HomeID,
+		GuestID: match.GuestID,
+		Week:    match.Week,
+	})
+	if !forfeited {
+		result := sim.Simulate(
+			simulation.MatchRand(season.Seed, match.ID),
+			simulation.RatingFromStrength(strength[match.HomeID]),
+			simulation.RatingFromStrength(strength[match.GuestID]),
+		)
+		homeScore, guestScore = result.HomeScore, result.GuestScore
+	}
+	log.Printf("Score: %s %d-%d %s", match.HomeTeamName, homeScore, guestScore, match.GuestTeamName)
+
+	// Save match result
+	err := repo.SaveResult(ctx, sqlc.SaveResultParams{
+		MatchID:    match.ID,
+		HomeScore:  homeScore,
+		GuestScore: guestScore,
+		WinnerID:   MatchWinner(match.HomeID, match.GuestID, homeScore, guestScore),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save match result: %w", err)
+	}
+
+	// Mark match as played
+	err = repo.MarkMatchAsPlayed(ctx, match.ID)
+	if err != nil {
+		return fmt.Errorf("failed to mark match as played: %w", err)
+	}
+
+	return nil
+}
+
+// EditResult replaces the score of a league match of the current season up
+// to its current week and brings the standings in line. Scores cannot be
+// negative, and a match forfeited under a sanction keeps the score it was
+// awarded, returning ErrMatchForfeited.
+func EditResult(ctx context.Context, repo repository.Repository, matchID, homeScore, guestScore int64) error {
+	fields := map[string]string{}
+	if homeScore < 0 {
+		fields["home_score"] = "Home score cannot be negative"
+	}
+	if guestScore < 0 {
+		fields["guest_score"] = "Guest score cannot be negative"
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+
+	currentSeason, err := activeSeason(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	currentWeek, err := repo.GetCurrentWeek(ctx, currentSeason.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch current week: %w", err)
+	}
+
+	return repo.WithTx(ctx, func(tx repository.Repository) error {
+		// Search for the match in all weeks up to current week
+		var match sqlc.GetMatchesByWeekRow
+		for week := 1; week <= currentWeek && match.ID == 0; week++ {
+			matches, err := tx.GetMatchesByWeek(ctx, int64(week), currentSeason.ID)
+			if err != nil && err != sql.ErrNoRows {
+				return fmt.Errorf("failed to fetch matches: %w", err)
+			}
+			for _, m := range matches {
+				if m.ID == matchID {
+					match = m
+					break
+				}
+			}
+		}
+		if match.ID == 0 {
+			return ErrMatchNotFound
+		}
+
+		sanctions, err := tx.ListSanctions(ctx, currentSeason.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sanctions: %w", err)
+		}
+		if _, _, forfeited := forfeit(sanctions, sqlc.Match{
+			ID:      match.ID,
+			HomeID:  match.HomeID,
+			GuestID: match.GuestID,
+			Week:    match.Week,
+		}); forfeited {
+			return ErrMatchForfeited
+		}
+
+		err = tx.SaveResult(ctx, sqlc.SaveResultParams{
+			MatchID:    matchID,
+			HomeScore:  homeScore,
+			GuestScore: guestScore,
+			WinnerID:   MatchWinner(match.HomeID, match.GuestID, homeScore, guestScore),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update match result: %w", err)
+		}
+
+		// Mark match as played if not already
+		if !match.Played.Bool {
+			if err := tx.MarkMatchAsPlayed(ctx, matchID); err != nil {
+				return fmt.Errorf("failed to mark match as played: %w", err)
+			}
+		}
+
+		// Update standings from the corrected results
+		if err := standings.Refresh(ctx, tx, currentSeason.ID); err != nil {
+			return fmt.Errorf("failed to update standings: %w", err)
+		}
+		return nil
+	})
+}
+
+// MatchWinner returns the ID of the winning team, or an invalid value for a draw
+func MatchWinner(homeID, guestID, homeScore, guestScore int64) sql.NullInt64 {
+	switch {
+	case homeScore > guestScore:
+		return sql.NullInt64{Int64: homeID, Valid: true}
+	case guestScore > homeScore:
+		return sql.NullInt64{Int64: guestID, Valid: true}
+	default:
+		return sql.NullInt64{}
+	}
+}
@@ -0,0 +1,80 @@
+package league
+
+import (
+	"context"
+
+	"github.com/orhosko/go-backend/prediction"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+// Predictions estimates each team's chance of winning the title, finishing
+// in the top places and finishing last by simulating the remaining fixtures
+// of the season. Only the top division plays for the title.
+func Predictions(ctx context.Context, repo repository.Repository, predictor *prediction.MonteCarloPredictor, season sqlc.Season) ([]prediction.TeamProbability, error) {
+	rules, err := standings.LeagueRules(ctx, repo, season.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := standings.LeaguePoints(ctx, repo, season.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the current table as the starting point of every simulation
+	tables, err := Tables(ctx, repo, season)
+	if err != nil {
+		return nil, err
+	}
+	top := tables[0]
+
+	strength, err := seasonStrengths(ctx, repo, season.ID)
+	if err != nil {
+		return nil, err
+	}
+	var states []prediction.TeamState
+	for _, entry := range top.Entries {
+		states = append(states, prediction.TeamState{
+			Entry:  entry,
+			Rating: simulation.RatingFromStrength(strength[entry.Team.ID]),
+		})
+	}
+
+	// Results already played count towards head-to-head tie-breakers
+	results, err := standings.Results(ctx, repo, season.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get every fixture still to be played this season
+	matches, err := repo.GetUnplayedMatchesBySeason(ctx, season.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fixtures forfeited under a sanction are awarded as PlayWeek will
+	sanctions, err := repo.ListSanctions(ctx, season.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := make([]prediction.Fixture, 0, len(matches))
+	for _, match := range matches {
+		if top.Division.ID != 0 && match.DivisionID.Int64 != top.Division.ID {
+			continue
+		}
+		fixture := prediction.Fixture{
+			HomeID:  match.HomeID,
+			GuestID: match.GuestID,
+		}
+		if homeScore, guestScore, forfeited := forfeit(sanctions, match); forfeited {
+			fixture.Awarded = &simulation.Result{HomeScore: homeScore, GuestScore: guestScore}
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	return predictor.Predict(season.Seed, rules, points, states, results, fixtures), nil
+}
@@ -0,0 +1,132 @@
+package league
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+const (
+	// PrizeMoneyPerPlace is the prize money a team earns for every team it
+	// finishes level with or above, so the last team earns it once
+	PrizeMoneyPerPlace = 10000000
+	// DevelopmentSpread is the standard deviation of the random change in
+	// strength a team goes through between two seasons
+	DevelopmentSpread = 0.6
+)
+
+// progressionStream offsets the random streams of the teams' development
+// from the match IDs and the negative division IDs of the playoffs.
+const progressionStream = -1 << 40
+
+// progressTeams lets every team of a complete season develop before the
+// next one. Each team earns prize money for its place across the divisions,
+// and its strength moves up when it finished in the top half, down when it
+// finished in the bottom half, plus a random development factor drawn from
+// the season seed. The change is recorded once per season, along with the
+// place as the team's last season standing.
+func progressTeams(ctx context.Context, repo repository.Repository, season sqlc.Season) error {
+	if !season.IsComplete.Bool {
+		return nil
+	}
+
+	history, err := repo.ListTeamHistory(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch team history: %w", err)
+	}
+	for _, entry := range history {
+		if entry.SeasonID == season.ID {
+			return nil
+		}
+	}
+
+	tables, err := Tables(ctx, repo, season)
+	if err != nil {
+		return err
+	}
+
+	// The divisions come from the top down, so the teams of a division rank
+	// below every team of the divisions above it. Teams that joined after
+	// the season did not play it.
+	var entries []standings.Entry
+	for _, table := range tables {
+		for _, entry := range table.Entries {
+			if entry.Played > 0 {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	n := len(entries)
+	for i, entry := range entries {
+		team := entry.Team
+		prize := int64(n-i) * PrizeMoneyPerPlace
+
+		// form is 1 for the champion, -1 for the last team
+		form := 1.0
+		if n > 1 {
+			form = 1 - 2*float64(i)/float64(n-1)
+		}
+		rng := simulation.StreamRand(season.Seed, progressionStream-team.ID)
+		change := int64(math.Round(form + rng.NormFloat64()*DevelopmentSpread))
+		strength := min(max(team.Strength.Int64+change, MinStrength), MaxStrength)
+
+		err := repo.UpdateTeam(ctx, sqlc.UpdateTeamParams{
+			ID:       team.ID,
+			Name:     team.Name,
+			Strength: sql.NullInt64{Int64: strength, Valid: true},
+			Budget:   sql.NullInt64{Int64: team.Budget.Int64 + prize, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update team: %w", err)
+		}
+
+		err = repo.CreateTeamHistory(ctx, sqlc.CreateTeamHistoryParams{
+			TeamID:             team.ID,
+			SeasonID:           season.ID,
+			LastSeasonStanding: int64(i + 1),
+			PrizeMoney:         prize,
+			StrengthBefore:     team.Strength.Int64,
+			StrengthAfter:      strength,
+			BudgetBefore:       team.Budget.Int64,
+			BudgetAfter:        team.Budget.Int64 + prize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save team history: %w", err)
+		}
+	}
+	return nil
+}
+
+// seasonStrengths returns the strength each team plays a season with, by
+// team ID. Progression changes the teams' strength at the end of a season,
+// so a season it went through, even if rolled back since, keeps the
+// strength the team history recorded before it. Any other season is played
+// with the teams' current strength.
+func seasonStrengths(ctx context.Context, repo repository.Repository, seasonID int64) (map[int64]int64, error) {
+	teams, err := repo.ListTeams(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch teams: %w", err)
+	}
+	strength := make(map[int64]int64, len(teams))
+	for _, team := range teams {
+		strength[team.ID] = team.Strength.Int64
+	}
+
+	history, err := repo.ListTeamHistory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch team history: %w", err)
+	}
+	for _, entry := range history {
+		if entry.SeasonID == seasonID {
+			strength[entry.TeamID] = entry.StrengthBefore
+		}
+	}
+	return strength, nil
+}
@@ -0,0 +1,139 @@
+package league
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+var (
+	ErrSeasonNotFound = errors.New("season not found")
+	ErrInvalidWeek    = errors.New("week is outside the season")
+	ErrInvalidToken   = errors.New("confirmation token does not match the season")
+)
+
+// Rollback describes what rolling a season back to a week undoes. Its token
+// confirms the rollback and changes whenever the season does, so a
+// confirmation given for an older state of the season is refused.
+type Rollback struct {
+	Season     sqlc.Season
+	Week       int // first week whose results are undone
+	TotalWeeks int
+	Results    int // number of match, cup tie and tournament results undone
+	Token      string
+}
+
+// PlanRollback returns what rolling a season back to a week would undo.
+func PlanRollback(ctx context.Context, repo repository.Repository, seasonID int64, week int) (Rollback, error) {
+	season, err := repo.GetSeason(ctx, seasonID)
+	if err == sql.ErrNoRows {
+		return Rollback{}, ErrSeasonNotFound
+	}
+	if err != nil {
+		return Rollback{}, fmt.Errorf("failed to fetch season: %w", err)
+	}
+
+	totalWeeks, err := TotalWeeks(ctx, repo, season.ID)
+	if err != nil {
+		return Rollback{}, err
+	}
+	if week < 1 || week > totalWeeks {
+		return Rollback{}, ErrInvalidWeek
+	}
+
+	currentWeek, err := repo.GetCurrentWeek(ctx, season.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return Rollback{}, fmt.Errorf("failed to fetch current week: %w", err)
+	}
+
+	results, err := repo.GetResultsBySeason(ctx, season.ID)
+	if err != nil {
+		return Rollback{}, fmt.Errorf("failed to fetch results: %w", err)
+	}
+
+	// The token covers everything the rollback changes
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d:%d:%d:%t:%t", season.ID, week, currentWeek, season.IsCurrent.Bool, season.IsComplete.Bool)
+	undone := 0
+	for _, result := range results {
+		if result.Week < int64(week) {
+			continue
+		}
+		undone++
+		fmt.Fprintf(hash, ":%d=%d-%d", result.ID, result.HomeScore, result.GuestScore)
+	}
+
+	cup, err := GetCup(ctx, repo, season)
+	if err != nil && !errors.Is(err, ErrNoCup) {
+		return Rollback{}, err
+	}
+	for _, round := range cup.Rounds {
+		for _, tie := range round.Ties {
+			if tie.Week < int64(week) || !tie.Played || Bye(tie) {
+				continue
+			}
+			undone++
+			fmt.Fprintf(hash, ":cup%d=%d-%d/%d-%d", tie.ID, tie.HomeScore.Int64, tie.GuestScore.Int64, tie.HomePenalties.Int64, tie.GuestPenalties.Int64)
+		}
+	}
+
+	tournament, err := GetTournament(ctx, repo, season)
+	if err != nil && !errors.Is(err, ErrNoTournament) {
+		return Rollback{}, err
+	}
+	var tournamentMatches []sqlc.ListTournamentMatchesRow
+	for _, group := range tournament.Groups {
+		tournamentMatches = append(tournamentMatches, group.Matches...)
+	}
+	for _, round := range tournament.Rounds {
+		for _, tie := range round.Ties {
+			tournamentMatches = append(tournamentMatches, tie.First, tie.Second)
+		}
+	}
+	for _, match := range tournamentMatches {
+		if match.Week < int64(week) || !match.Played {
+			continue
+		}
+		undone++
+		fmt.Fprintf(hash, ":tournament%d=%d-%d/%d-%d", match.ID, match.HomeScore.Int64, match.GuestScore.Int64, match.HomePenalties.Int64, match.GuestPenalties.Int64)
+	}
+
+	return Rollback{
+		Season:     season,
+		Week:       week,
+		TotalWeeks: totalWeeks,
+		Results:    undone,
+		Token:      hex.EncodeToString(hash.Sum(nil))[:16],
+	}, nil
+}
+
+// RollbackSeason undoes the results of a season from the given week onward,
+// recomputes its standings and makes it the current season so it can be
+// played again. Other seasons keep their history. The token must be the one
+// of the season's current rollback plan.
+func RollbackSeason(ctx context.Context, repo repository.Repository, seasonID int64, week int, token string) error {
+	return repo.WithTx(ctx, func(tx repository.Repository) error {
+		plan, err := PlanRollback(ctx, tx, seasonID, week)
+		if err != nil {
+			return err
+		}
+		if token == "" || token != plan.Token {
+			return ErrInvalidToken
+		}
+
+		if err := tx.RollbackSeason(ctx, seasonID, int64(week)); err != nil {
+			return fmt.Errorf("failed to roll back season: %w", err)
+		}
+		if err := tx.SetCurrentSeason(ctx, seasonID); err != nil {
+			return fmt.Errorf("failed to set current season: %w", err)
+		}
+		return standings.Refresh(ctx, tx, seasonID)
+	})
+}
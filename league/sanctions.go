@@ -0,0 +1,358 @@
+package league
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+// Kinds of sanction. A deduction takes points from a team, a forfeit loses
+// one of its league matches and a ban forfeits all its league matches over
+// a range of weeks.
+const (
+	SanctionDeduction = standings.DeductionKind
+	SanctionForfeit   = "forfeit"
+	SanctionBan       = standings.BanKind
+)
+
+// ForfeitGoals is the score a match is awarded by to the team whose opponent
+// forfeits it.
+const ForfeitGoals = 3
+
+// MaxReasonLength bounds the length of the reason for a sanction
+const MaxReasonLength = 200
+
+var ErrSanctionNotFound = errors.New("sanction not found")
+
+// SanctionInput holds the fields of a sanction. Points only apply to a
+// deduction, MatchID to a forfeit and FirstWeek and LastWeek to a ban.
+// Forfeits and bans apply to the league, deductions to the competition of
+// Format, the league if empty.
+type SanctionInput struct {
+	Kind      string
+	Format    string
+	TeamID    int64
+	Points    int64
+	MatchID   int64
+	FirstWeek int64
+	LastWeek  int64
+	Reason    string
+}
+
+// validate checks a sanction against a team of a competition, returning a
+// *ValidationError if any field is invalid. It fills in the weeks of a
+// forfeit from its match.
+func (in *SanctionInput) validate(competition sqlc.Competition, teams []sqlc.ListCompetitionTeamsRow, matches []sqlc.GetMatchesBySeasonRow, weeks int) error {
+	fields := map[string]string{}
+
+	switch in.Kind {
+	case SanctionDeduction:
+		if competition.Format == FormatCup {
+			fields["format"] = "A knockout cup awards no points to deduct"
+		}
+		if in.Points < 1 {
+			fields["points"] = "At least one point must be deducted"
+		}
+	case SanctionForfeit:
+		if competition.Format != FormatLeague {
+			fields["format"] = "Only league matches can be forfeited"
+		}
+		i := slices.IndexFunc(matches, func(m sqlc.GetMatchesBySeasonRow) bool { return m.ID == in.MatchID })
+		if i < 0 || (matches[i].HomeID != in.TeamID && matches[i].GuestID != in.TeamID) {
+			fields["match_id"] = "The match is not a league match of the team this season"
+		} else {
+			in.FirstWeek, in.LastWeek = matches[i].Week, matches[i].Week
+		}
+	case SanctionBan:
+		if competition.Format != FormatLeague {
+			fields["format"] = "Bans only apply to league matches"
+		}
+		if in.FirstWeek < 1 || in.LastWeek < in.FirstWeek || in.LastWeek > int64(weeks) {
+			fields["weeks"] = fmt.Sprintf("Weeks must run forwards between 1 and %d", weeks)
+		}
+	default:
+		fields["kind"] = fmt.Sprintf("Kind must be %s, %s or %s", SanctionDeduction, SanctionForfeit, SanctionBan)
+	}
+
+	if !slices.ContainsFunc(teams, func(t sqlc.ListCompetitionTeamsRow) bool { return t.TeamID == in.TeamID }) {
+		fields["team_id"] = fmt.Sprintf("The team does not take part in the %s", competition.Name)
+	}
+	reason := strings.TrimSpace(in.Reason)
+	switch {
+	case reason == "":
+		fields["reason"] = "Reason is required"
+	case len(reason) > MaxReasonLength:
+		fields["reason"] = fmt.Sprintf("Reason must be at most %d characters", MaxReasonLength)
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// ListSanctions returns the sanctions taken in a season in the order they
+// were taken.
+func ListSanctions(ctx context.Context, repo repository.Repository, seasonID int64) ([]sqlc.ListSanctionsRow, error) {
+	sanctions, err := repo.ListSanctions(ctx, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sanctions: %w", err)
+	}
+	return sanctions, nil
+}
+
+// AddSanction takes a sanction against a team in the current season and
+// returns it. Sanctions can be taken at any time of the season: forfeited
+// matches already played are awarded 3-0 at once, replacing their result,
+// and the league table counts deductions straight away.
+func AddSanction(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, in SanctionInput) (sqlc.ListSanctionsRow, error) {
+	in.Reason = strings.TrimSpace(in.Reason)
+	if in.Format == "" {
+		in.Format = FormatLeague
+	}
+
+	var sanction sqlc.ListSanctionsRow
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		season, err := activeSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		competition, err := getCompetition(ctx, tx, season.ID, in.Format)
+		if err != nil {
+			return err
+		}
+		teams, err := tx.ListCompetitionTeams(ctx, competition.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch competition teams: %w", err)
+		}
+		matches, err := tx.GetMatchesBySeason(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch matches: %w", err)
+		}
+		weeks, err := TotalWeeks(ctx, tx, season.ID)
+		if err != nil {
+			return err
+		}
+		if err := in.validate(competition, teams, matches, weeks); err != nil {
+			return err
+		}
+
+		created, err := tx.CreateSanction(ctx, sqlc.CreateSanctionParams{
+			CompetitionID: competition.ID,
+			TeamID:        in.TeamID,
+			Kind:          in.Kind,
+			Points:        sanctionPoints(in),
+			MatchID:       sql.NullInt64{Int64: in.MatchID, Valid: in.Kind == SanctionForfeit},
+			FirstWeek:     in.FirstWeek,
+			LastWeek:      in.LastWeek,
+			Reason:        in.Reason,
+			CreatedAt:     time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create sanction: %w", err)
+		}
+		if err := replaySanctioned(ctx, tx, sim, season, created); err != nil {
+			return err
+		}
+
+		sanctions, err := tx.ListSanctions(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sanctions: %w", err)
+		}
+		i := slices.IndexFunc(sanctions, func(s sqlc.ListSanctionsRow) bool { return s.ID == created.ID })
+		if i < 0 {
+			return ErrSanctionNotFound
+		}
+		sanction = sanctions[i]
+		return nil
+	})
+	if err != nil {
+		return sqlc.ListSanctionsRow{}, err
+	}
+	return sanction, nil
+}
+
+// AddPointDeduction deducts points from a team of a competition of the
+// current season, given by its format, and returns the competition. It
+// backs the deduction endpoints of the v1 API, which predate the other
+// kinds of sanction.
+func AddPointDeduction(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, format string, in SanctionInput) (Competition, error) {
+	in.Kind, in.Format = SanctionDeduction, format
+	if _, err := AddSanction(ctx, repo, sim, in); err != nil {
+		return Competition{}, err
+	}
+
+	season, err := activeSeason(ctx, repo)
+	if err != nil {
+		return Competition{}, err
+	}
+	return findCompetition(ctx, repo, season, format)
+}
+
+// DeletePointDeduction gives back the points of a deduction taken in the
+// current season, like DeleteSanction for the deductions only.
+func DeletePointDeduction(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, id int64) error {
+	return deleteSanction(ctx, repo, sim, id, SanctionDeduction)
+}
+
+// DeleteSanction lifts a sanction taken in the current season. The played
+// matches it forfeited are played again as if it had never been taken.
+func DeleteSanction(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, id int64) error {
+	return deleteSanction(ctx, repo, sim, id, "")
+}
+
+// deleteSanction lifts a sanction taken in the current season, which must
+// be of the given kind unless it is empty.
+func deleteSanction(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, id int64, kind string) error {
+	return repo.WithTx(ctx, func(tx repository.Repository) error {
+		season, err := activeSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		sanction, err := tx.GetSanction(ctx, id)
+		if err == sql.ErrNoRows || (err == nil && kind != "" && sanction.Kind != kind) {
+			return ErrSanctionNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch sanction: %w", err)
+		}
+		competition, err := tx.GetCompetition(ctx, sanction.CompetitionID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch competition: %w", err)
+		}
+		// Past seasons are archived, their sanctions included
+		if competition.SeasonID != season.ID {
+			return ErrSanctionNotFound
+		}
+
+		if err := tx.DeleteSanction(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete sanction: %w", err)
+		}
+		return replaySanctioned(ctx, tx, sim, season, sanction)
+	})
+}
+
+// sanctionPoints returns the points a sanction deducts, none unless it is a
+// deduction.
+func sanctionPoints(in SanctionInput) int64 {
+	if in.Kind != SanctionDeduction {
+		return 0
+	}
+	return in.Points
+}
+
+// replaySanctioned plays again the played league matches a sanction
+// forfeits, or forfeited before it was lifted, by the sanctions now in
+// place, then brings the cached standings in line.
+func replaySanctioned(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, season sqlc.Season, sanction sqlc.Sanction) error {
+	if sanction.Kind != SanctionDeduction {
+		sanctions, err := repo.ListSanctions(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch sanctions: %w", err)
+		}
+		strength, err := seasonStrengths(ctx, repo, season.ID)
+		if err != nil {
+			return err
+		}
+
+		for week := sanction.FirstWeek; week <= sanction.LastWeek; week++ {
+			matches, err := repo.GetMatchesByWeek(ctx, week, season.ID)
+			if err != nil && err != sql.ErrNoRows {
+				return fmt.Errorf("failed to fetch matches: %w", err)
+			}
+			for _, match := range matches {
+				if !match.Played.Bool || (match.HomeID != sanction.TeamID && match.GuestID != sanction.TeamID) {
+					continue
+				}
+				if sanction.Kind == SanctionForfeit && match.ID != sanction.MatchID.Int64 {
+					continue
+				}
+				if err := playMatch(ctx, repo, sim, season, sqlc.GetUnplayedMatchesByWeekRow(match), strength, sanctions); err != nil {
+					return fmt.Errorf("match %d: %w", match.ID, err)
+				}
+			}
+		}
+	}
+
+	if err := standings.Refresh(ctx, repo, season.ID); err != nil {
+		return fmt.Errorf("failed to update standings: %w", err)
+	}
+	return nil
+}
+
+// forfeit returns the score of a league match forfeited by either team
+// under the sanctions of its season: 3-0 to the other team, or 0-0 if both
+// forfeit it. It reports false if neither team does.
+func forfeit(sanctions []sqlc.ListSanctionsRow, match sqlc.Match) (int64, int64, bool) {
+	home := slices.ContainsFunc(sanctions, func(s sqlc.ListSanctionsRow) bool { return forfeits(s, match, match.HomeID) })
+	guest := slices.ContainsFunc(sanctions, func(s sqlc.ListSanctionsRow) bool { return forfeits(s, match, match.GuestID) })
+	switch {
+	case home && guest:
+		return 0, 0, true
+	case home:
+		return 0, ForfeitGoals, true
+	case guest:
+		return ForfeitGoals, 0, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// forfeits reports whether a sanction makes a team forfeit a league match.
+func forfeits(sanction sqlc.ListSanctionsRow, match sqlc.Match, teamID int64) bool {
+	if sanction.TeamID != teamID || sanction.Format != FormatLeague {
+		return false
+	}
+	switch sanction.Kind {
+	case SanctionForfeit:
+		return sanction.MatchID.Valid && sanction.MatchID.Int64 == match.ID
+	case SanctionBan:
+		return sanction.FirstWeek <= match.Week && match.Week <= sanction.LastWeek
+	default:
+		return false
+	}
+}
+
+// SanctionNotes describes the sanctions against each team of a competition
+// of a season, in the order they were taken, to annotate its table.
+func SanctionNotes(sanctions []sqlc.ListSanctionsRow, format string) map[int64][]string {
+	notes := map[int64][]string{}
+	for _, sanction := range sanctions {
+		if sanction.Format != format {
+			continue
+		}
+		notes[sanction.TeamID] = append(notes[sanction.TeamID], SanctionSummary(sanction)+": "+sanction.Reason)
+	}
+	return notes
+}
+
+// SanctionSummary describes what a sanction does, without its reason.
+func SanctionSummary(sanction sqlc.ListSanctionsRow) string {
+	switch sanction.Kind {
+	case SanctionDeduction:
+		if sanction.Points == 1 {
+			return "1 point deducted"
+		}
+		return fmt.Sprintf("%d points deducted", sanction.Points)
+	case SanctionForfeit:
+		return fmt.Sprintf("Forfeited the week %d match", sanction.FirstWeek)
+	case SanctionBan:
+		if sanction.FirstWeek == sanction.LastWeek {
+			return fmt.Sprintf("Banned from the week %d fixtures", sanction.FirstWeek)
+		}
+		return fmt.Sprintf("Banned from the fixtures of weeks %d to %d", sanction.FirstWeek, sanction.LastWeek)
+	default:
+		return sanction.Kind
+	}
+}
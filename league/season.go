@@ -0,0 +1,260 @@
+// Package league implements the actions that drive a season: generating
+// fixtures, simulating weeks and starting new seasons. It is shared by the
+// HTML pages and the JSON API.
+package league
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+// InitialYear is the year of the first season.
+const InitialYear = 2025
+
+var (
+	ErrNoActiveSeason  = errors.New("no active season, please generate fixtures first")
+	ErrNoMatchesToPlay = errors.New("no matches available to play, please generate fixtures first")
+	ErrNoFixtures      = errors.New("no matches found for current week, please generate fixtures first")
+	ErrWeekNotFinished = errors.New("cannot proceed to next week until all matches are played")
+	ErrSeasonComplete  = errors.New("season is complete")
+	ErrUnknownRules    = errors.New("unknown ranking rules")
+)
+
+// NewSeed returns a random seed for a season that was not given one.
+func NewSeed() int64 {
+	return rand.Int63()
+}
+
+// CurrentSeason returns the current season, creating the initial season if
+// none exists yet.
+func CurrentSeason(ctx context.Context, repo repository.Repository) (sqlc.Season, error) {
+	season, err := repo.GetCurrentSeason(ctx)
+	if err == nil {
+		return season, nil
+	}
+	if err != sql.ErrNoRows {
+		return sqlc.Season{}, fmt.Errorf("failed to fetch current season: %w", err)
+	}
+
+	// If no season exists, create one starting from the initial year
+	season, err = repo.CreateNewSeason(ctx, InitialYear, NewSeed())
+	if err != nil {
+		return sqlc.Season{}, fmt.Errorf("failed to create initial season: %w", err)
+	}
+	if err := repo.SetCurrentSeason(ctx, season.ID); err != nil {
+		return sqlc.Season{}, fmt.Errorf("failed to set current season: %w", err)
+	}
+	return season, nil
+}
+
+// activeSeason returns the current season without creating one.
+func activeSeason(ctx context.Context, repo repository.Repository) (sqlc.Season, error) {
+	season, err := repo.GetCurrentSeason(ctx)
+	if err == sql.ErrNoRows {
+		return sqlc.Season{}, ErrNoActiveSeason
+	}
+	if err != nil {
+		return sqlc.Season{}, fmt.Errorf("failed to fetch current season: %w", err)
+	}
+	return season, nil
+}
+
+// TotalWeeks returns the number of weeks in a season. Once fixtures are
+// generated it is fixed by them, otherwise it is the length of the league's
+// round-robin in the season's largest division.
+func TotalWeeks(ctx context.Context, repo repository.Repository, seasonID int64) (int, error) {
+	weeks, err := repo.GetSeasonWeeks(ctx, seasonID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count weeks: %w", err)
+	}
+	if weeks > 0 {
+		return weeks, nil
+	}
+
+	teams, err := repo.ListTeams(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch teams: %w", err)
+	}
+	divisions, err := repo.ListDivisions(ctx, seasonID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch divisions: %w", err)
+	}
+	members, err := repo.ListDivisionTeams(ctx, seasonID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch division teams: %w", err)
+	}
+
+	// Teams in no division yet will join the bottom one
+	sizes := map[int64]int{}
+	for _, member := range members {
+		sizes[member.DivisionID]++
+	}
+	var bottom int64
+	if len(divisions) > 0 {
+		bottom = divisions[len(divisions)-1].ID
+	}
+	sizes[bottom] += len(teams) - len(members)
+
+	n := 0
+	for _, size := range sizes {
+		n = max(n, size)
+	}
+
+	competition, err := competitionRules(ctx, repo, seasonID, FormatLeague)
+	if err != nil {
+		return 0, err
+	}
+	return roundRobinRounds(n, int(competition.Legs)), nil
+}
+
+// StartNewSeason creates the season following the current one, makes it
+// current and generates its fixtures. Its divisions are seeded from the
+// previous season's, with promotion and relegation if that season is
+// complete, in which case the teams' strength and budget also change with
+// their final positions. A cup or tournament of the previous season is drawn
+// again for the new one. The league ranks its teams by the named tie-breaker
+// rules, or by those of the previous league if the name is empty.
+func StartNewSeason(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, seed int64, rulesName string) (sqlc.Season, error) {
+	// Get current season
+	currentSeason, err := repo.GetCurrentSeason(ctx)
+	if err != nil && err != sql.ErrNoRows {
+		return sqlc.Season{}, fmt.Errorf("failed to fetch current season: %w", err)
+	}
+
+	hasCurrent := err == nil
+
+	// Follow the latest season, which a rolled back season is not
+	seasons, err := repo.ListSeasons(ctx)
+	if err != nil {
+		return sqlc.Season{}, fmt.Errorf("failed to fetch seasons: %w", err)
+	}
+	newYear := int64(InitialYear) // Start with the initial year if no season exists
+	if len(seasons) > 0 {
+		newYear = seasons[len(seasons)-1].Year + 1
+	}
+
+	// Check the requested tie-breaker rules, otherwise the league keeps
+	// the previous season's with the rest of its rules
+	var rules standings.Rules
+	if rulesName != "" {
+		rules, err = standings.RulesByName(rulesName)
+		if err != nil {
+			return sqlc.Season{}, ErrUnknownRules
+		}
+	}
+
+	var newSeason sqlc.Season
+	err = repo.WithTx(ctx, func(tx repository.Repository) error {
+		// Archive the previous season as complete once all its matches are
+		// played, it may not have been shown since its last week
+		if hasCurrent {
+			if err := completeIfPlayed(ctx, tx, currentSeason.ID); err != nil {
+				return err
+			}
+		}
+
+		// Create new season
+		newSeason, err = tx.CreateNewSeason(ctx, newYear, seed)
+		if err != nil {
+			return fmt.Errorf("failed to create new season: %w", err)
+		}
+
+		// Set it as the current season
+		if err := tx.SetCurrentSeason(ctx, newSeason.ID); err != nil {
+			return fmt.Errorf("failed to set current season: %w", err)
+		}
+		newSeason.IsCurrent = sql.NullBool{Bool: true, Valid: true}
+
+		// Carry the competitions and divisions over, promoting and
+		// relegating teams, then let the teams develop from where they
+		// finished
+		if hasCurrent {
+			previous, err := tx.GetSeason(ctx, currentSeason.ID)
+			if err != nil {
+				return fmt.Errorf("failed to fetch season: %w", err)
+			}
+			if err := copyCompetitions(ctx, tx, previous, newSeason); err != nil {
+				return err
+			}
+			if err := seedDivisions(ctx, tx, sim, previous, newSeason); err != nil {
+				return err
+			}
+			if err := progressTeams(ctx, tx, previous); err != nil {
+				return err
+			}
+		}
+
+		// The league takes the requested tie-breakers
+		if rulesName != "" {
+			competition, err := seasonCompetition(ctx, tx, newSeason.ID, FormatLeague)
+			if err != nil {
+				return err
+			}
+			if err := tx.SetCompetitionRankingRules(ctx, competition.ID, rules.Name); err != nil {
+				return fmt.Errorf("failed to set ranking rules: %w", err)
+			}
+		}
+
+		// Initialize game state for the new season
+		if err := tx.InitializeGameState(ctx, newSeason.ID); err != nil {
+			return fmt.Errorf("failed to initialize game state: %w", err)
+		}
+
+		// Generate fixtures for the new season
+		if err := GenerateRoundRobinFixtures(ctx, tx); err != nil {
+			return err
+		}
+
+		// A cup and a tournament run again every season, in the same
+		// format
+		if !hasCurrent {
+			return nil
+		}
+		if err := newTournament(ctx, tx, currentSeason, newSeason); err != nil {
+			return err
+		}
+		cup, err := tx.GetCupBySeason(ctx, currentSeason.ID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch cup: %w", err)
+		}
+		return newCup(ctx, tx, newSeason, cup.Seeded)
+	})
+	if err != nil {
+		return sqlc.Season{}, err
+	}
+
+	return newSeason, nil
+}
+
+// completeIfPlayed marks a season complete if it has matches and all of them
+// are played.
+func completeIfPlayed(ctx context.Context, repo repository.Repository, seasonID int64) error {
+	matches, err := repo.GetMatchesBySeason(ctx, seasonID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch matches: %w", err)
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+	for _, match := range matches {
+		if !match.Played.Bool {
+			return nil
+		}
+	}
+
+	if err := repo.CompleteSeason(ctx, seasonID); err != nil {
+		return fmt.Errorf("failed to mark season as complete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package league
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+const (
+	// MinStrength and MaxStrength bound the strength rating of a team
+	MinStrength = 1
+	MaxStrength = 10
+	// MaxTeamNameLength bounds the length of a team name
+	MaxTeamNameLength = 50
+	// DefaultBudget is the budget of a team created without one
+	DefaultBudget = 1000000
+)
+
+var (
+	ErrTeamNotFound     = errors.New("team not found")
+	ErrTeamNameTaken    = errors.New("a team with this name already exists")
+	ErrTeamHasMatches   = errors.New("team has matches and cannot be deleted")
+	ErrSeasonInProgress = errors.New("teams cannot be changed while a season is in progress")
+)
+
+// ValidationError lists the invalid fields of a team or division and why each
+// is invalid.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, field+": "+e.Fields[field])
+	}
+	return strings.Join(messages, "; ")
+}
+
+// TeamInput holds the editable fields of a team.
+type TeamInput struct {
+	Name     string
+	Strength int64
+	Budget   int64
+}
+
+// Validate checks the fields of a team, returning a *ValidationError if any
+// is invalid.
+func (in TeamInput) Validate() error {
+	fields := map[string]string{}
+
+	name := strings.TrimSpace(in.Name)
+	switch {
+	case name == "":
+		fields["name"] = "Name is required"
+	case len(name) > MaxTeamNameLength:
+		fields["name"] = fmt.Sprintf("Name must be at most %d characters", MaxTeamNameLength)
+	}
+	if in.Strength < MinStrength || in.Strength > MaxStrength {
+		fields["strength"] = fmt.Sprintf("Strength must be between %d and %d", MinStrength, MaxStrength)
+	}
+	if in.Budget < 0 {
+		fields["budget"] = "Budget cannot be negative"
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// CreateTeam adds a team to the league. Teams can only join before the
+// current season's first match or after its last.
+func CreateTeam(ctx context.Context, repo repository.Repository, in TeamInput) (sqlc.Team, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if err := in.Validate(); err != nil {
+		return sqlc.Team{}, err
+	}
+
+	var team sqlc.Team
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		if err := checkNameFree(ctx, tx, in.Name, 0); err != nil {
+			return err
+		}
+
+		season, err := rosterSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		team, err = tx.CreateTeam(ctx, sqlc.CreateTeamParams{
+			Name:     in.Name,
+			Strength: sql.NullInt64{Int64: in.Strength, Valid: true},
+			Budget:   sql.NullInt64{Int64: in.Budget, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create team: %w", err)
+		}
+
+		return regenerateFixtures(ctx, tx, season)
+	})
+	if err != nil {
+		return sqlc.Team{}, err
+	}
+
+	return team, nil
+}
+
+// UpdateTeam changes the name, strength and budget of a team. Editing a team
+// does not change the roster, so it is allowed during a season.
+func UpdateTeam(ctx context.Context, repo repository.Repository, id int64, in TeamInput) (sqlc.Team, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if err := in.Validate(); err != nil {
+		return sqlc.Team{}, err
+	}
+
+	var team sqlc.Team
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		var err error
+		team, err = getTeam(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := checkNameFree(ctx, tx, in.Name, id); err != nil {
+			return err
+		}
+
+		team.Name = in.Name
+		team.Strength = sql.NullInt64{Int64: in.Strength, Valid: true}
+		team.Budget = sql.NullInt64{Int64: in.Budget, Valid: true}
+		err = tx.UpdateTeam(ctx, sqlc.UpdateTeamParams{
+			ID:       team.ID,
+			Name:     team.Name,
+			Strength: team.Strength,
+			Budget:   team.Budget,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update team: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return sqlc.Team{}, err
+	}
+
+	return team, nil
+}
+
+// DeleteTeam removes a team from the league. Teams with played matches keep
+// their history and cannot be deleted.
+func DeleteTeam(ctx context.Context, repo repository.Repository, id int64) error {
+	return repo.WithTx(ctx, func(tx repository.Repository) error {
+		if _, err := getTeam(ctx, tx, id); err != nil {
+			return err
+		}
+
+		season, err := rosterSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		// Fixtures not played yet and the cup and tournament draws are
+		// rebuilt without the team
+		if season != nil {
+			if err := tx.DeleteUnplayedMatchesBySeason(ctx, season.ID); err != nil {
+				return fmt.Errorf("failed to delete fixtures: %w", err)
+			}
+			if err := deleteCupTies(ctx, tx, season.ID); err != nil {
+				return err
+			}
+			if err := deleteSeasonTournamentDraw(ctx, tx, season.ID); err != nil {
+				return err
+			}
+		}
+
+		err = tx.DeleteTeam(ctx, id)
+		if errors.Is(err, repository.ErrTeamHasMatches) {
+			return ErrTeamHasMatches
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete team: %w", err)
+		}
+
+		return regenerateFixtures(ctx, tx, season)
+	})
+}
+
+// RosterLocked reports whether teams cannot currently be added or removed.
+func RosterLocked(ctx context.Context, repo repository.Repository) (bool, error) {
+	_, err := rosterSeason(ctx, repo)
+	if errors.Is(err, ErrSeasonInProgress) {
+		return true, nil
+	}
+	return false, err
+}
+
+func getTeam(ctx context.Context, repo repository.Repository, id int64) (sqlc.Team, error) {
+	team, err := repo.GetTeam(ctx, id)
+	if err == sql.ErrNoRows {
+		return sqlc.Team{}, ErrTeamNotFound
+	}
+	if err != nil {
+		return sqlc.Team{}, fmt.Errorf("failed to fetch team: %w", err)
+	}
+	return team, nil
+}
+
+// checkNameFree makes sure no team other than exceptID is named name.
+func checkNameFree(ctx context.Context, repo repository.Repository, name string, exceptID int64) error {
+	teams, err := repo.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch teams: %w", err)
+	}
+	for _, team := range teams {
+		if team.ID != exceptID && strings.EqualFold(team.Name, name) {
+			return ErrTeamNameTaken
+		}
+	}
+	return nil
+}
+
+// rosterSeason checks that the roster can change. It returns the current
+// season if its fixtures must be regenerated, which is the case when they
+// are generated but no match has been played yet.
+func rosterSeason(ctx context.Context, repo repository.Repository) (*sqlc.Season, error) {
+	season, err := repo.GetCurrentSeason(ctx)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch current season: %w", err)
+	}
+
+	unplayed, err := repo.GetUnplayedMatchesBySeason(ctx, season.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fixtures: %w", err)
+	}
+	if len(unplayed) == 0 {
+		// Either no fixtures yet or the season is over
+		return nil, nil
+	}
+
+	results, err := repo.GetResultsBySeason(ctx, season.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch results: %w", err)
+	}
+	if len(results) > 0 {
+		return nil, ErrSeasonInProgress
+	}
+
+	return &season, nil
+}
+
+// regenerateFixtures rebuilds the fixtures and the cup and tournament draws
+// of a season that has not started so they include the current roster.
+func regenerateFixtures(ctx context.Context, repo repository.Repository, season *sqlc.Season) error {
+	if season == nil {
+		return nil
+	}
+
+	if err := repo.DeleteUnplayedMatchesBySeason(ctx, season.ID); err != nil {
+		return fmt.Errorf("failed to delete fixtures: %w", err)
+	}
+
+	// A league left with a single team has no fixtures
+	err := GenerateRoundRobinFixtures(ctx, repo)
+	if err != nil && !errors.Is(err, ErrNotEnoughTeams) {
+		return err
+	}
+	if err := redrawCup(ctx, repo, *season); err != nil {
+		return err
+	}
+	return redrawTournament(ctx, repo, *season)
+}
@@ -0,0 +1,217 @@
+package league
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+// FormLength is how many of its latest results a team's form lists
+const FormLength = 5
+
+// Outcomes of a match seen from one of its teams
+const (
+	OutcomeWin  = "W"
+	OutcomeDraw = "D"
+	OutcomeLoss = "L"
+)
+
+// TeamMatch is a fixture of a team, seen from the team.
+type TeamMatch struct {
+	ID           int64
+	Week         int64
+	OpponentID   int64
+	OpponentName string
+	Home         bool
+	Played       bool
+	GoalsFor     int64
+	GoalsAgainst int64
+	Outcome      string // empty until the match is played
+}
+
+// TeamWeek is where a team stood in its division after a week.
+type TeamWeek struct {
+	Week     int64
+	Points   int64
+	Position int
+}
+
+// TeamSeason follows a team through a season.
+type TeamSeason struct {
+	Team     sqlc.Team
+	Season   sqlc.Season
+	Division sqlc.Division
+	Matches  []TeamMatch // every fixture, by week
+	Upcoming []TeamMatch // the fixtures still to play
+	Form     []string    // the outcomes of the latest played matches, oldest first
+	Home     Record
+	Away     Record
+	Weeks    []TeamWeek // one per week with results
+}
+
+// GetTeamSeason gathers a team's fixtures and results of a season, and
+// replays its division's results week by week to track its position.
+func GetTeamSeason(ctx context.Context, repo repository.Repository, teamID int64, season sqlc.Season) (TeamSeason, error) {
+	team, err := getTeam(ctx, repo, teamID)
+	if err != nil {
+		return TeamSeason{}, err
+	}
+	ts := TeamSeason{Team: team, Season: season}
+
+	rows, err := repo.GetMatchesByTeam(ctx, teamID, season.ID)
+	if err != nil {
+		return TeamSeason{}, fmt.Errorf("failed to fetch matches: %w", err)
+	}
+	for _, row := range rows {
+		m := teamMatch(row, teamID)
+		ts.Matches = append(ts.Matches, m)
+		if !m.Played {
+			ts.Upcoming = append(ts.Upcoming, m)
+			continue
+		}
+
+		record := &ts.Away
+		if m.Home {
+			record = &ts.Home
+		}
+		record.add(m.GoalsFor, m.GoalsAgainst)
+		ts.Form = append(ts.Form, m.Outcome)
+	}
+	ts.Form = ts.Form[max(len(ts.Form)-FormLength, 0):]
+
+	tables, err := Tables(ctx, repo, season)
+	if err != nil {
+		return TeamSeason{}, err
+	}
+	for _, table := range tables {
+		for _, entry := range table.Entries {
+			if entry.Team.ID == teamID {
+				ts.Division = table.Division
+				ts.Weeks, err = teamWeeks(ctx, repo, season, table.Entries, teamID)
+				if err != nil {
+					return TeamSeason{}, err
+				}
+			}
+		}
+	}
+
+	return ts, nil
+}
+
+// teamMatch turns a fixture into the team's view of it.
+func teamMatch(row sqlc.GetMatchesByTeamRow, teamID int64) TeamMatch {
+	m := TeamMatch{
+		ID:           row.ID,
+		Week:         row.Week,
+		OpponentID:   row.GuestID,
+		OpponentName: row.GuestTeamName,
+		Home:         row.HomeID == teamID,
+		Played:       row.Played.Bool && row.HomeScore.Valid,
+		GoalsFor:     row.HomeScore.Int64,
+		GoalsAgainst: row.GuestScore.Int64,
+	}
+	if !m.Home {
+		m.OpponentID, m.OpponentName = row.HomeID, row.HomeTeamName
+		m.GoalsFor, m.GoalsAgainst = m.GoalsAgainst, m.GoalsFor
+	}
+	if m.Played {
+		m.Outcome = outcome(m.GoalsFor, m.GoalsAgainst)
+	}
+	return m
+}
+
+func outcome(goalsFor, goalsAgainst int64) string {
+	switch {
+	case goalsFor > goalsAgainst:
+		return OutcomeWin
+	case goalsFor == goalsAgainst:
+		return OutcomeDraw
+	}
+	return OutcomeLoss
+}
+
+// add counts a result in the record.
+func (r *Record) add(goalsFor, goalsAgainst int64) {
+	r.Played++
+	r.GoalsFor += goalsFor
+	r.GoalsAgainst += goalsAgainst
+	switch outcome(goalsFor, goalsAgainst) {
+	case OutcomeWin:
+		r.Wins++
+	case OutcomeDraw:
+		r.Draws++
+	default:
+		r.Losses++
+	}
+}
+
+// teamWeeks ranks a division after each of its played weeks and returns the
+// team's points and position in each. The division's teams start level, in
+// the order by name the final table starts from, so the last week matches
+// the division's table.
+func teamWeeks(ctx context.Context, repo repository.Repository, season sqlc.Season, division []standings.Entry, teamID int64) ([]TeamWeek, error) {
+	rules, err := standings.LeagueRules(ctx, repo, season.ID)
+	if err != nil {
+		return nil, err
+	}
+	points, err := standings.LeaguePoints(ctx, repo, season.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.GetResultsBySeason(ctx, season.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch results: %w", err)
+	}
+
+	index := make(map[int64]int, len(division))
+	table := make([]standings.Entry, 0, len(division))
+	// Sanctions count from the first week, so the last one matches the table
+	for _, entry := range division {
+		table = append(table, standings.Entry{
+			Team:           entry.Team,
+			Points:         -entry.Deducted,
+			Deducted:       entry.Deducted,
+			FairPlayPoints: entry.FairPlayPoints,
+		})
+	}
+	slices.SortFunc(table, func(a, b standings.Entry) int { return cmp.Compare(a.Team.Name, b.Team.Name) })
+	for i, entry := range table {
+		index[entry.Team.ID] = i
+	}
+
+	var weeks []TeamWeek
+	var results []standings.Result
+	for i, row := range rows {
+		hi, homeOK := index[row.HomeID]
+		gi, guestOK := index[row.GuestID]
+		if homeOK && guestOK {
+			results = append(results, standings.Result{
+				HomeID:     row.HomeID,
+				GuestID:    row.GuestID,
+				HomeScore:  row.HomeScore,
+				GuestScore: row.GuestScore,
+			})
+			points.Record(&table[hi], row.HomeScore, row.GuestScore, false)
+			points.Record(&table[gi], row.GuestScore, row.HomeScore, true)
+		}
+
+		// Rank once every result of the week is in
+		if i+1 < len(rows) && rows[i+1].Week == row.Week {
+			continue
+		}
+		ranked := slices.Clone(table)
+		standings.Rank(ranked, results, rules, points, season.Seed)
+		for position, entry := range ranked {
+			if entry.Team.ID == teamID {
+				weeks = append(weeks, TeamWeek{Week: row.Week, Points: entry.Points, Position: position + 1})
+			}
+		}
+	}
+	return weeks, nil
+}
@@ -0,0 +1,762 @@
+package league
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/bits"
+	"math/rand"
+	"slices"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+var (
+	ErrNoTournament      = errors.New("the season has no tournament")
+	ErrTournamentLocked  = errors.New("the tournament can only be drawn before the season's first match")
+	ErrTournamentTooLong = errors.New("the league has fewer weeks than the tournament has matchdays")
+	ErrDrawImpossible    = errors.New("no draw keeps the teams of each division in different groups")
+)
+
+// tournamentStream offsets the random streams of the tournament from the
+// cup's. The draw uses the stream itself and each match the stream minus its
+// ID.
+const tournamentStream = -3 << 40
+
+// TournamentInput is the format of a tournament.
+type TournamentInput struct {
+	Groups     int64
+	Qualifiers int64 // teams of each group going through to the knockout
+	// AwayGoals settles a knockout tie level on aggregate in favour of the
+	// side that scored more goals away, before extra time
+	AwayGoals bool
+	// SeparateDivisions keeps teams of the same division in different
+	// groups
+	SeparateDivisions bool
+}
+
+// validate checks the format against the number of teams entering,
+// returning a *ValidationError if it does not fit.
+func (in TournamentInput) validate(teams int) error {
+	fields := map[string]string{}
+
+	// Every group plays at least one match
+	if in.Groups < 1 || in.Groups > int64(teams/2) {
+		fields["groups"] = fmt.Sprintf("Groups must be between 1 and %d for %d teams", max(1, teams/2), teams)
+	} else {
+		smallest := int64(teams) / in.Groups
+		qualified := in.Groups * in.Qualifiers
+		switch {
+		case in.Qualifiers < 1 || in.Qualifiers > smallest:
+			fields["qualifiers"] = fmt.Sprintf("Qualifiers must be between 1 and %d, the size of the smallest group", smallest)
+		case qualified < 2 || qualified&(qualified-1) != 0:
+			fields["qualifiers"] = "Groups times qualifiers must be a power of two of at least 2 for the knockout"
+		}
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// Tournament is a season's tournament: its groups and then its knockout.
+type Tournament struct {
+	Tournament sqlc.Tournament
+	Groups     []TournamentGroup
+	Rounds     []TournamentRound // knockout rounds, first round first
+	// WinnerID and WinnerName are the team that won the final, zero until
+	// it is decided
+	WinnerID   int64
+	WinnerName string
+}
+
+// TournamentGroup is a group of the group stage with its table.
+type TournamentGroup struct {
+	Number  int64
+	Name    string
+	Teams   []sqlc.ListTournamentTeamsRow // by pot
+	Table   []standings.Entry
+	Matches []sqlc.ListTournamentMatchesRow
+}
+
+// Complete reports whether every match of the group has been played.
+func (g TournamentGroup) Complete() bool {
+	for _, match := range g.Matches {
+		if !match.Played {
+			return false
+		}
+	}
+	return true
+}
+
+// TournamentRound is a round of the knockout.
+type TournamentRound struct {
+	Round int64
+	Name  string
+	Ties  []TournamentTie // by slot
+}
+
+// TournamentTie is a two-legged knockout tie. The better placed side plays
+// the second leg at home.
+type TournamentTie struct {
+	Slot   int64
+	First  sqlc.ListTournamentMatchesRow
+	Second sqlc.ListTournamentMatchesRow
+}
+
+// Aggregate returns the goals of the second leg's home and guest sides over
+// both legs played so far.
+func (t TournamentTie) Aggregate() (int64, int64) {
+	home := t.Second.HomeScore.Int64 + t.First.GuestScore.Int64
+	guest := t.Second.GuestScore.Int64 + t.First.HomeScore.Int64
+	return home, guest
+}
+
+// GroupName names a group of the group stage, such as "Group A".
+func GroupName(number int64) string {
+	return fmt.Sprintf("Group %c", 'A'+rune(number))
+}
+
+// GetTournament returns a season's tournament with the tables of its groups,
+// or ErrNoTournament if it has none.
+func GetTournament(ctx context.Context, repo repository.Repository, season sqlc.Season) (Tournament, error) {
+	tournament, err := repo.GetTournamentBySeason(ctx, season.ID)
+	if err == sql.ErrNoRows {
+		return Tournament{}, ErrNoTournament
+	}
+	if err != nil {
+		return Tournament{}, fmt.Errorf("failed to fetch tournament: %w", err)
+	}
+
+	teams, err := repo.ListTournamentTeams(ctx, tournament.ID)
+	if err != nil {
+		return Tournament{}, fmt.Errorf("failed to fetch tournament teams: %w", err)
+	}
+	matches, err := repo.ListTournamentMatches(ctx, tournament.ID)
+	if err != nil {
+		return Tournament{}, fmt.Errorf("failed to fetch tournament matches: %w", err)
+	}
+
+	result := Tournament{Tournament: tournament}
+	for _, team := range teams {
+		if len(result.Groups) == 0 || result.Groups[len(result.Groups)-1].Number != team.GroupNumber {
+			result.Groups = append(result.Groups, TournamentGroup{Number: team.GroupNumber, Name: GroupName(team.GroupNumber)})
+		}
+		group := &result.Groups[len(result.Groups)-1]
+		group.Teams = append(group.Teams, team)
+	}
+
+	var rounds int64
+	for _, match := range matches {
+		rounds = max(rounds, match.Round)
+	}
+	for _, match := range matches {
+		if match.Round == 0 {
+			if i := slices.IndexFunc(result.Groups, func(g TournamentGroup) bool { return g.Number == match.Slot }); i >= 0 {
+				result.Groups[i].Matches = append(result.Groups[i].Matches, match)
+			}
+			continue
+		}
+
+		if len(result.Rounds) == 0 || result.Rounds[len(result.Rounds)-1].Round != match.Round {
+			result.Rounds = append(result.Rounds, TournamentRound{Round: match.Round, Name: RoundName(match.Round, rounds)})
+		}
+		round := &result.Rounds[len(result.Rounds)-1]
+		if match.Leg == 1 {
+			round.Ties = append(round.Ties, TournamentTie{Slot: match.Slot, First: match})
+			continue
+		}
+		tie := &round.Ties[len(round.Ties)-1]
+		tie.Second = match
+
+		if match.Round == rounds && match.Played {
+			result.WinnerID = match.WinnerID.Int64
+			result.WinnerName = match.HomeTeamName.String
+			if match.WinnerID == match.GuestID {
+				result.WinnerName = match.GuestTeamName.String
+			}
+		}
+	}
+
+	competition, err := competitionRules(ctx, repo, season.ID, FormatTournament)
+	if err != nil {
+		return Tournament{}, err
+	}
+	rules, err := standings.CompetitionRules(competition)
+	if err != nil {
+		return Tournament{}, err
+	}
+	penalties, err := standings.TeamPenalties(ctx, repo, season.ID, competition.ID)
+	if err != nil {
+		return Tournament{}, err
+	}
+	for i := range result.Groups {
+		result.Groups[i].Table = groupTable(result.Groups[i], rules, standings.CompetitionPoints(competition), penalties, season.Seed)
+	}
+	return result, nil
+}
+
+// TournamentWeek returns the tournament matches of a week of a season whose
+// teams are known, none if the season has no tournament.
+func TournamentWeek(ctx context.Context, repo repository.Repository, season sqlc.Season, week int) ([]sqlc.ListTournamentMatchesRow, error) {
+	tournament, err := repo.GetTournamentBySeason(ctx, season.ID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tournament: %w", err)
+	}
+
+	matches, err := repo.ListTournamentMatches(ctx, tournament.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tournament matches: %w", err)
+	}
+	var weekMatches []sqlc.ListTournamentMatchesRow
+	for _, match := range matches {
+		if match.Week == int64(week) && match.HomeID.Valid && match.GuestID.Valid {
+			weekMatches = append(weekMatches, match)
+		}
+	}
+	return weekMatches, nil
+}
+
+// groupTable ranks the teams of a group by their played matches, worth the
+// tournament's points less the points deducted from each team.
+func groupTable(group TournamentGroup, rules standings.Rules, points standings.Points, penalties map[int64]standings.Penalties, seed int64) []standings.Entry {
+	table := make([]standings.Entry, 0, len(group.Teams))
+	index := make(map[int64]int, len(group.Teams))
+	for _, team := range group.Teams {
+		index[team.TeamID] = len(table)
+		p := penalties[team.TeamID]
+		table = append(table, standings.Entry{
+			Team:           sqlc.Team{ID: team.TeamID, Name: team.TeamName},
+			Points:         -p.Deducted,
+			Deducted:       p.Deducted,
+			FairPlayPoints: p.FairPlayPoints,
+		})
+	}
+
+	var results []standings.Result
+	for _, match := range group.Matches {
+		hi, homeOK := index[match.HomeID.Int64]
+		gi, guestOK := index[match.GuestID.Int64]
+		if !match.Played || !homeOK || !guestOK {
+			continue
+		}
+		points.Record(&table[hi], match.HomeScore.Int64, match.GuestScore.Int64, false)
+		points.Record(&table[gi], match.GuestScore.Int64, match.HomeScore.Int64, true)
+		results = append(results, standings.Result{
+			HomeID:     match.HomeID.Int64,
+			GuestID:    match.GuestID.Int64,
+			HomeScore:  match.HomeScore.Int64,
+			GuestScore: match.GuestScore.Int64,
+		})
+	}
+
+	standings.Rank(table, results, rules, points, seed)
+	return table
+}
+
+// DrawTournament draws the tournament of the current season, replacing any
+// earlier draw. The tournament can only be drawn before the season's first
+// match. Its matchdays are spread over the weeks of the league, generating
+// the league's fixtures if needed.
+func DrawTournament(ctx context.Context, repo repository.Repository, in TournamentInput) (Tournament, error) {
+	var season sqlc.Season
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		var err error
+		season, err = activeSeason(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		results, err := tx.GetResultsBySeason(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch results: %w", err)
+		}
+		if len(results) > 0 {
+			return ErrTournamentLocked
+		}
+
+		weeks, err := tx.GetSeasonWeeks(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count weeks: %w", err)
+		}
+		if weeks == 0 {
+			if err := GenerateRoundRobinFixtures(ctx, tx); err != nil {
+				return err
+			}
+		}
+
+		previous, err := tx.GetTournamentBySeason(ctx, season.ID)
+		if err == nil {
+			err = tx.DeleteTournament(ctx, previous.ID)
+		}
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to delete tournament: %w", err)
+		}
+
+		tournament, err := tx.CreateTournament(ctx, sqlc.CreateTournamentParams{
+			SeasonID:          season.ID,
+			GroupCount:        in.Groups,
+			Qualifiers:        in.Qualifiers,
+			AwayGoals:         in.AwayGoals,
+			SeparateDivisions: in.SeparateDivisions,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create tournament: %w", err)
+		}
+		return drawTournament(ctx, tx, season, tournament)
+	})
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	return GetTournament(ctx, repo, season)
+}
+
+// newTournament gives a new season a tournament in the format of the
+// previous season's, if it had one.
+func newTournament(ctx context.Context, repo repository.Repository, previous, season sqlc.Season) error {
+	tournament, err := repo.GetTournamentBySeason(ctx, previous.ID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch tournament: %w", err)
+	}
+
+	_, err = repo.CreateTournament(ctx, sqlc.CreateTournamentParams{
+		SeasonID:          season.ID,
+		GroupCount:        tournament.GroupCount,
+		Qualifiers:        tournament.Qualifiers,
+		AwayGoals:         tournament.AwayGoals,
+		SeparateDivisions: tournament.SeparateDivisions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tournament: %w", err)
+	}
+	return redrawTournament(ctx, repo, season)
+}
+
+// redrawTournament draws the tournament of a season again, if it has one,
+// after its teams changed.
+func redrawTournament(ctx context.Context, repo repository.Repository, season sqlc.Season) error {
+	tournament, err := repo.GetTournamentBySeason(ctx, season.ID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch tournament: %w", err)
+	}
+
+	if err := deleteTournamentDraw(ctx, repo, tournament.ID); err != nil {
+		return err
+	}
+
+	// A format the teams no longer fit leaves the tournament undrawn until
+	// it is drawn again
+	err = drawTournament(ctx, repo, season, tournament)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) || errors.Is(err, ErrTournamentTooLong) || errors.Is(err, ErrDrawImpossible) {
+		return nil
+	}
+	return err
+}
+
+// deleteSeasonTournamentDraw deletes the draw of a season's tournament, if
+// it has one.
+func deleteSeasonTournamentDraw(ctx context.Context, repo repository.Repository, seasonID int64) error {
+	tournament, err := repo.GetTournamentBySeason(ctx, seasonID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch tournament: %w", err)
+	}
+	return deleteTournamentDraw(ctx, repo, tournament.ID)
+}
+
+// deleteTournamentDraw deletes the groups and matches of a tournament.
+func deleteTournamentDraw(ctx context.Context, repo repository.Repository, tournamentID int64) error {
+	if err := repo.DeleteTournamentMatches(ctx, tournamentID); err != nil {
+		return fmt.Errorf("failed to delete tournament matches: %w", err)
+	}
+	if err := repo.DeleteTournamentTeams(ctx, tournamentID); err != nil {
+		return fmt.Errorf("failed to delete tournament teams: %w", err)
+	}
+	return nil
+}
+
+// drawTournament draws the groups of a tournament and creates its matches.
+// Every team of the season enters. The teams are ranked like the seeds of a
+// cup and split into pots of one team per group, the best teams in the
+// first pot, and each group draws one team from every pot. The groups play
+// a single round-robin, and the knockout rounds two legs each, their teams
+// only known once the round before is decided. The matchdays are spread
+// evenly over the league's weeks, the final leg coming before the league's
+// last week.
+func drawTournament(ctx context.Context, repo repository.Repository, season sqlc.Season, tournament sqlc.Tournament) error {
+	_, byDivision, err := divisionTeams(ctx, repo, season)
+	if err != nil {
+		return err
+	}
+	divisionOf := make(map[int64]int)
+	for i, division := range byDivision {
+		for _, team := range division {
+			divisionOf[team.ID] = i
+		}
+	}
+
+	teams := seededTeams(byDivision)
+	in := TournamentInput{Groups: tournament.GroupCount, Qualifiers: tournament.Qualifiers}
+	if err := in.validate(len(teams)); err != nil {
+		return err
+	}
+	competition, err := seasonCompetition(ctx, repo, season.ID, FormatTournament)
+	if err != nil {
+		return err
+	}
+	if err := setCompetitionTeams(ctx, repo, competition, teams); err != nil {
+		return err
+	}
+
+	groupCount := int(tournament.GroupCount)
+	rng := simulation.StreamRand(season.Seed, tournamentStream)
+	var pots [][]sqlc.Team
+	for start := 0; start < len(teams); start += groupCount {
+		pot := slices.Clone(teams[start:min(start+groupCount, len(teams))])
+		rng.Shuffle(len(pot), func(i, j int) { pot[i], pot[j] = pot[j], pot[i] })
+		pots = append(pots, pot)
+	}
+
+	groupOf, ok := drawGroups(pots, groupCount, divisionOf, tournament.SeparateDivisions)
+	if !ok {
+		return ErrDrawImpossible
+	}
+	groups := make([][]sqlc.Team, groupCount)
+	for pot, potTeams := range pots {
+		for _, team := range potTeams {
+			group := groupOf[team.ID]
+			groups[group] = append(groups[group], team)
+			err := repo.CreateTournamentTeam(ctx, sqlc.CreateTournamentTeamParams{
+				TournamentID: tournament.ID,
+				TeamID:       team.ID,
+				Pot:          int64(pot),
+				GroupNumber:  int64(group),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to add tournament team: %w", err)
+			}
+		}
+	}
+
+	// Each group plays a single round-robin
+	groupRounds := make([][][]sqlc.CreateFixtureParams, groupCount)
+	groupDays := 0
+	for i, group := range groups {
+		groupRounds[i] = roundRobin(group, 1, 0, season.ID)
+		groupDays = max(groupDays, len(groupRounds[i]))
+	}
+
+	totalWeeks, err := TotalWeeks(ctx, repo, season.ID)
+	if err != nil {
+		return err
+	}
+	knockoutRounds := bits.Len(uint(tournament.GroupCount*tournament.Qualifiers)) - 1
+	matchdays := groupDays + 2*knockoutRounds
+	if totalWeeks < matchdays+1 {
+		return ErrTournamentTooLong
+	}
+	week := func(matchday int) int64 {
+		return int64((matchday*totalWeeks + matchdays) / (matchdays + 1))
+	}
+
+	for group, rounds := range groupRounds {
+		for day, fixtures := range rounds {
+			for _, fixture := range fixtures {
+				err := repo.CreateTournamentMatch(ctx, sqlc.CreateTournamentMatchParams{
+					TournamentID: tournament.ID,
+					Slot:         int64(group),
+					Leg:          1,
+					Week:         week(day + 1),
+					HomeID:       sql.NullInt64{Int64: fixture.HomeID, Valid: true},
+					GuestID:      sql.NullInt64{Int64: fixture.GuestID, Valid: true},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to create tournament match: %w", err)
+				}
+			}
+		}
+	}
+
+	ties := int(tournament.GroupCount * tournament.Qualifiers)
+	for round := 1; round <= knockoutRounds; round++ {
+		ties /= 2
+		for slot := 0; slot < ties; slot++ {
+			for leg := 1; leg <= 2; leg++ {
+				err := repo.CreateTournamentMatch(ctx, sqlc.CreateTournamentMatchParams{
+					TournamentID: tournament.ID,
+					Round:        int64(round),
+					Slot:         int64(slot),
+					Leg:          int64(leg),
+					Week:         week(groupDays + 2*(round-1) + leg),
+				})
+				if err != nil {
+					return fmt.Errorf("failed to create tournament match: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// drawGroups places the teams of the pots in groups, at most one team of
+// each pot per group, trying the groups in order for each team in turn and
+// going back on a placement when a later team has no group left. With
+// separate set, teams of the same division never share a group. It reports
+// false if no draw meets the constraints.
+func drawGroups(pots [][]sqlc.Team, groups int, divisionOf map[int64]int, separate bool) (map[int64]int, bool) {
+	type placement struct {
+		pot  int
+		team sqlc.Team
+	}
+	var order []placement
+	for pot, teams := range pots {
+		for _, team := range teams {
+			order = append(order, placement{pot, team})
+		}
+	}
+
+	groupOf := make(map[int64]int, len(order))
+	potTaken := make([]map[int]bool, len(pots))
+	for i := range potTaken {
+		potTaken[i] = map[int]bool{}
+	}
+	divisionTaken := make([]map[int]bool, groups)
+	for i := range divisionTaken {
+		divisionTaken[i] = map[int]bool{}
+	}
+
+	var place func(i int) bool
+	place = func(i int) bool {
+		if i == len(order) {
+			return true
+		}
+		p := order[i]
+		division := divisionOf[p.team.ID]
+		for group := range groups {
+			if potTaken[p.pot][group] || (separate && divisionTaken[group][division]) {
+				continue
+			}
+			potTaken[p.pot][group] = true
+			divisionTaken[group][division] = true
+			groupOf[p.team.ID] = group
+			if place(i + 1) {
+				return true
+			}
+			delete(potTaken[p.pot], group)
+			delete(divisionTaken[group], division)
+		}
+		return false
+	}
+
+	if !place(0) {
+		return nil, false
+	}
+	return groupOf, true
+}
+
+// playTournamentWeek plays the tournament matches of a week and fills the
+// next knockout round once the stage before it is decided, returning the
+// number of matches played. Matches are seeded from the season seed and the
+// match ID like league matches.
+func playTournamentWeek(ctx context.Context, repo repository.Repository, sim simulation.MatchSimulator, season sqlc.Season, week int) (int, error) {
+	tournament, err := repo.GetTournamentBySeason(ctx, season.ID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch tournament: %w", err)
+	}
+
+	matches, err := repo.ListTournamentMatches(ctx, tournament.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch tournament matches: %w", err)
+	}
+	strength, err := seasonStrengths(ctx, repo, season.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	played := 0
+	for i := range matches {
+		match := &matches[i]
+		if match.Week != int64(week) || match.Played || !match.HomeID.Valid || !match.GuestID.Valid {
+			continue
+		}
+
+		log.Printf("Playing tournament match: Home(%s) vs Guest(%s)", match.HomeTeamName.String, match.GuestTeamName.String)
+		rng := simulation.StreamRand(season.Seed, tournamentStream-match.ID)
+		home := simulation.RatingFromStrength(strength[match.HomeID.Int64])
+		guest := simulation.RatingFromStrength(strength[match.GuestID.Int64])
+
+		var result sqlc.SaveTournamentMatchResultParams
+		if match.Round == 0 || match.Leg == 1 {
+			score := sim.Simulate(rng, home, guest)
+			result = sqlc.SaveTournamentMatchResultParams{
+				HomeScore:  sql.NullInt64{Int64: score.HomeScore, Valid: true},
+				GuestScore: sql.NullInt64{Int64: score.GuestScore, Valid: true},
+				WinnerID:   MatchWinner(match.HomeID.Int64, match.GuestID.Int64, score.HomeScore, score.GuestScore),
+			}
+		} else {
+			first := matches[slices.IndexFunc(matches, func(m sqlc.ListTournamentMatchesRow) bool {
+				return m.Round == match.Round && m.Slot == match.Slot && m.Leg == 1
+			})]
+			result = playSecondLeg(sim, rng, home, guest, *match, first, tournament.AwayGoals)
+		}
+		log.Printf("Score: %s %d-%d %s", match.HomeTeamName.String, result.HomeScore.Int64, result.GuestScore.Int64, match.GuestTeamName.String)
+
+		result.ID = match.ID
+		if err := repo.SaveTournamentMatchResult(ctx, result); err != nil {
+			return 0, fmt.Errorf("failed to save tournament match result: %w", err)
+		}
+		match.HomeScore, match.GuestScore = result.HomeScore, result.GuestScore
+		match.WinnerID = result.WinnerID
+		match.Played = true
+		played++
+	}
+
+	if err := advanceTournament(ctx, repo, season, tournament, matches); err != nil {
+		return 0, err
+	}
+	return played, nil
+}
+
+// playSecondLeg plays the second leg of a knockout tie. A tie level on
+// aggregate, and on away goals if they count, goes to extra time in the
+// second leg and then to penalties.
+func playSecondLeg(sim simulation.MatchSimulator, rng *rand.Rand, home, guest simulation.Rating, match, first sqlc.ListTournamentMatchesRow, awayGoals bool) sqlc.SaveTournamentMatchResultParams {
+	score := sim.Simulate(rng, home, guest)
+	homeAhead, level := tieLeader(first, score.HomeScore, score.GuestScore, awayGoals)
+
+	result := sqlc.SaveTournamentMatchResultParams{}
+	if level {
+		extra := simulation.SimulateExtraTime(sim, rng, home, guest)
+		score.HomeScore += extra.HomeScore
+		score.GuestScore += extra.GuestScore
+		result.ExtraTime = true
+		homeAhead, level = tieLeader(first, score.HomeScore, score.GuestScore, awayGoals)
+	}
+	if level {
+		homePenalties, guestPenalties := simulation.Shootout(rng)
+		result.HomePenalties = sql.NullInt64{Int64: homePenalties, Valid: true}
+		result.GuestPenalties = sql.NullInt64{Int64: guestPenalties, Valid: true}
+		homeAhead = homePenalties > guestPenalties
+	}
+
+	result.HomeScore = sql.NullInt64{Int64: score.HomeScore, Valid: true}
+	result.GuestScore = sql.NullInt64{Int64: score.GuestScore, Valid: true}
+	result.WinnerID = match.GuestID
+	if homeAhead {
+		result.WinnerID = match.HomeID
+	}
+	return result
+}
+
+// tieLeader compares the sides of a two-legged tie after the first leg and
+// the given second leg score. It reports whether the second leg's home side
+// is ahead, on aggregate and then on away goals if they count, or whether
+// nothing separates the sides.
+func tieLeader(first sqlc.ListTournamentMatchesRow, homeScore, guestScore int64, awayGoals bool) (homeAhead, level bool) {
+	home := homeScore + first.GuestScore.Int64
+	guest := guestScore + first.HomeScore.Int64
+	switch {
+	case home != guest:
+		return home > guest, false
+	case awayGoals && first.GuestScore.Int64 != guestScore:
+		return first.GuestScore.Int64 > guestScore, false
+	}
+	return false, true
+}
+
+// advanceTournament fills the knockout ties whose teams are decided: the
+// first round once every group is complete, and each later tie once both
+// ties feeding it are. The better placed side of a tie plays the second leg
+// at home: the higher seed in the first round, and later the winner of the
+// upper tie.
+func advanceTournament(ctx context.Context, repo repository.Repository, season sqlc.Season, tournament sqlc.Tournament, matches []sqlc.ListTournamentMatchesRow) error {
+	// sides holds the better and the other side of each undecided tie of
+	// a round, by slot
+	type sides struct{ better, other sql.NullInt64 }
+	fill := func(round int64, slot int64, s sides) error {
+		for _, match := range matches {
+			if match.Round != round || match.Slot != slot || match.HomeID.Valid {
+				continue
+			}
+			home, guest := s.other, s.better
+			if match.Leg == 2 {
+				home, guest = s.better, s.other
+			}
+			err := repo.SetTournamentMatchTeams(ctx, sqlc.SetTournamentMatchTeamsParams{ID: match.ID, HomeID: home, GuestID: guest})
+			if err != nil {
+				return fmt.Errorf("failed to fill tournament tie: %w", err)
+			}
+		}
+		return nil
+	}
+
+	groupStageDone, firstRoundOpen := true, false
+	for _, match := range matches {
+		if match.Round == 0 && !match.Played {
+			groupStageDone = false
+		}
+		if match.Round == 1 && !match.HomeID.Valid {
+			firstRoundOpen = true
+		}
+	}
+	if groupStageDone && firstRoundOpen {
+		result, err := GetTournament(ctx, repo, season)
+		if err != nil {
+			return err
+		}
+
+		// Group winners are seeded first, then the runners-up and so on,
+		// so the first round keeps teams of the same group apart where it
+		// can
+		var seeds []sql.NullInt64
+		for position := range int(tournament.Qualifiers) {
+			for _, group := range result.Groups {
+				seeds = append(seeds, sql.NullInt64{Int64: group.Table[position].Team.ID, Valid: true})
+			}
+		}
+		order := bracketOrder(len(seeds))
+		for slot := range len(seeds) / 2 {
+			if err := fill(1, int64(slot), sides{seeds[order[2*slot]], seeds[order[2*slot+1]]}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, match := range matches {
+		if match.Round == 0 || match.Leg != 2 || !match.Played || match.Slot%2 != 0 {
+			continue
+		}
+		lower := slices.IndexFunc(matches, func(m sqlc.ListTournamentMatchesRow) bool {
+			return m.Round == match.Round && m.Slot == match.Slot+1 && m.Leg == 2
+		})
+		if lower < 0 || !matches[lower].Played {
+			continue
+		}
+		if err := fill(match.Round+1, match.Slot/2, sides{match.WinnerID, matches[lower].WinnerID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,17 +1,20 @@
 package main
 
 import (
+	"context"
 	_ "embed"
+	"errors"
+	"fmt"
 	"log"
-
-	_ "modernc.org/sqlite"
+	"os"
+	"text/tabwriter"
 
 	"github.com/orhosko/go-backend/config"
 	database "github.com/orhosko/go-backend/db"
 	"github.com/orhosko/go-backend/handlers"
+	"github.com/orhosko/go-backend/prediction"
 	"github.com/orhosko/go-backend/repository"
-
-	"github.com/gin-gonic/gin"
+	"github.com/orhosko/go-backend/simulation"
 )
 
 func main() {
@@ -27,31 +30,93 @@ func main() {
 
 	println("Database initialized")
 
-	// Ensure the schema is created for SQLite in development
-	// In production, use migrations
-	if err := dbConn.EnsureSchema("sqlc/schema.sql"); err != nil {
-		log.Fatalf("Error ensuring database schema: %v", err)
+	// `migrate up|down|status` manages the schema without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(dbConn, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
 	}
 
-	// Initialize repositories
-	repo := repository.NewSQLCRepository(dbConn.Queries, dbConn.Conn)
+	// Bring the schema up to date before serving
+	if _, err := dbConn.MigrateUp(context.Background()); err != nil {
+		log.Fatalf("Error migrating database: %v", err)
+	}
 
-	// Initialize Gin router
-	router := gin.Default()
+	// Initialize repositories for the configured database
+	var repo repository.Repository
+	switch dbConn.Dialect {
+	case database.Postgres:
+		repo = repository.NewPostgresRepository(dbConn.Conn)
+	default:
+		repo = repository.NewSQLCRepository(dbConn.Queries, dbConn.Conn)
+	}
 
-	// Serve static files
-	router.Static("/static", "./static")
+	// Initialize the match engine shared by every simulation endpoint
+	sim := simulation.NewPoissonSimulator()
+	predictor := prediction.NewMonteCarloPredictor(sim, cfg.PredictionIterations, cfg.PredictionWorkers, cfg.PredictionTopN)
 
-	// Register all routes
-	handlers.RegisterHomeRoutes(router, repo)
-	handlers.RegisterTeamRoutes(router, repo)
-	handlers.RegisterFixtureRoutes(router, repo)
-	handlers.RegisterSeasonRoutes(router, repo)
-	handlers.RegisterMatchRoutes(router, repo)
-	handlers.RegisterStandingsRoutes(router, repo)
+	// Initialize Gin router with all routes
+	router := handlers.NewRouter(repo, sim, predictor)
 
 	// Start the server without closing the database connection
 	if err := router.Run(); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+const migrateUsage = "usage: migrate up|down|status"
+
+// runMigrate runs the migrate subcommand
+func runMigrate(dbConn *database.DB, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := dbConn.MigrateUp(ctx)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+
+	case "down":
+		m, ok, err := dbConn.MigrateDown(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("No migration to revert")
+			return nil
+		}
+		fmt.Printf("Reverted %04d_%s\n", m.Version, m.Name)
+
+	case "status":
+		statuses, err := dbConn.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
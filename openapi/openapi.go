@@ -0,0 +1,136 @@
+// Package openapi models the parts of an OpenAPI 3 document the server
+// publishes, and builds JSON schemas from Go types.
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Version is the OpenAPI version of the documents built by this package.
+const Version = "3.0.3"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps lower-case HTTP methods to the operations of a path.
+type PathItem map[string]*Operation
+
+// Operation describes a single route.
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of a request.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body in one content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas of a document.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is a JSON schema as used by OpenAPI 3.0.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	AllOf       []*Schema          `json:"allOf,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+// New returns an empty document.
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      map[string]PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}},
+	}
+}
+
+// Add registers an operation under a path. Gin style path parameters such as
+// :id are converted to {id}.
+func (d *Document) Add(method, path string, op *Operation) {
+	path = Path(path)
+	if d.Paths[path] == nil {
+		d.Paths[path] = PathItem{}
+	}
+	d.Paths[path][strings.ToLower(method)] = op
+}
+
+// Has reports whether the document describes a route.
+func (d *Document) Has(method, path string) bool {
+	_, ok := d.Paths[Path(path)][strings.ToLower(method)]
+	return ok
+}
+
+// Routes lists the method and path of every operation in the document, in
+// a stable order.
+func (d *Document) Routes() [][2]string {
+	var routes [][2]string
+	for path, item := range d.Paths {
+		for method := range item {
+			routes = append(routes, [2]string{method, path})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i][1] != routes[j][1] {
+			return routes[i][1] < routes[j][1]
+		}
+		return routes[i][0] < routes[j][0]
+	})
+	return routes
+}
+
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// Path converts a gin route path to an OpenAPI path.
+func Path(path string) string {
+	return ginParam.ReplaceAllString(path, "{$1}")
+}
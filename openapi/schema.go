@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// nullTypes maps the database/sql null wrappers used by the sqlc models to
+// the schema of the value they hold.
+var nullTypes = map[reflect.Type]Schema{
+	reflect.TypeOf(sql.NullInt64{}):   {Type: "integer", Format: "int64"},
+	reflect.TypeOf(sql.NullInt32{}):   {Type: "integer", Format: "int32"},
+	reflect.TypeOf(sql.NullFloat64{}): {Type: "number", Format: "double"},
+	reflect.TypeOf(sql.NullBool{}):    {Type: "boolean"},
+	reflect.TypeOf(sql.NullString{}):  {Type: "string"},
+}
+
+// SchemaOf returns the schema of the type of v. Named struct types are added
+// to the document's components and referenced.
+func (d *Document) SchemaOf(v any) *Schema {
+	return d.schema(reflect.TypeOf(v))
+}
+
+func (d *Document) schema(t reflect.Type) *Schema {
+	if s, ok := nullTypes[t]; ok {
+		s.Nullable = true
+		return &s
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := d.schema(t.Elem())
+		if s.Ref != "" {
+			// $ref siblings are ignored in OpenAPI 3.0, so wrap the reference
+			return &Schema{AllOf: []*Schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := d.Components.Schemas[name]; !ok {
+			// Reserve the name first so recursive types terminate
+			d.Components.Schemas[name] = &Schema{}
+			*d.Components.Schemas[name] = *d.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+// structSchema builds an object schema from the exported fields of a struct.
+// Fields are named by their json tag, or in snake case when untagged as in
+// the sqlc models.
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty := jsonName(field)
+		if name == "-" {
+			continue
+		}
+
+		s.Properties[name] = d.schema(field.Type)
+		if !omitEmpty {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return snakeCase(field.Name), false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero")
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word unless inside an acronym such as ID
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
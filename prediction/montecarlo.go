@@ -0,0 +1,212 @@
+package prediction
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/standings"
+)
+
+// TeamState is a team's line in the table before the remaining fixtures are
+// simulated, together with its rating for the match simulator.
+type TeamState struct {
+	Entry  standings.Entry
+	Rating simulation.Rating
+}
+
+// Fixture is a match that has not been played yet. A fixture already
+// decided, e.g. by a forfeit, has the score it will be awarded in Awarded
+// and is not simulated.
+type Fixture struct {
+	HomeID  int64
+	GuestID int64
+	Awarded *simulation.Result
+}
+
+// TeamProbability holds the estimated finishing probabilities of a team.
+type TeamProbability struct {
+	TeamID   int64
+	TeamName string
+	Title    float64 // probability of finishing first
+	TopN     float64 // probability of finishing in the top N places
+	Last     float64 // probability of finishing last
+}
+
+// MonteCarloPredictor estimates finishing probabilities by simulating the rest
+// of the season many times with the real match simulator.
+type MonteCarloPredictor struct {
+	Simulator  simulation.MatchSimulator
+	Iterations int
+	Workers    int
+	TopN       int
+}
+
+// NewMonteCarloPredictor creates a MonteCarloPredictor. Non-positive workers
+// default to the number of CPUs.
+func NewMonteCarloPredictor(sim simulation.MatchSimulator, iterations, workers, topN int) *MonteCarloPredictor {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &MonteCarloPredictor{
+		Simulator:  sim,
+		Iterations: iterations,
+		Workers:    workers,
+		TopN:       topN,
+	}
+}
+
+// Predict simulates the remaining fixtures on top of the played results and
+// returns the probabilities for every team, sorted by title probability.
+// Simulated results are worth points and each simulated table is ranked with
+// rules. Iteration i always uses the same random stream of seed, so the
+// result does not depend on the worker count.
+func (p *MonteCarloPredictor) Predict(seed int64, rules standings.Rules, points standings.Points, teams []TeamState, results []standings.Result, fixtures []Fixture) []TeamProbability {
+	if len(teams) == 0 {
+		return nil
+	}
+
+	iterations := p.Iterations
+	if len(fixtures) == 0 || iterations < 1 {
+		// Nothing left to play, the current table is final
+		iterations = 1
+	}
+	workers := min(p.Workers, iterations)
+
+	index := make(map[int64]int, len(teams))
+	for i, team := range teams {
+		index[team.Entry.Team.ID] = i
+	}
+
+	jobs := make(chan int)
+	counts := make([]outcomeCounts, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		counts[w] = newOutcomeCounts(len(teams))
+		wg.Add(1)
+		go func(c outcomeCounts) {
+			defer wg.Done()
+			s := newSeasonState(teams, results, len(fixtures))
+			for i := range jobs {
+				p.simulateSeason(simulation.StreamRand(seed, int64(i)), rules, points, s, teams, fixtures, index)
+				c.record(s.table, index, p.TopN)
+			}
+		}(counts[w])
+	}
+	for i := 0; i < iterations; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	total := newOutcomeCounts(len(teams))
+	for _, c := range counts {
+		total.add(c)
+	}
+
+	probabilities := make([]TeamProbability, len(teams))
+	for i, team := range teams {
+		probabilities[i] = TeamProbability{
+			TeamID:   team.Entry.Team.ID,
+			TeamName: team.Entry.Team.Name,
+			Title:    float64(total.title[i]) / float64(iterations),
+			TopN:     float64(total.topN[i]) / float64(iterations),
+			Last:     float64(total.last[i]) / float64(iterations),
+		}
+	}
+
+	sort.SliceStable(probabilities, func(i, j int) bool {
+		return probabilities[i].Title > probabilities[j].Title
+	})
+
+	return probabilities
+}
+
+// seasonState is the scratch space a worker reuses between iterations.
+type seasonState struct {
+	table   []standings.Entry
+	results []standings.Result
+	played  int // number of results that were played for real
+}
+
+func newSeasonState(teams []TeamState, results []standings.Result, remaining int) *seasonState {
+	s := &seasonState{
+		table:   make([]standings.Entry, len(teams)),
+		results: make([]standings.Result, len(results), len(results)+remaining),
+		played:  len(results),
+	}
+	copy(s.results, results)
+	return s
+}
+
+// simulateSeason plays every remaining fixture on top of the current table and
+// leaves the final, ranked table in s.table.
+func (p *MonteCarloPredictor) simulateSeason(rng *rand.Rand, rules standings.Rules, points standings.Points, s *seasonState, teams []TeamState, fixtures []Fixture, index map[int64]int) {
+	for i, team := range teams {
+		s.table[i] = team.Entry
+	}
+	s.results = s.results[:s.played]
+
+	for _, fixture := range fixtures {
+		hi, gi := index[fixture.HomeID], index[fixture.GuestID]
+		var result simulation.Result
+		if fixture.Awarded != nil {
+			result = *fixture.Awarded
+		} else {
+			result = p.Simulator.Simulate(rng, teams[hi].Rating, teams[gi].Rating)
+		}
+
+		points.Record(&s.table[hi], result.HomeScore, result.GuestScore, false)
+		points.Record(&s.table[gi], result.GuestScore, result.HomeScore, true)
+
+		s.results = append(s.results, standings.Result{
+			HomeID:     fixture.HomeID,
+			GuestID:    fixture.GuestID,
+			HomeScore:  result.HomeScore,
+			GuestScore: result.GuestScore,
+		})
+	}
+
+	// Every iteration draws its own lots for teams that cannot be separated
+	standings.Rank(s.table, s.results, rules, points, rng.Int63())
+}
+
+// outcomeCounts tallies how often each team finished in a given place.
+type outcomeCounts struct {
+	title []int
+	topN  []int
+	last  []int
+}
+
+func newOutcomeCounts(n int) outcomeCounts {
+	return outcomeCounts{
+		title: make([]int, n),
+		topN:  make([]int, n),
+		last:  make([]int, n),
+	}
+}
+
+func (c outcomeCounts) record(table []standings.Entry, index map[int64]int, topN int) {
+	for pos, entry := range table {
+		i := index[entry.Team.ID]
+		if pos == 0 {
+			c.title[i]++
+		}
+		if pos < topN {
+			c.topN[i]++
+		}
+		if pos == len(table)-1 {
+			c.last[i]++
+		}
+	}
+}
+
+func (c outcomeCounts) add(other outcomeCounts) {
+	for i := range c.title {
+		c.title[i] += other.title[i]
+		c.topN[i] += other.topN[i]
+		c.last[i] += other.last[i]
+	}
+}
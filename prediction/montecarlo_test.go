@@ -0,0 +1,144 @@
+package prediction
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/orhosko/go-backend/simulation"
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/standings"
+)
+
+// teams returns a team of each strength with the given points, IDs counting
+// up from 1.
+func teams(strengths, points []int64) []TeamState {
+	states := make([]TeamState, len(strengths))
+	for i, strength := range strengths {
+		states[i] = TeamState{
+			Entry: standings.Entry{
+				Team:   sqlc.Team{ID: int64(i + 1), Name: string(rune('A' + i))},
+				Points: points[i],
+			},
+			Rating: simulation.RatingFromStrength(strength),
+		}
+	}
+	return states
+}
+
+// roundRobin returns the fixtures of a double round robin between n teams.
+func roundRobin(n int) []Fixture {
+	var fixtures []Fixture
+	for home := 1; home <= n; home++ {
+		for guest := 1; guest <= n; guest++ {
+			if home != guest {
+				fixtures = append(fixtures, Fixture{HomeID: int64(home), GuestID: int64(guest)})
+			}
+		}
+	}
+	return fixtures
+}
+
+func TestPredictProbabilities(t *testing.T) {
+	predictor := NewMonteCarloPredictor(simulation.NewPoissonSimulator(), 500, 4, 2)
+	states := teams([]int64{10, 7, 6, 9}, []int64{0, 0, 0, 0})
+
+	probabilities := predictor.Predict(1, standings.PremierLeague, standings.DefaultPoints, states, nil, roundRobin(len(states)))
+	if len(probabilities) != len(states) {
+		t.Fatalf("Predict returned %d teams, want %d", len(probabilities), len(states))
+	}
+
+	var title, topN, last float64
+	for i, p := range probabilities {
+		if p.TopN < p.Title {
+			t.Errorf("%s top %d probability %.3f is below its title probability %.3f", p.TeamName, predictor.TopN, p.TopN, p.Title)
+		}
+		if i > 0 && p.Title > probabilities[i-1].Title {
+			t.Errorf("%s is listed after %s with a higher title probability", p.TeamName, probabilities[i-1].TeamName)
+		}
+		title += p.Title
+		topN += p.TopN
+		last += p.Last
+	}
+	for _, sum := range []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"title", title, 1},
+		{"top 2", topN, 2},
+		{"last", last, 1},
+	} {
+		if math.Abs(sum.got-sum.want) > 1e-9 {
+			t.Errorf("%s probabilities sum to %f, want %f", sum.name, sum.got, sum.want)
+		}
+	}
+
+	if probabilities[0].TeamName != "A" {
+		t.Errorf("favourite = %s, want the strongest team A", probabilities[0].TeamName)
+	}
+}
+
+func TestPredictIndependentOfWorkers(t *testing.T) {
+	sim := simulation.NewPoissonSimulator()
+	states := teams([]int64{5, 5, 5, 5}, []int64{0, 0, 0, 0})
+	fixtures := roundRobin(len(states))
+
+	want := NewMonteCarloPredictor(sim, 300, 1, 2).Predict(7, standings.LaLiga, standings.DefaultPoints, states, nil, fixtures)
+	for _, workers := range []int{2, 3, 8} {
+		got := NewMonteCarloPredictor(sim, 300, workers, 2).Predict(7, standings.LaLiga, standings.DefaultPoints, states, nil, fixtures)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Predict with %d workers = %+v, want %+v as with 1 worker", workers, got, want)
+		}
+	}
+
+	other := NewMonteCarloPredictor(sim, 300, 1, 2).Predict(8, standings.LaLiga, standings.DefaultPoints, states, nil, fixtures)
+	if reflect.DeepEqual(other, want) {
+		t.Error("Predict gave the same result for a different seed")
+	}
+}
+
+func TestPredictClinchedTitle(t *testing.T) {
+	predictor := NewMonteCarloPredictor(simulation.NewPoissonSimulator(), 200, 2, 2)
+
+	// The weakest team leads by more points than anyone can still earn
+	states := teams([]int64{1, 10, 9, 8}, []int64{12, 0, 0, 0})
+	fixtures := []Fixture{{HomeID: 1, GuestID: 2}, {HomeID: 3, GuestID: 4}}
+
+	probabilities := predictor.Predict(3, standings.SerieA, standings.DefaultPoints, states, nil, fixtures)
+	for _, p := range probabilities {
+		want := 0.0
+		if p.TeamID == 1 {
+			want = 1
+		}
+		if p.Title != want {
+			t.Errorf("%s title probability = %f, want %f", p.TeamName, p.Title, want)
+		}
+	}
+}
+
+func TestPredictSeasonOver(t *testing.T) {
+	predictor := NewMonteCarloPredictor(simulation.NewPoissonSimulator(), 200, 2, 2)
+	states := teams([]int64{5, 5, 5}, []int64{3, 6, 0})
+
+	probabilities := predictor.Predict(3, standings.PremierLeague, standings.DefaultPoints, states, nil, nil)
+	if got := probabilities[0]; got.TeamID != 2 || got.Title != 1 || got.TopN != 1 {
+		t.Errorf("leader of a finished season = %+v, want team 2 certain of the title", got)
+	}
+	if got := probabilities[2]; got.TeamID != 3 || got.Last != 1 {
+		t.Errorf("bottom of a finished season = %+v, want team 3 certain to finish last", got)
+	}
+}
+
+func TestPredictAwardedFixtures(t *testing.T) {
+	predictor := NewMonteCarloPredictor(simulation.NewPoissonSimulator(), 200, 2, 1)
+
+	// The strongest team forfeits the match that decides the title
+	states := teams([]int64{10, 1}, []int64{0, 0})
+	fixtures := []Fixture{{HomeID: 1, GuestID: 2, Awarded: &simulation.Result{GuestScore: 3}}}
+
+	probabilities := predictor.Predict(5, standings.PremierLeague, standings.DefaultPoints, states, nil, fixtures)
+	if got := probabilities[0]; got.TeamID != 2 || got.Title != 1 {
+		t.Errorf("favourite = %+v, want team 2 awarded the title", got)
+	}
+}
@@ -0,0 +1,1624 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+// ErrUniqueTeamName is returned by the in-memory repository when a team would
+// share its name with another, where the databases report their unique
+// constraint error.
+var ErrUniqueTeamName = errors.New("UNIQUE constraint failed: team.name")
+
+// ErrUniqueTeamHistory is returned by the in-memory repository when a team
+// would get a second history entry for the same season.
+var ErrUniqueTeamHistory = errors.New("UNIQUE constraint failed: team_history.team_id, team_history.season_id")
+
+// ErrUniqueCup is returned by the in-memory repository when a season would
+// get a second cup.
+var ErrUniqueCup = errors.New("UNIQUE constraint failed: cup.season_id")
+
+// ErrUniqueTournament is returned by the in-memory repository when a season
+// would get a second tournament.
+var ErrUniqueTournament = errors.New("UNIQUE constraint failed: tournament.season_id")
+
+// ErrUniqueCompetition is returned by the in-memory repository when a season
+// would get a second competition of the same format.
+var ErrUniqueCompetition = errors.New("UNIQUE constraint failed: competition.season_id, competition.format")
+
+// MemoryRepository implements the Repository interface in memory with the
+// same semantics as the SQL implementations, including sql.ErrNoRows for
+// missing rows. It starts empty, without the seed data of the migrations.
+type MemoryRepository struct {
+	store *memoryStore
+	tx    bool // set when the repository is bound to a transaction
+}
+
+// memoryStore holds the tables. Like the single SQLite connection, a
+// transaction holds mu until it finishes, so other callers wait for it.
+type memoryStore struct {
+	mu   sync.Mutex
+	data memoryData
+}
+
+// memoryData holds one slice per table, each ordered by id.
+type memoryData struct {
+	seasons           []sqlc.Season
+	teams             []sqlc.Team
+	standings         []sqlc.Standing
+	matches           []sqlc.Match
+	results           []sqlc.MatchResult
+	gameStates        []sqlc.GameState
+	divisions         []sqlc.Division
+	members           []sqlc.DivisionTeam // ordered by division, then team
+	playoffs          []sqlc.Playoff
+	history           []sqlc.TeamHistory
+	cups              []sqlc.Cup
+	cupTies           []sqlc.CupTie
+	tournaments       []sqlc.Tournament
+	tournamentTeams   []sqlc.TournamentTeam
+	tournamentMatches []sqlc.TournamentMatch
+	competitions      []sqlc.Competition
+	competitionTeams  []sqlc.CompetitionTeam
+	sanctions         []sqlc.Sanction
+}
+
+func (d memoryData) clone() memoryData {
+	return memoryData{
+		seasons:           slices.Clone(d.seasons),
+		teams:             slices.Clone(d.teams),
+		standings:         slices.Clone(d.standings),
+		matches:           slices.Clone(d.matches),
+		results:           slices.Clone(d.results),
+		gameStates:        slices.Clone(d.gameStates),
+		divisions:         slices.Clone(d.divisions),
+		members:           slices.Clone(d.members),
+		playoffs:          slices.Clone(d.playoffs),
+		history:           slices.Clone(d.history),
+		cups:              slices.Clone(d.cups),
+		cupTies:           slices.Clone(d.cupTies),
+		tournaments:       slices.Clone(d.tournaments),
+		tournamentTeams:   slices.Clone(d.tournamentTeams),
+		tournamentMatches: slices.Clone(d.tournamentMatches),
+		competitions:      slices.Clone(d.competitions),
+		competitionTeams:  slices.Clone(d.competitionTeams),
+		sanctions:         slices.Clone(d.sanctions),
+	}
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{store: &memoryStore{}}
+}
+
+// lock locks the store unless the repository is bound to a transaction,
+// which already holds the lock, and returns the matching unlock.
+func (r *MemoryRepository) lock() func() {
+	if r.tx {
+		return func() {}
+	}
+	r.store.mu.Lock()
+	return r.store.mu.Unlock
+}
+
+func (r *MemoryRepository) WithTx(ctx context.Context, fn func(Repository) error) error {
+	return r.inTx(func(tx *MemoryRepository) error {
+		return fn(tx)
+	})
+}
+
+// inTx runs fn with a repository bound to a transaction, joining the current
+// transaction if there is one. The tables are restored if fn fails.
+func (r *MemoryRepository) inTx(fn func(*MemoryRepository) error) error {
+	if r.tx {
+		return fn(r)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	snapshot := r.store.data.clone()
+	if err := fn(&MemoryRepository{store: r.store, tx: true}); err != nil {
+		r.store.data = snapshot
+		return err
+	}
+	return nil
+}
+
+// nextID returns the id the next row of a table gets, which like a SQLite
+// INTEGER PRIMARY KEY is one past the largest id.
+func nextID[T any](rows []T, id func(T) int64) int64 {
+	if len(rows) == 0 {
+		return 1
+	}
+	return id(rows[len(rows)-1]) + 1
+}
+
+func (r *MemoryRepository) season(id int64) (int, bool) {
+	return find(r.store.data.seasons, func(s sqlc.Season) bool { return s.ID == id })
+}
+
+func (r *MemoryRepository) team(id int64) (sqlc.Team, bool) {
+	i, ok := find(r.store.data.teams, func(t sqlc.Team) bool { return t.ID == id })
+	if !ok {
+		return sqlc.Team{}, false
+	}
+	return r.store.data.teams[i], true
+}
+
+func (r *MemoryRepository) result(matchID int64) (int, bool) {
+	return find(r.store.data.results, func(mr sqlc.MatchResult) bool { return mr.MatchID == matchID })
+}
+
+// find returns the index of the first row matching the condition.
+func find[T any](rows []T, match func(T) bool) (int, bool) {
+	i := slices.IndexFunc(rows, match)
+	return i, i >= 0
+}
+
+// isTrue and isFalse compare a nullable boolean the way SQL does, where NULL
+// is neither TRUE nor FALSE.
+func isTrue(b sql.NullBool) bool  { return b.Valid && b.Bool }
+func isFalse(b sql.NullBool) bool { return b.Valid && !b.Bool }
+
+func (r *MemoryRepository) GetCurrentSeason(ctx context.Context) (sqlc.Season, error) {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.seasons, func(s sqlc.Season) bool { return isTrue(s.IsCurrent) })
+	if !ok {
+		return sqlc.Season{}, sql.ErrNoRows
+	}
+	return r.store.data.seasons[i], nil
+}
+
+func (r *MemoryRepository) GetSeason(ctx context.Context, id int64) (sqlc.Season, error) {
+	defer r.lock()()
+
+	i, ok := r.season(id)
+	if !ok {
+		return sqlc.Season{}, sql.ErrNoRows
+	}
+	return r.store.data.seasons[i], nil
+}
+
+func (r *MemoryRepository) ListSeasons(ctx context.Context) ([]sqlc.Season, error) {
+	defer r.lock()()
+
+	seasons := slices.Clone(r.store.data.seasons)
+	slices.SortStableFunc(seasons, func(a, b sqlc.Season) int {
+		return cmp.Compare(a.Year, b.Year)
+	})
+	return seasons, nil
+}
+
+func (r *MemoryRepository) CreateNewSeason(ctx context.Context, year int64, seed int64) (sqlc.Season, error) {
+	defer r.lock()()
+
+	season := sqlc.Season{
+		ID:         nextID(r.store.data.seasons, func(s sqlc.Season) int64 { return s.ID }),
+		Year:       year,
+		Seed:       seed,
+		IsCurrent:  sql.NullBool{Bool: false, Valid: true},
+		IsComplete: sql.NullBool{Bool: false, Valid: true},
+	}
+	r.store.data.seasons = append(r.store.data.seasons, season)
+	return season, nil
+}
+
+func (r *MemoryRepository) SetCurrentSeason(ctx context.Context, id int64) error {
+	defer r.lock()()
+
+	for i := range r.store.data.seasons {
+		season := &r.store.data.seasons[i]
+		season.IsCurrent = sql.NullBool{Bool: season.ID == id, Valid: true}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) CompleteSeason(ctx context.Context, id int64) error {
+	defer r.lock()()
+
+	if i, ok := r.season(id); ok {
+		r.store.data.seasons[i].IsComplete = sql.NullBool{Bool: true, Valid: true}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) RollbackSeason(ctx context.Context, seasonID int64, week int64) error {
+	return r.inTx(func(tx *MemoryRepository) error {
+		data := &tx.store.data
+
+		// Undo the results from the given week onward
+		for i := range data.matches {
+			match := &data.matches[i]
+			if match.SeasonID != seasonID || match.Week < week {
+				continue
+			}
+			data.results = slices.DeleteFunc(data.results, func(mr sqlc.MatchResult) bool {
+				return mr.MatchID == match.ID
+			})
+			match.Played = sql.NullBool{Bool: false, Valid: true}
+		}
+
+		// Move the season back to that week
+		if _, ok := find(data.gameStates, func(g sqlc.GameState) bool { return g.SeasonID == seasonID }); !ok {
+			tx.initializeGameState(seasonID)
+		}
+		for i := range data.gameStates {
+			if data.gameStates[i].SeasonID == seasonID {
+				data.gameStates[i].CurrentWeek = sql.NullInt64{Int64: week, Valid: true}
+			}
+		}
+
+		// Cup rounds from that week onward are played again by the teams of
+		// the rounds before them
+		if i, ok := find(data.cups, func(c sqlc.Cup) bool { return c.SeasonID == seasonID }); ok {
+			cupID := data.cups[i].ID
+			firstRound := int64(-1)
+			for j := range data.cupTies {
+				tie := &data.cupTies[j]
+				if tie.CupID != cupID || tie.Week < week {
+					continue
+				}
+				tie.HomeScore, tie.GuestScore = sql.NullInt64{}, sql.NullInt64{}
+				tie.ExtraTime = false
+				tie.HomePenalties, tie.GuestPenalties = sql.NullInt64{}, sql.NullInt64{}
+				tie.WinnerID = sql.NullInt64{}
+				tie.Played = false
+				if firstRound < 0 || tie.Round < firstRound {
+					firstRound = tie.Round
+				}
+			}
+			for j := range data.cupTies {
+				tie := &data.cupTies[j]
+				if tie.CupID == cupID && firstRound >= 0 && tie.Round > firstRound {
+					tie.HomeID, tie.GuestID = sql.NullInt64{}, sql.NullInt64{}
+				}
+			}
+		}
+
+		// So are the tournament's, the group stage keeping its draw
+		if i, ok := find(data.tournaments, func(t sqlc.Tournament) bool { return t.SeasonID == seasonID }); ok {
+			tournamentID := data.tournaments[i].ID
+			firstRound := int64(-1)
+			for j := range data.tournamentMatches {
+				match := &data.tournamentMatches[j]
+				if match.TournamentID != tournamentID || match.Week < week {
+					continue
+				}
+				match.HomeScore, match.GuestScore = sql.NullInt64{}, sql.NullInt64{}
+				match.ExtraTime = false
+				match.HomePenalties, match.GuestPenalties = sql.NullInt64{}, sql.NullInt64{}
+				match.WinnerID = sql.NullInt64{}
+				match.Played = false
+				if firstRound < 0 || match.Round < firstRound {
+					firstRound = match.Round
+				}
+			}
+			for j := range data.tournamentMatches {
+				match := &data.tournamentMatches[j]
+				if match.TournamentID == tournamentID && firstRound >= 0 && match.Round > 0 && match.Round > firstRound {
+					match.HomeID, match.GuestID = sql.NullInt64{}, sql.NullInt64{}
+				}
+			}
+		}
+
+		data.playoffs = slices.DeleteFunc(data.playoffs, func(p sqlc.Playoff) bool { return p.SeasonID == seasonID })
+		if i, ok := tx.season(seasonID); ok {
+			data.seasons[i].IsComplete = sql.NullBool{Bool: false, Valid: true}
+		}
+		return nil
+	})
+}
+
+func (r *MemoryRepository) InitializeGameState(ctx context.Context, seasonID int64) error {
+	defer r.lock()()
+
+	r.initializeGameState(seasonID)
+	return nil
+}
+
+func (r *MemoryRepository) initializeGameState(seasonID int64) {
+	r.store.data.gameStates = append(r.store.data.gameStates, sqlc.GameState{
+		ID:          nextID(r.store.data.gameStates, func(g sqlc.GameState) int64 { return g.ID }),
+		CurrentWeek: sql.NullInt64{Int64: 1, Valid: true},
+		SeasonID:    seasonID,
+	})
+}
+
+func (r *MemoryRepository) GetCurrentWeek(ctx context.Context, seasonID int64) (int, error) {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.gameStates, func(g sqlc.GameState) bool { return g.SeasonID == seasonID })
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	return int(r.store.data.gameStates[i].CurrentWeek.Int64), nil
+}
+
+func (r *MemoryRepository) IncrementWeek(ctx context.Context, seasonID int64) error {
+	defer r.lock()()
+
+	for i := range r.store.data.gameStates {
+		state := &r.store.data.gameStates[i]
+		if state.SeasonID == seasonID && state.CurrentWeek.Valid {
+			state.CurrentWeek.Int64++
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) GetAllMatchesPlayedForWeek(ctx context.Context, week int64, seasonID int64) (bool, error) {
+	defer r.lock()()
+
+	_, unplayed := find(r.store.data.matches, func(m sqlc.Match) bool {
+		return m.Week == week && m.SeasonID == seasonID && isFalse(m.Played)
+	})
+	return !unplayed, nil
+}
+
+// teamMatches returns the matches passing the filter whose teams both exist,
+// as the SQL queries join the teams, along with the teams.
+func (r *MemoryRepository) teamMatches(filter func(sqlc.Match) bool) []teamMatch {
+	var matches []teamMatch
+	for _, m := range r.store.data.matches {
+		if !filter(m) {
+			continue
+		}
+		home, ok := r.team(m.HomeID)
+		if !ok {
+			continue
+		}
+		guest, ok := r.team(m.GuestID)
+		if !ok {
+			continue
+		}
+		matches = append(matches, teamMatch{Match: m, home: home, guest: guest})
+	}
+	return matches
+}
+
+type teamMatch struct {
+	sqlc.Match
+	home, guest sqlc.Team
+}
+
+func (r *MemoryRepository) GetMatchesByWeek(ctx context.Context, week int64, seasonID int64) ([]sqlc.GetMatchesByWeekRow, error) {
+	defer r.lock()()
+
+	var rows []sqlc.GetMatchesByWeekRow
+	for _, m := range r.teamMatches(func(m sqlc.Match) bool {
+		return m.Week == week && m.SeasonID == seasonID
+	}) {
+		rows = append(rows, sqlc.GetMatchesByWeekRow{
+			ID:                m.ID,
+			SeasonID:          m.SeasonID,
+			HomeID:            m.HomeID,
+			GuestID:           m.GuestID,
+			Played:            m.Played,
+			Week:              m.Week,
+			DivisionID:        m.DivisionID,
+			HomeTeamName:      m.home.Name,
+			GuestTeamName:     m.guest.Name,
+			HomeTeamStrength:  m.home.Strength,
+			GuestTeamStrength: m.guest.Strength,
+		})
+	}
+	return rows, nil
+}
+
+func (r *MemoryRepository) GetUnplayedMatchesByWeek(ctx context.Context, week int64, seasonID int64) ([]sqlc.GetUnplayedMatchesByWeekRow, error) {
+	defer r.lock()()
+
+	var rows []sqlc.GetUnplayedMatchesByWeekRow
+	for _, m := range r.teamMatches(func(m sqlc.Match) bool {
+		return m.Week == week && m.SeasonID == seasonID && isFalse(m.Played)
+	}) {
+		rows = append(rows, sqlc.GetUnplayedMatchesByWeekRow{
+			ID:                m.ID,
+			SeasonID:          m.SeasonID,
+			HomeID:            m.HomeID,
+			GuestID:           m.GuestID,
+			Played:            m.Played,
+			Week:              m.Week,
+			DivisionID:        m.DivisionID,
+			HomeTeamName:      m.home.Name,
+			GuestTeamName:     m.guest.Name,
+			HomeTeamStrength:  m.home.Strength,
+			GuestTeamStrength: m.guest.Strength,
+		})
+	}
+	return rows, nil
+}
+
+// sortByWeek orders matches by week, then id.
+func sortByWeek[T any](rows []T, match func(T) sqlc.Match) {
+	slices.SortStableFunc(rows, func(a, b T) int {
+		return cmp.Compare(match(a).Week, match(b).Week)
+	})
+}
+
+func (r *MemoryRepository) GetUnplayedMatchesBySeason(ctx context.Context, seasonID int64) ([]sqlc.Match, error) {
+	defer r.lock()()
+
+	var matches []sqlc.Match
+	for _, m := range r.store.data.matches {
+		if m.SeasonID == seasonID && isFalse(m.Played) {
+			matches = append(matches, m)
+		}
+	}
+	sortByWeek(matches, func(m sqlc.Match) sqlc.Match { return m })
+	return matches, nil
+}
+
+func (r *MemoryRepository) GetMatchesBySeason(ctx context.Context, seasonID int64) ([]sqlc.GetMatchesBySeasonRow, error) {
+	defer r.lock()()
+
+	matches := r.teamMatches(func(m sqlc.Match) bool { return m.SeasonID == seasonID })
+	sortByWeek(matches, func(m teamMatch) sqlc.Match { return m.Match })
+
+	var rows []sqlc.GetMatchesBySeasonRow
+	for _, m := range matches {
+		row := sqlc.GetMatchesBySeasonRow{
+			ID:            m.ID,
+			SeasonID:      m.SeasonID,
+			HomeID:        m.HomeID,
+			GuestID:       m.GuestID,
+			Played:        m.Played,
+			Week:          m.Week,
+			DivisionID:    m.DivisionID,
+			HomeTeamName:  m.home.Name,
+			GuestTeamName: m.guest.Name,
+		}
+		if i, ok := r.result(m.ID); ok {
+			result := r.store.data.results[i]
+			row.HomeScore = sql.NullInt64{Int64: result.HomeScore, Valid: true}
+			row.GuestScore = sql.NullInt64{Int64: result.GuestScore, Valid: true}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (r *MemoryRepository) GetMatchesByTeam(ctx context.Context, teamID int64, seasonID int64) ([]sqlc.GetMatchesByTeamRow, error) {
+	defer r.lock()()
+
+	matches := r.teamMatches(func(m sqlc.Match) bool {
+		return m.SeasonID == seasonID && (m.HomeID == teamID || m.GuestID == teamID)
+	})
+	sortByWeek(matches, func(m teamMatch) sqlc.Match { return m.Match })
+
+	var rows []sqlc.GetMatchesByTeamRow
+	for _, m := range matches {
+		row := sqlc.GetMatchesByTeamRow{
+			ID:            m.ID,
+			SeasonID:      m.SeasonID,
+			HomeID:        m.HomeID,
+			GuestID:       m.GuestID,
+			Played:        m.Played,
+			Week:          m.Week,
+			DivisionID:    m.DivisionID,
+			HomeTeamName:  m.home.Name,
+			GuestTeamName: m.guest.Name,
+		}
+		if i, ok := r.result(m.ID); ok {
+			result := r.store.data.results[i]
+			row.HomeScore = sql.NullInt64{Int64: result.HomeScore, Valid: true}
+			row.GuestScore = sql.NullInt64{Int64: result.GuestScore, Valid: true}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (r *MemoryRepository) GetSeasonWeeks(ctx context.Context, seasonID int64) (int, error) {
+	defer r.lock()()
+
+	weeks := int64(0)
+	for _, m := range r.store.data.matches {
+		if m.SeasonID == seasonID {
+			weeks = max(weeks, m.Week)
+		}
+	}
+	return int(weeks), nil
+}
+
+func (r *MemoryRepository) DeleteUnplayedMatchesBySeason(ctx context.Context, seasonID int64) error {
+	defer r.lock()()
+
+	r.store.data.matches = slices.DeleteFunc(r.store.data.matches, func(m sqlc.Match) bool {
+		return m.SeasonID == seasonID && isFalse(m.Played)
+	})
+	return nil
+}
+
+// playedResults returns the played matches of the season that have a result,
+// ordered by week and id, with their results.
+func (r *MemoryRepository) playedResults(seasonID int64) []playedResult {
+	var played []playedResult
+	for _, m := range r.store.data.matches {
+		if m.SeasonID != seasonID || !isTrue(m.Played) {
+			continue
+		}
+		if i, ok := r.result(m.ID); ok {
+			played = append(played, playedResult{Match: m, result: r.store.data.results[i]})
+		}
+	}
+	sortByWeek(played, func(p playedResult) sqlc.Match { return p.Match })
+	return played
+}
+
+type playedResult struct {
+	sqlc.Match
+	result sqlc.MatchResult
+}
+
+func (r *MemoryRepository) GetResultsBySeason(ctx context.Context, seasonID int64) ([]sqlc.GetResultsBySeasonRow, error) {
+	defer r.lock()()
+
+	var rows []sqlc.GetResultsBySeasonRow
+	for _, p := range r.playedResults(seasonID) {
+		rows = append(rows, sqlc.GetResultsBySeasonRow{
+			ID:         p.ID,
+			Week:       p.Week,
+			HomeID:     p.HomeID,
+			GuestID:    p.GuestID,
+			HomeScore:  p.result.HomeScore,
+			GuestScore: p.result.GuestScore,
+		})
+	}
+	return rows, nil
+}
+
+// headToHead returns the played meetings of two teams with their results,
+// latest first, the scores of each seen from the team.
+func (r *MemoryRepository) headToHead(teamID, opponentID int64) []sqlc.ListHeadToHeadMatchesRow {
+	var rows []sqlc.ListHeadToHeadMatchesRow
+	for _, m := range r.store.data.matches {
+		meets := (m.HomeID == teamID && m.GuestID == opponentID) || (m.HomeID == opponentID && m.GuestID == teamID)
+		if !meets || !isTrue(m.Played) {
+			continue
+		}
+		i, ok := r.result(m.ID)
+		if !ok {
+			continue
+		}
+		s, ok := r.season(m.SeasonID)
+		if !ok {
+			continue
+		}
+		rows = append(rows, sqlc.ListHeadToHeadMatchesRow{
+			ID:         m.ID,
+			SeasonID:   m.SeasonID,
+			SeasonYear: r.store.data.seasons[s].Year,
+			Week:       m.Week,
+			HomeID:     m.HomeID,
+			GuestID:    m.GuestID,
+			HomeScore:  r.store.data.results[i].HomeScore,
+			GuestScore: r.store.data.results[i].GuestScore,
+		})
+	}
+	slices.SortFunc(rows, func(a, b sqlc.ListHeadToHeadMatchesRow) int {
+		return cmp.Or(cmp.Compare(b.SeasonYear, a.SeasonYear), cmp.Compare(b.Week, a.Week), cmp.Compare(b.ID, a.ID))
+	})
+	return rows
+}
+
+func (r *MemoryRepository) ListHeadToHeadSeasons(ctx context.Context, teamID int64, opponentID int64) ([]sqlc.ListHeadToHeadSeasonsRow, error) {
+	defer r.lock()()
+
+	var rows []sqlc.ListHeadToHeadSeasonsRow
+	meetings := r.headToHead(teamID, opponentID)
+	for i := len(meetings) - 1; i >= 0; i-- {
+		m := meetings[i]
+		if len(rows) == 0 || rows[len(rows)-1].SeasonID != m.SeasonID {
+			rows = append(rows, sqlc.ListHeadToHeadSeasonsRow{SeasonID: m.SeasonID, SeasonYear: m.SeasonYear})
+		}
+		row := &rows[len(rows)-1]
+
+		goalsFor, goalsAgainst := m.HomeScore, m.GuestScore
+		if m.GuestID == teamID {
+			goalsFor, goalsAgainst = m.GuestScore, m.HomeScore
+		}
+		row.Played++
+		row.GoalsFor += goalsFor
+		row.GoalsAgainst += goalsAgainst
+		switch {
+		case goalsFor > goalsAgainst:
+			row.Wins++
+		case goalsFor == goalsAgainst:
+			row.Draws++
+		default:
+			row.Losses++
+		}
+	}
+	return rows, nil
+}
+
+func (r *MemoryRepository) ListHeadToHeadMatches(ctx context.Context, teamID int64, opponentID int64) ([]sqlc.ListHeadToHeadMatchesRow, error) {
+	defer r.lock()()
+	return r.headToHead(teamID, opponentID), nil
+}
+
+func (r *MemoryRepository) GetStanding(ctx context.Context, teamID int64, seasonID int64) (sqlc.Standing, error) {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.standings, func(s sqlc.Standing) bool {
+		return s.TeamID == teamID && s.SeasonID == seasonID
+	})
+	if !ok {
+		return sqlc.Standing{}, sql.ErrNoRows
+	}
+	return r.store.data.standings[i], nil
+}
+
+func (r *MemoryRepository) UpdateStanding(ctx context.Context, arg sqlc.UpdateStandingParams) error {
+	defer r.lock()()
+
+	for i := range r.store.data.standings {
+		standing := &r.store.data.standings[i]
+		if standing.TeamID != arg.TeamID || standing.SeasonID != arg.SeasonID {
+			continue
+		}
+		standing.Points = arg.Points
+		standing.Wins = arg.Wins
+		standing.Draws = arg.Draws
+		standing.Losses = arg.Losses
+		standing.GoalDiff = arg.GoalDiff
+		standing.GoalsFor = arg.GoalsFor
+		standing.GoalsAgainst = arg.GoalsAgainst
+	}
+	return nil
+}
+
+func (r *MemoryRepository) CreateStanding(ctx context.Context, arg sqlc.CreateStandingParams) error {
+	defer r.lock()()
+
+	r.store.data.standings = append(r.store.data.standings, sqlc.Standing{
+		ID:           nextID(r.store.data.standings, func(s sqlc.Standing) int64 { return s.ID }),
+		TeamID:       arg.TeamID,
+		SeasonID:     arg.SeasonID,
+		Points:       arg.Points,
+		Wins:         arg.Wins,
+		Draws:        arg.Draws,
+		Losses:       arg.Losses,
+		GoalDiff:     arg.GoalDiff,
+		GoalsFor:     arg.GoalsFor,
+		GoalsAgainst: arg.GoalsAgainst,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) ComputeStandings(ctx context.Context, seasonID int64) ([]sqlc.ComputeStandingsRow, error) {
+	defer r.lock()()
+
+	rows := make(map[int64]*sqlc.ComputeStandingsRow, len(r.store.data.teams))
+	for _, team := range r.store.data.teams {
+		rows[team.ID] = &sqlc.ComputeStandingsRow{Team: team}
+	}
+
+	record := func(teamID, goalsFor, goalsAgainst int64, away bool) {
+		row, ok := rows[teamID]
+		if !ok {
+			return
+		}
+		row.Played++
+		row.GoalsFor += goalsFor
+		row.GoalsAgainst += goalsAgainst
+		row.GoalDiff += goalsFor - goalsAgainst
+		if away {
+			row.AwayGoalsFor += goalsFor
+		}
+		switch {
+		case goalsFor > goalsAgainst:
+			row.Wins++
+		case goalsFor == goalsAgainst:
+			row.Draws++
+		default:
+			row.Losses++
+		}
+	}
+	for _, p := range r.playedResults(seasonID) {
+		record(p.HomeID, p.result.HomeScore, p.result.GuestScore, false)
+		record(p.GuestID, p.result.GuestScore, p.result.HomeScore, true)
+	}
+
+	standings := make([]sqlc.ComputeStandingsRow, 0, len(rows))
+	for _, team := range r.sortedTeams() {
+		standings = append(standings, *rows[team.ID])
+	}
+	return standings, nil
+}
+
+func (r *MemoryRepository) CreateFixture(ctx context.Context, arg sqlc.CreateFixtureParams) error {
+	defer r.lock()()
+
+	r.store.data.matches = append(r.store.data.matches, sqlc.Match{
+		ID:         nextID(r.store.data.matches, func(m sqlc.Match) int64 { return m.ID }),
+		SeasonID:   arg.SeasonID,
+		HomeID:     arg.HomeID,
+		GuestID:    arg.GuestID,
+		Played:     arg.Played,
+		Week:       arg.Week,
+		DivisionID: arg.DivisionID,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) SaveResult(ctx context.Context, arg sqlc.SaveResultParams) error {
+	defer r.lock()()
+
+	if i, ok := r.result(arg.MatchID); ok {
+		result := &r.store.data.results[i]
+		result.HomeScore = arg.HomeScore
+		result.GuestScore = arg.GuestScore
+		result.WinnerID = arg.WinnerID
+		return nil
+	}
+
+	r.store.data.results = append(r.store.data.results, sqlc.MatchResult{
+		ID:         nextID(r.store.data.results, func(mr sqlc.MatchResult) int64 { return mr.ID }),
+		MatchID:    arg.MatchID,
+		HomeScore:  arg.HomeScore,
+		GuestScore: arg.GuestScore,
+		WinnerID:   arg.WinnerID,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) GetMatchResult(ctx context.Context, matchID int64) (sqlc.GetMatchResultRow, error) {
+	defer r.lock()()
+
+	i, ok := r.result(matchID)
+	if !ok {
+		return sqlc.GetMatchResultRow{}, sql.ErrNoRows
+	}
+	matches := r.teamMatches(func(m sqlc.Match) bool { return m.ID == matchID })
+	if len(matches) == 0 {
+		return sqlc.GetMatchResultRow{}, sql.ErrNoRows
+	}
+
+	result := r.store.data.results[i]
+	return sqlc.GetMatchResultRow{
+		ID:            result.ID,
+		MatchID:       result.MatchID,
+		HomeScore:     result.HomeScore,
+		GuestScore:    result.GuestScore,
+		WinnerID:      result.WinnerID,
+		HomeTeamName:  matches[0].home.Name,
+		GuestTeamName: matches[0].guest.Name,
+	}, nil
+}
+
+func (r *MemoryRepository) MarkMatchAsPlayed(ctx context.Context, id int64) error {
+	defer r.lock()()
+
+	for i := range r.store.data.matches {
+		if r.store.data.matches[i].ID == id {
+			r.store.data.matches[i].Played = sql.NullBool{Bool: true, Valid: true}
+		}
+	}
+	return nil
+}
+
+// nameTaken reports whether a team other than id is named name.
+func (r *MemoryRepository) nameTaken(name string, id int64) bool {
+	_, ok := find(r.store.data.teams, func(t sqlc.Team) bool { return t.Name == name && t.ID != id })
+	return ok
+}
+
+func (r *MemoryRepository) CreateTeam(ctx context.Context, arg sqlc.CreateTeamParams) (sqlc.Team, error) {
+	defer r.lock()()
+
+	if r.nameTaken(arg.Name, 0) {
+		return sqlc.Team{}, ErrUniqueTeamName
+	}
+
+	team := sqlc.Team{
+		ID:       nextID(r.store.data.teams, func(t sqlc.Team) int64 { return t.ID }),
+		Name:     arg.Name,
+		Strength: arg.Strength,
+		Budget:   arg.Budget,
+	}
+	r.store.data.teams = append(r.store.data.teams, team)
+	return team, nil
+}
+
+func (r *MemoryRepository) GetTeam(ctx context.Context, id int64) (sqlc.Team, error) {
+	defer r.lock()()
+
+	team, ok := r.team(id)
+	if !ok {
+		return sqlc.Team{}, sql.ErrNoRows
+	}
+	return team, nil
+}
+
+func (r *MemoryRepository) GetTeamByName(ctx context.Context, name string) (sqlc.Team, error) {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.teams, func(t sqlc.Team) bool { return t.Name == name })
+	if !ok {
+		return sqlc.Team{}, sql.ErrNoRows
+	}
+	return r.store.data.teams[i], nil
+}
+
+func (r *MemoryRepository) ListTeams(ctx context.Context) ([]sqlc.Team, error) {
+	defer r.lock()()
+
+	return r.sortedTeams(), nil
+}
+
+// sortedTeams returns the teams ordered by name.
+func (r *MemoryRepository) sortedTeams() []sqlc.Team {
+	teams := slices.Clone(r.store.data.teams)
+	slices.SortStableFunc(teams, func(a, b sqlc.Team) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return teams
+}
+
+func (r *MemoryRepository) UpdateTeamStrength(ctx context.Context, arg sqlc.UpdateTeamStrengthParams) error {
+	defer r.lock()()
+
+	for i := range r.store.data.teams {
+		if r.store.data.teams[i].ID == arg.ID {
+			r.store.data.teams[i].Strength = arg.Strength
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) UpdateTeam(ctx context.Context, arg sqlc.UpdateTeamParams) error {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.teams, func(t sqlc.Team) bool { return t.ID == arg.ID })
+	if !ok {
+		return nil
+	}
+	if r.nameTaken(arg.Name, arg.ID) {
+		return ErrUniqueTeamName
+	}
+
+	team := &r.store.data.teams[i]
+	team.Name = arg.Name
+	team.Strength = arg.Strength
+	team.Budget = arg.Budget
+	return nil
+}
+
+func (r *MemoryRepository) DeleteTeam(ctx context.Context, id int64) error {
+	return r.inTx(func(tx *MemoryRepository) error {
+		// Matches keep a team's history, so only teams without any can go
+		if tx.countTeamMatches(id) > 0 {
+			return ErrTeamHasMatches
+		}
+
+		data := &tx.store.data
+		data.standings = slices.DeleteFunc(data.standings, func(s sqlc.Standing) bool { return s.TeamID == id })
+		data.members = slices.DeleteFunc(data.members, func(m sqlc.DivisionTeam) bool { return m.TeamID == id })
+		data.competitionTeams = slices.DeleteFunc(data.competitionTeams, func(t sqlc.CompetitionTeam) bool { return t.TeamID == id })
+		data.sanctions = slices.DeleteFunc(data.sanctions, func(s sqlc.Sanction) bool { return s.TeamID == id })
+		data.teams = slices.DeleteFunc(data.teams, func(t sqlc.Team) bool { return t.ID == id })
+		return nil
+	})
+}
+
+func (r *MemoryRepository) CountTeamMatches(ctx context.Context, teamID int64) (int64, error) {
+	defer r.lock()()
+
+	return r.countTeamMatches(teamID), nil
+}
+
+func (r *MemoryRepository) countTeamMatches(teamID int64) int64 {
+	var count int64
+	for _, m := range r.store.data.matches {
+		if m.HomeID == teamID || m.GuestID == teamID {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *MemoryRepository) CreateTeamHistory(ctx context.Context, arg sqlc.CreateTeamHistoryParams) error {
+	defer r.lock()()
+
+	if _, ok := find(r.store.data.history, func(h sqlc.TeamHistory) bool {
+		return h.TeamID == arg.TeamID && h.SeasonID == arg.SeasonID
+	}); ok {
+		return ErrUniqueTeamHistory
+	}
+
+	r.store.data.history = append(r.store.data.history, sqlc.TeamHistory{
+		ID:                 nextID(r.store.data.history, func(h sqlc.TeamHistory) int64 { return h.ID }),
+		TeamID:             arg.TeamID,
+		SeasonID:           arg.SeasonID,
+		LastSeasonStanding: arg.LastSeasonStanding,
+		PrizeMoney:         arg.PrizeMoney,
+		StrengthBefore:     arg.StrengthBefore,
+		StrengthAfter:      arg.StrengthAfter,
+		BudgetBefore:       arg.BudgetBefore,
+		BudgetAfter:        arg.BudgetAfter,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) ListTeamHistory(ctx context.Context) ([]sqlc.ListTeamHistoryRow, error) {
+	defer r.lock()()
+
+	var rows []sqlc.ListTeamHistoryRow
+	for _, h := range r.store.data.history {
+		i, ok := r.season(h.SeasonID)
+		if !ok {
+			continue
+		}
+		rows = append(rows, sqlc.ListTeamHistoryRow{
+			ID:                 h.ID,
+			TeamID:             h.TeamID,
+			SeasonID:           h.SeasonID,
+			LastSeasonStanding: h.LastSeasonStanding,
+			PrizeMoney:         h.PrizeMoney,
+			StrengthBefore:     h.StrengthBefore,
+			StrengthAfter:      h.StrengthAfter,
+			BudgetBefore:       h.BudgetBefore,
+			BudgetAfter:        h.BudgetAfter,
+			SeasonYear:         r.store.data.seasons[i].Year,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b sqlc.ListTeamHistoryRow) int {
+		return cmp.Or(cmp.Compare(a.TeamID, b.TeamID), cmp.Compare(a.SeasonYear, b.SeasonYear))
+	})
+	return rows, nil
+}
+
+func (r *MemoryRepository) CreateDivision(ctx context.Context, arg sqlc.CreateDivisionParams) (sqlc.Division, error) {
+	defer r.lock()()
+
+	division := sqlc.Division{
+		ID:               nextID(r.store.data.divisions, func(d sqlc.Division) int64 { return d.ID }),
+		SeasonID:         arg.SeasonID,
+		Name:             arg.Name,
+		Tier:             arg.Tier,
+		RelegationPlaces: arg.RelegationPlaces,
+		Playoff:          arg.Playoff,
+	}
+	r.store.data.divisions = append(r.store.data.divisions, division)
+	return division, nil
+}
+
+func (r *MemoryRepository) division(id int64) (int, bool) {
+	return find(r.store.data.divisions, func(d sqlc.Division) bool { return d.ID == id })
+}
+
+func (r *MemoryRepository) GetDivision(ctx context.Context, id int64) (sqlc.Division, error) {
+	defer r.lock()()
+
+	i, ok := r.division(id)
+	if !ok {
+		return sqlc.Division{}, sql.ErrNoRows
+	}
+	return r.store.data.divisions[i], nil
+}
+
+func (r *MemoryRepository) ListDivisions(ctx context.Context, seasonID int64) ([]sqlc.Division, error) {
+	defer r.lock()()
+
+	var divisions []sqlc.Division
+	for _, d := range r.store.data.divisions {
+		if d.SeasonID == seasonID {
+			divisions = append(divisions, d)
+		}
+	}
+	slices.SortStableFunc(divisions, func(a, b sqlc.Division) int { return cmp.Compare(a.Tier, b.Tier) })
+	return divisions, nil
+}
+
+func (r *MemoryRepository) UpdateDivision(ctx context.Context, arg sqlc.UpdateDivisionParams) error {
+	defer r.lock()()
+
+	if i, ok := r.division(arg.ID); ok {
+		division := &r.store.data.divisions[i]
+		division.Name = arg.Name
+		division.Tier = arg.Tier
+		division.RelegationPlaces = arg.RelegationPlaces
+		division.Playoff = arg.Playoff
+	}
+	return nil
+}
+
+func (r *MemoryRepository) DeleteDivision(ctx context.Context, id int64) error {
+	defer r.lock()()
+
+	r.store.data.divisions = slices.DeleteFunc(r.store.data.divisions, func(d sqlc.Division) bool { return d.ID == id })
+	return nil
+}
+
+func (r *MemoryRepository) SetTeamDivision(ctx context.Context, seasonID int64, divisionID int64, teamID int64) error {
+	return r.inTx(func(tx *MemoryRepository) error {
+		data := &tx.store.data
+		data.members = slices.DeleteFunc(data.members, func(m sqlc.DivisionTeam) bool {
+			i, ok := tx.division(m.DivisionID)
+			return m.TeamID == teamID && ok && data.divisions[i].SeasonID == seasonID
+		})
+
+		member := sqlc.DivisionTeam{DivisionID: divisionID, TeamID: teamID}
+		i, _ := slices.BinarySearchFunc(data.members, member, compareMembers)
+		data.members = slices.Insert(data.members, i, member)
+		return nil
+	})
+}
+
+func compareMembers(a, b sqlc.DivisionTeam) int {
+	return cmp.Or(cmp.Compare(a.DivisionID, b.DivisionID), cmp.Compare(a.TeamID, b.TeamID))
+}
+
+func (r *MemoryRepository) ListDivisionTeams(ctx context.Context, seasonID int64) ([]sqlc.DivisionTeam, error) {
+	defer r.lock()()
+
+	tiers := map[int64]int64{}
+	for _, d := range r.store.data.divisions {
+		if d.SeasonID == seasonID {
+			tiers[d.ID] = d.Tier
+		}
+	}
+
+	var members []sqlc.DivisionTeam
+	for _, m := range r.store.data.members {
+		if _, ok := tiers[m.DivisionID]; ok {
+			members = append(members, m)
+		}
+	}
+	slices.SortStableFunc(members, func(a, b sqlc.DivisionTeam) int {
+		return cmp.Or(cmp.Compare(tiers[a.DivisionID], tiers[b.DivisionID]), cmp.Compare(a.TeamID, b.TeamID))
+	})
+	return members, nil
+}
+
+func (r *MemoryRepository) CreatePlayoff(ctx context.Context, arg sqlc.CreatePlayoffParams) error {
+	defer r.lock()()
+
+	r.store.data.playoffs = append(r.store.data.playoffs, sqlc.Playoff{
+		ID:          nextID(r.store.data.playoffs, func(p sqlc.Playoff) int64 { return p.ID }),
+		SeasonID:    arg.SeasonID,
+		DivisionID:  arg.DivisionID,
+		UpperTeamID: arg.UpperTeamID,
+		LowerTeamID: arg.LowerTeamID,
+		UpperScore:  arg.UpperScore,
+		LowerScore:  arg.LowerScore,
+		WinnerID:    arg.WinnerID,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) ListPlayoffsBySeason(ctx context.Context, seasonID int64) ([]sqlc.ListPlayoffsBySeasonRow, error) {
+	defer r.lock()()
+
+	var rows []sqlc.ListPlayoffsBySeasonRow
+	for _, p := range r.store.data.playoffs {
+		if p.SeasonID != seasonID {
+			continue
+		}
+		upper, ok := r.team(p.UpperTeamID)
+		if !ok {
+			continue
+		}
+		lower, ok := r.team(p.LowerTeamID)
+		if !ok {
+			continue
+		}
+		rows = append(rows, sqlc.ListPlayoffsBySeasonRow{
+			ID:            p.ID,
+			SeasonID:      p.SeasonID,
+			DivisionID:    p.DivisionID,
+			UpperTeamID:   p.UpperTeamID,
+			LowerTeamID:   p.LowerTeamID,
+			UpperScore:    p.UpperScore,
+			LowerScore:    p.LowerScore,
+			WinnerID:      p.WinnerID,
+			UpperTeamName: upper.Name,
+			LowerTeamName: lower.Name,
+		})
+	}
+	return rows, nil
+}
+
+func (r *MemoryRepository) CreateCup(ctx context.Context, arg sqlc.CreateCupParams) (sqlc.Cup, error) {
+	defer r.lock()()
+
+	if _, ok := find(r.store.data.cups, func(c sqlc.Cup) bool { return c.SeasonID == arg.SeasonID }); ok {
+		return sqlc.Cup{}, ErrUniqueCup
+	}
+
+	cup := sqlc.Cup{
+		ID:       nextID(r.store.data.cups, func(c sqlc.Cup) int64 { return c.ID }),
+		SeasonID: arg.SeasonID,
+		Seeded:   arg.Seeded,
+	}
+	r.store.data.cups = append(r.store.data.cups, cup)
+	return cup, nil
+}
+
+func (r *MemoryRepository) GetCupBySeason(ctx context.Context, seasonID int64) (sqlc.Cup, error) {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.cups, func(c sqlc.Cup) bool { return c.SeasonID == seasonID })
+	if !ok {
+		return sqlc.Cup{}, sql.ErrNoRows
+	}
+	return r.store.data.cups[i], nil
+}
+
+func (r *MemoryRepository) DeleteCup(ctx context.Context, id int64) error {
+	defer r.lock()()
+
+	r.store.data.cupTies = slices.DeleteFunc(r.store.data.cupTies, func(t sqlc.CupTie) bool { return t.CupID == id })
+	r.store.data.cups = slices.DeleteFunc(r.store.data.cups, func(c sqlc.Cup) bool { return c.ID == id })
+	return nil
+}
+
+func (r *MemoryRepository) CreateCupTie(ctx context.Context, arg sqlc.CreateCupTieParams) error {
+	defer r.lock()()
+
+	r.store.data.cupTies = append(r.store.data.cupTies, sqlc.CupTie{
+		ID:      nextID(r.store.data.cupTies, func(t sqlc.CupTie) int64 { return t.ID }),
+		CupID:   arg.CupID,
+		Round:   arg.Round,
+		Slot:    arg.Slot,
+		Week:    arg.Week,
+		HomeID:  arg.HomeID,
+		GuestID: arg.GuestID,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) ListCupTies(ctx context.Context, cupID int64) ([]sqlc.ListCupTiesRow, error) {
+	defer r.lock()()
+
+	// Missing teams have no name, like the LEFT JOIN of the SQL query
+	teamName := func(id sql.NullInt64) sql.NullString {
+		team, ok := r.team(id.Int64)
+		if !id.Valid || !ok {
+			return sql.NullString{}
+		}
+		return sql.NullString{String: team.Name, Valid: true}
+	}
+
+	var rows []sqlc.ListCupTiesRow
+	for _, t := range r.store.data.cupTies {
+		if t.CupID != cupID {
+			continue
+		}
+		rows = append(rows, sqlc.ListCupTiesRow{
+			ID:             t.ID,
+			CupID:          t.CupID,
+			Round:          t.Round,
+			Slot:           t.Slot,
+			Week:           t.Week,
+			HomeID:         t.HomeID,
+			GuestID:        t.GuestID,
+			HomeScore:      t.HomeScore,
+			GuestScore:     t.GuestScore,
+			ExtraTime:      t.ExtraTime,
+			HomePenalties:  t.HomePenalties,
+			GuestPenalties: t.GuestPenalties,
+			WinnerID:       t.WinnerID,
+			Played:         t.Played,
+			HomeTeamName:   teamName(t.HomeID),
+			GuestTeamName:  teamName(t.GuestID),
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b sqlc.ListCupTiesRow) int {
+		return cmp.Or(cmp.Compare(a.Round, b.Round), cmp.Compare(a.Slot, b.Slot))
+	})
+	return rows, nil
+}
+
+func (r *MemoryRepository) DeleteCupTies(ctx context.Context, cupID int64) error {
+	defer r.lock()()
+
+	r.store.data.cupTies = slices.DeleteFunc(r.store.data.cupTies, func(t sqlc.CupTie) bool { return t.CupID == cupID })
+	return nil
+}
+
+func (r *MemoryRepository) cupTie(id int64) (int, bool) {
+	return find(r.store.data.cupTies, func(t sqlc.CupTie) bool { return t.ID == id })
+}
+
+func (r *MemoryRepository) SetCupTieTeams(ctx context.Context, arg sqlc.SetCupTieTeamsParams) error {
+	defer r.lock()()
+
+	if i, ok := r.cupTie(arg.ID); ok {
+		tie := &r.store.data.cupTies[i]
+		tie.HomeID = arg.HomeID
+		tie.GuestID = arg.GuestID
+	}
+	return nil
+}
+
+func (r *MemoryRepository) SaveCupTieResult(ctx context.Context, arg sqlc.SaveCupTieResultParams) error {
+	defer r.lock()()
+
+	if i, ok := r.cupTie(arg.ID); ok {
+		tie := &r.store.data.cupTies[i]
+		tie.HomeScore = arg.HomeScore
+		tie.GuestScore = arg.GuestScore
+		tie.ExtraTime = arg.ExtraTime
+		tie.HomePenalties = arg.HomePenalties
+		tie.GuestPenalties = arg.GuestPenalties
+		tie.WinnerID = arg.WinnerID
+		tie.Played = true
+	}
+	return nil
+}
+
+func (r *MemoryRepository) CreateTournament(ctx context.Context, arg sqlc.CreateTournamentParams) (sqlc.Tournament, error) {
+	defer r.lock()()
+
+	if _, ok := find(r.store.data.tournaments, func(t sqlc.Tournament) bool { return t.SeasonID == arg.SeasonID }); ok {
+		return sqlc.Tournament{}, ErrUniqueTournament
+	}
+
+	tournament := sqlc.Tournament{
+		ID:                nextID(r.store.data.tournaments, func(t sqlc.Tournament) int64 { return t.ID }),
+		SeasonID:          arg.SeasonID,
+		GroupCount:        arg.GroupCount,
+		Qualifiers:        arg.Qualifiers,
+		AwayGoals:         arg.AwayGoals,
+		SeparateDivisions: arg.SeparateDivisions,
+	}
+	r.store.data.tournaments = append(r.store.data.tournaments, tournament)
+	return tournament, nil
+}
+
+func (r *MemoryRepository) GetTournamentBySeason(ctx context.Context, seasonID int64) (sqlc.Tournament, error) {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.tournaments, func(t sqlc.Tournament) bool { return t.SeasonID == seasonID })
+	if !ok {
+		return sqlc.Tournament{}, sql.ErrNoRows
+	}
+	return r.store.data.tournaments[i], nil
+}
+
+func (r *MemoryRepository) DeleteTournament(ctx context.Context, id int64) error {
+	defer r.lock()()
+
+	data := &r.store.data
+	data.tournamentMatches = slices.DeleteFunc(data.tournamentMatches, func(m sqlc.TournamentMatch) bool { return m.TournamentID == id })
+	data.tournamentTeams = slices.DeleteFunc(data.tournamentTeams, func(t sqlc.TournamentTeam) bool { return t.TournamentID == id })
+	data.tournaments = slices.DeleteFunc(data.tournaments, func(t sqlc.Tournament) bool { return t.ID == id })
+	return nil
+}
+
+func (r *MemoryRepository) CreateTournamentTeam(ctx context.Context, arg sqlc.CreateTournamentTeamParams) error {
+	defer r.lock()()
+
+	r.store.data.tournamentTeams = append(r.store.data.tournamentTeams, sqlc.TournamentTeam(arg))
+	return nil
+}
+
+func (r *MemoryRepository) ListTournamentTeams(ctx context.Context, tournamentID int64) ([]sqlc.ListTournamentTeamsRow, error) {
+	defer r.lock()()
+
+	var rows []sqlc.ListTournamentTeamsRow
+	for _, t := range r.store.data.tournamentTeams {
+		team, ok := r.team(t.TeamID)
+		if t.TournamentID != tournamentID || !ok {
+			continue
+		}
+		rows = append(rows, sqlc.ListTournamentTeamsRow{
+			TournamentID: t.TournamentID,
+			TeamID:       t.TeamID,
+			Pot:          t.Pot,
+			GroupNumber:  t.GroupNumber,
+			TeamName:     team.Name,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b sqlc.ListTournamentTeamsRow) int {
+		return cmp.Or(cmp.Compare(a.GroupNumber, b.GroupNumber), cmp.Compare(a.Pot, b.Pot))
+	})
+	return rows, nil
+}
+
+func (r *MemoryRepository) DeleteTournamentTeams(ctx context.Context, tournamentID int64) error {
+	defer r.lock()()
+
+	r.store.data.tournamentTeams = slices.DeleteFunc(r.store.data.tournamentTeams, func(t sqlc.TournamentTeam) bool {
+		return t.TournamentID == tournamentID
+	})
+	return nil
+}
+
+func (r *MemoryRepository) CreateTournamentMatch(ctx context.Context, arg sqlc.CreateTournamentMatchParams) error {
+	defer r.lock()()
+
+	r.store.data.tournamentMatches = append(r.store.data.tournamentMatches, sqlc.TournamentMatch{
+		ID:           nextID(r.store.data.tournamentMatches, func(m sqlc.TournamentMatch) int64 { return m.ID }),
+		TournamentID: arg.TournamentID,
+		Round:        arg.Round,
+		Slot:         arg.Slot,
+		Leg:          arg.Leg,
+		Week:         arg.Week,
+		HomeID:       arg.HomeID,
+		GuestID:      arg.GuestID,
+	})
+	return nil
+}
+
+func (r *MemoryRepository) ListTournamentMatches(ctx context.Context, tournamentID int64) ([]sqlc.ListTournamentMatchesRow, error) {
+	defer r.lock()()
+
+	// Missing teams have no name, like the LEFT JOIN of the SQL query
+	teamName := func(id sql.NullInt64) sql.NullString {
+		team, ok := r.team(id.Int64)
+		if !id.Valid || !ok {
+			return sql.NullString{}
+		}
+		return sql.NullString{String: team.Name, Valid: true}
+	}
+
+	var rows []sqlc.ListTournamentMatchesRow
+	for _, m := range r.store.data.tournamentMatches {
+		if m.TournamentID != tournamentID {
+			continue
+		}
+		rows = append(rows, sqlc.ListTournamentMatchesRow{
+			ID:             m.ID,
+			TournamentID:   m.TournamentID,
+			Round:          m.Round,
+			Slot:           m.Slot,
+			Leg:            m.Leg,
+			Week:           m.Week,
+			HomeID:         m.HomeID,
+			GuestID:        m.GuestID,
+			HomeScore:      m.HomeScore,
+			GuestScore:     m.GuestScore,
+			ExtraTime:      m.ExtraTime,
+			HomePenalties:  m.HomePenalties,
+			GuestPenalties: m.GuestPenalties,
+			WinnerID:       m.WinnerID,
+			Played:         m.Played,
+			HomeTeamName:   teamName(m.HomeID),
+			GuestTeamName:  teamName(m.GuestID),
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b sqlc.ListTournamentMatchesRow) int {
+		return cmp.Or(cmp.Compare(a.Round, b.Round), cmp.Compare(a.Slot, b.Slot), cmp.Compare(a.Leg, b.Leg))
+	})
+	return rows, nil
+}
+
+func (r *MemoryRepository) DeleteTournamentMatches(ctx context.Context, tournamentID int64) error {
+	defer r.lock()()
+
+	r.store.data.tournamentMatches = slices.DeleteFunc(r.store.data.tournamentMatches, func(m sqlc.TournamentMatch) bool {
+		return m.TournamentID == tournamentID
+	})
+	return nil
+}
+
+func (r *MemoryRepository) tournamentMatch(id int64) (int, bool) {
+	return find(r.store.data.tournamentMatches, func(m sqlc.TournamentMatch) bool { return m.ID == id })
+}
+
+func (r *MemoryRepository) SetTournamentMatchTeams(ctx context.Context, arg sqlc.SetTournamentMatchTeamsParams) error {
+	defer r.lock()()
+
+	if i, ok := r.tournamentMatch(arg.ID); ok {
+		match := &r.store.data.tournamentMatches[i]
+		match.HomeID = arg.HomeID
+		match.GuestID = arg.GuestID
+	}
+	return nil
+}
+
+func (r *MemoryRepository) SaveTournamentMatchResult(ctx context.Context, arg sqlc.SaveTournamentMatchResultParams) error {
+	defer r.lock()()
+
+	if i, ok := r.tournamentMatch(arg.ID); ok {
+		match := &r.store.data.tournamentMatches[i]
+		match.HomeScore = arg.HomeScore
+		match.GuestScore = arg.GuestScore
+		match.ExtraTime = arg.ExtraTime
+		match.HomePenalties = arg.HomePenalties
+		match.GuestPenalties = arg.GuestPenalties
+		match.WinnerID = arg.WinnerID
+		match.Played = true
+	}
+	return nil
+}
+
+func (r *MemoryRepository) CreateCompetition(ctx context.Context, arg sqlc.CreateCompetitionParams) (sqlc.Competition, error) {
+	defer r.lock()()
+
+	if _, ok := find(r.store.data.competitions, func(c sqlc.Competition) bool {
+		return c.SeasonID == arg.SeasonID && c.Format == arg.Format
+	}); ok {
+		return sqlc.Competition{}, ErrUniqueCompetition
+	}
+
+	competition := sqlc.Competition{
+		ID:           nextID(r.store.data.competitions, func(c sqlc.Competition) int64 { return c.ID }),
+		SeasonID:     arg.SeasonID,
+		Format:       arg.Format,
+		Name:         arg.Name,
+		Legs:         arg.Legs,
+		WinPoints:    arg.WinPoints,
+		DrawPoints:   arg.DrawPoints,
+		LossPoints:   arg.LossPoints,
+		BonusGoals:   arg.BonusGoals,
+		BonusPoints:  arg.BonusPoints,
+		RankingRules: arg.RankingRules,
+	}
+	r.store.data.competitions = append(r.store.data.competitions, competition)
+	return competition, nil
+}
+
+func (r *MemoryRepository) GetCompetition(ctx context.Context, id int64) (sqlc.Competition, error) {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.competitions, func(c sqlc.Competition) bool { return c.ID == id })
+	if !ok {
+		return sqlc.Competition{}, sql.ErrNoRows
+	}
+	return r.store.data.competitions[i], nil
+}
+
+func (r *MemoryRepository) GetCompetitionBySeason(ctx context.Context, arg sqlc.GetCompetitionBySeasonParams) (sqlc.Competition, error) {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.competitions, func(c sqlc.Competition) bool {
+		return c.SeasonID == arg.SeasonID && c.Format == arg.Format
+	})
+	if !ok {
+		return sqlc.Competition{}, sql.ErrNoRows
+	}
+	return r.store.data.competitions[i], nil
+}
+
+func (r *MemoryRepository) ListCompetitions(ctx context.Context, seasonID int64) ([]sqlc.Competition, error) {
+	defer r.lock()()
+
+	var competitions []sqlc.Competition
+	for _, c := range r.store.data.competitions {
+		if c.SeasonID == seasonID {
+			competitions = append(competitions, c)
+		}
+	}
+	return competitions, nil
+}
+
+func (r *MemoryRepository) UpdateCompetitionRules(ctx context.Context, arg sqlc.UpdateCompetitionRulesParams) error {
+	defer r.lock()()
+
+	if i, ok := find(r.store.data.competitions, func(c sqlc.Competition) bool { return c.ID == arg.ID }); ok {
+		competition := &r.store.data.competitions[i]
+		competition.Legs = arg.Legs
+		competition.WinPoints = arg.WinPoints
+		competition.DrawPoints = arg.DrawPoints
+		competition.LossPoints = arg.LossPoints
+		competition.BonusGoals = arg.BonusGoals
+		competition.BonusPoints = arg.BonusPoints
+		competition.RankingRules = arg.RankingRules
+	}
+	return nil
+}
+
+func (r *MemoryRepository) SetCompetitionRankingRules(ctx context.Context, id int64, rules string) error {
+	defer r.lock()()
+
+	if i, ok := find(r.store.data.competitions, func(c sqlc.Competition) bool { return c.ID == id }); ok {
+		r.store.data.competitions[i].RankingRules = rules
+	}
+	return nil
+}
+
+func (r *MemoryRepository) CreateCompetitionTeam(ctx context.Context, arg sqlc.CreateCompetitionTeamParams) error {
+	defer r.lock()()
+
+	r.store.data.competitionTeams = append(r.store.data.competitionTeams, sqlc.CompetitionTeam(arg))
+	return nil
+}
+
+func (r *MemoryRepository) ListCompetitionTeams(ctx context.Context, competitionID int64) ([]sqlc.ListCompetitionTeamsRow, error) {
+	defer r.lock()()
+
+	var rows []sqlc.ListCompetitionTeamsRow
+	for _, t := range r.store.data.competitionTeams {
+		team, ok := r.team(t.TeamID)
+		if t.CompetitionID != competitionID || !ok {
+			continue
+		}
+		rows = append(rows, sqlc.ListCompetitionTeamsRow{
+			CompetitionID: t.CompetitionID,
+			TeamID:        t.TeamID,
+			TeamName:      team.Name,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b sqlc.ListCompetitionTeamsRow) int { return cmp.Compare(a.TeamName, b.TeamName) })
+	return rows, nil
+}
+
+func (r *MemoryRepository) DeleteCompetitionTeams(ctx context.Context, competitionID int64) error {
+	defer r.lock()()
+
+	r.store.data.competitionTeams = slices.DeleteFunc(r.store.data.competitionTeams, func(t sqlc.CompetitionTeam) bool {
+		return t.CompetitionID == competitionID
+	})
+	return nil
+}
+
+func (r *MemoryRepository) CreateSanction(ctx context.Context, arg sqlc.CreateSanctionParams) (sqlc.Sanction, error) {
+	defer r.lock()()
+
+	sanction := sqlc.Sanction{
+		ID:            nextID(r.store.data.sanctions, func(s sqlc.Sanction) int64 { return s.ID }),
+		CompetitionID: arg.CompetitionID,
+		TeamID:        arg.TeamID,
+		Kind:          arg.Kind,
+		Points:        arg.Points,
+		MatchID:       arg.MatchID,
+		FirstWeek:     arg.FirstWeek,
+		LastWeek:      arg.LastWeek,
+		Reason:        arg.Reason,
+		CreatedAt:     arg.CreatedAt,
+	}
+	r.store.data.sanctions = append(r.store.data.sanctions, sanction)
+	return sanction, nil
+}
+
+func (r *MemoryRepository) GetSanction(ctx context.Context, id int64) (sqlc.Sanction, error) {
+	defer r.lock()()
+
+	i, ok := find(r.store.data.sanctions, func(s sqlc.Sanction) bool { return s.ID == id })
+	if !ok {
+		return sqlc.Sanction{}, sql.ErrNoRows
+	}
+	return r.store.data.sanctions[i], nil
+}
+
+func (r *MemoryRepository) ListSanctions(ctx context.Context, seasonID int64) ([]sqlc.ListSanctionsRow, error) {
+	defer r.lock()()
+
+	var rows []sqlc.ListSanctionsRow
+	for _, s := range r.store.data.sanctions {
+		i, ok := find(r.store.data.competitions, func(c sqlc.Competition) bool { return c.ID == s.CompetitionID })
+		if !ok || r.store.data.competitions[i].SeasonID != seasonID {
+			continue
+		}
+		team, ok := r.team(s.TeamID)
+		if !ok {
+			continue
+		}
+		rows = append(rows, sqlc.ListSanctionsRow{
+			ID:            s.ID,
+			CompetitionID: s.CompetitionID,
+			TeamID:        s.TeamID,
+			Kind:          s.Kind,
+			Points:        s.Points,
+			MatchID:       s.MatchID,
+			FirstWeek:     s.FirstWeek,
+			LastWeek:      s.LastWeek,
+			Reason:        s.Reason,
+			CreatedAt:     s.CreatedAt,
+			Format:        r.store.data.competitions[i].Format,
+			TeamName:      team.Name,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b sqlc.ListSanctionsRow) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return rows, nil
+}
+
+func (r *MemoryRepository) DeleteSanction(ctx context.Context, id int64) error {
+	defer r.lock()()
+
+	r.store.data.sanctions = slices.DeleteFunc(r.store.data.sanctions, func(s sqlc.Sanction) bool { return s.ID == id })
+	return nil
+}
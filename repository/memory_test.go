@@ -0,0 +1,19 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/repository/repotest"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.RunSuite(t, func(t *testing.T) repository.Repository {
+		repo := repository.NewMemoryRepository()
+		if err := repotest.Seed(context.Background(), repo); err != nil {
+			t.Fatalf("Seed: %v", err)
+		}
+		return repo
+	})
+}
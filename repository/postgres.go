@@ -0,0 +1,507 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/orhosko/go-backend/sqlc"
+	"github.com/orhosko/go-backend/sqlc/pg"
+)
+
+// NewPostgresRepository creates a SQLCRepository backed by the PostgreSQL
+// queries.
+func NewPostgresRepository(conn *sql.DB) *SQLCRepository {
+	return &SQLCRepository{
+		queries: pgQuerier{pg.New(conn)},
+		newQueries: func(db sqlc.DBTX) sqlc.Querier {
+			return pgQuerier{pg.New(db)}
+		},
+		Conn: conn,
+	}
+}
+
+// pgQuerier adapts the PostgreSQL queries to sqlc.Querier. Both packages are
+// generated from the same query names and columns, so their rows and
+// parameters only differ in package and convert directly.
+type pgQuerier struct {
+	q *pg.Queries
+}
+
+var _ sqlc.Querier = pgQuerier{}
+
+// convertRows converts each generated PostgreSQL row to its SQLite
+// counterpart.
+func convertRows[From, To any](rows []From, err error, convert func(From) To) ([]To, error) {
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]To, len(rows))
+	for i, row := range rows {
+		converted[i] = convert(row)
+	}
+	return converted, nil
+}
+
+func (p pgQuerier) AddDivisionTeam(ctx context.Context, arg sqlc.AddDivisionTeamParams) error {
+	return p.q.AddDivisionTeam(ctx, pg.AddDivisionTeamParams(arg))
+}
+
+func (p pgQuerier) ClearCupTieTeamsFromWeek(ctx context.Context, arg sqlc.ClearCupTieTeamsFromWeekParams) error {
+	return p.q.ClearCupTieTeamsFromWeek(ctx, pg.ClearCupTieTeamsFromWeekParams(arg))
+}
+
+func (p pgQuerier) ClearTournamentMatchTeamsFromWeek(ctx context.Context, arg sqlc.ClearTournamentMatchTeamsFromWeekParams) error {
+	return p.q.ClearTournamentMatchTeamsFromWeek(ctx, pg.ClearTournamentMatchTeamsFromWeekParams(arg))
+}
+
+func (p pgQuerier) CompleteSeason(ctx context.Context, id int64) error {
+	return p.q.CompleteSeason(ctx, id)
+}
+
+func (p pgQuerier) ComputeStandings(ctx context.Context, seasonID int64) ([]sqlc.ComputeStandingsRow, error) {
+	rows, err := p.q.ComputeStandings(ctx, seasonID)
+	return convertRows(rows, err, func(row pg.ComputeStandingsRow) sqlc.ComputeStandingsRow {
+		return sqlc.ComputeStandingsRow{
+			Team:         sqlc.Team(row.Team),
+			Played:       row.Played,
+			Wins:         row.Wins,
+			Draws:        row.Draws,
+			Losses:       row.Losses,
+			GoalsFor:     row.GoalsFor,
+			GoalsAgainst: row.GoalsAgainst,
+			GoalDiff:     row.GoalDiff,
+			AwayGoalsFor: row.AwayGoalsFor,
+		}
+	})
+}
+
+func (p pgQuerier) CountTeamMatches(ctx context.Context, teamID int64) (int64, error) {
+	return p.q.CountTeamMatches(ctx, teamID)
+}
+
+func (p pgQuerier) CreateCompetition(ctx context.Context, arg sqlc.CreateCompetitionParams) (sqlc.Competition, error) {
+	competition, err := p.q.CreateCompetition(ctx, pg.CreateCompetitionParams(arg))
+	return sqlc.Competition(competition), err
+}
+
+func (p pgQuerier) CreateCompetitionTeam(ctx context.Context, arg sqlc.CreateCompetitionTeamParams) error {
+	return p.q.CreateCompetitionTeam(ctx, pg.CreateCompetitionTeamParams(arg))
+}
+
+func (p pgQuerier) CreateCup(ctx context.Context, arg sqlc.CreateCupParams) (sqlc.Cup, error) {
+	cup, err := p.q.CreateCup(ctx, pg.CreateCupParams(arg))
+	return sqlc.Cup(cup), err
+}
+
+func (p pgQuerier) CreateCupTie(ctx context.Context, arg sqlc.CreateCupTieParams) error {
+	return p.q.CreateCupTie(ctx, pg.CreateCupTieParams(arg))
+}
+
+func (p pgQuerier) CreateDivision(ctx context.Context, arg sqlc.CreateDivisionParams) (sqlc.Division, error) {
+	division, err := p.q.CreateDivision(ctx, pg.CreateDivisionParams(arg))
+	return sqlc.Division(division), err
+}
+
+func (p pgQuerier) CreateFixture(ctx context.Context, arg sqlc.CreateFixtureParams) error {
+	return p.q.CreateFixture(ctx, pg.CreateFixtureParams(arg))
+}
+
+func (p pgQuerier) CreateNewSeason(ctx context.Context, arg sqlc.CreateNewSeasonParams) (sqlc.Season, error) {
+	season, err := p.q.CreateNewSeason(ctx, pg.CreateNewSeasonParams(arg))
+	return sqlc.Season(season), err
+}
+
+func (p pgQuerier) CreatePlayoff(ctx context.Context, arg sqlc.CreatePlayoffParams) error {
+	return p.q.CreatePlayoff(ctx, pg.CreatePlayoffParams(arg))
+}
+
+func (p pgQuerier) CreateSanction(ctx context.Context, arg sqlc.CreateSanctionParams) (sqlc.Sanction, error) {
+	sanction, err := p.q.CreateSanction(ctx, pg.CreateSanctionParams(arg))
+	return sqlc.Sanction(sanction), err
+}
+
+func (p pgQuerier) CreateStanding(ctx context.Context, arg sqlc.CreateStandingParams) error {
+	return p.q.CreateStanding(ctx, pg.CreateStandingParams(arg))
+}
+
+func (p pgQuerier) CreateTeam(ctx context.Context, arg sqlc.CreateTeamParams) (sqlc.Team, error) {
+	team, err := p.q.CreateTeam(ctx, pg.CreateTeamParams(arg))
+	return sqlc.Team(team), err
+}
+
+func (p pgQuerier) CreateTeamHistory(ctx context.Context, arg sqlc.CreateTeamHistoryParams) error {
+	return p.q.CreateTeamHistory(ctx, pg.CreateTeamHistoryParams(arg))
+}
+
+func (p pgQuerier) CreateTournament(ctx context.Context, arg sqlc.CreateTournamentParams) (sqlc.Tournament, error) {
+	tournament, err := p.q.CreateTournament(ctx, pg.CreateTournamentParams(arg))
+	return sqlc.Tournament(tournament), err
+}
+
+func (p pgQuerier) CreateTournamentMatch(ctx context.Context, arg sqlc.CreateTournamentMatchParams) error {
+	return p.q.CreateTournamentMatch(ctx, pg.CreateTournamentMatchParams(arg))
+}
+
+func (p pgQuerier) CreateTournamentTeam(ctx context.Context, arg sqlc.CreateTournamentTeamParams) error {
+	return p.q.CreateTournamentTeam(ctx, pg.CreateTournamentTeamParams(arg))
+}
+
+func (p pgQuerier) DeleteCompetitionTeams(ctx context.Context, competitionID int64) error {
+	return p.q.DeleteCompetitionTeams(ctx, competitionID)
+}
+
+func (p pgQuerier) DeleteCup(ctx context.Context, id int64) error {
+	return p.q.DeleteCup(ctx, id)
+}
+
+func (p pgQuerier) DeleteCupTies(ctx context.Context, cupID int64) error {
+	return p.q.DeleteCupTies(ctx, cupID)
+}
+
+func (p pgQuerier) DeleteDivision(ctx context.Context, id int64) error {
+	return p.q.DeleteDivision(ctx, id)
+}
+
+func (p pgQuerier) DeletePlayoffsBySeason(ctx context.Context, seasonID int64) error {
+	return p.q.DeletePlayoffsBySeason(ctx, seasonID)
+}
+
+func (p pgQuerier) DeleteResultsFromWeek(ctx context.Context, arg sqlc.DeleteResultsFromWeekParams) error {
+	return p.q.DeleteResultsFromWeek(ctx, pg.DeleteResultsFromWeekParams(arg))
+}
+
+func (p pgQuerier) DeleteSanction(ctx context.Context, id int64) error {
+	return p.q.DeleteSanction(ctx, id)
+}
+
+func (p pgQuerier) DeleteTeam(ctx context.Context, id int64) error {
+	return p.q.DeleteTeam(ctx, id)
+}
+
+func (p pgQuerier) DeleteTeamCompetitions(ctx context.Context, teamID int64) error {
+	return p.q.DeleteTeamCompetitions(ctx, teamID)
+}
+
+func (p pgQuerier) DeleteTeamDivisions(ctx context.Context, teamID int64) error {
+	return p.q.DeleteTeamDivisions(ctx, teamID)
+}
+
+func (p pgQuerier) DeleteTeamSanctions(ctx context.Context, teamID int64) error {
+	return p.q.DeleteTeamSanctions(ctx, teamID)
+}
+
+func (p pgQuerier) DeleteTeamStandings(ctx context.Context, teamID int64) error {
+	return p.q.DeleteTeamStandings(ctx, teamID)
+}
+
+func (p pgQuerier) DeleteTournament(ctx context.Context, id int64) error {
+	return p.q.DeleteTournament(ctx, id)
+}
+
+func (p pgQuerier) DeleteTournamentMatches(ctx context.Context, tournamentID int64) error {
+	return p.q.DeleteTournamentMatches(ctx, tournamentID)
+}
+
+func (p pgQuerier) DeleteTournamentTeams(ctx context.Context, tournamentID int64) error {
+	return p.q.DeleteTournamentTeams(ctx, tournamentID)
+}
+
+func (p pgQuerier) DeleteUnplayedMatchesBySeason(ctx context.Context, seasonID int64) error {
+	return p.q.DeleteUnplayedMatchesBySeason(ctx, seasonID)
+}
+
+func (p pgQuerier) GetAllMatchesPlayedForWeek(ctx context.Context, arg sqlc.GetAllMatchesPlayedForWeekParams) (bool, error) {
+	return p.q.GetAllMatchesPlayedForWeek(ctx, pg.GetAllMatchesPlayedForWeekParams(arg))
+}
+
+func (p pgQuerier) GetCompetition(ctx context.Context, id int64) (sqlc.Competition, error) {
+	competition, err := p.q.GetCompetition(ctx, id)
+	return sqlc.Competition(competition), err
+}
+
+func (p pgQuerier) GetCompetitionBySeason(ctx context.Context, arg sqlc.GetCompetitionBySeasonParams) (sqlc.Competition, error) {
+	competition, err := p.q.GetCompetitionBySeason(ctx, pg.GetCompetitionBySeasonParams(arg))
+	return sqlc.Competition(competition), err
+}
+
+func (p pgQuerier) GetCupBySeason(ctx context.Context, seasonID int64) (sqlc.Cup, error) {
+	cup, err := p.q.GetCupBySeason(ctx, seasonID)
+	return sqlc.Cup(cup), err
+}
+
+func (p pgQuerier) GetCurrentSeason(ctx context.Context) (sqlc.Season, error) {
+	season, err := p.q.GetCurrentSeason(ctx)
+	return sqlc.Season(season), err
+}
+
+func (p pgQuerier) GetCurrentWeek(ctx context.Context, seasonID int64) (sql.NullInt64, error) {
+	return p.q.GetCurrentWeek(ctx, seasonID)
+}
+
+func (p pgQuerier) GetDivision(ctx context.Context, id int64) (sqlc.Division, error) {
+	division, err := p.q.GetDivision(ctx, id)
+	return sqlc.Division(division), err
+}
+
+func (p pgQuerier) GetMatchResult(ctx context.Context, matchID int64) (sqlc.GetMatchResultRow, error) {
+	result, err := p.q.GetMatchResult(ctx, matchID)
+	return sqlc.GetMatchResultRow(result), err
+}
+
+func (p pgQuerier) GetMatchesBySeason(ctx context.Context, seasonID int64) ([]sqlc.GetMatchesBySeasonRow, error) {
+	rows, err := p.q.GetMatchesBySeason(ctx, seasonID)
+	return convertRows(rows, err, func(row pg.GetMatchesBySeasonRow) sqlc.GetMatchesBySeasonRow {
+		return sqlc.GetMatchesBySeasonRow(row)
+	})
+}
+
+func (p pgQuerier) GetMatchesByTeam(ctx context.Context, arg sqlc.GetMatchesByTeamParams) ([]sqlc.GetMatchesByTeamRow, error) {
+	rows, err := p.q.GetMatchesByTeam(ctx, pg.GetMatchesByTeamParams(arg))
+	return convertRows(rows, err, func(row pg.GetMatchesByTeamRow) sqlc.GetMatchesByTeamRow {
+		return sqlc.GetMatchesByTeamRow(row)
+	})
+}
+
+func (p pgQuerier) GetMatchesByWeek(ctx context.Context, arg sqlc.GetMatchesByWeekParams) ([]sqlc.GetMatchesByWeekRow, error) {
+	rows, err := p.q.GetMatchesByWeek(ctx, pg.GetMatchesByWeekParams(arg))
+	return convertRows(rows, err, func(row pg.GetMatchesByWeekRow) sqlc.GetMatchesByWeekRow {
+		return sqlc.GetMatchesByWeekRow(row)
+	})
+}
+
+func (p pgQuerier) GetResultsBySeason(ctx context.Context, seasonID int64) ([]sqlc.GetResultsBySeasonRow, error) {
+	rows, err := p.q.GetResultsBySeason(ctx, seasonID)
+	return convertRows(rows, err, func(row pg.GetResultsBySeasonRow) sqlc.GetResultsBySeasonRow {
+		return sqlc.GetResultsBySeasonRow(row)
+	})
+}
+
+func (p pgQuerier) GetSanction(ctx context.Context, id int64) (sqlc.Sanction, error) {
+	sanction, err := p.q.GetSanction(ctx, id)
+	return sqlc.Sanction(sanction), err
+}
+
+func (p pgQuerier) GetSeason(ctx context.Context, id int64) (sqlc.Season, error) {
+	season, err := p.q.GetSeason(ctx, id)
+	return sqlc.Season(season), err
+}
+
+func (p pgQuerier) GetSeasonWeeks(ctx context.Context, seasonID int64) (int64, error) {
+	return p.q.GetSeasonWeeks(ctx, seasonID)
+}
+
+func (p pgQuerier) GetStanding(ctx context.Context, arg sqlc.GetStandingParams) (sqlc.Standing, error) {
+	standing, err := p.q.GetStanding(ctx, pg.GetStandingParams(arg))
+	return sqlc.Standing(standing), err
+}
+
+func (p pgQuerier) GetTeam(ctx context.Context, id int64) (sqlc.Team, error) {
+	team, err := p.q.GetTeam(ctx, id)
+	return sqlc.Team(team), err
+}
+
+func (p pgQuerier) GetTeamByName(ctx context.Context, name string) (sqlc.Team, error) {
+	team, err := p.q.GetTeamByName(ctx, name)
+	return sqlc.Team(team), err
+}
+
+func (p pgQuerier) GetTournamentBySeason(ctx context.Context, seasonID int64) (sqlc.Tournament, error) {
+	tournament, err := p.q.GetTournamentBySeason(ctx, seasonID)
+	return sqlc.Tournament(tournament), err
+}
+
+func (p pgQuerier) GetUnplayedMatchesBySeason(ctx context.Context, seasonID int64) ([]sqlc.Match, error) {
+	rows, err := p.q.GetUnplayedMatchesBySeason(ctx, seasonID)
+	return convertRows(rows, err, func(row pg.Match) sqlc.Match {
+		return sqlc.Match(row)
+	})
+}
+
+func (p pgQuerier) GetUnplayedMatchesByWeek(ctx context.Context, arg sqlc.GetUnplayedMatchesByWeekParams) ([]sqlc.GetUnplayedMatchesByWeekRow, error) {
+	rows, err := p.q.GetUnplayedMatchesByWeek(ctx, pg.GetUnplayedMatchesByWeekParams(arg))
+	return convertRows(rows, err, func(row pg.GetUnplayedMatchesByWeekRow) sqlc.GetUnplayedMatchesByWeekRow {
+		return sqlc.GetUnplayedMatchesByWeekRow(row)
+	})
+}
+
+func (p pgQuerier) IncrementWeek(ctx context.Context, seasonID int64) error {
+	return p.q.IncrementWeek(ctx, seasonID)
+}
+
+func (p pgQuerier) InitializeGameState(ctx context.Context, seasonID int64) error {
+	return p.q.InitializeGameState(ctx, seasonID)
+}
+
+func (p pgQuerier) ListCompetitionTeams(ctx context.Context, competitionID int64) ([]sqlc.ListCompetitionTeamsRow, error) {
+	rows, err := p.q.ListCompetitionTeams(ctx, competitionID)
+	return convertRows(rows, err, func(row pg.ListCompetitionTeamsRow) sqlc.ListCompetitionTeamsRow {
+		return sqlc.ListCompetitionTeamsRow(row)
+	})
+}
+
+func (p pgQuerier) ListCompetitions(ctx context.Context, seasonID int64) ([]sqlc.Competition, error) {
+	rows, err := p.q.ListCompetitions(ctx, seasonID)
+	return convertRows(rows, err, func(row pg.Competition) sqlc.Competition {
+		return sqlc.Competition(row)
+	})
+}
+
+func (p pgQuerier) ListCupTies(ctx context.Context, cupID int64) ([]sqlc.ListCupTiesRow, error) {
+	rows, err := p.q.ListCupTies(ctx, cupID)
+	return convertRows(rows, err, func(row pg.ListCupTiesRow) sqlc.ListCupTiesRow {
+		return sqlc.ListCupTiesRow(row)
+	})
+}
+
+func (p pgQuerier) ListDivisionTeams(ctx context.Context, seasonID int64) ([]sqlc.DivisionTeam, error) {
+	rows, err := p.q.ListDivisionTeams(ctx, seasonID)
+	return convertRows(rows, err, func(row pg.DivisionTeam) sqlc.DivisionTeam {
+		return sqlc.DivisionTeam(row)
+	})
+}
+
+func (p pgQuerier) ListDivisions(ctx context.Context, seasonID int64) ([]sqlc.Division, error) {
+	rows, err := p.q.ListDivisions(ctx, seasonID)
+	return convertRows(rows, err, func(row pg.Division) sqlc.Division {
+		return sqlc.Division(row)
+	})
+}
+
+func (p pgQuerier) ListHeadToHeadMatches(ctx context.Context, arg sqlc.ListHeadToHeadMatchesParams) ([]sqlc.ListHeadToHeadMatchesRow, error) {
+	rows, err := p.q.ListHeadToHeadMatches(ctx, pg.ListHeadToHeadMatchesParams(arg))
+	return convertRows(rows, err, func(row pg.ListHeadToHeadMatchesRow) sqlc.ListHeadToHeadMatchesRow {
+		return sqlc.ListHeadToHeadMatchesRow(row)
+	})
+}
+
+func (p pgQuerier) ListHeadToHeadSeasons(ctx context.Context, arg sqlc.ListHeadToHeadSeasonsParams) ([]sqlc.ListHeadToHeadSeasonsRow, error) {
+	rows, err := p.q.ListHeadToHeadSeasons(ctx, pg.ListHeadToHeadSeasonsParams(arg))
+	return convertRows(rows, err, func(row pg.ListHeadToHeadSeasonsRow) sqlc.ListHeadToHeadSeasonsRow {
+		return sqlc.ListHeadToHeadSeasonsRow(row)
+	})
+}
+
+func (p pgQuerier) ListPlayoffsBySeason(ctx context.Context, seasonID int64) ([]sqlc.ListPlayoffsBySeasonRow, error) {
+	rows, err := p.q.ListPlayoffsBySeason(ctx, seasonID)
+	return convertRows(rows, err, func(row pg.ListPlayoffsBySeasonRow) sqlc.ListPlayoffsBySeasonRow {
+		return sqlc.ListPlayoffsBySeasonRow(row)
+	})
+}
+
+func (p pgQuerier) ListSanctions(ctx context.Context, seasonID int64) ([]sqlc.ListSanctionsRow, error) {
+	rows, err := p.q.ListSanctions(ctx, seasonID)
+	return convertRows(rows, err, func(row pg.ListSanctionsRow) sqlc.ListSanctionsRow {
+		return sqlc.ListSanctionsRow(row)
+	})
+}
+
+func (p pgQuerier) ListSeasons(ctx context.Context) ([]sqlc.Season, error) {
+	rows, err := p.q.ListSeasons(ctx)
+	return convertRows(rows, err, func(row pg.Season) sqlc.Season {
+		return sqlc.Season(row)
+	})
+}
+
+func (p pgQuerier) ListTeamHistory(ctx context.Context) ([]sqlc.ListTeamHistoryRow, error) {
+	rows, err := p.q.ListTeamHistory(ctx)
+	return convertRows(rows, err, func(row pg.ListTeamHistoryRow) sqlc.ListTeamHistoryRow {
+		return sqlc.ListTeamHistoryRow(row)
+	})
+}
+
+func (p pgQuerier) ListTeams(ctx context.Context) ([]sqlc.Team, error) {
+	rows, err := p.q.ListTeams(ctx)
+	return convertRows(rows, err, func(row pg.Team) sqlc.Team {
+		return sqlc.Team(row)
+	})
+}
+
+func (p pgQuerier) ListTournamentMatches(ctx context.Context, tournamentID int64) ([]sqlc.ListTournamentMatchesRow, error) {
+	rows, err := p.q.ListTournamentMatches(ctx, tournamentID)
+	return convertRows(rows, err, func(row pg.ListTournamentMatchesRow) sqlc.ListTournamentMatchesRow {
+		return sqlc.ListTournamentMatchesRow(row)
+	})
+}
+
+func (p pgQuerier) ListTournamentTeams(ctx context.Context, tournamentID int64) ([]sqlc.ListTournamentTeamsRow, error) {
+	rows, err := p.q.ListTournamentTeams(ctx, tournamentID)
+	return convertRows(rows, err, func(row pg.ListTournamentTeamsRow) sqlc.ListTournamentTeamsRow {
+		return sqlc.ListTournamentTeamsRow(row)
+	})
+}
+
+func (p pgQuerier) MarkMatchAsPlayed(ctx context.Context, id int64) error {
+	return p.q.MarkMatchAsPlayed(ctx, id)
+}
+
+func (p pgQuerier) RemoveDivisionTeam(ctx context.Context, arg sqlc.RemoveDivisionTeamParams) error {
+	return p.q.RemoveDivisionTeam(ctx, pg.RemoveDivisionTeamParams(arg))
+}
+
+func (p pgQuerier) ReopenSeason(ctx context.Context, id int64) error {
+	return p.q.ReopenSeason(ctx, id)
+}
+
+func (p pgQuerier) ResetCupTiesFromWeek(ctx context.Context, arg sqlc.ResetCupTiesFromWeekParams) error {
+	return p.q.ResetCupTiesFromWeek(ctx, pg.ResetCupTiesFromWeekParams(arg))
+}
+
+func (p pgQuerier) ResetTournamentMatchesFromWeek(ctx context.Context, arg sqlc.ResetTournamentMatchesFromWeekParams) error {
+	return p.q.ResetTournamentMatchesFromWeek(ctx, pg.ResetTournamentMatchesFromWeekParams(arg))
+}
+
+func (p pgQuerier) SaveCupTieResult(ctx context.Context, arg sqlc.SaveCupTieResultParams) error {
+	return p.q.SaveCupTieResult(ctx, pg.SaveCupTieResultParams(arg))
+}
+
+func (p pgQuerier) SaveResult(ctx context.Context, arg sqlc.SaveResultParams) error {
+	return p.q.SaveResult(ctx, pg.SaveResultParams(arg))
+}
+
+func (p pgQuerier) SaveTournamentMatchResult(ctx context.Context, arg sqlc.SaveTournamentMatchResultParams) error {
+	return p.q.SaveTournamentMatchResult(ctx, pg.SaveTournamentMatchResultParams(arg))
+}
+
+func (p pgQuerier) SetCupTieTeams(ctx context.Context, arg sqlc.SetCupTieTeamsParams) error {
+	return p.q.SetCupTieTeams(ctx, pg.SetCupTieTeamsParams(arg))
+}
+
+func (p pgQuerier) SetCompetitionRankingRules(ctx context.Context, arg sqlc.SetCompetitionRankingRulesParams) error {
+	return p.q.SetCompetitionRankingRules(ctx, pg.SetCompetitionRankingRulesParams(arg))
+}
+
+func (p pgQuerier) SetCurrentSeason(ctx context.Context, id int64) error {
+	return p.q.SetCurrentSeason(ctx, id)
+}
+
+func (p pgQuerier) SetCurrentWeek(ctx context.Context, arg sqlc.SetCurrentWeekParams) error {
+	return p.q.SetCurrentWeek(ctx, pg.SetCurrentWeekParams(arg))
+}
+
+func (p pgQuerier) SetTournamentMatchTeams(ctx context.Context, arg sqlc.SetTournamentMatchTeamsParams) error {
+	return p.q.SetTournamentMatchTeams(ctx, pg.SetTournamentMatchTeamsParams(arg))
+}
+
+func (p pgQuerier) UnplayMatchesFromWeek(ctx context.Context, arg sqlc.UnplayMatchesFromWeekParams) error {
+	return p.q.UnplayMatchesFromWeek(ctx, pg.UnplayMatchesFromWeekParams(arg))
+}
+
+func (p pgQuerier) UpdateCompetitionRules(ctx context.Context, arg sqlc.UpdateCompetitionRulesParams) error {
+	return p.q.UpdateCompetitionRules(ctx, pg.UpdateCompetitionRulesParams(arg))
+}
+
+func (p pgQuerier) UpdateDivision(ctx context.Context, arg sqlc.UpdateDivisionParams) error {
+	return p.q.UpdateDivision(ctx, pg.UpdateDivisionParams(arg))
+}
+
+func (p pgQuerier) UpdateStanding(ctx context.Context, arg sqlc.UpdateStandingParams) error {
+	return p.q.UpdateStanding(ctx, pg.UpdateStandingParams(arg))
+}
+
+func (p pgQuerier) UpdateTeam(ctx context.Context, arg sqlc.UpdateTeamParams) error {
+	return p.q.UpdateTeam(ctx, pg.UpdateTeamParams(arg))
+}
+
+func (p pgQuerier) UpdateTeamStrength(ctx context.Context, arg sqlc.UpdateTeamStrengthParams) error {
+	return p.q.UpdateTeamStrength(ctx, pg.UpdateTeamStrengthParams(arg))
+}
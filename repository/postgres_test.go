@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	database "github.com/orhosko/go-backend/db"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/repository/repotest"
+)
+
+// TestPostgresRepository runs the shared suite against the PostgreSQL server
+// in POSTGRES_TEST_URL, e.g. a local or throwaway container instance. Each
+// test migrates its own schema, which is dropped afterwards.
+func TestPostgresRepository(t *testing.T) {
+	serverURL := os.Getenv("POSTGRES_TEST_URL")
+	if serverURL == "" {
+		t.Skip("POSTGRES_TEST_URL is not set")
+	}
+
+	admin, err := database.NewDB(serverURL)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	n := 0
+	repotest.RunSuite(t, func(t *testing.T) repository.Repository {
+		ctx := context.Background()
+
+		n++
+		schema := fmt.Sprintf("repotest_%d_%d", time.Now().UnixNano(), n)
+		if _, err := admin.Conn.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+			t.Fatalf("create schema: %v", err)
+		}
+		t.Cleanup(func() {
+			admin.Conn.ExecContext(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		})
+
+		dbConn, err := database.NewDB(withSearchPath(t, serverURL, schema))
+		if err != nil {
+			t.Fatalf("NewDB: %v", err)
+		}
+		t.Cleanup(func() { dbConn.Close() })
+
+		if _, err := dbConn.MigrateUp(ctx); err != nil {
+			t.Fatalf("MigrateUp: %v", err)
+		}
+		return repository.NewPostgresRepository(dbConn.Conn)
+	})
+}
+
+// withSearchPath points every connection of the URL at the schema.
+func withSearchPath(t *testing.T, rawURL, schema string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("invalid POSTGRES_TEST_URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
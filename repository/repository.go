@@ -2,18 +2,31 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/orhosko/go-backend/sqlc"
 )
 
+// ErrTeamHasMatches is returned when deleting a team that has played or is
+// scheduled to play matches.
+var ErrTeamHasMatches = errors.New("team has matches")
+
 // TeamRepository defines the interface for team-related database operations.
 type TeamRepository interface {
 	CreateTeam(ctx context.Context, arg sqlc.CreateTeamParams) (sqlc.Team, error)
 	GetTeam(ctx context.Context, id int64) (sqlc.Team, error)
 	GetTeamByName(ctx context.Context, name string) (sqlc.Team, error)
 	ListTeams(ctx context.Context) ([]sqlc.Team, error)
+	UpdateTeam(ctx context.Context, arg sqlc.UpdateTeamParams) error
 	UpdateTeamStrength(ctx context.Context, arg sqlc.UpdateTeamStrengthParams) error
+	// DeleteTeam deletes a team, its standings and division places. It returns
+	// ErrTeamHasMatches if the team has any fixtures.
 	DeleteTeam(ctx context.Context, id int64) error
+	CountTeamMatches(ctx context.Context, teamID int64) (int64, error)
+	CreateTeamHistory(ctx context.Context, arg sqlc.CreateTeamHistoryParams) error
+	// ListTeamHistory returns how every team changed at the end of each
+	// season, ordered by team and then season.
+	ListTeamHistory(ctx context.Context) ([]sqlc.ListTeamHistoryRow, error)
 }
 
 // StandingRepository defines the interface for standing-related database operations.
@@ -21,6 +34,7 @@ type StandingRepository interface {
 	GetStanding(ctx context.Context, teamID int64, seasonID int64) (sqlc.Standing, error)
 	UpdateStanding(ctx context.Context, arg sqlc.UpdateStandingParams) error
 	CreateStanding(ctx context.Context, arg sqlc.CreateStandingParams) error
+	ComputeStandings(ctx context.Context, seasonID int64) ([]sqlc.ComputeStandingsRow, error)
 }
 
 // MatchRepository defines the interface for match-related database operations.
@@ -29,6 +43,20 @@ type MatchRepository interface {
 	SaveResult(ctx context.Context, arg sqlc.SaveResultParams) error
 	GetMatchesByWeek(ctx context.Context, week int64, seasonID int64) ([]sqlc.GetMatchesByWeekRow, error)
 	GetUnplayedMatchesByWeek(ctx context.Context, week int64, seasonID int64) ([]sqlc.GetUnplayedMatchesByWeekRow, error)
+	GetUnplayedMatchesBySeason(ctx context.Context, seasonID int64) ([]sqlc.Match, error)
+	GetMatchesBySeason(ctx context.Context, seasonID int64) ([]sqlc.GetMatchesBySeasonRow, error)
+	// GetMatchesByTeam returns every fixture of a team in a season with its
+	// score once played, ordered by week.
+	GetMatchesByTeam(ctx context.Context, teamID int64, seasonID int64) ([]sqlc.GetMatchesByTeamRow, error)
+	GetSeasonWeeks(ctx context.Context, seasonID int64) (int, error)
+	DeleteUnplayedMatchesBySeason(ctx context.Context, seasonID int64) error
+	GetResultsBySeason(ctx context.Context, seasonID int64) ([]sqlc.GetResultsBySeasonRow, error)
+	// ListHeadToHeadSeasons returns the record of a team against an opponent
+	// in every season they met, oldest first.
+	ListHeadToHeadSeasons(ctx context.Context, teamID int64, opponentID int64) ([]sqlc.ListHeadToHeadSeasonsRow, error)
+	// ListHeadToHeadMatches returns the played meetings of two teams across
+	// every season, latest first.
+	ListHeadToHeadMatches(ctx context.Context, teamID int64, opponentID int64) ([]sqlc.ListHeadToHeadMatchesRow, error)
 	GetMatchResult(ctx context.Context, matchID int64) (sqlc.GetMatchResultRow, error)
 	MarkMatchAsPlayed(ctx context.Context, id int64) error
 	GetCurrentWeek(ctx context.Context, seasonID int64) (int, error)
@@ -39,17 +67,114 @@ type MatchRepository interface {
 // SeasonRepository defines the interface for season-related database operations.
 type SeasonRepository interface {
 	GetCurrentSeason(ctx context.Context) (sqlc.Season, error)
-	CreateNewSeason(ctx context.Context, year int64) (sqlc.Season, error)
+	GetSeason(ctx context.Context, id int64) (sqlc.Season, error)
+	ListSeasons(ctx context.Context) ([]sqlc.Season, error)
+	CreateNewSeason(ctx context.Context, year int64, seed int64) (sqlc.Season, error)
 	SetCurrentSeason(ctx context.Context, id int64) error
 	CompleteSeason(ctx context.Context, id int64) error
-	ResetToYear(ctx context.Context, year int64) error
+	// RollbackSeason undoes the results of a season from the given week
+	// onward, moves it back to that week and marks it incomplete, dropping
+	// the playoffs its end decided. Cup ties from that week are unplayed and
+	// the rounds after them lose their teams. Other seasons are left
+	// untouched.
+	RollbackSeason(ctx context.Context, seasonID int64, week int64) error
 	InitializeGameState(ctx context.Context, seasonID int64) error
 }
 
+// DivisionRepository defines the interface for division-related database operations.
+type DivisionRepository interface {
+	CreateDivision(ctx context.Context, arg sqlc.CreateDivisionParams) (sqlc.Division, error)
+	GetDivision(ctx context.Context, id int64) (sqlc.Division, error)
+	ListDivisions(ctx context.Context, seasonID int64) ([]sqlc.Division, error)
+	UpdateDivision(ctx context.Context, arg sqlc.UpdateDivisionParams) error
+	DeleteDivision(ctx context.Context, id int64) error
+	// SetTeamDivision puts a team in a division, taking it out of any other
+	// division of the season.
+	SetTeamDivision(ctx context.Context, seasonID int64, divisionID int64, teamID int64) error
+	ListDivisionTeams(ctx context.Context, seasonID int64) ([]sqlc.DivisionTeam, error)
+	CreatePlayoff(ctx context.Context, arg sqlc.CreatePlayoffParams) error
+	ListPlayoffsBySeason(ctx context.Context, seasonID int64) ([]sqlc.ListPlayoffsBySeasonRow, error)
+}
+
+// CupRepository defines the interface for cup-related database operations.
+type CupRepository interface {
+	CreateCup(ctx context.Context, arg sqlc.CreateCupParams) (sqlc.Cup, error)
+	GetCupBySeason(ctx context.Context, seasonID int64) (sqlc.Cup, error)
+	// DeleteCup deletes a cup and its ties.
+	DeleteCup(ctx context.Context, id int64) error
+	CreateCupTie(ctx context.Context, arg sqlc.CreateCupTieParams) error
+	// ListCupTies returns the ties of a cup ordered by round and then slot.
+	ListCupTies(ctx context.Context, cupID int64) ([]sqlc.ListCupTiesRow, error)
+	DeleteCupTies(ctx context.Context, cupID int64) error
+	SetCupTieTeams(ctx context.Context, arg sqlc.SetCupTieTeamsParams) error
+	SaveCupTieResult(ctx context.Context, arg sqlc.SaveCupTieResultParams) error
+}
+
+// TournamentRepository defines the interface for tournament-related database
+// operations.
+type TournamentRepository interface {
+	CreateTournament(ctx context.Context, arg sqlc.CreateTournamentParams) (sqlc.Tournament, error)
+	GetTournamentBySeason(ctx context.Context, seasonID int64) (sqlc.Tournament, error)
+	// DeleteTournament deletes a tournament with its teams and matches.
+	DeleteTournament(ctx context.Context, id int64) error
+	CreateTournamentTeam(ctx context.Context, arg sqlc.CreateTournamentTeamParams) error
+	// ListTournamentTeams returns the teams of a tournament ordered by group
+	// and then pot.
+	ListTournamentTeams(ctx context.Context, tournamentID int64) ([]sqlc.ListTournamentTeamsRow, error)
+	DeleteTournamentTeams(ctx context.Context, tournamentID int64) error
+	CreateTournamentMatch(ctx context.Context, arg sqlc.CreateTournamentMatchParams) error
+	// ListTournamentMatches returns the matches of a tournament ordered by
+	// round, slot and leg, and then in the order they were created.
+	ListTournamentMatches(ctx context.Context, tournamentID int64) ([]sqlc.ListTournamentMatchesRow, error)
+	DeleteTournamentMatches(ctx context.Context, tournamentID int64) error
+	SetTournamentMatchTeams(ctx context.Context, arg sqlc.SetTournamentMatchTeamsParams) error
+	SaveTournamentMatchResult(ctx context.Context, arg sqlc.SaveTournamentMatchResultParams) error
+}
+
+// CompetitionRepository defines the interface for competition operations
+type CompetitionRepository interface {
+	CreateCompetition(ctx context.Context, arg sqlc.CreateCompetitionParams) (sqlc.Competition, error)
+	GetCompetition(ctx context.Context, id int64) (sqlc.Competition, error)
+	GetCompetitionBySeason(ctx context.Context, arg sqlc.GetCompetitionBySeasonParams) (sqlc.Competition, error)
+	ListCompetitions(ctx context.Context, seasonID int64) ([]sqlc.Competition, error)
+	UpdateCompetitionRules(ctx context.Context, arg sqlc.UpdateCompetitionRulesParams) error
+	SetCompetitionRankingRules(ctx context.Context, id int64, rules string) error
+	CreateCompetitionTeam(ctx context.Context, arg sqlc.CreateCompetitionTeamParams) error
+	// ListCompetitionTeams returns the teams of a competition ordered by name.
+	ListCompetitionTeams(ctx context.Context, competitionID int64) ([]sqlc.ListCompetitionTeamsRow, error)
+	DeleteCompetitionTeams(ctx context.Context, competitionID int64) error
+}
+
+// SanctionRepository defines the interface for sanction-related database
+// operations.
+type SanctionRepository interface {
+	CreateSanction(ctx context.Context, arg sqlc.CreateSanctionParams) (sqlc.Sanction, error)
+	GetSanction(ctx context.Context, id int64) (sqlc.Sanction, error)
+	// ListSanctions returns the sanctions against the teams of every
+	// competition of a season in the order they were taken.
+	ListSanctions(ctx context.Context, seasonID int64) ([]sqlc.ListSanctionsRow, error)
+	DeleteSanction(ctx context.Context, id int64) error
+}
+
+// Transactor runs a unit of work against the database.
+type Transactor interface {
+	// WithTx calls fn with a Repository bound to a single transaction. The
+	// transaction is committed if fn returns nil and rolled back otherwise.
+	// Calling WithTx on a Repository that is already bound to a transaction
+	// runs fn inside that transaction.
+	WithTx(ctx context.Context, fn func(Repository) error) error
+}
+
 // Repository combines all repository interfaces.
 type Repository interface {
 	TeamRepository
 	StandingRepository
 	MatchRepository
 	SeasonRepository
+	DivisionRepository
+	CupRepository
+	TournamentRepository
+	CompetitionRepository
+	SanctionRepository
+	Transactor
 }
@@ -0,0 +1,1516 @@
+// Package repotest holds the behaviour every repository.Repository
+// implementation must share, so each database backend runs the same tests.
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+// Open returns a fresh repository holding the seed data: the current 2025
+// season at week 1 and the four seeded teams in its single division, without
+// fixtures.
+type Open func(t *testing.T) repository.Repository
+
+// Seed adds the seed data of the migrations to an empty repository.
+func Seed(ctx context.Context, repo repository.Repository) error {
+	return repo.WithTx(ctx, func(tx repository.Repository) error {
+		season, err := tx.CreateNewSeason(ctx, 2025, rand.Int63())
+		if err != nil {
+			return err
+		}
+		if err := tx.SetCurrentSeason(ctx, season.ID); err != nil {
+			return err
+		}
+		if err := tx.InitializeGameState(ctx, season.ID); err != nil {
+			return err
+		}
+
+		// The migrations put every team of a season in its first division
+		division, err := tx.CreateDivision(ctx, sqlc.CreateDivisionParams{SeasonID: season.ID, Name: "Division 1", Tier: 1})
+		if err != nil {
+			return err
+		}
+
+		for _, team := range []struct {
+			name     string
+			strength int64
+		}{
+			{"Manchester City", 10},
+			{"Chelsea", 7},
+			{"Arsenal", 6},
+			{"Liverpool", 9},
+		} {
+			created, err := tx.CreateTeam(ctx, sqlc.CreateTeamParams{
+				Name:     team.name,
+				Strength: sql.NullInt64{Int64: team.strength, Valid: true},
+				Budget:   sql.NullInt64{Int64: team.strength * 100000000, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			if err := tx.SetTeamDivision(ctx, season.ID, division.ID, created.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RunSuite runs the shared repository tests, opening a fresh repository for
+// each of them.
+func RunSuite(t *testing.T, open Open) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, repository.Repository)
+	}{
+		{"SeedData", testSeedData},
+		{"Seasons", testSeasons},
+		{"Teams", testTeams},
+		{"TeamNamesAreUnique", testTeamNamesAreUnique},
+		{"DeleteTeam", testDeleteTeam},
+		{"TeamHistory", testTeamHistory},
+		{"Fixtures", testFixtures},
+		{"Results", testResults},
+		{"MatchesByTeam", testMatchesByTeam},
+		{"HeadToHead", testHeadToHead},
+		{"ComputeStandings", testComputeStandings},
+		{"CachedStandings", testCachedStandings},
+		{"Weeks", testWeeks},
+		{"Divisions", testDivisions},
+		{"Cup", testCup},
+		{"Tournament", testTournament},
+		{"Competitions", testCompetitions},
+		{"Sanctions", testSanctions},
+		{"RollbackSeason", testRollbackSeason},
+		{"WithTx", testWithTx},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, open(t))
+		})
+	}
+}
+
+func testSeedData(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	season := currentSeason(t, repo)
+	if season.Year != 2025 || !season.IsCurrent.Bool || season.IsComplete.Bool {
+		t.Errorf("seeded season = %+v, want the current, incomplete 2025 season", season)
+	}
+
+	week, err := repo.GetCurrentWeek(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetCurrentWeek: %v", err)
+	}
+	if week != 1 {
+		t.Errorf("current week = %d, want 1", week)
+	}
+
+	var names []string
+	for _, team := range listTeams(t, repo) {
+		names = append(names, team.Name)
+	}
+	if want := []string{"Arsenal", "Chelsea", "Liverpool", "Manchester City"}; !slices.Equal(names, want) {
+		t.Errorf("team names = %v, want %v", names, want)
+	}
+}
+
+func testSeasons(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	first := currentSeason(t, repo)
+
+	next, err := repo.CreateNewSeason(ctx, 2026, 42)
+	if err != nil {
+		t.Fatalf("CreateNewSeason: %v", err)
+	}
+	if next.Year != 2026 || next.Seed != 42 || next.IsCurrent.Bool || next.IsComplete.Bool {
+		t.Errorf("new season = %+v, want a non-current, incomplete 2026 season with seed 42", next)
+	}
+
+	if err := repo.SetCurrentSeason(ctx, next.ID); err != nil {
+		t.Fatalf("SetCurrentSeason: %v", err)
+	}
+	if current := currentSeason(t, repo); current.ID != next.ID {
+		t.Errorf("current season = %d, want %d", current.ID, next.ID)
+	}
+	previous, err := repo.GetSeason(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetSeason: %v", err)
+	}
+	if previous.IsCurrent.Bool {
+		t.Error("previous season is still current")
+	}
+
+	if err := repo.CompleteSeason(ctx, next.ID); err != nil {
+		t.Fatalf("CompleteSeason: %v", err)
+	}
+	got, err := repo.GetSeason(ctx, next.ID)
+	if err != nil {
+		t.Fatalf("GetSeason: %v", err)
+	}
+	if !got.IsComplete.Bool {
+		t.Errorf("season = %+v, want complete", got)
+	}
+
+	seasons, err := repo.ListSeasons(ctx)
+	if err != nil {
+		t.Fatalf("ListSeasons: %v", err)
+	}
+	if len(seasons) != 2 || seasons[0].Year != 2025 || seasons[1].Year != 2026 {
+		t.Errorf("ListSeasons = %+v, want 2025 then 2026", seasons)
+	}
+
+	if _, err := repo.GetSeason(ctx, next.ID+100); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetSeason(missing) error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testTeams(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	team, err := repo.CreateTeam(ctx, sqlc.CreateTeamParams{
+		Name:     "Brighton",
+		Strength: sql.NullInt64{Int64: 5, Valid: true},
+		Budget:   sql.NullInt64{Int64: 500, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+	if team.Name != "Brighton" || team.Strength.Int64 != 5 || team.Budget.Int64 != 500 {
+		t.Errorf("created team = %+v", team)
+	}
+
+	byName, err := repo.GetTeamByName(ctx, "Brighton")
+	if err != nil {
+		t.Fatalf("GetTeamByName: %v", err)
+	}
+	if byName != team {
+		t.Errorf("GetTeamByName = %+v, want %+v", byName, team)
+	}
+
+	err = repo.UpdateTeam(ctx, sqlc.UpdateTeamParams{
+		ID:       team.ID,
+		Name:     "Brighton & Hove",
+		Strength: sql.NullInt64{Int64: 6, Valid: true},
+		Budget:   sql.NullInt64{Int64: 600, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("UpdateTeam: %v", err)
+	}
+	err = repo.UpdateTeamStrength(ctx, sqlc.UpdateTeamStrengthParams{
+		ID:       team.ID,
+		Strength: sql.NullInt64{Int64: 7, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("UpdateTeamStrength: %v", err)
+	}
+
+	got, err := repo.GetTeam(ctx, team.ID)
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+	if got.Name != "Brighton & Hove" || got.Strength.Int64 != 7 || got.Budget.Int64 != 600 {
+		t.Errorf("updated team = %+v", got)
+	}
+
+	if _, err := repo.GetTeam(ctx, team.ID+100); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetTeam(missing) error = %v, want sql.ErrNoRows", err)
+	}
+	if len(listTeams(t, repo)) != 5 {
+		t.Errorf("ListTeams returned %d teams, want 5", len(listTeams(t, repo)))
+	}
+}
+
+func testTeamNamesAreUnique(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	if _, err := repo.CreateTeam(ctx, sqlc.CreateTeamParams{Name: "Chelsea"}); err == nil {
+		t.Error("creating a second Chelsea succeeded, want a unique constraint error")
+	}
+
+	arsenal, err := repo.GetTeamByName(ctx, "Arsenal")
+	if err != nil {
+		t.Fatalf("GetTeamByName: %v", err)
+	}
+	err = repo.UpdateTeam(ctx, sqlc.UpdateTeamParams{ID: arsenal.ID, Name: "Chelsea"})
+	if err == nil {
+		t.Error("renaming Arsenal to Chelsea succeeded, want a unique constraint error")
+	}
+}
+
+func testDeleteTeam(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+
+	// A team with a standing but no matches can be deleted
+	err := repo.CreateStanding(ctx, sqlc.CreateStandingParams{TeamID: teams[0].ID, SeasonID: season.ID})
+	if err != nil {
+		t.Fatalf("CreateStanding: %v", err)
+	}
+	if err := repo.DeleteTeam(ctx, teams[0].ID); err != nil {
+		t.Fatalf("DeleteTeam: %v", err)
+	}
+	if _, err := repo.GetTeam(ctx, teams[0].ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetTeam(deleted) error = %v, want sql.ErrNoRows", err)
+	}
+
+	// A team with fixtures keeps its history
+	createFixture(t, repo, season.ID, 1, teams[1].ID, teams[2].ID)
+	count, err := repo.CountTeamMatches(ctx, teams[1].ID)
+	if err != nil {
+		t.Fatalf("CountTeamMatches: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("CountTeamMatches = %d, want 1", count)
+	}
+	if err := repo.DeleteTeam(ctx, teams[1].ID); !errors.Is(err, repository.ErrTeamHasMatches) {
+		t.Errorf("DeleteTeam(with matches) error = %v, want ErrTeamHasMatches", err)
+	}
+}
+
+func testTeamHistory(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	first := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+
+	next, err := repo.CreateNewSeason(ctx, 2026, 42)
+	if err != nil {
+		t.Fatalf("CreateNewSeason: %v", err)
+	}
+
+	// Inserted out of order to check the listing is sorted by team, then year
+	entries := []sqlc.CreateTeamHistoryParams{
+		{TeamID: teams[1].ID, SeasonID: first.ID, LastSeasonStanding: 1, PrizeMoney: 40, StrengthBefore: 4, StrengthAfter: 5, BudgetBefore: 100, BudgetAfter: 140},
+		{TeamID: teams[0].ID, SeasonID: next.ID, LastSeasonStanding: 3, PrizeMoney: 20, StrengthBefore: 3, StrengthAfter: 3, BudgetBefore: 110, BudgetAfter: 130},
+		{TeamID: teams[0].ID, SeasonID: first.ID, LastSeasonStanding: 4, PrizeMoney: 10, StrengthBefore: 4, StrengthAfter: 3, BudgetBefore: 100, BudgetAfter: 110},
+	}
+	for _, entry := range entries {
+		if err := repo.CreateTeamHistory(ctx, entry); err != nil {
+			t.Fatalf("CreateTeamHistory: %v", err)
+		}
+	}
+	if err := repo.CreateTeamHistory(ctx, entries[0]); err == nil {
+		t.Error("recording a team twice for a season succeeded, want a unique constraint error")
+	}
+
+	history, err := repo.ListTeamHistory(ctx)
+	if err != nil {
+		t.Fatalf("ListTeamHistory: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("ListTeamHistory returned %d entries, want 3", len(history))
+	}
+	want := []struct {
+		teamID int64
+		year   int64
+	}{{teams[0].ID, 2025}, {teams[0].ID, 2026}, {teams[1].ID, 2025}}
+	if teams[1].ID < teams[0].ID {
+		want = []struct {
+			teamID int64
+			year   int64
+		}{{teams[1].ID, 2025}, {teams[0].ID, 2025}, {teams[0].ID, 2026}}
+	}
+	for i, w := range want {
+		if history[i].TeamID != w.teamID || history[i].SeasonYear != w.year {
+			t.Errorf("history[%d] = team %d in %d, want team %d in %d",
+				i, history[i].TeamID, history[i].SeasonYear, w.teamID, w.year)
+		}
+	}
+	for _, entry := range history {
+		if entry.TeamID == teams[1].ID && (entry.PrizeMoney != 40 || entry.StrengthAfter != 5 || entry.BudgetAfter != 140) {
+			t.Errorf("history of %s = %+v, want the recorded values", teams[1].Name, entry)
+		}
+	}
+}
+
+func testFixtures(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+
+	weeks, err := repo.GetSeasonWeeks(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetSeasonWeeks: %v", err)
+	}
+	if weeks != 0 {
+		t.Errorf("GetSeasonWeeks without fixtures = %d, want 0", weeks)
+	}
+
+	createFixture(t, repo, season.ID, 1, teams[0].ID, teams[1].ID)
+	createFixture(t, repo, season.ID, 1, teams[2].ID, teams[3].ID)
+	createFixture(t, repo, season.ID, 2, teams[1].ID, teams[0].ID)
+
+	week1, err := repo.GetMatchesByWeek(ctx, 1, season.ID)
+	if err != nil {
+		t.Fatalf("GetMatchesByWeek: %v", err)
+	}
+	if len(week1) != 2 {
+		t.Fatalf("week 1 has %d matches, want 2", len(week1))
+	}
+	for _, m := range week1 {
+		if m.HomeID == teams[0].ID && (m.HomeTeamName != teams[0].Name || m.GuestTeamName != teams[1].Name) {
+			t.Errorf("match %d is %s vs %s, want %s vs %s", m.ID, m.HomeTeamName, m.GuestTeamName, teams[0].Name, teams[1].Name)
+		}
+	}
+
+	weeks, err = repo.GetSeasonWeeks(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetSeasonWeeks: %v", err)
+	}
+	if weeks != 2 {
+		t.Errorf("GetSeasonWeeks = %d, want 2", weeks)
+	}
+
+	unplayed, err := repo.GetUnplayedMatchesBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetUnplayedMatchesBySeason: %v", err)
+	}
+	if len(unplayed) != 3 || unplayed[0].Week != 1 || unplayed[2].Week != 2 {
+		t.Errorf("GetUnplayedMatchesBySeason = %+v, want 3 matches ordered by week", unplayed)
+	}
+
+	if err := repo.DeleteUnplayedMatchesBySeason(ctx, season.ID); err != nil {
+		t.Fatalf("DeleteUnplayedMatchesBySeason: %v", err)
+	}
+	all, err := repo.GetMatchesBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetMatchesBySeason: %v", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("GetMatchesBySeason after deleting unplayed = %d matches, want 0", len(all))
+	}
+}
+
+func testResults(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+
+	createFixture(t, repo, season.ID, 1, teams[0].ID, teams[1].ID)
+	createFixture(t, repo, season.ID, 1, teams[2].ID, teams[3].ID)
+	week1, err := repo.GetMatchesByWeek(ctx, 1, season.ID)
+	if err != nil {
+		t.Fatalf("GetMatchesByWeek: %v", err)
+	}
+	played := week1[0]
+
+	saveResult(t, repo, played, 1, 1)
+	// Saving again replaces the result
+	saveResult(t, repo, played, 3, 1)
+	if err := repo.MarkMatchAsPlayed(ctx, played.ID); err != nil {
+		t.Fatalf("MarkMatchAsPlayed: %v", err)
+	}
+
+	result, err := repo.GetMatchResult(ctx, played.ID)
+	if err != nil {
+		t.Fatalf("GetMatchResult: %v", err)
+	}
+	if result.HomeScore != 3 || result.GuestScore != 1 || result.WinnerID.Int64 != played.HomeID {
+		t.Errorf("GetMatchResult = %+v, want a 3-1 home win", result)
+	}
+	if result.HomeTeamName != played.HomeTeamName || result.GuestTeamName != played.GuestTeamName {
+		t.Errorf("GetMatchResult teams = %s vs %s, want %s vs %s", result.HomeTeamName, result.GuestTeamName, played.HomeTeamName, played.GuestTeamName)
+	}
+
+	allPlayed, err := repo.GetAllMatchesPlayedForWeek(ctx, 1, season.ID)
+	if err != nil {
+		t.Fatalf("GetAllMatchesPlayedForWeek: %v", err)
+	}
+	if allPlayed {
+		t.Error("GetAllMatchesPlayedForWeek = true with an unplayed match")
+	}
+	unplayed, err := repo.GetUnplayedMatchesByWeek(ctx, 1, season.ID)
+	if err != nil {
+		t.Fatalf("GetUnplayedMatchesByWeek: %v", err)
+	}
+	if len(unplayed) != 1 || unplayed[0].ID != week1[1].ID {
+		t.Errorf("GetUnplayedMatchesByWeek = %+v, want only match %d", unplayed, week1[1].ID)
+	}
+
+	results, err := repo.GetResultsBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetResultsBySeason: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != played.ID || results[0].HomeScore != 3 {
+		t.Errorf("GetResultsBySeason = %+v, want only the 3-1", results)
+	}
+
+	matches, err := repo.GetMatchesBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetMatchesBySeason: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("GetMatchesBySeason returned %d matches, want 2", len(matches))
+	}
+	for _, m := range matches {
+		hasScore := m.HomeScore.Valid && m.GuestScore.Valid
+		if hasScore != (m.ID == played.ID) {
+			t.Errorf("match %d has score %v, want a score only for the played match", m.ID, hasScore)
+		}
+	}
+
+	if _, err := repo.GetMatchResult(ctx, week1[1].ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetMatchResult(unplayed) error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testMatchesByTeam(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+
+	createFixture(t, repo, season.ID, 2, teams[1].ID, teams[0].ID)
+	createFixture(t, repo, season.ID, 1, teams[0].ID, teams[2].ID)
+	createFixture(t, repo, season.ID, 1, teams[1].ID, teams[3].ID)
+	week1, err := repo.GetMatchesByWeek(ctx, 1, season.ID)
+	if err != nil {
+		t.Fatalf("GetMatchesByWeek: %v", err)
+	}
+	saveResult(t, repo, week1[0], 2, 0)
+	if err := repo.MarkMatchAsPlayed(ctx, week1[0].ID); err != nil {
+		t.Fatalf("MarkMatchAsPlayed: %v", err)
+	}
+
+	matches, err := repo.GetMatchesByTeam(ctx, teams[0].ID, season.ID)
+	if err != nil {
+		t.Fatalf("GetMatchesByTeam: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("GetMatchesByTeam returned %d matches, want 2", len(matches))
+	}
+	played, upcoming := matches[0], matches[1]
+	if played.Week != 1 || played.GuestTeamName != teams[2].Name || played.HomeScore.Int64 != 2 || !played.GuestScore.Valid {
+		t.Errorf("first match = %+v, want the 2-0 against %s in week 1", played, teams[2].Name)
+	}
+	if upcoming.Week != 2 || upcoming.HomeID != teams[1].ID || upcoming.HomeScore.Valid {
+		t.Errorf("second match = %+v, want the unplayed match at %s in week 2", upcoming, teams[1].Name)
+	}
+}
+
+func testHeadToHead(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	first := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+	a, b := teams[0].ID, teams[1].ID
+
+	second, err := repo.CreateNewSeason(ctx, 2026, 42)
+	if err != nil {
+		t.Fatalf("CreateNewSeason: %v", err)
+	}
+
+	// play plays the only match of a week, whoever is at home
+	play := func(seasonID, week, homeID, guestID, homeScore, guestScore int64) {
+		t.Helper()
+		createFixture(t, repo, seasonID, week, homeID, guestID)
+		matches, err := repo.GetMatchesByWeek(ctx, week, seasonID)
+		if err != nil || len(matches) != 1 {
+			t.Fatalf("GetMatchesByWeek = %+v, %v", matches, err)
+		}
+		saveResult(t, repo, matches[0], homeScore, guestScore)
+		if err := repo.MarkMatchAsPlayed(ctx, matches[0].ID); err != nil {
+			t.Fatalf("MarkMatchAsPlayed: %v", err)
+		}
+	}
+	play(first.ID, 1, a, b, 3, 0)
+	play(first.ID, 2, b, a, 1, 1)
+	play(second.ID, 1, b, a, 2, 1)
+	// Neither another pairing nor an unplayed meeting counts
+	play(second.ID, 2, a, teams[2].ID, 5, 0)
+	createFixture(t, repo, second.ID, 3, a, b)
+
+	seasons, err := repo.ListHeadToHeadSeasons(ctx, a, b)
+	if err != nil {
+		t.Fatalf("ListHeadToHeadSeasons: %v", err)
+	}
+	want := []sqlc.ListHeadToHeadSeasonsRow{
+		{SeasonID: first.ID, SeasonYear: 2025, Played: 2, Wins: 1, Draws: 1, GoalsFor: 4, GoalsAgainst: 1},
+		{SeasonID: second.ID, SeasonYear: 2026, Played: 1, Losses: 1, GoalsFor: 1, GoalsAgainst: 2},
+	}
+	if !slices.Equal(seasons, want) {
+		t.Errorf("ListHeadToHeadSeasons = %+v, want %+v", seasons, want)
+	}
+
+	meetings, err := repo.ListHeadToHeadMatches(ctx, b, a)
+	if err != nil {
+		t.Fatalf("ListHeadToHeadMatches: %v", err)
+	}
+	if len(meetings) != 3 {
+		t.Fatalf("ListHeadToHeadMatches returned %d meetings, want 3", len(meetings))
+	}
+	latest := meetings[0]
+	if latest.SeasonYear != 2026 || latest.HomeID != b || latest.HomeScore != 2 || latest.GuestScore != 1 {
+		t.Errorf("latest meeting = %+v, want the 2-1 of 2026", latest)
+	}
+	if meetings[1].Week != 2 || meetings[2].Week != 1 || meetings[2].SeasonYear != 2025 {
+		t.Errorf("meetings = %+v, want latest first", meetings)
+	}
+}
+
+func testComputeStandings(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+
+	// Arsenal 2-0 Chelsea and Liverpool 1-1 Manchester City in week 1,
+	// Chelsea 1-3 Arsenal in week 2, which is left unplayed
+	createFixture(t, repo, season.ID, 1, teams[0].ID, teams[1].ID)
+	createFixture(t, repo, season.ID, 1, teams[2].ID, teams[3].ID)
+	createFixture(t, repo, season.ID, 2, teams[1].ID, teams[0].ID)
+	week1, err := repo.GetMatchesByWeek(ctx, 1, season.ID)
+	if err != nil {
+		t.Fatalf("GetMatchesByWeek: %v", err)
+	}
+	for _, m := range week1 {
+		if m.HomeID == teams[0].ID {
+			saveResult(t, repo, m, 2, 0)
+		} else {
+			saveResult(t, repo, m, 1, 1)
+		}
+		if err := repo.MarkMatchAsPlayed(ctx, m.ID); err != nil {
+			t.Fatalf("MarkMatchAsPlayed: %v", err)
+		}
+	}
+
+	rows, err := repo.ComputeStandings(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ComputeStandings: %v", err)
+	}
+
+	want := map[string]sqlc.ComputeStandingsRow{
+		"Arsenal":         {Played: 1, Wins: 1, GoalsFor: 2, GoalDiff: 2},
+		"Chelsea":         {Played: 1, Losses: 1, GoalsAgainst: 2, GoalDiff: -2},
+		"Liverpool":       {Played: 1, Draws: 1, GoalsFor: 1, GoalsAgainst: 1},
+		"Manchester City": {Played: 1, Draws: 1, GoalsFor: 1, GoalsAgainst: 1, AwayGoalsFor: 1},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("ComputeStandings returned %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if row.Team.Name != teams[i].Name {
+			t.Errorf("row %d is %s, want %s (ordered by name)", i, row.Team.Name, teams[i].Name)
+		}
+		expected := want[row.Team.Name]
+		expected.Team = row.Team
+		if row != expected {
+			t.Errorf("%s standing = %+v, want %+v", row.Team.Name, row, expected)
+		}
+	}
+}
+
+func testCachedStandings(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+	team := listTeams(t, repo)[0]
+
+	err := repo.CreateStanding(ctx, sqlc.CreateStandingParams{
+		TeamID:   team.ID,
+		SeasonID: season.ID,
+		Points:   sql.NullInt64{Int64: 0, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateStanding: %v", err)
+	}
+
+	err = repo.UpdateStanding(ctx, sqlc.UpdateStandingParams{
+		TeamID:       team.ID,
+		SeasonID:     season.ID,
+		Points:       sql.NullInt64{Int64: 4, Valid: true},
+		Wins:         sql.NullInt64{Int64: 1, Valid: true},
+		Draws:        sql.NullInt64{Int64: 1, Valid: true},
+		Losses:       sql.NullInt64{Int64: 0, Valid: true},
+		GoalDiff:     sql.NullInt64{Int64: 2, Valid: true},
+		GoalsFor:     sql.NullInt64{Int64: 3, Valid: true},
+		GoalsAgainst: sql.NullInt64{Int64: 1, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("UpdateStanding: %v", err)
+	}
+
+	standing, err := repo.GetStanding(ctx, team.ID, season.ID)
+	if err != nil {
+		t.Fatalf("GetStanding: %v", err)
+	}
+	if standing.Points.Int64 != 4 || standing.Wins.Int64 != 1 || standing.Draws.Int64 != 1 ||
+		standing.GoalDiff.Int64 != 2 || standing.GoalsFor.Int64 != 3 || standing.GoalsAgainst.Int64 != 1 {
+		t.Errorf("GetStanding = %+v", standing)
+	}
+
+	if _, err := repo.GetStanding(ctx, team.ID, season.ID+100); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetStanding(missing) error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testWeeks(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+
+	if err := repo.IncrementWeek(ctx, season.ID); err != nil {
+		t.Fatalf("IncrementWeek: %v", err)
+	}
+	week, err := repo.GetCurrentWeek(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetCurrentWeek: %v", err)
+	}
+	if week != 2 {
+		t.Errorf("current week = %d, want 2", week)
+	}
+
+	next, err := repo.CreateNewSeason(ctx, 2026, 1)
+	if err != nil {
+		t.Fatalf("CreateNewSeason: %v", err)
+	}
+	if _, err := repo.GetCurrentWeek(ctx, next.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetCurrentWeek without game state error = %v, want sql.ErrNoRows", err)
+	}
+	if err := repo.InitializeGameState(ctx, next.ID); err != nil {
+		t.Fatalf("InitializeGameState: %v", err)
+	}
+	week, err = repo.GetCurrentWeek(ctx, next.ID)
+	if err != nil {
+		t.Fatalf("GetCurrentWeek: %v", err)
+	}
+	if week != 1 {
+		t.Errorf("current week of a new season = %d, want 1", week)
+	}
+}
+
+func testDivisions(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+
+	divisions, err := repo.ListDivisions(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ListDivisions: %v", err)
+	}
+	if len(divisions) != 1 || divisions[0].Name != "Division 1" || divisions[0].Tier != 1 {
+		t.Fatalf("seeded divisions = %+v, want only Division 1", divisions)
+	}
+	top := divisions[0]
+
+	// Divisions are listed by tier
+	second, err := repo.CreateDivision(ctx, sqlc.CreateDivisionParams{
+		SeasonID:         season.ID,
+		Name:             "Division 2",
+		Tier:             2,
+		RelegationPlaces: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateDivision: %v", err)
+	}
+	err = repo.UpdateDivision(ctx, sqlc.UpdateDivisionParams{
+		ID:               top.ID,
+		Name:             "Premier",
+		Tier:             1,
+		RelegationPlaces: 2,
+		Playoff:          true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateDivision: %v", err)
+	}
+	top, err = repo.GetDivision(ctx, top.ID)
+	if err != nil {
+		t.Fatalf("GetDivision: %v", err)
+	}
+	if top.Name != "Premier" || top.RelegationPlaces != 2 || !top.Playoff {
+		t.Errorf("updated division = %+v", top)
+	}
+	divisions, err = repo.ListDivisions(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ListDivisions: %v", err)
+	}
+	if len(divisions) != 2 || divisions[0].ID != top.ID || divisions[1].ID != second.ID {
+		t.Errorf("divisions = %+v, want Premier then Division 2", divisions)
+	}
+
+	// A team is in a single division of a season
+	if err := repo.SetTeamDivision(ctx, season.ID, second.ID, teams[0].ID); err != nil {
+		t.Fatalf("SetTeamDivision: %v", err)
+	}
+	members, err := repo.ListDivisionTeams(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ListDivisionTeams: %v", err)
+	}
+	if len(members) != len(teams) {
+		t.Fatalf("%d division teams, want %d", len(members), len(teams))
+	}
+	if last := members[len(members)-1]; last.DivisionID != second.ID || last.TeamID != teams[0].ID {
+		t.Errorf("last division team = %+v, want %s in Division 2", last, teams[0].Name)
+	}
+
+	// Playoffs belong to the season they end
+	err = repo.CreatePlayoff(ctx, sqlc.CreatePlayoffParams{
+		SeasonID:    season.ID,
+		DivisionID:  top.ID,
+		UpperTeamID: teams[1].ID,
+		LowerTeamID: teams[0].ID,
+		UpperScore:  0,
+		LowerScore:  2,
+		WinnerID:    teams[0].ID,
+	})
+	if err != nil {
+		t.Fatalf("CreatePlayoff: %v", err)
+	}
+	playoffs, err := repo.ListPlayoffsBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ListPlayoffsBySeason: %v", err)
+	}
+	if len(playoffs) != 1 || playoffs[0].UpperTeamName != teams[1].Name || playoffs[0].LowerTeamName != teams[0].Name {
+		t.Errorf("playoffs = %+v", playoffs)
+	}
+
+	// Fixtures keep their division
+	err = repo.CreateFixture(ctx, sqlc.CreateFixtureParams{
+		HomeID:     teams[1].ID,
+		GuestID:    teams[2].ID,
+		Played:     sql.NullBool{Bool: false, Valid: true},
+		Week:       1,
+		SeasonID:   season.ID,
+		DivisionID: sql.NullInt64{Int64: top.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("CreateFixture: %v", err)
+	}
+	matches, err := repo.GetMatchesBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetMatchesBySeason: %v", err)
+	}
+	if len(matches) != 1 || matches[0].DivisionID.Int64 != top.ID {
+		t.Errorf("matches = %+v, want one in Premier", matches)
+	}
+
+	// Deleting a team takes it out of its division, an empty division can go
+	if err := repo.DeleteTeam(ctx, teams[3].ID); err != nil {
+		t.Fatalf("DeleteTeam: %v", err)
+	}
+	if err := repo.SetTeamDivision(ctx, season.ID, top.ID, teams[0].ID); err != nil {
+		t.Fatalf("SetTeamDivision: %v", err)
+	}
+	if err := repo.DeleteDivision(ctx, second.ID); err != nil {
+		t.Fatalf("DeleteDivision: %v", err)
+	}
+	members, err = repo.ListDivisionTeams(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ListDivisionTeams: %v", err)
+	}
+	if len(members) != len(teams)-1 {
+		t.Errorf("%d division teams after deleting a team, want %d", len(members), len(teams)-1)
+	}
+	if _, err := repo.GetDivision(ctx, second.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetDivision(deleted) error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func testCup(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+	team := func(id int64) sql.NullInt64 { return sql.NullInt64{Int64: id, Valid: true} }
+
+	if _, err := repo.GetCupBySeason(ctx, season.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("GetCupBySeason before a draw error = %v, want sql.ErrNoRows", err)
+	}
+	cup, err := repo.CreateCup(ctx, sqlc.CreateCupParams{SeasonID: season.ID, Seeded: true})
+	if err != nil {
+		t.Fatalf("CreateCup: %v", err)
+	}
+	if _, err := repo.CreateCup(ctx, sqlc.CreateCupParams{SeasonID: season.ID}); err == nil {
+		t.Error("CreateCup allowed a second cup in a season")
+	}
+	got, err := repo.GetCupBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetCupBySeason: %v", err)
+	}
+	if got != cup || !got.Seeded {
+		t.Errorf("cup = %+v, want %+v", got, cup)
+	}
+
+	// A semi-final, a bye and a final waiting for its teams
+	for _, tie := range []sqlc.CreateCupTieParams{
+		{CupID: cup.ID, Round: 2, Slot: 0, Week: 2},
+		{CupID: cup.ID, Round: 1, Slot: 1, Week: 1, HomeID: team(teams[2].ID)},
+		{CupID: cup.ID, Round: 1, Slot: 0, Week: 1, HomeID: team(teams[0].ID), GuestID: team(teams[1].ID)},
+	} {
+		if err := repo.CreateCupTie(ctx, tie); err != nil {
+			t.Fatalf("CreateCupTie: %v", err)
+		}
+	}
+	ties, err := repo.ListCupTies(ctx, cup.ID)
+	if err != nil {
+		t.Fatalf("ListCupTies: %v", err)
+	}
+	if len(ties) != 3 || ties[0].Slot != 0 || ties[1].Slot != 1 || ties[2].Round != 2 {
+		t.Fatalf("ties = %+v, want them by round and slot", ties)
+	}
+	if ties[0].HomeTeamName.String != teams[0].Name || ties[0].GuestTeamName.String != teams[1].Name {
+		t.Errorf("first tie = %+v, want %s against %s", ties[0], teams[0].Name, teams[1].Name)
+	}
+	if ties[1].GuestTeamName.Valid || ties[2].HomeTeamName.Valid {
+		t.Errorf("missing teams have names: %+v", ties)
+	}
+
+	// Play both rounds, the final on penalties
+	semi, bye, final := ties[0], ties[1], ties[2]
+	err = repo.SaveCupTieResult(ctx, sqlc.SaveCupTieResultParams{
+		HomeScore:  team(2),
+		GuestScore: team(1),
+		WinnerID:   semi.HomeID,
+		ID:         semi.ID,
+	})
+	if err != nil {
+		t.Fatalf("SaveCupTieResult: %v", err)
+	}
+	if err := repo.SaveCupTieResult(ctx, sqlc.SaveCupTieResultParams{WinnerID: bye.HomeID, ID: bye.ID}); err != nil {
+		t.Fatalf("SaveCupTieResult: %v", err)
+	}
+	err = repo.SetCupTieTeams(ctx, sqlc.SetCupTieTeamsParams{HomeID: semi.HomeID, GuestID: bye.HomeID, ID: final.ID})
+	if err != nil {
+		t.Fatalf("SetCupTieTeams: %v", err)
+	}
+	err = repo.SaveCupTieResult(ctx, sqlc.SaveCupTieResultParams{
+		HomeScore:      team(1),
+		GuestScore:     team(1),
+		ExtraTime:      true,
+		HomePenalties:  team(3),
+		GuestPenalties: team(4),
+		WinnerID:       bye.HomeID,
+		ID:             final.ID,
+	})
+	if err != nil {
+		t.Fatalf("SaveCupTieResult: %v", err)
+	}
+	ties, err = repo.ListCupTies(ctx, cup.ID)
+	if err != nil {
+		t.Fatalf("ListCupTies: %v", err)
+	}
+	final = ties[2]
+	if !final.Played || !final.ExtraTime || final.GuestPenalties.Int64 != 4 || final.WinnerID.Int64 != teams[2].ID {
+		t.Errorf("final = %+v, want %s winning on penalties", final, teams[2].Name)
+	}
+	if final.HomeTeamName.String != teams[0].Name || final.GuestTeamName.String != teams[2].Name {
+		t.Errorf("final teams = %v and %v", final.HomeTeamName, final.GuestTeamName)
+	}
+
+	// Rolling back the final replays it with the same teams
+	if err := repo.RollbackSeason(ctx, season.ID, 2); err != nil {
+		t.Fatalf("RollbackSeason: %v", err)
+	}
+	ties, err = repo.ListCupTies(ctx, cup.ID)
+	if err != nil {
+		t.Fatalf("ListCupTies: %v", err)
+	}
+	if !ties[0].Played || !ties[1].Played {
+		t.Error("rolling back to week 2 undid the first round")
+	}
+	final = ties[2]
+	if final.Played || final.ExtraTime || final.HomeScore.Valid || final.HomePenalties.Valid || final.WinnerID.Valid {
+		t.Errorf("final after rollback = %+v, want it unplayed", final)
+	}
+	if final.HomeID != semi.HomeID || final.GuestID != bye.HomeID {
+		t.Errorf("final after rollback = %+v, want it to keep its teams", final)
+	}
+
+	// Rolling back the whole cup leaves the final without teams
+	if err := repo.RollbackSeason(ctx, season.ID, 1); err != nil {
+		t.Fatalf("RollbackSeason: %v", err)
+	}
+	ties, err = repo.ListCupTies(ctx, cup.ID)
+	if err != nil {
+		t.Fatalf("ListCupTies: %v", err)
+	}
+	if ties[0].Played || ties[1].Played || ties[0].HomeID != semi.HomeID || ties[1].HomeID != bye.HomeID {
+		t.Errorf("first round after rollback = %+v, want it unplayed with its teams", ties[:2])
+	}
+	if ties[2].HomeID.Valid || ties[2].GuestID.Valid {
+		t.Errorf("final after rollback = %+v, want it without teams", ties[2])
+	}
+
+	if err := repo.DeleteCupTies(ctx, cup.ID); err != nil {
+		t.Fatalf("DeleteCupTies: %v", err)
+	}
+	if ties, _ := repo.ListCupTies(ctx, cup.ID); len(ties) != 0 {
+		t.Errorf("%d ties after DeleteCupTies, want 0", len(ties))
+	}
+	if err := repo.CreateCupTie(ctx, sqlc.CreateCupTieParams{CupID: cup.ID, Round: 1, Week: 1}); err != nil {
+		t.Fatalf("CreateCupTie: %v", err)
+	}
+	if err := repo.DeleteCup(ctx, cup.ID); err != nil {
+		t.Fatalf("DeleteCup: %v", err)
+	}
+	if _, err := repo.GetCupBySeason(ctx, season.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetCupBySeason after DeleteCup error = %v, want sql.ErrNoRows", err)
+	}
+	if ties, _ := repo.ListCupTies(ctx, cup.ID); len(ties) != 0 {
+		t.Errorf("%d ties after DeleteCup, want 0", len(ties))
+	}
+}
+
+func testRollbackSeason(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	first := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+
+	// Play two weeks of the first season and a week of the next
+	playWeek := func(seasonID, week int64) {
+		createFixture(t, repo, seasonID, week, teams[0].ID, teams[1].ID)
+		matches, err := repo.GetMatchesByWeek(ctx, week, seasonID)
+		if err != nil {
+			t.Fatalf("GetMatchesByWeek: %v", err)
+		}
+		saveResult(t, repo, matches[0], 1, 0)
+		if err := repo.MarkMatchAsPlayed(ctx, matches[0].ID); err != nil {
+			t.Fatalf("MarkMatchAsPlayed: %v", err)
+		}
+	}
+	playWeek(first.ID, 1)
+	playWeek(first.ID, 2)
+	if err := repo.IncrementWeek(ctx, first.ID); err != nil {
+		t.Fatalf("IncrementWeek: %v", err)
+	}
+	if err := repo.CompleteSeason(ctx, first.ID); err != nil {
+		t.Fatalf("CompleteSeason: %v", err)
+	}
+
+	next, err := repo.CreateNewSeason(ctx, 2026, 1)
+	if err != nil {
+		t.Fatalf("CreateNewSeason: %v", err)
+	}
+	if err := repo.InitializeGameState(ctx, next.ID); err != nil {
+		t.Fatalf("InitializeGameState: %v", err)
+	}
+	playWeek(next.ID, 1)
+
+	// The playoffs that ended the first season are played again too
+	divisions, err := repo.ListDivisions(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("ListDivisions: %v", err)
+	}
+	err = repo.CreatePlayoff(ctx, sqlc.CreatePlayoffParams{
+		SeasonID:    first.ID,
+		DivisionID:  divisions[0].ID,
+		UpperTeamID: teams[0].ID,
+		LowerTeamID: teams[1].ID,
+		UpperScore:  1,
+		WinnerID:    teams[0].ID,
+	})
+	if err != nil {
+		t.Fatalf("CreatePlayoff: %v", err)
+	}
+
+	if err := repo.RollbackSeason(ctx, first.ID, 2); err != nil {
+		t.Fatalf("RollbackSeason: %v", err)
+	}
+
+	results, err := repo.GetResultsBySeason(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetResultsBySeason: %v", err)
+	}
+	if len(results) != 1 || results[0].Week != 1 {
+		t.Errorf("results after rolling back to week 2 = %+v, want only week 1", results)
+	}
+	unplayed, err := repo.GetUnplayedMatchesBySeason(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetUnplayedMatchesBySeason: %v", err)
+	}
+	if len(unplayed) != 1 || unplayed[0].Week != 2 {
+		t.Errorf("unplayed matches after rollback = %+v, want the week 2 match", unplayed)
+	}
+	week, err := repo.GetCurrentWeek(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetCurrentWeek: %v", err)
+	}
+	if week != 2 {
+		t.Errorf("current week after rollback = %d, want 2", week)
+	}
+	season, err := repo.GetSeason(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetSeason: %v", err)
+	}
+	if season.IsComplete.Bool {
+		t.Error("rolled back season is still complete")
+	}
+	if playoffs, _ := repo.ListPlayoffsBySeason(ctx, first.ID); len(playoffs) != 0 {
+		t.Errorf("%d playoffs after rollback, want 0", len(playoffs))
+	}
+
+	// Other seasons keep their history
+	seasons, err := repo.ListSeasons(ctx)
+	if err != nil {
+		t.Fatalf("ListSeasons: %v", err)
+	}
+	if len(seasons) != 2 {
+		t.Errorf("%d seasons after rollback, want 2", len(seasons))
+	}
+	results, err = repo.GetResultsBySeason(ctx, next.ID)
+	if err != nil {
+		t.Fatalf("GetResultsBySeason: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("%d results in the other season after rollback, want 1", len(results))
+	}
+
+	// Rolling back to week 1 undoes the whole season
+	if err := repo.RollbackSeason(ctx, first.ID, 1); err != nil {
+		t.Fatalf("RollbackSeason: %v", err)
+	}
+	results, err = repo.GetResultsBySeason(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetResultsBySeason: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("%d results after rolling back to week 1, want 0", len(results))
+	}
+	if week, _ := repo.GetCurrentWeek(ctx, first.ID); week != 1 {
+		t.Errorf("current week after rolling back to week 1 = %d, want 1", week)
+	}
+	if len(listTeams(t, repo)) != len(teams) {
+		t.Error("rollback removed teams")
+	}
+}
+
+func testWithTx(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	errRollback := errors.New("rollback")
+
+	err := repo.WithTx(ctx, func(tx repository.Repository) error {
+		if _, err := tx.CreateTeam(ctx, sqlc.CreateTeamParams{Name: "Rolled Back"}); err != nil {
+			return err
+		}
+		// Nested calls join the outer transaction
+		return tx.WithTx(ctx, func(inner repository.Repository) error {
+			if _, err := inner.GetTeamByName(ctx, "Rolled Back"); err != nil {
+				return err
+			}
+			return errRollback
+		})
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithTx error = %v, want the error returned by fn", err)
+	}
+	if _, err := repo.GetTeamByName(ctx, "Rolled Back"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("team from a rolled back transaction exists (err = %v)", err)
+	}
+
+	err = repo.WithTx(ctx, func(tx repository.Repository) error {
+		_, err := tx.CreateTeam(ctx, sqlc.CreateTeamParams{Name: "Committed"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	if _, err := repo.GetTeamByName(ctx, "Committed"); err != nil {
+		t.Errorf("team from a committed transaction is missing: %v", err)
+	}
+}
+
+func currentSeason(t *testing.T, repo repository.Repository) sqlc.Season {
+	t.Helper()
+	season, err := repo.GetCurrentSeason(context.Background())
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	return season
+}
+
+// listTeams returns the teams ordered by name.
+func listTeams(t *testing.T, repo repository.Repository) []sqlc.Team {
+	t.Helper()
+	teams, err := repo.ListTeams(context.Background())
+	if err != nil {
+		t.Fatalf("ListTeams: %v", err)
+	}
+	return teams
+}
+
+func createFixture(t *testing.T, repo repository.Repository, seasonID, week, homeID, guestID int64) {
+	t.Helper()
+	err := repo.CreateFixture(context.Background(), sqlc.CreateFixtureParams{
+		HomeID:   homeID,
+		GuestID:  guestID,
+		Played:   sql.NullBool{Bool: false, Valid: true},
+		Week:     week,
+		SeasonID: seasonID,
+	})
+	if err != nil {
+		t.Fatalf("CreateFixture: %v", err)
+	}
+}
+
+func saveResult(t *testing.T, repo repository.Repository, m sqlc.GetMatchesByWeekRow, homeScore, guestScore int64) {
+	t.Helper()
+	var winner sql.NullInt64
+	switch {
+	case homeScore > guestScore:
+		winner = sql.NullInt64{Int64: m.HomeID, Valid: true}
+	case guestScore > homeScore:
+		winner = sql.NullInt64{Int64: m.GuestID, Valid: true}
+	}
+
+	err := repo.SaveResult(context.Background(), sqlc.SaveResultParams{
+		MatchID:    m.ID,
+		HomeScore:  homeScore,
+		GuestScore: guestScore,
+		WinnerID:   winner,
+	})
+	if err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+}
+
+func testTournament(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	season := currentSeason(t, repo)
+	teams := listTeams(t, repo)
+	team := func(id int64) sql.NullInt64 { return sql.NullInt64{Int64: id, Valid: true} }
+
+	if _, err := repo.GetTournamentBySeason(ctx, season.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("GetTournamentBySeason before a draw error = %v, want sql.ErrNoRows", err)
+	}
+	tournament, err := repo.CreateTournament(ctx, sqlc.CreateTournamentParams{
+		SeasonID:   season.ID,
+		GroupCount: 2,
+		Qualifiers: 1,
+		AwayGoals:  true,
+	})
+	if err != nil {
+		t.Fatalf("CreateTournament: %v", err)
+	}
+	if _, err := repo.CreateTournament(ctx, sqlc.CreateTournamentParams{SeasonID: season.ID, GroupCount: 1, Qualifiers: 2}); err == nil {
+		t.Error("CreateTournament allowed a second tournament in a season")
+	}
+	got, err := repo.GetTournamentBySeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetTournamentBySeason: %v", err)
+	}
+	if got != tournament || !got.AwayGoals || got.SeparateDivisions {
+		t.Errorf("tournament = %+v, want %+v", got, tournament)
+	}
+
+	// Two groups of two, listed by group and pot
+	for i, tt := range teams[:4] {
+		err := repo.CreateTournamentTeam(ctx, sqlc.CreateTournamentTeamParams{
+			TournamentID: tournament.ID,
+			TeamID:       tt.ID,
+			Pot:          int64(i / 2),
+			GroupNumber:  int64(1 - i%2),
+		})
+		if err != nil {
+			t.Fatalf("CreateTournamentTeam: %v", err)
+		}
+	}
+	members, err := repo.ListTournamentTeams(ctx, tournament.ID)
+	if err != nil {
+		t.Fatalf("ListTournamentTeams: %v", err)
+	}
+	if len(members) != 4 || members[0].TeamID != teams[1].ID || members[1].TeamID != teams[3].ID || members[0].TeamName != teams[1].Name {
+		t.Fatalf("tournament teams = %+v, want them by group and pot", members)
+	}
+
+	// A group match and a final waiting for its teams
+	for _, match := range []sqlc.CreateTournamentMatchParams{
+		{TournamentID: tournament.ID, Round: 1, Leg: 2, Week: 3},
+		{TournamentID: tournament.ID, Round: 1, Leg: 1, Week: 2},
+		{TournamentID: tournament.ID, Slot: 1, Leg: 1, Week: 1, HomeID: team(teams[0].ID), GuestID: team(teams[2].ID)},
+	} {
+		if err := repo.CreateTournamentMatch(ctx, match); err != nil {
+			t.Fatalf("CreateTournamentMatch: %v", err)
+		}
+	}
+	matches, err := repo.ListTournamentMatches(ctx, tournament.ID)
+	if err != nil {
+		t.Fatalf("ListTournamentMatches: %v", err)
+	}
+	if len(matches) != 3 || matches[0].Round != 0 || matches[1].Leg != 1 || matches[2].Leg != 2 {
+		t.Fatalf("matches = %+v, want them by round and leg", matches)
+	}
+	if matches[0].HomeTeamName.String != teams[0].Name || matches[0].GuestTeamName.String != teams[2].Name || matches[1].HomeTeamName.Valid {
+		t.Errorf("matches = %+v, want names only for known teams", matches)
+	}
+
+	// Play the group match and both legs, the second after extra time
+	group, first, second := matches[0], matches[1], matches[2]
+	save := func(params sqlc.SaveTournamentMatchResultParams) {
+		t.Helper()
+		if err := repo.SaveTournamentMatchResult(ctx, params); err != nil {
+			t.Fatalf("SaveTournamentMatchResult: %v", err)
+		}
+	}
+	save(sqlc.SaveTournamentMatchResultParams{HomeScore: team(1), GuestScore: team(1), ID: group.ID})
+	for _, match := range []sqlc.ListTournamentMatchesRow{first, second} {
+		home, guest := team(teams[0].ID), team(teams[1].ID)
+		if match.Leg == 2 {
+			home, guest = guest, home
+		}
+		if err := repo.SetTournamentMatchTeams(ctx, sqlc.SetTournamentMatchTeamsParams{HomeID: home, GuestID: guest, ID: match.ID}); err != nil {
+			t.Fatalf("SetTournamentMatchTeams: %v", err)
+		}
+	}
+	save(sqlc.SaveTournamentMatchResultParams{HomeScore: team(2), GuestScore: team(0), WinnerID: team(teams[0].ID), ID: first.ID})
+	save(sqlc.SaveTournamentMatchResultParams{HomeScore: team(3), GuestScore: team(0), ExtraTime: true, WinnerID: team(teams[1].ID), ID: second.ID})
+	matches, err = repo.ListTournamentMatches(ctx, tournament.ID)
+	if err != nil {
+		t.Fatalf("ListTournamentMatches: %v", err)
+	}
+	second = matches[2]
+	if !matches[0].Played || matches[0].WinnerID.Valid {
+		t.Errorf("group match = %+v, want a played draw", matches[0])
+	}
+	if !second.Played || !second.ExtraTime || second.WinnerID.Int64 != teams[1].ID || second.HomeTeamName.String != teams[1].Name {
+		t.Errorf("second leg = %+v, want %s winning after extra time", second, teams[1].Name)
+	}
+
+	// Rolling back the second leg replays it with the same teams
+	if err := repo.RollbackSeason(ctx, season.ID, 3); err != nil {
+		t.Fatalf("RollbackSeason: %v", err)
+	}
+	matches, err = repo.ListTournamentMatches(ctx, tournament.ID)
+	if err != nil {
+		t.Fatalf("ListTournamentMatches: %v", err)
+	}
+	if !matches[0].Played || !matches[1].Played {
+		t.Error("rolling back to week 3 undid the first leg")
+	}
+	second = matches[2]
+	if second.Played || second.ExtraTime || second.HomeScore.Valid || second.WinnerID.Valid || second.HomeID.Int64 != teams[1].ID {
+		t.Errorf("second leg after rollback = %+v, want it unplayed with its teams", second)
+	}
+
+	// Rolling back the group stage leaves the final without teams
+	if err := repo.RollbackSeason(ctx, season.ID, 1); err != nil {
+		t.Fatalf("RollbackSeason: %v", err)
+	}
+	matches, err = repo.ListTournamentMatches(ctx, tournament.ID)
+	if err != nil {
+		t.Fatalf("ListTournamentMatches: %v", err)
+	}
+	if matches[0].Played || matches[0].HomeID.Int64 != teams[0].ID {
+		t.Errorf("group match after rollback = %+v, want it unplayed with its teams", matches[0])
+	}
+	if matches[1].Played || matches[1].HomeID.Valid || matches[2].GuestID.Valid {
+		t.Errorf("final after rollback = %+v, want it without teams", matches[1:])
+	}
+
+	if err := repo.DeleteTournamentMatches(ctx, tournament.ID); err != nil {
+		t.Fatalf("DeleteTournamentMatches: %v", err)
+	}
+	if err := repo.DeleteTournamentTeams(ctx, tournament.ID); err != nil {
+		t.Fatalf("DeleteTournamentTeams: %v", err)
+	}
+	if matches, _ := repo.ListTournamentMatches(ctx, tournament.ID); len(matches) != 0 {
+		t.Errorf("%d matches after DeleteTournamentMatches, want 0", len(matches))
+	}
+	if members, _ := repo.ListTournamentTeams(ctx, tournament.ID); len(members) != 0 {
+		t.Errorf("%d teams after DeleteTournamentTeams, want 0", len(members))
+	}
+	if err := repo.CreateTournamentTeam(ctx, sqlc.CreateTournamentTeamParams{TournamentID: tournament.ID, TeamID: teams[0].ID}); err != nil {
+		t.Fatalf("CreateTournamentTeam: %v", err)
+	}
+	if err := repo.CreateTournamentMatch(ctx, sqlc.CreateTournamentMatchParams{TournamentID: tournament.ID, Round: 1, Leg: 1, Week: 1}); err != nil {
+		t.Fatalf("CreateTournamentMatch: %v", err)
+	}
+	if err := repo.DeleteTournament(ctx, tournament.ID); err != nil {
+		t.Fatalf("DeleteTournament: %v", err)
+	}
+	if _, err := repo.GetTournamentBySeason(ctx, season.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetTournamentBySeason after DeleteTournament error = %v, want sql.ErrNoRows", err)
+	}
+	if matches, _ := repo.ListTournamentMatches(ctx, tournament.ID); len(matches) != 0 {
+		t.Errorf("%d matches after DeleteTournament, want 0", len(matches))
+	}
+	if members, _ := repo.ListTournamentTeams(ctx, tournament.ID); len(members) != 0 {
+		t.Errorf("%d teams after DeleteTournament, want 0", len(members))
+	}
+}
+
+func testCompetitions(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	teams := listTeams(t, repo)
+
+	// The migrations give existing seasons a league, so start a new one
+	season, err := repo.CreateNewSeason(ctx, 2026, 42)
+	if err != nil {
+		t.Fatalf("CreateNewSeason: %v", err)
+	}
+
+	league, err := repo.CreateCompetition(ctx, sqlc.CreateCompetitionParams{
+		SeasonID:     season.ID,
+		Format:       "league",
+		Name:         "League",
+		Legs:         2,
+		WinPoints:    3,
+		DrawPoints:   1,
+		RankingRules: "premier-league",
+	})
+	if err != nil {
+		t.Fatalf("CreateCompetition: %v", err)
+	}
+	if _, err := repo.CreateCompetition(ctx, sqlc.CreateCompetitionParams{SeasonID: season.ID, Format: "league", Name: "Second"}); err == nil {
+		t.Error("CreateCompetition allowed a second league in a season")
+	}
+	cup, err := repo.CreateCompetition(ctx, sqlc.CreateCompetitionParams{SeasonID: season.ID, Format: "cup", Name: "Cup", Legs: 1})
+	if err != nil {
+		t.Fatalf("CreateCompetition(cup): %v", err)
+	}
+
+	got, err := repo.GetCompetitionBySeason(ctx, sqlc.GetCompetitionBySeasonParams{SeasonID: season.ID, Format: "league"})
+	if err != nil {
+		t.Fatalf("GetCompetitionBySeason: %v", err)
+	}
+	if got != league {
+		t.Errorf("league = %+v, want %+v", got, league)
+	}
+	if _, err := repo.GetCompetitionBySeason(ctx, sqlc.GetCompetitionBySeasonParams{SeasonID: season.ID, Format: "tournament"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetCompetitionBySeason(missing) error = %v, want sql.ErrNoRows", err)
+	}
+	competitions, err := repo.ListCompetitions(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ListCompetitions: %v", err)
+	}
+	if len(competitions) != 2 || competitions[0] != league || competitions[1] != cup {
+		t.Errorf("competitions = %+v, want the league and the cup", competitions)
+	}
+
+	// Two points for a win and one more for scoring four, played once
+	err = repo.UpdateCompetitionRules(ctx, sqlc.UpdateCompetitionRulesParams{ID: league.ID, Legs: 1, WinPoints: 2, DrawPoints: 1, BonusGoals: 4, BonusPoints: 1, RankingRules: "la-liga"})
+	if err != nil {
+		t.Fatalf("UpdateCompetitionRules: %v", err)
+	}
+	got, err = repo.GetCompetition(ctx, league.ID)
+	if err != nil {
+		t.Fatalf("GetCompetition: %v", err)
+	}
+	if got.Legs != 1 || got.WinPoints != 2 || got.DrawPoints != 1 || got.LossPoints != 0 || got.BonusGoals != 4 || got.BonusPoints != 1 || got.RankingRules != "la-liga" {
+		t.Errorf("league after update = %+v, want one leg, two points for a win, a bonus for four goals and la-liga rules", got)
+	}
+
+	if err := repo.SetCompetitionRankingRules(ctx, league.ID, "serie-a"); err != nil {
+		t.Fatalf("SetCompetitionRankingRules: %v", err)
+	}
+	got, err = repo.GetCompetition(ctx, league.ID)
+	if err != nil {
+		t.Fatalf("GetCompetition: %v", err)
+	}
+	if got.RankingRules != "serie-a" || got.Legs != 1 {
+		t.Errorf("league after setting rules = %+v, want serie-a rules and one leg", got)
+	}
+
+	// Teams are listed by name, and leave with a deleted team
+	for _, team := range []sqlc.Team{teams[3], teams[0], teams[2]} {
+		err := repo.CreateCompetitionTeam(ctx, sqlc.CreateCompetitionTeamParams{CompetitionID: league.ID, TeamID: team.ID})
+		if err != nil {
+			t.Fatalf("CreateCompetitionTeam: %v", err)
+		}
+	}
+	if err := repo.DeleteTeam(ctx, teams[2].ID); err != nil {
+		t.Fatalf("DeleteTeam: %v", err)
+	}
+	members, err := repo.ListCompetitionTeams(ctx, league.ID)
+	if err != nil {
+		t.Fatalf("ListCompetitionTeams: %v", err)
+	}
+	if len(members) != 2 || members[0].TeamName != teams[0].Name || members[1].TeamName != teams[3].Name {
+		t.Errorf("competition teams = %+v, want %s and %s", members, teams[0].Name, teams[3].Name)
+	}
+
+	if err := repo.DeleteCompetitionTeams(ctx, league.ID); err != nil {
+		t.Fatalf("DeleteCompetitionTeams: %v", err)
+	}
+	members, err = repo.ListCompetitionTeams(ctx, league.ID)
+	if err != nil {
+		t.Fatalf("ListCompetitionTeams: %v", err)
+	}
+	if len(members) != 0 {
+		t.Errorf("competition teams after delete = %+v, want none", members)
+	}
+}
+
+func testSanctions(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	teams := listTeams(t, repo)
+
+	previous, err := repo.GetCurrentSeason(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSeason: %v", err)
+	}
+	season, err := repo.CreateNewSeason(ctx, 2026, 42)
+	if err != nil {
+		t.Fatalf("CreateNewSeason: %v", err)
+	}
+	league, err := repo.CreateCompetition(ctx, sqlc.CreateCompetitionParams{SeasonID: season.ID, Format: "league", Name: "League", Legs: 2, WinPoints: 3, DrawPoints: 1})
+	if err != nil {
+		t.Fatalf("CreateCompetition: %v", err)
+	}
+	err = repo.CreateFixture(ctx, sqlc.CreateFixtureParams{HomeID: teams[3].ID, GuestID: teams[2].ID, Week: 1, SeasonID: season.ID})
+	if err != nil {
+		t.Fatalf("CreateFixture: %v", err)
+	}
+	matches, err := repo.GetMatchesByWeek(ctx, 1, season.ID)
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("GetMatchesByWeek = %+v, %v, want one match", matches, err)
+	}
+
+	// Sanctions are listed in the order they were taken, whatever their ids
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, sanction := range []sqlc.CreateSanctionParams{
+		{CompetitionID: league.ID, TeamID: teams[1].ID, Kind: "deduction", Points: 6, Reason: "Financial breach", CreatedAt: now.Add(2 * time.Hour)},
+		{CompetitionID: league.ID, TeamID: teams[3].ID, Kind: "forfeit", MatchID: sql.NullInt64{Int64: matches[0].ID, Valid: true}, FirstWeek: 1, LastWeek: 1, Reason: "Fielded an ineligible player", CreatedAt: now},
+		{CompetitionID: league.ID, TeamID: teams[2].ID, Kind: "ban", FirstWeek: 2, LastWeek: 4, Reason: "Crowd trouble", CreatedAt: now.Add(time.Hour)},
+	} {
+		created, err := repo.CreateSanction(ctx, sanction)
+		if err != nil {
+			t.Fatalf("CreateSanction: %v", err)
+		}
+		got, err := repo.GetSanction(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("GetSanction: %v", err)
+		}
+		if got.TeamID != sanction.TeamID || got.Kind != sanction.Kind || got.Points != sanction.Points || got.MatchID != sanction.MatchID ||
+			got.FirstWeek != sanction.FirstWeek || got.LastWeek != sanction.LastWeek || got.Reason != sanction.Reason || !got.CreatedAt.Equal(sanction.CreatedAt) {
+			t.Errorf("sanction = %+v, want %+v", got, sanction)
+		}
+	}
+	sanctions, err := repo.ListSanctions(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ListSanctions: %v", err)
+	}
+	if len(sanctions) != 3 || sanctions[0].TeamName != teams[3].Name || sanctions[1].TeamName != teams[2].Name || sanctions[2].TeamName != teams[1].Name {
+		t.Fatalf("sanctions = %+v, want %s's, %s's then %s's", sanctions, teams[3].Name, teams[2].Name, teams[1].Name)
+	}
+	if sanctions[0].Format != "league" {
+		t.Errorf("sanction format = %q, want league", sanctions[0].Format)
+	}
+	if others, err := repo.ListSanctions(ctx, previous.ID); err != nil || len(others) != 0 {
+		t.Errorf("ListSanctions(previous season) = %+v, %v, want none", others, err)
+	}
+
+	if err := repo.DeleteSanction(ctx, sanctions[0].ID); err != nil {
+		t.Fatalf("DeleteSanction: %v", err)
+	}
+	if _, err := repo.GetSanction(ctx, sanctions[0].ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetSanction(deleted) error = %v, want sql.ErrNoRows", err)
+	}
+
+	// A deleted team takes its sanctions with it
+	if err := repo.DeleteTeam(ctx, teams[1].ID); err != nil {
+		t.Fatalf("DeleteTeam: %v", err)
+	}
+	sanctions, err = repo.ListSanctions(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("ListSanctions: %v", err)
+	}
+	if len(sanctions) != 1 || sanctions[0].TeamID != teams[2].ID {
+		t.Errorf("sanctions after DeleteTeam = %+v, want %s's ban", sanctions, teams[2].Name)
+	}
+}
@@ -8,25 +8,75 @@ import (
 )
 
 // SQLCRepository implements the Repository interface using SQLC-generated code.
+// The queries are generated per SQL dialect behind the same sqlc.Querier
+// interface, so one implementation serves every supported database.
 type SQLCRepository struct {
-	queries *sqlc.Queries
-	Conn    *sql.DB
+	queries    sqlc.Querier
+	newQueries func(sqlc.DBTX) sqlc.Querier // binds the queries to a transaction
+	Conn       *sql.DB
+	tx         *sql.Tx // set when the repository is bound to a transaction
 }
 
-// NewSQLCRepository creates a new SQLCRepository instance.
+// NewSQLCRepository creates a new SQLCRepository instance for SQLite.
 func NewSQLCRepository(queries *sqlc.Queries, conn *sql.DB) *SQLCRepository {
 	return &SQLCRepository{
 		queries: queries,
-		Conn:    conn,
+		newQueries: func(db sqlc.DBTX) sqlc.Querier {
+			return sqlc.New(db)
+		},
+		Conn: conn,
 	}
 }
 
+func (r *SQLCRepository) WithTx(ctx context.Context, fn func(Repository) error) error {
+	return r.inTx(ctx, func(txRepo *SQLCRepository) error {
+		return fn(txRepo)
+	})
+}
+
+// inTx runs fn with a repository bound to a transaction, joining the current
+// transaction if there is one.
+func (r *SQLCRepository) inTx(ctx context.Context, fn func(*SQLCRepository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(&SQLCRepository{
+		queries:    r.newQueries(tx),
+		newQueries: r.newQueries,
+		Conn:       r.Conn,
+		tx:         tx,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *SQLCRepository) GetCurrentSeason(ctx context.Context) (sqlc.Season, error) {
 	return r.queries.GetCurrentSeason(ctx)
 }
 
-func (r *SQLCRepository) CreateNewSeason(ctx context.Context, year int64) (sqlc.Season, error) {
-	return r.queries.CreateNewSeason(ctx, year)
+func (r *SQLCRepository) GetSeason(ctx context.Context, id int64) (sqlc.Season, error) {
+	return r.queries.GetSeason(ctx, id)
+}
+
+func (r *SQLCRepository) ListSeasons(ctx context.Context) ([]sqlc.Season, error) {
+	return r.queries.ListSeasons(ctx)
+}
+
+func (r *SQLCRepository) CreateNewSeason(ctx context.Context, year int64, seed int64) (sqlc.Season, error) {
+	return r.queries.CreateNewSeason(ctx, sqlc.CreateNewSeasonParams{
+		Year: year,
+		Seed: seed,
+	})
 }
 
 func (r *SQLCRepository) SetCurrentSeason(ctx context.Context, id int64) error {
@@ -37,50 +87,58 @@ func (r *SQLCRepository) CompleteSeason(ctx context.Context, id int64) error {
 	return r.queries.CompleteSeason(ctx, id)
 }
 
-func (r *SQLCRepository) ResetToYear(ctx context.Context, year int64) error {
+func (r *SQLCRepository) RollbackSeason(ctx context.Context, seasonID int64, week int64) error {
 	// Execute each statement in a transaction
-	tx, err := r.Conn.BeginTx(ctx, nil)
-	if err != nil {
+	return r.inTx(ctx, func(r *SQLCRepository) error {
+		return r.rollbackSeason(ctx, seasonID, week)
+	})
+}
+
+func (r *SQLCRepository) rollbackSeason(ctx context.Context, seasonID int64, week int64) error {
+	// Undo the results from the given week onward
+	if err := r.queries.DeleteResultsFromWeek(ctx, sqlc.DeleteResultsFromWeekParams{SeasonID: seasonID, Week: week}); err != nil {
 		return err
 	}
-	defer tx.Rollback()
-
-	// Delete match results
-	if _, err := tx.ExecContext(ctx, "DELETE FROM match_result"); err != nil {
+	if err := r.queries.UnplayMatchesFromWeek(ctx, sqlc.UnplayMatchesFromWeekParams{SeasonID: seasonID, Week: week}); err != nil {
 		return err
 	}
 
-	// Delete matches
-	if _, err := tx.ExecContext(ctx, "DELETE FROM match"); err != nil {
+	// Move the season back to that week
+	if _, err := r.queries.GetCurrentWeek(ctx, seasonID); err == sql.ErrNoRows {
+		if err := r.queries.InitializeGameState(ctx, seasonID); err != nil {
+			return err
+		}
+	} else if err != nil {
 		return err
 	}
-
-	// Delete standings
-	if _, err := tx.ExecContext(ctx, "DELETE FROM standing"); err != nil {
+	if err := r.queries.SetCurrentWeek(ctx, sqlc.SetCurrentWeekParams{
+		CurrentWeek: sql.NullInt64{Int64: week, Valid: true},
+		SeasonID:    seasonID,
+	}); err != nil {
 		return err
 	}
 
-	// Delete game state
-	if _, err := tx.ExecContext(ctx, "DELETE FROM game_state"); err != nil {
+	// Cup rounds from that week onward are played again by the teams of the
+	// rounds before them
+	if err := r.queries.ResetCupTiesFromWeek(ctx, sqlc.ResetCupTiesFromWeekParams{Week: week, SeasonID: seasonID}); err != nil {
 		return err
 	}
-
-	// Delete seasons except the target year
-	if _, err := tx.ExecContext(ctx, "DELETE FROM season WHERE year != ?", year); err != nil {
+	if err := r.queries.ClearCupTieTeamsFromWeek(ctx, sqlc.ClearCupTieTeamsFromWeekParams{SeasonID: seasonID, Week: week}); err != nil {
 		return err
 	}
 
-	// Reset the target season
-	if _, err := tx.ExecContext(ctx, "UPDATE season SET is_complete = FALSE, is_current = TRUE WHERE year = ?", year); err != nil {
+	// So are the tournament's, the group stage keeping its draw
+	if err := r.queries.ResetTournamentMatchesFromWeek(ctx, sqlc.ResetTournamentMatchesFromWeekParams{Week: week, SeasonID: seasonID}); err != nil {
 		return err
 	}
-
-	// Initialize game state for the reset season
-	if _, err := tx.ExecContext(ctx, "INSERT INTO game_state (current_week, season_id) SELECT 1, id FROM season WHERE is_current = TRUE"); err != nil {
+	if err := r.queries.ClearTournamentMatchTeamsFromWeek(ctx, sqlc.ClearTournamentMatchTeamsFromWeekParams{SeasonID: seasonID, Week: week}); err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	if err := r.queries.DeletePlayoffsBySeason(ctx, seasonID); err != nil {
+		return err
+	}
+	return r.queries.ReopenSeason(ctx, seasonID)
 }
 
 func (r *SQLCRepository) GetCurrentWeek(ctx context.Context, seasonID int64) (int, error) {
@@ -116,6 +174,48 @@ func (r *SQLCRepository) GetUnplayedMatchesByWeek(ctx context.Context, week int6
 	})
 }
 
+func (r *SQLCRepository) GetUnplayedMatchesBySeason(ctx context.Context, seasonID int64) ([]sqlc.Match, error) {
+	return r.queries.GetUnplayedMatchesBySeason(ctx, seasonID)
+}
+
+func (r *SQLCRepository) GetMatchesBySeason(ctx context.Context, seasonID int64) ([]sqlc.GetMatchesBySeasonRow, error) {
+	return r.queries.GetMatchesBySeason(ctx, seasonID)
+}
+
+func (r *SQLCRepository) GetMatchesByTeam(ctx context.Context, teamID int64, seasonID int64) ([]sqlc.GetMatchesByTeamRow, error) {
+	return r.queries.GetMatchesByTeam(ctx, sqlc.GetMatchesByTeamParams{
+		SeasonID: seasonID,
+		TeamID:   teamID,
+	})
+}
+
+func (r *SQLCRepository) GetSeasonWeeks(ctx context.Context, seasonID int64) (int, error) {
+	weeks, err := r.queries.GetSeasonWeeks(ctx, seasonID)
+	return int(weeks), err
+}
+
+func (r *SQLCRepository) DeleteUnplayedMatchesBySeason(ctx context.Context, seasonID int64) error {
+	return r.queries.DeleteUnplayedMatchesBySeason(ctx, seasonID)
+}
+
+func (r *SQLCRepository) GetResultsBySeason(ctx context.Context, seasonID int64) ([]sqlc.GetResultsBySeasonRow, error) {
+	return r.queries.GetResultsBySeason(ctx, seasonID)
+}
+
+func (r *SQLCRepository) ListHeadToHeadSeasons(ctx context.Context, teamID int64, opponentID int64) ([]sqlc.ListHeadToHeadSeasonsRow, error) {
+	return r.queries.ListHeadToHeadSeasons(ctx, sqlc.ListHeadToHeadSeasonsParams{
+		TeamID:     teamID,
+		OpponentID: opponentID,
+	})
+}
+
+func (r *SQLCRepository) ListHeadToHeadMatches(ctx context.Context, teamID int64, opponentID int64) ([]sqlc.ListHeadToHeadMatchesRow, error) {
+	return r.queries.ListHeadToHeadMatches(ctx, sqlc.ListHeadToHeadMatchesParams{
+		TeamID:     teamID,
+		OpponentID: opponentID,
+	})
+}
+
 func (r *SQLCRepository) GetStanding(ctx context.Context, teamID int64, seasonID int64) (sqlc.Standing, error) {
 	return r.queries.GetStanding(ctx, sqlc.GetStandingParams{
 		TeamID:   teamID,
@@ -131,6 +231,10 @@ func (r *SQLCRepository) CreateStanding(ctx context.Context, arg sqlc.CreateStan
 	return r.queries.CreateStanding(ctx, arg)
 }
 
+func (r *SQLCRepository) ComputeStandings(ctx context.Context, seasonID int64) ([]sqlc.ComputeStandingsRow, error) {
+	return r.queries.ComputeStandings(ctx, seasonID)
+}
+
 func (r *SQLCRepository) CreateFixture(ctx context.Context, arg sqlc.CreateFixtureParams) error {
 	return r.queries.CreateFixture(ctx, arg)
 }
@@ -167,12 +271,241 @@ func (r *SQLCRepository) UpdateTeamStrength(ctx context.Context, arg sqlc.Update
 	return r.queries.UpdateTeamStrength(ctx, arg)
 }
 
+func (r *SQLCRepository) UpdateTeam(ctx context.Context, arg sqlc.UpdateTeamParams) error {
+	return r.queries.UpdateTeam(ctx, arg)
+}
+
 func (r *SQLCRepository) DeleteTeam(ctx context.Context, id int64) error {
-	// Implement if needed
-	return nil
+	return r.inTx(ctx, func(tx *SQLCRepository) error {
+		// Matches keep a team's history, so only teams without any can go
+		matches, err := tx.queries.CountTeamMatches(ctx, id)
+		if err != nil {
+			return err
+		}
+		if matches > 0 {
+			return ErrTeamHasMatches
+		}
+
+		if err := tx.queries.DeleteTeamStandings(ctx, id); err != nil {
+			return err
+		}
+		if err := tx.queries.DeleteTeamDivisions(ctx, id); err != nil {
+			return err
+		}
+		if err := tx.queries.DeleteTeamCompetitions(ctx, id); err != nil {
+			return err
+		}
+		if err := tx.queries.DeleteTeamSanctions(ctx, id); err != nil {
+			return err
+		}
+		return tx.queries.DeleteTeam(ctx, id)
+	})
+}
+
+func (r *SQLCRepository) CountTeamMatches(ctx context.Context, teamID int64) (int64, error) {
+	return r.queries.CountTeamMatches(ctx, teamID)
+}
+
+func (r *SQLCRepository) CreateTeamHistory(ctx context.Context, arg sqlc.CreateTeamHistoryParams) error {
+	return r.queries.CreateTeamHistory(ctx, arg)
+}
+
+func (r *SQLCRepository) ListTeamHistory(ctx context.Context) ([]sqlc.ListTeamHistoryRow, error) {
+	return r.queries.ListTeamHistory(ctx)
 }
 
 func (r *SQLCRepository) InitializeGameState(ctx context.Context, seasonID int64) error {
-	_, err := r.Conn.ExecContext(ctx, "INSERT INTO game_state (current_week, season_id) VALUES (1, ?)", seasonID)
-	return err
+	return r.queries.InitializeGameState(ctx, seasonID)
+}
+
+func (r *SQLCRepository) CreateDivision(ctx context.Context, arg sqlc.CreateDivisionParams) (sqlc.Division, error) {
+	return r.queries.CreateDivision(ctx, arg)
+}
+
+func (r *SQLCRepository) GetDivision(ctx context.Context, id int64) (sqlc.Division, error) {
+	return r.queries.GetDivision(ctx, id)
+}
+
+func (r *SQLCRepository) ListDivisions(ctx context.Context, seasonID int64) ([]sqlc.Division, error) {
+	return r.queries.ListDivisions(ctx, seasonID)
+}
+
+func (r *SQLCRepository) UpdateDivision(ctx context.Context, arg sqlc.UpdateDivisionParams) error {
+	return r.queries.UpdateDivision(ctx, arg)
+}
+
+func (r *SQLCRepository) DeleteDivision(ctx context.Context, id int64) error {
+	return r.queries.DeleteDivision(ctx, id)
+}
+
+func (r *SQLCRepository) SetTeamDivision(ctx context.Context, seasonID int64, divisionID int64, teamID int64) error {
+	return r.inTx(ctx, func(tx *SQLCRepository) error {
+		err := tx.queries.RemoveDivisionTeam(ctx, sqlc.RemoveDivisionTeamParams{
+			TeamID:   teamID,
+			SeasonID: seasonID,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.queries.AddDivisionTeam(ctx, sqlc.AddDivisionTeamParams{
+			DivisionID: divisionID,
+			TeamID:     teamID,
+		})
+	})
+}
+
+func (r *SQLCRepository) ListDivisionTeams(ctx context.Context, seasonID int64) ([]sqlc.DivisionTeam, error) {
+	return r.queries.ListDivisionTeams(ctx, seasonID)
+}
+
+func (r *SQLCRepository) CreatePlayoff(ctx context.Context, arg sqlc.CreatePlayoffParams) error {
+	return r.queries.CreatePlayoff(ctx, arg)
+}
+
+func (r *SQLCRepository) ListPlayoffsBySeason(ctx context.Context, seasonID int64) ([]sqlc.ListPlayoffsBySeasonRow, error) {
+	return r.queries.ListPlayoffsBySeason(ctx, seasonID)
+}
+
+func (r *SQLCRepository) CreateCup(ctx context.Context, arg sqlc.CreateCupParams) (sqlc.Cup, error) {
+	return r.queries.CreateCup(ctx, arg)
+}
+
+func (r *SQLCRepository) GetCupBySeason(ctx context.Context, seasonID int64) (sqlc.Cup, error) {
+	return r.queries.GetCupBySeason(ctx, seasonID)
+}
+
+func (r *SQLCRepository) DeleteCup(ctx context.Context, id int64) error {
+	return r.inTx(ctx, func(tx *SQLCRepository) error {
+		if err := tx.queries.DeleteCupTies(ctx, id); err != nil {
+			return err
+		}
+		return tx.queries.DeleteCup(ctx, id)
+	})
+}
+
+func (r *SQLCRepository) CreateCupTie(ctx context.Context, arg sqlc.CreateCupTieParams) error {
+	return r.queries.CreateCupTie(ctx, arg)
+}
+
+func (r *SQLCRepository) ListCupTies(ctx context.Context, cupID int64) ([]sqlc.ListCupTiesRow, error) {
+	return r.queries.ListCupTies(ctx, cupID)
+}
+
+func (r *SQLCRepository) DeleteCupTies(ctx context.Context, cupID int64) error {
+	return r.queries.DeleteCupTies(ctx, cupID)
+}
+
+func (r *SQLCRepository) SetCupTieTeams(ctx context.Context, arg sqlc.SetCupTieTeamsParams) error {
+	return r.queries.SetCupTieTeams(ctx, arg)
+}
+
+func (r *SQLCRepository) SaveCupTieResult(ctx context.Context, arg sqlc.SaveCupTieResultParams) error {
+	return r.queries.SaveCupTieResult(ctx, arg)
+}
+
+func (r *SQLCRepository) CreateTournament(ctx context.Context, arg sqlc.CreateTournamentParams) (sqlc.Tournament, error) {
+	return r.queries.CreateTournament(ctx, arg)
+}
+
+func (r *SQLCRepository) GetTournamentBySeason(ctx context.Context, seasonID int64) (sqlc.Tournament, error) {
+	return r.queries.GetTournamentBySeason(ctx, seasonID)
+}
+
+func (r *SQLCRepository) DeleteTournament(ctx context.Context, id int64) error {
+	return r.inTx(ctx, func(tx *SQLCRepository) error {
+		if err := tx.queries.DeleteTournamentMatches(ctx, id); err != nil {
+			return err
+		}
+		if err := tx.queries.DeleteTournamentTeams(ctx, id); err != nil {
+			return err
+		}
+		return tx.queries.DeleteTournament(ctx, id)
+	})
+}
+
+func (r *SQLCRepository) CreateTournamentTeam(ctx context.Context, arg sqlc.CreateTournamentTeamParams) error {
+	return r.queries.CreateTournamentTeam(ctx, arg)
+}
+
+func (r *SQLCRepository) ListTournamentTeams(ctx context.Context, tournamentID int64) ([]sqlc.ListTournamentTeamsRow, error) {
+	return r.queries.ListTournamentTeams(ctx, tournamentID)
+}
+
+func (r *SQLCRepository) DeleteTournamentTeams(ctx context.Context, tournamentID int64) error {
+	return r.queries.DeleteTournamentTeams(ctx, tournamentID)
+}
+
+func (r *SQLCRepository) CreateTournamentMatch(ctx context.Context, arg sqlc.CreateTournamentMatchParams) error {
+	return r.queries.CreateTournamentMatch(ctx, arg)
+}
+
+func (r *SQLCRepository) ListTournamentMatches(ctx context.Context, tournamentID int64) ([]sqlc.ListTournamentMatchesRow, error) {
+	return r.queries.ListTournamentMatches(ctx, tournamentID)
+}
+
+func (r *SQLCRepository) DeleteTournamentMatches(ctx context.Context, tournamentID int64) error {
+	return r.queries.DeleteTournamentMatches(ctx, tournamentID)
+}
+
+func (r *SQLCRepository) SetTournamentMatchTeams(ctx context.Context, arg sqlc.SetTournamentMatchTeamsParams) error {
+	return r.queries.SetTournamentMatchTeams(ctx, arg)
+}
+
+func (r *SQLCRepository) SaveTournamentMatchResult(ctx context.Context, arg sqlc.SaveTournamentMatchResultParams) error {
+	return r.queries.SaveTournamentMatchResult(ctx, arg)
+}
+
+func (r *SQLCRepository) CreateCompetition(ctx context.Context, arg sqlc.CreateCompetitionParams) (sqlc.Competition, error) {
+	return r.queries.CreateCompetition(ctx, arg)
+}
+
+func (r *SQLCRepository) GetCompetition(ctx context.Context, id int64) (sqlc.Competition, error) {
+	return r.queries.GetCompetition(ctx, id)
+}
+
+func (r *SQLCRepository) GetCompetitionBySeason(ctx context.Context, arg sqlc.GetCompetitionBySeasonParams) (sqlc.Competition, error) {
+	return r.queries.GetCompetitionBySeason(ctx, arg)
+}
+
+func (r *SQLCRepository) ListCompetitions(ctx context.Context, seasonID int64) ([]sqlc.Competition, error) {
+	return r.queries.ListCompetitions(ctx, seasonID)
+}
+
+func (r *SQLCRepository) UpdateCompetitionRules(ctx context.Context, arg sqlc.UpdateCompetitionRulesParams) error {
+	return r.queries.UpdateCompetitionRules(ctx, arg)
+}
+
+func (r *SQLCRepository) SetCompetitionRankingRules(ctx context.Context, id int64, rules string) error {
+	return r.queries.SetCompetitionRankingRules(ctx, sqlc.SetCompetitionRankingRulesParams{
+		RankingRules: rules,
+		ID:           id,
+	})
+}
+
+func (r *SQLCRepository) CreateCompetitionTeam(ctx context.Context, arg sqlc.CreateCompetitionTeamParams) error {
+	return r.queries.CreateCompetitionTeam(ctx, arg)
+}
+
+func (r *SQLCRepository) ListCompetitionTeams(ctx context.Context, competitionID int64) ([]sqlc.ListCompetitionTeamsRow, error) {
+	return r.queries.ListCompetitionTeams(ctx, competitionID)
+}
+
+func (r *SQLCRepository) DeleteCompetitionTeams(ctx context.Context, competitionID int64) error {
+	return r.queries.DeleteCompetitionTeams(ctx, competitionID)
+}
+
+func (r *SQLCRepository) CreateSanction(ctx context.Context, arg sqlc.CreateSanctionParams) (sqlc.Sanction, error) {
+	return r.queries.CreateSanction(ctx, arg)
+}
+
+func (r *SQLCRepository) GetSanction(ctx context.Context, id int64) (sqlc.Sanction, error) {
+	return r.queries.GetSanction(ctx, id)
+}
+
+func (r *SQLCRepository) ListSanctions(ctx context.Context, seasonID int64) ([]sqlc.ListSanctionsRow, error) {
+	return r.queries.ListSanctions(ctx, seasonID)
+}
+
+func (r *SQLCRepository) DeleteSanction(ctx context.Context, id int64) error {
+	return r.queries.DeleteSanction(ctx, id)
 }
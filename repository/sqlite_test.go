@@ -0,0 +1,25 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	database "github.com/orhosko/go-backend/db"
+	"github.com/orhosko/go-backend/repository"
+	"github.com/orhosko/go-backend/repository/repotest"
+)
+
+func TestSQLiteRepository(t *testing.T) {
+	repotest.RunSuite(t, func(t *testing.T) repository.Repository {
+		dbConn, err := database.NewDB(":memory:")
+		if err != nil {
+			t.Fatalf("NewDB: %v", err)
+		}
+		t.Cleanup(func() { dbConn.Close() })
+
+		if _, err := dbConn.MigrateUp(context.Background()); err != nil {
+			t.Fatalf("MigrateUp: %v", err)
+		}
+		return repository.NewSQLCRepository(dbConn.Queries, dbConn.Conn)
+	})
+}
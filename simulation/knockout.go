@@ -0,0 +1,93 @@
+package simulation
+
+import "math/rand"
+
+const (
+	// ExtraTimeShare is the length of extra time relative to normal time.
+	ExtraTimeShare = 30.0 / 90.0
+	// ShootoutKicks is the number of penalties each side takes before a
+	// shootout goes to sudden death.
+	ShootoutKicks = 5
+	// PenaltyConversion is the chance a penalty of a shootout is scored.
+	PenaltyConversion = 0.75
+)
+
+// KnockoutResult is the outcome of a match that must have a winner.
+type KnockoutResult struct {
+	Result              // the score after extra time if there was any
+	ExtraTime      bool // the match was drawn after normal time
+	Penalties      bool // the match was still drawn after extra time
+	HomePenalties  int64
+	GuestPenalties int64
+}
+
+// HomeWins reports whether the home side won the match.
+func (r KnockoutResult) HomeWins() bool {
+	if r.Penalties {
+		return r.HomePenalties > r.GuestPenalties
+	}
+	return r.HomeScore > r.GuestScore
+}
+
+// SimulateKnockout plays a match that cannot end in a draw. A draw after
+// normal time goes to extra time and a draw after extra time to a penalty
+// shootout.
+func SimulateKnockout(sim MatchSimulator, rng *rand.Rand, home, guest Rating) KnockoutResult {
+	result := KnockoutResult{Result: sim.Simulate(rng, home, guest)}
+	if result.HomeScore != result.GuestScore {
+		return result
+	}
+
+	extra := SimulateExtraTime(sim, rng, home, guest)
+	result.ExtraTime = true
+	result.HomeScore += extra.HomeScore
+	result.GuestScore += extra.GuestScore
+	if result.HomeScore != result.GuestScore {
+		return result
+	}
+
+	result.Penalties = true
+	result.HomePenalties, result.GuestPenalties = Shootout(rng)
+	return result
+}
+
+// SimulateExtraTime plays the extra time of a match with sim, as a match
+// whose expected goals are cut to ExtraTimeShare by scaling both attack
+// ratings. It returns the goals of extra time only.
+func SimulateExtraTime(sim MatchSimulator, rng *rand.Rand, home, guest Rating) Result {
+	home.Attack *= ExtraTimeShare
+	guest.Attack *= ExtraTimeShare
+	return sim.Simulate(rng, home, guest)
+}
+
+// Shootout plays a penalty shootout, the home side kicking first. It stops
+// as soon as one side cannot be caught and goes to sudden death if the
+// sides are level after ShootoutKicks penalties each.
+func Shootout(rng *rand.Rand) (int64, int64) {
+	var home, guest int64
+	for kick := 1; kick <= ShootoutKicks; kick++ {
+		if rng.Float64() < PenaltyConversion {
+			home++
+		}
+		if home > guest+int64(ShootoutKicks-kick+1) || guest > home+int64(ShootoutKicks-kick) {
+			return home, guest
+		}
+		if rng.Float64() < PenaltyConversion {
+			guest++
+		}
+		left := int64(ShootoutKicks - kick)
+		if home > guest+left || guest > home+left {
+			return home, guest
+		}
+	}
+
+	for home == guest {
+		if rng.Float64() < PenaltyConversion {
+			home++
+		}
+		if rng.Float64() < PenaltyConversion {
+			guest++
+		}
+	}
+	return home, guest
+}
@@ -0,0 +1,111 @@
+package simulation
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// script is a random source whose Float64 draws score (below
+// PenaltyConversion) or miss a penalty in a fixed order. It counts the kicks
+// taken.
+type script struct {
+	scored []bool
+	kicks  int
+}
+
+func (s *script) Int63() int64 {
+	scored := s.scored[s.kicks]
+	s.kicks++
+	if scored {
+		return 0
+	}
+	return 7 << 60 // 0.875
+}
+
+func (s *script) Seed(int64) {}
+
+func TestShootoutStopsWhenDecided(t *testing.T) {
+	tests := []struct {
+		name        string
+		scored      []bool // alternating home and guest kicks
+		home, guest int64
+		kicks       int
+	}{
+		{
+			name:   "guest cannot catch up",
+			scored: []bool{true, false, true, false, true, false},
+			home:   3, guest: 0, kicks: 6,
+		},
+		{
+			name:   "home wins before the guest kicks",
+			scored: []bool{true, false, true, false, true, true, true},
+			home:   4, guest: 1, kicks: 7,
+		},
+		{
+			name:   "home cannot catch up",
+			scored: []bool{true, true, false, true, false, true, false},
+			home:   1, guest: 3, kicks: 7,
+		},
+		{
+			name:   "decided on the last kick",
+			scored: []bool{true, true, true, true, true, true, true, true, true, false},
+			home:   5, guest: 4, kicks: 10,
+		},
+		{
+			name: "sudden death",
+			scored: []bool{
+				true, true, true, true, true, true, true, true, true, true,
+				false, false, true, false,
+			},
+			home: 6, guest: 5, kicks: 14,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &script{scored: tt.scored}
+			home, guest := Shootout(rand.New(src))
+			if home != tt.home || guest != tt.guest {
+				t.Errorf("Shootout = %d-%d, want %d-%d", home, guest, tt.home, tt.guest)
+			}
+			if src.kicks != tt.kicks {
+				t.Errorf("Shootout took %d kicks, want %d", src.kicks, tt.kicks)
+			}
+		})
+	}
+}
+
+func TestShootoutAlwaysHasWinner(t *testing.T) {
+	for seed := int64(0); seed < 5000; seed++ {
+		home, guest := Shootout(rand.New(rand.NewSource(seed)))
+		if home == guest {
+			t.Fatalf("seed %d: shootout ended level at %d-%d", seed, home, guest)
+		}
+
+	}
+}
+
+func TestSimulateKnockoutHasWinner(t *testing.T) {
+	sim := NewPoissonSimulator()
+	home, guest := RatingFromStrength(5), RatingFromStrength(5)
+	var penalties int
+	for seed := int64(0); seed < 2000; seed++ {
+		result := SimulateKnockout(sim, rand.New(rand.NewSource(seed)), home, guest)
+		if result.Penalties {
+			penalties++
+			if result.HomeScore != result.GuestScore || result.HomePenalties == result.GuestPenalties {
+				t.Fatalf("seed %d: shootout result %+v", seed, result)
+			}
+			continue
+		}
+		if result.HomeScore == result.GuestScore {
+			t.Fatalf("seed %d: knockout drawn %+v", seed, result)
+		}
+		if result.HomeWins() != (result.HomeScore > result.GuestScore) {
+			t.Fatalf("seed %d: HomeWins = %v for %+v", seed, result.HomeWins(), result)
+		}
+	}
+	if penalties == 0 {
+		t.Error("no knockout went to penalties")
+	}
+}
@@ -0,0 +1,65 @@
+package simulation
+
+import (
+	"math"
+	"math/rand"
+)
+
+const (
+	// DefaultBaseGoals is the expected number of goals a side scores against
+	// an equally rated opponent on neutral ground.
+	DefaultBaseGoals = 1.35
+	// DefaultHomeAdvantage multiplies the home side's expected goals.
+	DefaultHomeAdvantage = 1.1
+)
+
+// PoissonSimulator draws each side's goals independently from a Poisson
+// distribution whose mean is the side's expected goals for the match.
+type PoissonSimulator struct {
+	BaseGoals     float64
+	HomeAdvantage float64
+}
+
+// NewPoissonSimulator creates a PoissonSimulator with the default model
+// parameters.
+func NewPoissonSimulator() *PoissonSimulator {
+	return &PoissonSimulator{
+		BaseGoals:     DefaultBaseGoals,
+		HomeAdvantage: DefaultHomeAdvantage,
+	}
+}
+
+// ExpectedGoals returns the mean number of goals for the home and guest side.
+func (s *PoissonSimulator) ExpectedGoals(home, guest Rating) (float64, float64) {
+	homeXG := s.BaseGoals * home.Attack / guest.Defence * s.HomeAdvantage
+	guestXG := s.BaseGoals * guest.Attack / home.Defence
+	return homeXG, guestXG
+}
+
+// Simulate plays a match and returns its score.
+func (s *PoissonSimulator) Simulate(rng *rand.Rand, home, guest Rating) Result {
+	homeXG, guestXG := s.ExpectedGoals(home, guest)
+	return Result{
+		HomeScore:  poisson(rng, homeXG),
+		GuestScore: poisson(rng, guestXG),
+	}
+}
+
+// poisson samples a Poisson distributed value using Knuth's algorithm, which
+// is plenty fast for the small means seen in football scores.
+func poisson(rng *rand.Rand, lambda float64) int64 {
+	if lambda <= 0 {
+		return 0
+	}
+
+	limit := math.Exp(-lambda)
+	var k int64
+	p := 1.0
+	for {
+		p *= rng.Float64()
+		if p <= limit {
+			return k
+		}
+		k++
+	}
+}
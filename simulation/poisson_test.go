@@ -0,0 +1,55 @@
+package simulation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPoissonSimulatorMatchesExpectedGoals(t *testing.T) {
+	sim := NewPoissonSimulator()
+	tests := []struct {
+		name        string
+		home, guest Rating
+	}{
+		{"equal", RatingFromStrength(5), RatingFromStrength(5)},
+		{"strong home", RatingFromStrength(9), RatingFromStrength(2)},
+		{"strong guest", RatingFromStrength(1), RatingFromStrength(10)},
+	}
+
+	const draws = 20000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			var homeGoals, guestGoals int64
+			for i := 0; i < draws; i++ {
+				result := sim.Simulate(rng, tt.home, tt.guest)
+				homeGoals += result.HomeScore
+				guestGoals += result.GuestScore
+			}
+
+			homeXG, guestXG := sim.ExpectedGoals(tt.home, tt.guest)
+			for _, side := range []struct {
+				name string
+				mean float64
+				xg   float64
+			}{
+				{"home", float64(homeGoals) / draws, homeXG},
+				{"guest", float64(guestGoals) / draws, guestXG},
+			} {
+				// Five standard errors of the mean of a Poisson distribution
+				if tolerance := 5 * math.Sqrt(side.xg/draws); math.Abs(side.mean-side.xg) > tolerance {
+					t.Errorf("%s goals average %.3f, want %.3f ± %.3f", side.name, side.mean, side.xg, tolerance)
+				}
+			}
+		})
+	}
+}
+
+func TestPoissonSimulatorHomeAdvantage(t *testing.T) {
+	sim := NewPoissonSimulator()
+	homeXG, guestXG := sim.ExpectedGoals(RatingFromStrength(5), RatingFromStrength(5))
+	if homeXG <= guestXG {
+		t.Errorf("equal teams expect %.3f home and %.3f guest goals, want more at home", homeXG, guestXG)
+	}
+}
@@ -0,0 +1,51 @@
+package simulation
+
+import "math/rand"
+
+// Rating describes how dangerous a team is going forward and how hard it is
+// to score against.
+type Rating struct {
+	Attack  float64
+	Defence float64
+}
+
+// Result is the final score of a simulated match.
+type Result struct {
+	HomeScore  int64
+	GuestScore int64
+}
+
+// MatchSimulator plays a single match between a home and a guest team. All
+// randomness is drawn from rng so a match can be replayed from its seed.
+type MatchSimulator interface {
+	Simulate(rng *rand.Rand, home, guest Rating) Result
+}
+
+// RatingFromStrength converts the single strength value stored on a team
+// (roughly 1-10) into attack and defence ratings centred around 1.0.
+func RatingFromStrength(strength int64) Rating {
+	r := 0.6 + 0.08*float64(strength)
+	return Rating{Attack: r, Defence: r}
+}
+
+// MatchRand returns the random source for a single match. It depends only on
+// the season seed and the match ID, so replaying a season with the same seed
+// reproduces every score regardless of the order matches are played in.
+func MatchRand(seed, matchID int64) *rand.Rand {
+	return StreamRand(seed, matchID)
+}
+
+// StreamRand returns an independent random source for the given stream of a
+// seed, e.g. one per Monte Carlo iteration.
+func StreamRand(seed, stream int64) *rand.Rand {
+	return rand.New(rand.NewSource(int64(Mix(uint64(seed), uint64(stream)))))
+}
+
+// Mix combines two values into a well distributed 64-bit seed using the
+// SplitMix64 finalizer.
+func Mix(a, b uint64) uint64 {
+	z := a + 0x9e3779b97f4a7c15*(b+1)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -6,8 +6,65 @@ package sqlc
 
 import (
 	"database/sql"
+	"time"
 )
 
+type Competition struct {
+	ID           int64
+	SeasonID     int64
+	Format       string
+	Name         string
+	Legs         int64
+	WinPoints    int64
+	DrawPoints   int64
+	LossPoints   int64
+	BonusGoals   int64
+	BonusPoints  int64
+	RankingRules string
+}
+
+type CompetitionTeam struct {
+	CompetitionID int64
+	TeamID        int64
+}
+
+type Cup struct {
+	ID       int64
+	SeasonID int64
+	Seeded   bool
+}
+
+type CupTie struct {
+	ID             int64
+	CupID          int64
+	Round          int64
+	Slot           int64
+	Week           int64
+	HomeID         sql.NullInt64
+	GuestID        sql.NullInt64
+	HomeScore      sql.NullInt64
+	GuestScore     sql.NullInt64
+	ExtraTime      bool
+	HomePenalties  sql.NullInt64
+	GuestPenalties sql.NullInt64
+	WinnerID       sql.NullInt64
+	Played         bool
+}
+
+type Division struct {
+	ID               int64
+	SeasonID         int64
+	Name             string
+	Tier             int64
+	RelegationPlaces int64
+	Playoff          bool
+}
+
+type DivisionTeam struct {
+	DivisionID int64
+	TeamID     int64
+}
+
 type GameState struct {
 	ID          int64
 	CurrentWeek sql.NullInt64
@@ -15,12 +72,13 @@ type GameState struct {
 }
 
 type Match struct {
-	ID       int64
-	SeasonID int64
-	HomeID   int64
-	GuestID  int64
-	Played   sql.NullBool
-	Week     int64
+	ID         int64
+	SeasonID   int64
+	HomeID     int64
+	GuestID    int64
+	Played     sql.NullBool
+	Week       int64
+	DivisionID sql.NullInt64
 }
 
 type MatchResult struct {
@@ -31,22 +89,49 @@ type MatchResult struct {
 	WinnerID   sql.NullInt64
 }
 
+type Playoff struct {
+	ID          int64
+	SeasonID    int64
+	DivisionID  int64
+	UpperTeamID int64
+	LowerTeamID int64
+	UpperScore  int64
+	LowerScore  int64
+	WinnerID    int64
+}
+
+type Sanction struct {
+	ID            int64
+	CompetitionID int64
+	TeamID        int64
+	Kind          string
+	Points        int64
+	MatchID       sql.NullInt64
+	FirstWeek     int64
+	LastWeek      int64
+	Reason        string
+	CreatedAt     time.Time
+}
+
 type Season struct {
 	ID         int64
 	Year       int64
+	Seed       int64
 	IsCurrent  sql.NullBool
 	IsComplete sql.NullBool
 }
 
 type Standing struct {
-	ID       int64
-	TeamID   int64
-	SeasonID int64
-	Points   sql.NullInt64
-	Wins     sql.NullInt64
-	Draws    sql.NullInt64
-	Losses   sql.NullInt64
-	GoalDiff sql.NullInt64
+	ID           int64
+	TeamID       int64
+	SeasonID     int64
+	Points       sql.NullInt64
+	Wins         sql.NullInt64
+	Draws        sql.NullInt64
+	Losses       sql.NullInt64
+	GoalDiff     sql.NullInt64
+	GoalsFor     sql.NullInt64
+	GoalsAgainst sql.NullInt64
 }
 
 type Team struct {
@@ -56,9 +141,48 @@ type Team struct {
 	Budget   sql.NullInt64
 }
 
-type Teamstat struct {
+type TeamHistory struct {
 	ID                 int64
-	Team               interface{}
-	Value              sql.NullInt64
-	Lastseasonstanding sql.NullInt64
+	TeamID             int64
+	SeasonID           int64
+	LastSeasonStanding int64
+	PrizeMoney         int64
+	StrengthBefore     int64
+	StrengthAfter      int64
+	BudgetBefore       int64
+	BudgetAfter        int64
+}
+
+type Tournament struct {
+	ID                int64
+	SeasonID          int64
+	GroupCount        int64
+	Qualifiers        int64
+	AwayGoals         bool
+	SeparateDivisions bool
+}
+
+type TournamentMatch struct {
+	ID             int64
+	TournamentID   int64
+	Round          int64
+	Slot           int64
+	Leg            int64
+	Week           int64
+	HomeID         sql.NullInt64
+	GuestID        sql.NullInt64
+	HomeScore      sql.NullInt64
+	GuestScore     sql.NullInt64
+	ExtraTime      bool
+	HomePenalties  sql.NullInt64
+	GuestPenalties sql.NullInt64
+	WinnerID       sql.NullInt64
+	Played         bool
+}
+
+type TournamentTeam struct {
+	TournamentID int64
+	TeamID       int64
+	Pot          int64
+	GroupNumber  int64
 }
@@ -0,0 +1,563 @@
+-- name: CreateFixture :exec
+INSERT INTO match (
+  home_id, guest_id, played, week, season_id, division_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+);
+
+-- name: SaveResult :exec
+INSERT INTO match_result (
+  match_id, home_score, guest_score, winner_id
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT(match_id) DO UPDATE SET
+  home_score = excluded.home_score,
+  guest_score = excluded.guest_score,
+  winner_id = excluded.winner_id;
+
+-- name: ListTeams :many
+SELECT * FROM team
+ORDER BY name;
+
+-- name: GetStanding :one
+SELECT * FROM standing
+WHERE team_id = $1 AND season_id = $2
+LIMIT 1;
+
+-- name: UpdateStanding :exec
+UPDATE standing
+SET points = $1,
+    wins = $2,
+    draws = $3,
+    losses = $4,
+    goal_diff = $5,
+    goals_for = $6,
+    goals_against = $7
+WHERE team_id = $8 AND season_id = $9;
+
+-- name: GetTeam :one
+SELECT * FROM team
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetTeamByName :one
+SELECT * FROM team
+WHERE name = $1
+LIMIT 1;
+
+-- name: CreateTeam :one
+INSERT INTO team (
+  name, strength, budget
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: UpdateTeamStrength :exec
+UPDATE team
+SET strength = $1
+WHERE id = $2;
+
+-- name: UpdateTeam :exec
+UPDATE team
+SET name = $1, strength = $2, budget = $3
+WHERE id = $4;
+
+-- name: DeleteTeam :exec
+DELETE FROM team
+WHERE id = $1;
+
+-- name: DeleteTeamStandings :exec
+DELETE FROM standing
+WHERE team_id = $1;
+
+-- name: CountTeamMatches :one
+SELECT COUNT(*) FROM match
+WHERE home_id = sqlc.arg(team_id) OR guest_id = sqlc.arg(team_id);
+
+-- name: CreateTeamHistory :exec
+INSERT INTO team_history (
+  team_id, season_id, last_season_standing, prize_money, strength_before, strength_after, budget_before, budget_after
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: ListTeamHistory :many
+SELECT th.*, s.year AS season_year
+FROM team_history th
+JOIN season s ON s.id = th.season_id
+ORDER BY th.team_id, s.year, th.id;
+
+-- name: ListHeadToHeadSeasons :many
+SELECT s.id AS season_id, s.year AS season_year,
+       CAST(COUNT(*) AS BIGINT) AS played,
+       CAST(SUM(CASE WHEN h.goals_for > h.goals_against THEN 1 ELSE 0 END) AS BIGINT) AS wins,
+       CAST(SUM(CASE WHEN h.goals_for = h.goals_against THEN 1 ELSE 0 END) AS BIGINT) AS draws,
+       CAST(SUM(CASE WHEN h.goals_for < h.goals_against THEN 1 ELSE 0 END) AS BIGINT) AS losses,
+       CAST(SUM(h.goals_for) AS BIGINT) AS goals_for,
+       CAST(SUM(h.goals_against) AS BIGINT) AS goals_against
+FROM (
+    SELECT m.season_id, mr.home_score AS goals_for, mr.guest_score AS goals_against
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.home_id = sqlc.arg(team_id) AND m.guest_id = sqlc.arg(opponent_id) AND m.played = TRUE
+    UNION ALL
+    SELECT m.season_id, mr.guest_score AS goals_for, mr.home_score AS goals_against
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.home_id = sqlc.arg(opponent_id) AND m.guest_id = sqlc.arg(team_id) AND m.played = TRUE
+) h
+JOIN season s ON s.id = h.season_id
+GROUP BY s.id, s.year
+ORDER BY s.year;
+
+-- name: ListHeadToHeadMatches :many
+SELECT m.id, m.season_id, s.year AS season_year, m.week, m.home_id, m.guest_id, mr.home_score, mr.guest_score
+FROM match m
+JOIN match_result mr ON mr.match_id = m.id
+JOIN season s ON s.id = m.season_id
+WHERE m.played = TRUE
+  AND ((m.home_id = sqlc.arg(team_id) AND m.guest_id = sqlc.arg(opponent_id))
+    OR (m.home_id = sqlc.arg(opponent_id) AND m.guest_id = sqlc.arg(team_id)))
+ORDER BY s.year DESC, m.week DESC, m.id DESC;
+
+-- name: GetMatchesByWeek :many
+SELECT m.*, 
+       ht.name as home_team_name, 
+       gt.name as guest_team_name,
+       ht.strength as home_team_strength,
+       gt.strength as guest_team_strength
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+WHERE m.week = $1 AND m.season_id = $2;
+
+-- name: GetMatchResult :one
+SELECT mr.*, 
+       ht.name as home_team_name, 
+       gt.name as guest_team_name
+FROM match_result mr
+JOIN match m ON mr.match_id = m.id
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+WHERE mr.match_id = $1;
+
+-- name: GetUnplayedMatchesByWeek :many
+SELECT m.*, 
+       ht.name as home_team_name, 
+       gt.name as guest_team_name,
+       ht.strength as home_team_strength,
+       gt.strength as guest_team_strength
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+WHERE m.week = $1 AND m.played = FALSE AND m.season_id = $2;
+
+-- name: GetUnplayedMatchesBySeason :many
+SELECT * FROM match
+WHERE played = FALSE AND season_id = $1
+ORDER BY week, id;
+
+-- name: GetSeasonWeeks :one
+SELECT CAST(COALESCE(MAX(week), 0) AS BIGINT) AS weeks FROM match WHERE season_id = $1;
+
+-- name: DeleteUnplayedMatchesBySeason :exec
+DELETE FROM match WHERE season_id = $1 AND played = FALSE;
+
+-- name: GetMatchesBySeason :many
+SELECT m.*,
+       ht.name as home_team_name,
+       gt.name as guest_team_name,
+       mr.home_score,
+       mr.guest_score
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+LEFT JOIN match_result mr ON mr.match_id = m.id
+WHERE m.season_id = $1
+ORDER BY m.week, m.id;
+
+-- name: GetMatchesByTeam :many
+SELECT m.*,
+       ht.name as home_team_name,
+       gt.name as guest_team_name,
+       mr.home_score,
+       mr.guest_score
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+LEFT JOIN match_result mr ON mr.match_id = m.id
+WHERE m.season_id = sqlc.arg(season_id) AND (m.home_id = sqlc.arg(team_id) OR m.guest_id = sqlc.arg(team_id))
+ORDER BY m.week, m.id;
+
+-- name: GetResultsBySeason :many
+SELECT m.id, m.week, m.home_id, m.guest_id, mr.home_score, mr.guest_score
+FROM match m
+JOIN match_result mr ON mr.match_id = m.id
+WHERE m.season_id = $1 AND m.played = TRUE
+ORDER BY m.week, m.id;
+
+-- name: MarkMatchAsPlayed :exec
+UPDATE match SET played = TRUE WHERE id = $1;
+
+-- name: CreateStanding :exec
+INSERT INTO standing (
+  team_id, season_id, points, wins, draws, losses, goal_diff, goals_for, goals_against
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+);
+
+-- name: GetCurrentWeek :one
+SELECT current_week FROM game_state WHERE season_id = $1 LIMIT 1;
+
+-- name: IncrementWeek :exec
+UPDATE game_state SET current_week = current_week + 1 WHERE season_id = $1;
+
+-- name: GetAllMatchesPlayedForWeek :one
+SELECT COUNT(*) = 0 as all_played FROM match WHERE week = $1 AND played = FALSE AND season_id = $2;
+
+-- name: GetCurrentSeason :one
+SELECT * FROM season WHERE is_current = TRUE LIMIT 1;
+
+-- name: GetSeason :one
+SELECT * FROM season WHERE id = $1;
+
+-- name: ListSeasons :many
+SELECT * FROM season ORDER BY year, id;
+
+-- name: CreateNewSeason :one
+INSERT INTO season (year, seed, is_current, is_complete) VALUES ($1, $2, FALSE, FALSE) RETURNING *;
+
+-- name: SetCurrentSeason :exec
+UPDATE season SET is_current = (season.id = $1) WHERE season.id IN (SELECT id FROM season);
+
+-- name: CompleteSeason :exec
+UPDATE season SET is_complete = TRUE WHERE id = $1;
+
+-- name: ReopenSeason :exec
+UPDATE season SET is_complete = FALSE WHERE id = $1;
+
+-- name: DeleteResultsFromWeek :exec
+DELETE FROM match_result
+WHERE match_id IN (SELECT id FROM match WHERE season_id = $1 AND week >= $2);
+
+-- name: UnplayMatchesFromWeek :exec
+UPDATE match SET played = FALSE WHERE season_id = $1 AND week >= $2;
+
+-- name: SetCurrentWeek :exec
+UPDATE game_state SET current_week = $1 WHERE season_id = $2;
+
+-- name: InitializeGameState :exec
+INSERT INTO game_state (current_week, season_id) VALUES (1, $1);
+
+-- name: CreateDivision :one
+INSERT INTO division (
+  season_id, name, tier, relegation_places, playoff
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetDivision :one
+SELECT * FROM division WHERE id = $1;
+
+-- name: ListDivisions :many
+SELECT * FROM division WHERE season_id = $1 ORDER BY tier, id;
+
+-- name: UpdateDivision :exec
+UPDATE division
+SET name = $1, tier = $2, relegation_places = $3, playoff = $4
+WHERE id = $5;
+
+-- name: DeleteDivision :exec
+DELETE FROM division WHERE id = $1;
+
+-- name: AddDivisionTeam :exec
+INSERT INTO division_team (division_id, team_id) VALUES ($1, $2);
+
+-- name: RemoveDivisionTeam :exec
+DELETE FROM division_team
+WHERE team_id = $1 AND division_id IN (SELECT id FROM division WHERE season_id = $2);
+
+-- name: DeleteTeamDivisions :exec
+DELETE FROM division_team WHERE team_id = $1;
+
+-- name: ListDivisionTeams :many
+SELECT dt.*
+FROM division_team dt
+JOIN division d ON d.id = dt.division_id
+WHERE d.season_id = $1
+ORDER BY d.tier, dt.team_id;
+
+-- name: CreatePlayoff :exec
+INSERT INTO playoff (
+  season_id, division_id, upper_team_id, lower_team_id, upper_score, lower_score, winner_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: ListPlayoffsBySeason :many
+SELECT p.*,
+       ut.name as upper_team_name,
+       lt.name as lower_team_name
+FROM playoff p
+JOIN team ut ON p.upper_team_id = ut.id
+JOIN team lt ON p.lower_team_id = lt.id
+WHERE p.season_id = $1
+ORDER BY p.id;
+
+-- name: DeletePlayoffsBySeason :exec
+DELETE FROM playoff WHERE season_id = $1;
+
+-- name: CreateCup :one
+INSERT INTO cup (season_id, seeded) VALUES ($1, $2) RETURNING *;
+
+-- name: GetCupBySeason :one
+SELECT * FROM cup WHERE season_id = $1;
+
+-- name: DeleteCup :exec
+DELETE FROM cup WHERE id = $1;
+
+-- name: CreateCupTie :exec
+INSERT INTO cup_tie (
+  cup_id, round, slot, week, home_id, guest_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListCupTies :many
+SELECT ct.*,
+       ht.name as home_team_name,
+       gt.name as guest_team_name
+FROM cup_tie ct
+LEFT JOIN team ht ON ct.home_id = ht.id
+LEFT JOIN team gt ON ct.guest_id = gt.id
+WHERE ct.cup_id = $1
+ORDER BY ct.round, ct.slot;
+
+-- name: SetCupTieTeams :exec
+UPDATE cup_tie SET home_id = $1, guest_id = $2 WHERE id = $3;
+
+-- name: SaveCupTieResult :exec
+UPDATE cup_tie
+SET home_score = $1,
+    guest_score = $2,
+    extra_time = $3,
+    home_penalties = $4,
+    guest_penalties = $5,
+    winner_id = $6,
+    played = TRUE
+WHERE id = $7;
+
+-- name: DeleteCupTies :exec
+DELETE FROM cup_tie WHERE cup_id = $1;
+
+-- name: ResetCupTiesFromWeek :exec
+UPDATE cup_tie
+SET home_score = NULL,
+    guest_score = NULL,
+    extra_time = FALSE,
+    home_penalties = NULL,
+    guest_penalties = NULL,
+    winner_id = NULL,
+    played = FALSE
+WHERE week >= sqlc.arg(week) AND cup_id IN (SELECT id FROM cup WHERE season_id = sqlc.arg(season_id));
+
+-- name: ClearCupTieTeamsFromWeek :exec
+UPDATE cup_tie
+SET home_id = NULL, guest_id = NULL
+WHERE cup_id IN (SELECT id FROM cup WHERE season_id = sqlc.arg(season_id))
+  AND round > (
+    SELECT MIN(ct.round) FROM cup_tie ct
+    JOIN cup c ON c.id = ct.cup_id
+    WHERE c.season_id = sqlc.arg(season_id) AND ct.week >= sqlc.arg(week)
+  );
+
+-- name: CreateTournament :one
+INSERT INTO tournament (
+  season_id, group_count, qualifiers, away_goals, separate_divisions
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetTournamentBySeason :one
+SELECT * FROM tournament WHERE season_id = $1;
+
+-- name: DeleteTournament :exec
+DELETE FROM tournament WHERE id = $1;
+
+-- name: CreateTournamentTeam :exec
+INSERT INTO tournament_team (
+  tournament_id, team_id, pot, group_number
+) VALUES (
+  $1, $2, $3, $4
+);
+
+-- name: ListTournamentTeams :many
+SELECT tt.*, t.name as team_name
+FROM tournament_team tt
+JOIN team t ON tt.team_id = t.id
+WHERE tt.tournament_id = $1
+ORDER BY tt.group_number, tt.pot;
+
+-- name: DeleteTournamentTeams :exec
+DELETE FROM tournament_team WHERE tournament_id = $1;
+
+-- name: CreateTournamentMatch :exec
+INSERT INTO tournament_match (
+  tournament_id, round, slot, leg, week, home_id, guest_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: ListTournamentMatches :many
+SELECT tm.*,
+       ht.name as home_team_name,
+       gt.name as guest_team_name
+FROM tournament_match tm
+LEFT JOIN team ht ON tm.home_id = ht.id
+LEFT JOIN team gt ON tm.guest_id = gt.id
+WHERE tm.tournament_id = $1
+ORDER BY tm.round, tm.slot, tm.leg, tm.id;
+
+-- name: SetTournamentMatchTeams :exec
+UPDATE tournament_match SET home_id = $1, guest_id = $2 WHERE id = $3;
+
+-- name: SaveTournamentMatchResult :exec
+UPDATE tournament_match
+SET home_score = $1,
+    guest_score = $2,
+    extra_time = $3,
+    home_penalties = $4,
+    guest_penalties = $5,
+    winner_id = $6,
+    played = TRUE
+WHERE id = $7;
+
+-- name: DeleteTournamentMatches :exec
+DELETE FROM tournament_match WHERE tournament_id = $1;
+
+-- name: ResetTournamentMatchesFromWeek :exec
+UPDATE tournament_match
+SET home_score = NULL,
+    guest_score = NULL,
+    extra_time = FALSE,
+    home_penalties = NULL,
+    guest_penalties = NULL,
+    winner_id = NULL,
+    played = FALSE
+WHERE week >= sqlc.arg(week) AND tournament_id IN (SELECT id FROM tournament WHERE season_id = sqlc.arg(season_id));
+
+-- name: ClearTournamentMatchTeamsFromWeek :exec
+UPDATE tournament_match
+SET home_id = NULL, guest_id = NULL
+WHERE tournament_id IN (SELECT id FROM tournament WHERE season_id = sqlc.arg(season_id))
+  AND round > 0
+  AND round > (
+    SELECT MIN(tm.round) FROM tournament_match tm
+    JOIN tournament t ON t.id = tm.tournament_id
+    WHERE t.season_id = sqlc.arg(season_id) AND tm.week >= sqlc.arg(week)
+  );
+
+-- name: CreateCompetition :one
+INSERT INTO competition (
+  season_id, format, name, legs, win_points, draw_points, loss_points, bonus_goals, bonus_points, ranking_rules
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetCompetition :one
+SELECT * FROM competition WHERE id = $1;
+
+-- name: GetCompetitionBySeason :one
+SELECT * FROM competition WHERE season_id = $1 AND format = $2;
+
+-- name: ListCompetitions :many
+SELECT * FROM competition WHERE season_id = $1 ORDER BY id;
+
+-- name: UpdateCompetitionRules :exec
+UPDATE competition
+SET legs = $1,
+    win_points = $2,
+    draw_points = $3,
+    loss_points = $4,
+    bonus_goals = $5,
+    bonus_points = $6,
+    ranking_rules = $7
+WHERE id = $8;
+
+-- name: SetCompetitionRankingRules :exec
+UPDATE competition SET ranking_rules = $1 WHERE id = $2;
+
+-- name: CreateCompetitionTeam :exec
+INSERT INTO competition_team (
+  competition_id, team_id
+) VALUES (
+  $1, $2
+);
+
+-- name: ListCompetitionTeams :many
+SELECT ct.*, t.name as team_name
+FROM competition_team ct
+JOIN team t ON ct.team_id = t.id
+WHERE ct.competition_id = $1
+ORDER BY t.name;
+
+-- name: DeleteCompetitionTeams :exec
+DELETE FROM competition_team WHERE competition_id = $1;
+
+-- name: DeleteTeamCompetitions :exec
+DELETE FROM competition_team WHERE team_id = $1;
+
+-- name: CreateSanction :one
+INSERT INTO sanction (
+  competition_id, team_id, kind, points, match_id, first_week, last_week, reason, created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetSanction :one
+SELECT * FROM sanction WHERE id = $1;
+
+-- name: ListSanctions :many
+SELECT s.*, c.format, t.name as team_name
+FROM sanction s
+JOIN competition c ON s.competition_id = c.id
+JOIN team t ON s.team_id = t.id
+WHERE c.season_id = $1
+ORDER BY s.created_at, s.id;
+
+-- name: DeleteSanction :exec
+DELETE FROM sanction WHERE id = $1;
+
+-- name: DeleteTeamSanctions :exec
+DELETE FROM sanction WHERE team_id = $1;
+
+-- name: ComputeStandings :many
+SELECT sqlc.embed(t),
+       CAST(COUNT(r.match_id) AS BIGINT) AS played,
+       CAST(COALESCE(SUM(CASE WHEN r.goals_for > r.goals_against THEN 1 ELSE 0 END), 0) AS BIGINT) AS wins,
+       CAST(COALESCE(SUM(CASE WHEN r.goals_for = r.goals_against THEN 1 ELSE 0 END), 0) AS BIGINT) AS draws,
+       CAST(COALESCE(SUM(CASE WHEN r.goals_for < r.goals_against THEN 1 ELSE 0 END), 0) AS BIGINT) AS losses,
+       CAST(COALESCE(SUM(r.goals_for), 0) AS BIGINT) AS goals_for,
+       CAST(COALESCE(SUM(r.goals_against), 0) AS BIGINT) AS goals_against,
+       CAST(COALESCE(SUM(r.goals_for - r.goals_against), 0) AS BIGINT) AS goal_diff,
+       CAST(COALESCE(SUM(CASE WHEN r.is_away THEN r.goals_for ELSE 0 END), 0) AS BIGINT) AS away_goals_for
+FROM team t
+LEFT JOIN (
+    SELECT m.id AS match_id, m.home_id AS team_id, mr.home_score AS goals_for, mr.guest_score AS goals_against, FALSE AS is_away
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.season_id = sqlc.arg(season_id) AND m.played = TRUE
+    UNION ALL
+    SELECT m.id AS match_id, m.guest_id AS team_id, mr.guest_score AS goals_for, mr.home_score AS goals_against, TRUE AS is_away
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.season_id = sqlc.arg(season_id) AND m.played = TRUE
+) r ON r.team_id = t.id
+GROUP BY t.id
+ORDER BY t.name;
@@ -1,8 +1,8 @@
 -- name: CreateFixture :exec
 INSERT INTO match (
-  home_id, guest_id, played, week, season_id
+  home_id, guest_id, played, week, season_id, division_id
 ) VALUES (
-  ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?
 );
 
 -- name: SaveResult :exec
@@ -31,7 +31,9 @@ SET points = ?,
     wins = ?,
     draws = ?,
     losses = ?,
-    goal_diff = ?
+    goal_diff = ?,
+    goals_for = ?,
+    goals_against = ?
 WHERE team_id = ? AND season_id = ?;
 
 -- name: GetTeam :one
@@ -57,10 +59,69 @@ UPDATE team
 SET strength = ?
 WHERE id = ?;
 
+-- name: UpdateTeam :exec
+UPDATE team
+SET name = ?, strength = ?, budget = ?
+WHERE id = ?;
+
 -- name: DeleteTeam :exec
 DELETE FROM team
 WHERE id = ?;
 
+-- name: DeleteTeamStandings :exec
+DELETE FROM standing
+WHERE team_id = ?;
+
+-- name: CountTeamMatches :one
+SELECT COUNT(*) FROM match
+WHERE home_id = sqlc.arg(team_id) OR guest_id = sqlc.arg(team_id);
+
+-- name: CreateTeamHistory :exec
+INSERT INTO team_history (
+  team_id, season_id, last_season_standing, prize_money, strength_before, strength_after, budget_before, budget_after
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?
+);
+
+-- name: ListTeamHistory :many
+SELECT th.*, s.year AS season_year
+FROM team_history th
+JOIN season s ON s.id = th.season_id
+ORDER BY th.team_id, s.year, th.id;
+
+-- name: ListHeadToHeadSeasons :many
+SELECT s.id AS season_id, s.year AS season_year,
+       CAST(COUNT(*) AS INTEGER) AS played,
+       CAST(SUM(h.goals_for > h.goals_against) AS INTEGER) AS wins,
+       CAST(SUM(h.goals_for = h.goals_against) AS INTEGER) AS draws,
+       CAST(SUM(h.goals_for < h.goals_against) AS INTEGER) AS losses,
+       CAST(SUM(h.goals_for) AS INTEGER) AS goals_for,
+       CAST(SUM(h.goals_against) AS INTEGER) AS goals_against
+FROM (
+    SELECT m.season_id, mr.home_score AS goals_for, mr.guest_score AS goals_against
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.home_id = sqlc.arg(team_id) AND m.guest_id = sqlc.arg(opponent_id) AND m.played = TRUE
+    UNION ALL
+    SELECT m.season_id, mr.guest_score AS goals_for, mr.home_score AS goals_against
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.home_id = sqlc.arg(opponent_id) AND m.guest_id = sqlc.arg(team_id) AND m.played = TRUE
+) h
+JOIN season s ON s.id = h.season_id
+GROUP BY s.id, s.year
+ORDER BY s.year;
+
+-- name: ListHeadToHeadMatches :many
+SELECT m.id, m.season_id, s.year AS season_year, m.week, m.home_id, m.guest_id, mr.home_score, mr.guest_score
+FROM match m
+JOIN match_result mr ON mr.match_id = m.id
+JOIN season s ON s.id = m.season_id
+WHERE m.played = TRUE
+  AND ((m.home_id = sqlc.arg(team_id) AND m.guest_id = sqlc.arg(opponent_id))
+    OR (m.home_id = sqlc.arg(opponent_id) AND m.guest_id = sqlc.arg(team_id)))
+ORDER BY s.year DESC, m.week DESC, m.id DESC;
+
 -- name: GetMatchesByWeek :many
 SELECT m.*, 
        ht.name as home_team_name, 
@@ -93,14 +154,58 @@ JOIN team ht ON m.home_id = ht.id
 JOIN team gt ON m.guest_id = gt.id
 WHERE m.week = ? AND m.played = FALSE AND m.season_id = ?;
 
+-- name: GetUnplayedMatchesBySeason :many
+SELECT * FROM match
+WHERE played = FALSE AND season_id = ?
+ORDER BY week, id;
+
+-- name: GetSeasonWeeks :one
+SELECT CAST(COALESCE(MAX(week), 0) AS INTEGER) AS weeks FROM match WHERE season_id = ?;
+
+-- name: DeleteUnplayedMatchesBySeason :exec
+DELETE FROM match WHERE season_id = ? AND played = FALSE;
+
+-- name: GetMatchesBySeason :many
+SELECT m.*,
+       ht.name as home_team_name,
+       gt.name as guest_team_name,
+       mr.home_score,
+       mr.guest_score
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+LEFT JOIN match_result mr ON mr.match_id = m.id
+WHERE m.season_id = ?
+ORDER BY m.week, m.id;
+
+-- name: GetMatchesByTeam :many
+SELECT m.*,
+       ht.name as home_team_name,
+       gt.name as guest_team_name,
+       mr.home_score,
+       mr.guest_score
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+LEFT JOIN match_result mr ON mr.match_id = m.id
+WHERE m.season_id = sqlc.arg(season_id) AND (m.home_id = sqlc.arg(team_id) OR m.guest_id = sqlc.arg(team_id))
+ORDER BY m.week, m.id;
+
+-- name: GetResultsBySeason :many
+SELECT m.id, m.week, m.home_id, m.guest_id, mr.home_score, mr.guest_score
+FROM match m
+JOIN match_result mr ON mr.match_id = m.id
+WHERE m.season_id = ? AND m.played = TRUE
+ORDER BY m.week, m.id;
+
 -- name: MarkMatchAsPlayed :exec
 UPDATE match SET played = TRUE WHERE id = ?;
 
 -- name: CreateStanding :exec
 INSERT INTO standing (
-  team_id, season_id, points, wins, draws, losses, goal_diff
+  team_id, season_id, points, wins, draws, losses, goal_diff, goals_for, goals_against
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?, ?, ?, ?
 );
 
 -- name: GetCurrentWeek :one
@@ -115,8 +220,14 @@ SELECT COUNT(*) = 0 as all_played FROM match WHERE week = ? AND played = FALSE A
 -- name: GetCurrentSeason :one
 SELECT * FROM season WHERE is_current = TRUE LIMIT 1;
 
+-- name: GetSeason :one
+SELECT * FROM season WHERE id = ?;
+
+-- name: ListSeasons :many
+SELECT * FROM season ORDER BY year, id;
+
 -- name: CreateNewSeason :one
-INSERT INTO season (year, is_current, is_complete) VALUES (?, FALSE, FALSE) RETURNING *;
+INSERT INTO season (year, seed, is_current, is_complete) VALUES (?, ?, FALSE, FALSE) RETURNING *;
 
 -- name: SetCurrentSeason :exec
 UPDATE season SET is_current = (season.id = ?) WHERE season.id IN (SELECT id FROM season);
@@ -124,11 +235,329 @@ UPDATE season SET is_current = (season.id = ?) WHERE season.id IN (SELECT id FRO
 -- name: CompleteSeason :exec
 UPDATE season SET is_complete = TRUE WHERE id = ?;
 
--- name: ResetToYear :exec
-DELETE FROM match_result;
-DELETE FROM match;
-DELETE FROM standing;
-DELETE FROM game_state;
-DELETE FROM season WHERE year != ?;
-UPDATE season SET is_complete = FALSE, is_current = TRUE WHERE year = ?;
-INSERT INTO game_state (current_week, season_id) SELECT 1, id FROM season WHERE is_current = TRUE;
+-- name: ReopenSeason :exec
+UPDATE season SET is_complete = FALSE WHERE id = ?;
+
+-- name: DeleteResultsFromWeek :exec
+DELETE FROM match_result
+WHERE match_id IN (SELECT id FROM match WHERE season_id = ? AND week >= ?);
+
+-- name: UnplayMatchesFromWeek :exec
+UPDATE match SET played = FALSE WHERE season_id = ? AND week >= ?;
+
+-- name: SetCurrentWeek :exec
+UPDATE game_state SET current_week = ? WHERE season_id = ?;
+
+-- name: InitializeGameState :exec
+INSERT INTO game_state (current_week, season_id) VALUES (1, ?);
+
+-- name: CreateDivision :one
+INSERT INTO division (
+  season_id, name, tier, relegation_places, playoff
+) VALUES (
+  ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: GetDivision :one
+SELECT * FROM division WHERE id = ?;
+
+-- name: ListDivisions :many
+SELECT * FROM division WHERE season_id = ? ORDER BY tier, id;
+
+-- name: UpdateDivision :exec
+UPDATE division
+SET name = ?, tier = ?, relegation_places = ?, playoff = ?
+WHERE id = ?;
+
+-- name: DeleteDivision :exec
+DELETE FROM division WHERE id = ?;
+
+-- name: AddDivisionTeam :exec
+INSERT INTO division_team (division_id, team_id) VALUES (?, ?);
+
+-- name: RemoveDivisionTeam :exec
+DELETE FROM division_team
+WHERE team_id = ? AND division_id IN (SELECT id FROM division WHERE season_id = ?);
+
+-- name: DeleteTeamDivisions :exec
+DELETE FROM division_team WHERE team_id = ?;
+
+-- name: ListDivisionTeams :many
+SELECT dt.*
+FROM division_team dt
+JOIN division d ON d.id = dt.division_id
+WHERE d.season_id = ?
+ORDER BY d.tier, dt.team_id;
+
+-- name: CreatePlayoff :exec
+INSERT INTO playoff (
+  season_id, division_id, upper_team_id, lower_team_id, upper_score, lower_score, winner_id
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?
+);
+
+-- name: ListPlayoffsBySeason :many
+SELECT p.*,
+       ut.name as upper_team_name,
+       lt.name as lower_team_name
+FROM playoff p
+JOIN team ut ON p.upper_team_id = ut.id
+JOIN team lt ON p.lower_team_id = lt.id
+WHERE p.season_id = ?
+ORDER BY p.id;
+
+-- name: DeletePlayoffsBySeason :exec
+DELETE FROM playoff WHERE season_id = ?;
+
+-- name: CreateCup :one
+INSERT INTO cup (season_id, seeded) VALUES (?, ?) RETURNING *;
+
+-- name: GetCupBySeason :one
+SELECT * FROM cup WHERE season_id = ?;
+
+-- name: DeleteCup :exec
+DELETE FROM cup WHERE id = ?;
+
+-- name: CreateCupTie :exec
+INSERT INTO cup_tie (
+  cup_id, round, slot, week, home_id, guest_id
+) VALUES (
+  ?, ?, ?, ?, ?, ?
+);
+
+-- name: ListCupTies :many
+SELECT ct.*,
+       ht.name as home_team_name,
+       gt.name as guest_team_name
+FROM cup_tie ct
+LEFT JOIN team ht ON ct.home_id = ht.id
+LEFT JOIN team gt ON ct.guest_id = gt.id
+WHERE ct.cup_id = ?
+ORDER BY ct.round, ct.slot;
+
+-- name: SetCupTieTeams :exec
+UPDATE cup_tie SET home_id = ?, guest_id = ? WHERE id = ?;
+
+-- name: SaveCupTieResult :exec
+UPDATE cup_tie
+SET home_score = ?,
+    guest_score = ?,
+    extra_time = ?,
+    home_penalties = ?,
+    guest_penalties = ?,
+    winner_id = ?,
+    played = TRUE
+WHERE id = ?;
+
+-- name: DeleteCupTies :exec
+DELETE FROM cup_tie WHERE cup_id = ?;
+
+-- name: ResetCupTiesFromWeek :exec
+UPDATE cup_tie
+SET home_score = NULL,
+    guest_score = NULL,
+    extra_time = FALSE,
+    home_penalties = NULL,
+    guest_penalties = NULL,
+    winner_id = NULL,
+    played = FALSE
+WHERE week >= sqlc.arg(week) AND cup_id IN (SELECT id FROM cup WHERE season_id = sqlc.arg(season_id));
+
+-- name: ClearCupTieTeamsFromWeek :exec
+UPDATE cup_tie
+SET home_id = NULL, guest_id = NULL
+WHERE cup_id IN (SELECT id FROM cup WHERE season_id = sqlc.arg(season_id))
+  AND round > (
+    SELECT MIN(ct.round) FROM cup_tie ct
+    JOIN cup c ON c.id = ct.cup_id
+    WHERE c.season_id = sqlc.arg(season_id) AND ct.week >= sqlc.arg(week)
+  );
+
+-- name: CreateTournament :one
+INSERT INTO tournament (
+  season_id, group_count, qualifiers, away_goals, separate_divisions
+) VALUES (
+  ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: GetTournamentBySeason :one
+SELECT * FROM tournament WHERE season_id = ?;
+
+-- name: DeleteTournament :exec
+DELETE FROM tournament WHERE id = ?;
+
+-- name: CreateTournamentTeam :exec
+INSERT INTO tournament_team (
+  tournament_id, team_id, pot, group_number
+) VALUES (
+  ?, ?, ?, ?
+);
+
+-- name: ListTournamentTeams :many
+SELECT tt.*, t.name as team_name
+FROM tournament_team tt
+JOIN team t ON tt.team_id = t.id
+WHERE tt.tournament_id = ?
+ORDER BY tt.group_number, tt.pot;
+
+-- name: DeleteTournamentTeams :exec
+DELETE FROM tournament_team WHERE tournament_id = ?;
+
+-- name: CreateTournamentMatch :exec
+INSERT INTO tournament_match (
+  tournament_id, round, slot, leg, week, home_id, guest_id
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?
+);
+
+-- name: ListTournamentMatches :many
+SELECT tm.*,
+       ht.name as home_team_name,
+       gt.name as guest_team_name
+FROM tournament_match tm
+LEFT JOIN team ht ON tm.home_id = ht.id
+LEFT JOIN team gt ON tm.guest_id = gt.id
+WHERE tm.tournament_id = ?
+ORDER BY tm.round, tm.slot, tm.leg, tm.id;
+
+-- name: SetTournamentMatchTeams :exec
+UPDATE tournament_match SET home_id = ?, guest_id = ? WHERE id = ?;
+
+-- name: SaveTournamentMatchResult :exec
+UPDATE tournament_match
+SET home_score = ?,
+    guest_score = ?,
+    extra_time = ?,
+    home_penalties = ?,
+    guest_penalties = ?,
+    winner_id = ?,
+    played = TRUE
+WHERE id = ?;
+
+-- name: DeleteTournamentMatches :exec
+DELETE FROM tournament_match WHERE tournament_id = ?;
+
+-- name: ResetTournamentMatchesFromWeek :exec
+UPDATE tournament_match
+SET home_score = NULL,
+    guest_score = NULL,
+    extra_time = FALSE,
+    home_penalties = NULL,
+    guest_penalties = NULL,
+    winner_id = NULL,
+    played = FALSE
+WHERE week >= sqlc.arg(week) AND tournament_id IN (SELECT id FROM tournament WHERE season_id = sqlc.arg(season_id));
+
+-- name: ClearTournamentMatchTeamsFromWeek :exec
+UPDATE tournament_match
+SET home_id = NULL, guest_id = NULL
+WHERE tournament_id IN (SELECT id FROM tournament WHERE season_id = sqlc.arg(season_id))
+  AND round > 0
+  AND round > (
+    SELECT MIN(tm.round) FROM tournament_match tm
+    JOIN tournament t ON t.id = tm.tournament_id
+    WHERE t.season_id = sqlc.arg(season_id) AND tm.week >= sqlc.arg(week)
+  );
+
+-- name: CreateCompetition :one
+INSERT INTO competition (
+  season_id, format, name, legs, win_points, draw_points, loss_points, bonus_goals, bonus_points, ranking_rules
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: GetCompetition :one
+SELECT * FROM competition WHERE id = ?;
+
+-- name: GetCompetitionBySeason :one
+SELECT * FROM competition WHERE season_id = ? AND format = ?;
+
+-- name: ListCompetitions :many
+SELECT * FROM competition WHERE season_id = ? ORDER BY id;
+
+-- name: UpdateCompetitionRules :exec
+UPDATE competition
+SET legs = ?,
+    win_points = ?,
+    draw_points = ?,
+    loss_points = ?,
+    bonus_goals = ?,
+    bonus_points = ?,
+    ranking_rules = ?
+WHERE id = ?;
+
+-- name: SetCompetitionRankingRules :exec
+UPDATE competition SET ranking_rules = ? WHERE id = ?;
+
+-- name: CreateCompetitionTeam :exec
+INSERT INTO competition_team (
+  competition_id, team_id
+) VALUES (
+  ?, ?
+);
+
+-- name: ListCompetitionTeams :many
+SELECT ct.*, t.name as team_name
+FROM competition_team ct
+JOIN team t ON ct.team_id = t.id
+WHERE ct.competition_id = ?
+ORDER BY t.name;
+
+-- name: DeleteCompetitionTeams :exec
+DELETE FROM competition_team WHERE competition_id = ?;
+
+-- name: DeleteTeamCompetitions :exec
+DELETE FROM competition_team WHERE team_id = ?;
+
+-- name: CreateSanction :one
+INSERT INTO sanction (
+  competition_id, team_id, kind, points, match_id, first_week, last_week, reason, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: GetSanction :one
+SELECT * FROM sanction WHERE id = ?;
+
+-- name: ListSanctions :many
+SELECT s.*, c.format, t.name as team_name
+FROM sanction s
+JOIN competition c ON s.competition_id = c.id
+JOIN team t ON s.team_id = t.id
+WHERE c.season_id = ?
+ORDER BY s.created_at, s.id;
+
+-- name: DeleteSanction :exec
+DELETE FROM sanction WHERE id = ?;
+
+-- name: DeleteTeamSanctions :exec
+DELETE FROM sanction WHERE team_id = ?;
+
+-- name: ComputeStandings :many
+SELECT sqlc.embed(t),
+       CAST(COUNT(r.match_id) AS INTEGER) AS played,
+       CAST(COALESCE(SUM(r.goals_for > r.goals_against), 0) AS INTEGER) AS wins,
+       CAST(COALESCE(SUM(r.goals_for = r.goals_against), 0) AS INTEGER) AS draws,
+       CAST(COALESCE(SUM(r.goals_for < r.goals_against), 0) AS INTEGER) AS losses,
+       CAST(COALESCE(SUM(r.goals_for), 0) AS INTEGER) AS goals_for,
+       CAST(COALESCE(SUM(r.goals_against), 0) AS INTEGER) AS goals_against,
+       CAST(COALESCE(SUM(r.goals_for - r.goals_against), 0) AS INTEGER) AS goal_diff,
+       CAST(COALESCE(SUM(CASE WHEN r.is_away THEN r.goals_for ELSE 0 END), 0) AS INTEGER) AS away_goals_for
+FROM team t
+LEFT JOIN (
+    SELECT m.id AS match_id, m.home_id AS team_id, mr.home_score AS goals_for, mr.guest_score AS goals_against, FALSE AS is_away
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.season_id = sqlc.arg(season_id) AND m.played = TRUE
+    UNION ALL
+    SELECT m.id AS match_id, m.guest_id AS team_id, mr.guest_score AS goals_for, mr.home_score AS goals_against, TRUE AS is_away
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.season_id = sqlc.arg(season_id) AND m.played = TRUE
+) r ON r.team_id = t.id
+GROUP BY t.id
+ORDER BY t.name;
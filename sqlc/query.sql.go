@@ -8,8 +8,66 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
+const addDivisionTeam = `-- name: AddDivisionTeam :exec
+INSERT INTO division_team (division_id, team_id) VALUES (?, ?)
+`
+
+type AddDivisionTeamParams struct {
+	DivisionID int64
+	TeamID     int64
+}
+
+func (q *Queries) AddDivisionTeam(ctx context.Context, arg AddDivisionTeamParams) error {
+	_, err := q.db.ExecContext(ctx, addDivisionTeam, arg.DivisionID, arg.TeamID)
+	return err
+}
+
+const clearCupTieTeamsFromWeek = `-- name: ClearCupTieTeamsFromWeek :exec
+UPDATE cup_tie
+SET home_id = NULL, guest_id = NULL
+WHERE cup_id IN (SELECT id FROM cup WHERE season_id = ?1)
+  AND round > (
+    SELECT MIN(ct.round) FROM cup_tie ct
+    JOIN cup c ON c.id = ct.cup_id
+    WHERE c.season_id = ?1 AND ct.week >= ?2
+  )
+`
+
+type ClearCupTieTeamsFromWeekParams struct {
+	SeasonID int64
+	Week     int64
+}
+
+func (q *Queries) ClearCupTieTeamsFromWeek(ctx context.Context, arg ClearCupTieTeamsFromWeekParams) error {
+	_, err := q.db.ExecContext(ctx, clearCupTieTeamsFromWeek, arg.SeasonID, arg.Week)
+	return err
+}
+
+const clearTournamentMatchTeamsFromWeek = `-- name: ClearTournamentMatchTeamsFromWeek :exec
+UPDATE tournament_match
+SET home_id = NULL, guest_id = NULL
+WHERE tournament_id IN (SELECT id FROM tournament WHERE season_id = ?1)
+  AND round > 0
+  AND round > (
+    SELECT MIN(tm.round) FROM tournament_match tm
+    JOIN tournament t ON t.id = tm.tournament_id
+    WHERE t.season_id = ?1 AND tm.week >= ?2
+  )
+`
+
+type ClearTournamentMatchTeamsFromWeekParams struct {
+	SeasonID int64
+	Week     int64
+}
+
+func (q *Queries) ClearTournamentMatchTeamsFromWeek(ctx context.Context, arg ClearTournamentMatchTeamsFromWeekParams) error {
+	_, err := q.db.ExecContext(ctx, clearTournamentMatchTeamsFromWeek, arg.SeasonID, arg.Week)
+	return err
+}
+
 const completeSeason = `-- name: CompleteSeason :exec
 UPDATE season SET is_complete = TRUE WHERE id = ?
 `
@@ -19,20 +77,259 @@ func (q *Queries) CompleteSeason(ctx context.Context, id int64) error {
 	return err
 }
 
+const computeStandings = `-- name: ComputeStandings :many
+SELECT t.id, t.name, t.strength, t.budget,
+       CAST(COUNT(r.match_id) AS INTEGER) AS played,
+       CAST(COALESCE(SUM(r.goals_for > r.goals_against), 0) AS INTEGER) AS wins,
+       CAST(COALESCE(SUM(r.goals_for = r.goals_against), 0) AS INTEGER) AS draws,
+       CAST(COALESCE(SUM(r.goals_for < r.goals_against), 0) AS INTEGER) AS losses,
+       CAST(COALESCE(SUM(r.goals_for), 0) AS INTEGER) AS goals_for,
+       CAST(COALESCE(SUM(r.goals_against), 0) AS INTEGER) AS goals_against,
+       CAST(COALESCE(SUM(r.goals_for - r.goals_against), 0) AS INTEGER) AS goal_diff,
+       CAST(COALESCE(SUM(CASE WHEN r.is_away THEN r.goals_for ELSE 0 END), 0) AS INTEGER) AS away_goals_for
+FROM team t
+LEFT JOIN (
+    SELECT m.id AS match_id, m.home_id AS team_id, mr.home_score AS goals_for, mr.guest_score AS goals_against, FALSE AS is_away
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.season_id = ?1 AND m.played = TRUE
+    UNION ALL
+    SELECT m.id AS match_id, m.guest_id AS team_id, mr.guest_score AS goals_for, mr.home_score AS goals_against, TRUE AS is_away
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.season_id = ?1 AND m.played = TRUE
+) r ON r.team_id = t.id
+GROUP BY t.id
+ORDER BY t.name
+`
+
+type ComputeStandingsRow struct {
+	Team         Team
+	Played       int64
+	Wins         int64
+	Draws        int64
+	Losses       int64
+	GoalsFor     int64
+	GoalsAgainst int64
+	GoalDiff     int64
+	AwayGoalsFor int64
+}
+
+func (q *Queries) ComputeStandings(ctx context.Context, seasonID int64) ([]ComputeStandingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, computeStandings, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ComputeStandingsRow
+	for rows.Next() {
+		var i ComputeStandingsRow
+		if err := rows.Scan(
+			&i.Team.ID,
+			&i.Team.Name,
+			&i.Team.Strength,
+			&i.Team.Budget,
+			&i.Played,
+			&i.Wins,
+			&i.Draws,
+			&i.Losses,
+			&i.GoalsFor,
+			&i.GoalsAgainst,
+			&i.GoalDiff,
+			&i.AwayGoalsFor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countTeamMatches = `-- name: CountTeamMatches :one
+SELECT COUNT(*) FROM match
+WHERE home_id = ?1 OR guest_id = ?1
+`
+
+func (q *Queries) CountTeamMatches(ctx context.Context, teamID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTeamMatches, teamID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createCompetition = `-- name: CreateCompetition :one
+INSERT INTO competition (
+  season_id, format, name, legs, win_points, draw_points, loss_points, bonus_goals, bonus_points, ranking_rules
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING id, season_id, format, name, legs, win_points, draw_points, loss_points, bonus_goals, bonus_points, ranking_rules
+`
+
+type CreateCompetitionParams struct {
+	SeasonID     int64
+	Format       string
+	Name         string
+	Legs         int64
+	WinPoints    int64
+	DrawPoints   int64
+	LossPoints   int64
+	BonusGoals   int64
+	BonusPoints  int64
+	RankingRules string
+}
+
+func (q *Queries) CreateCompetition(ctx context.Context, arg CreateCompetitionParams) (Competition, error) {
+	row := q.db.QueryRowContext(ctx, createCompetition,
+		arg.SeasonID,
+		arg.Format,
+		arg.Name,
+		arg.Legs,
+		arg.WinPoints,
+		arg.DrawPoints,
+		arg.LossPoints,
+		arg.BonusGoals,
+		arg.BonusPoints,
+		arg.RankingRules,
+	)
+	var i Competition
+	err := row.Scan(
+		&i.ID,
+		&i.SeasonID,
+		&i.Format,
+		&i.Name,
+		&i.Legs,
+		&i.WinPoints,
+		&i.DrawPoints,
+		&i.LossPoints,
+		&i.BonusGoals,
+		&i.BonusPoints,
+		&i.RankingRules,
+	)
+	return i, err
+}
+
+const createCompetitionTeam = `-- name: CreateCompetitionTeam :exec
+INSERT INTO competition_team (
+  competition_id, team_id
+) VALUES (
+  ?, ?
+)
+`
+
+type CreateCompetitionTeamParams struct {
+	CompetitionID int64
+	TeamID        int64
+}
+
+func (q *Queries) CreateCompetitionTeam(ctx context.Context, arg CreateCompetitionTeamParams) error {
+	_, err := q.db.ExecContext(ctx, createCompetitionTeam, arg.CompetitionID, arg.TeamID)
+	return err
+}
+
+const createCup = `-- name: CreateCup :one
+INSERT INTO cup (season_id, seeded) VALUES (?, ?) RETURNING id, season_id, seeded
+`
+
+type CreateCupParams struct {
+	SeasonID int64
+	Seeded   bool
+}
+
+func (q *Queries) CreateCup(ctx context.Context, arg CreateCupParams) (Cup, error) {
+	row := q.db.QueryRowContext(ctx, createCup, arg.SeasonID, arg.Seeded)
+	var i Cup
+	err := row.Scan(&i.ID, &i.SeasonID, &i.Seeded)
+	return i, err
+}
+
+const createCupTie = `-- name: CreateCupTie :exec
+INSERT INTO cup_tie (
+  cup_id, round, slot, week, home_id, guest_id
+) VALUES (
+  ?, ?, ?, ?, ?, ?
+)
+`
+
+type CreateCupTieParams struct {
+	CupID   int64
+	Round   int64
+	Slot    int64
+	Week    int64
+	HomeID  sql.NullInt64
+	GuestID sql.NullInt64
+}
+
+func (q *Queries) CreateCupTie(ctx context.Context, arg CreateCupTieParams) error {
+	_, err := q.db.ExecContext(ctx, createCupTie,
+		arg.CupID,
+		arg.Round,
+		arg.Slot,
+		arg.Week,
+		arg.HomeID,
+		arg.GuestID,
+	)
+	return err
+}
+
+const createDivision = `-- name: CreateDivision :one
+INSERT INTO division (
+  season_id, name, tier, relegation_places, playoff
+) VALUES (
+  ?, ?, ?, ?, ?
+)
+RETURNING id, season_id, name, tier, relegation_places, playoff
+`
+
+type CreateDivisionParams struct {
+	SeasonID         int64
+	Name             string
+	Tier             int64
+	RelegationPlaces int64
+	Playoff          bool
+}
+
+func (q *Queries) CreateDivision(ctx context.Context, arg CreateDivisionParams) (Division, error) {
+	row := q.db.QueryRowContext(ctx, createDivision,
+		arg.SeasonID,
+		arg.Name,
+		arg.Tier,
+		arg.RelegationPlaces,
+		arg.Playoff,
+	)
+	var i Division
+	err := row.Scan(
+		&i.ID,
+		&i.SeasonID,
+		&i.Name,
+		&i.Tier,
+		&i.RelegationPlaces,
+		&i.Playoff,
+	)
+	return i, err
+}
+
 const createFixture = `-- name: CreateFixture :exec
 INSERT INTO match (
-  home_id, guest_id, played, week, season_id
+  home_id, guest_id, played, week, season_id, division_id
 ) VALUES (
-  ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?
 )
 `
 
 type CreateFixtureParams struct {
-	HomeID   int64
-	GuestID  int64
-	Played   sql.NullBool
-	Week     int64
-	SeasonID int64
+	HomeID     int64
+	GuestID    int64
+	Played     sql.NullBool
+	Week       int64
+	SeasonID   int64
+	DivisionID sql.NullInt64
 }
 
 func (q *Queries) CreateFixture(ctx context.Context, arg CreateFixtureParams) error {
@@ -42,42 +339,131 @@ func (q *Queries) CreateFixture(ctx context.Context, arg CreateFixtureParams) er
 		arg.Played,
 		arg.Week,
 		arg.SeasonID,
+		arg.DivisionID,
 	)
 	return err
 }
 
 const createNewSeason = `-- name: CreateNewSeason :one
-INSERT INTO season (year, is_current, is_complete) VALUES (?, FALSE, FALSE) RETURNING id, year, is_current, is_complete
+INSERT INTO season (year, seed, is_current, is_complete) VALUES (?, ?, FALSE, FALSE) RETURNING id, year, seed, is_current, is_complete
 `
 
-func (q *Queries) CreateNewSeason(ctx context.Context, year int64) (Season, error) {
-	row := q.db.QueryRowContext(ctx, createNewSeason, year)
+type CreateNewSeasonParams struct {
+	Year int64
+	Seed int64
+}
+
+func (q *Queries) CreateNewSeason(ctx context.Context, arg CreateNewSeasonParams) (Season, error) {
+	row := q.db.QueryRowContext(ctx, createNewSeason, arg.Year, arg.Seed)
 	var i Season
 	err := row.Scan(
 		&i.ID,
 		&i.Year,
+		&i.Seed,
 		&i.IsCurrent,
 		&i.IsComplete,
 	)
 	return i, err
 }
 
+const createPlayoff = `-- name: CreatePlayoff :exec
+INSERT INTO playoff (
+  season_id, division_id, upper_team_id, lower_team_id, upper_score, lower_score, winner_id
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?
+)
+`
+
+type CreatePlayoffParams struct {
+	SeasonID    int64
+	DivisionID  int64
+	UpperTeamID int64
+	LowerTeamID int64
+	UpperScore  int64
+	LowerScore  int64
+	WinnerID    int64
+}
+
+func (q *Queries) CreatePlayoff(ctx context.Context, arg CreatePlayoffParams) error {
+	_, err := q.db.ExecContext(ctx, createPlayoff,
+		arg.SeasonID,
+		arg.DivisionID,
+		arg.UpperTeamID,
+		arg.LowerTeamID,
+		arg.UpperScore,
+		arg.LowerScore,
+		arg.WinnerID,
+	)
+	return err
+}
+
+const createSanction = `-- name: CreateSanction :one
+INSERT INTO sanction (
+  competition_id, team_id, kind, points, match_id, first_week, last_week, reason, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING id, competition_id, team_id, kind, points, match_id, first_week, last_week, reason, created_at
+`
+
+type CreateSanctionParams struct {
+	CompetitionID int64
+	TeamID        int64
+	Kind          string
+	Points        int64
+	MatchID       sql.NullInt64
+	FirstWeek     int64
+	LastWeek      int64
+	Reason        string
+	CreatedAt     time.Time
+}
+
+func (q *Queries) CreateSanction(ctx context.Context, arg CreateSanctionParams) (Sanction, error) {
+	row := q.db.QueryRowContext(ctx, createSanction,
+		arg.CompetitionID,
+		arg.TeamID,
+		arg.Kind,
+		arg.Points,
+		arg.MatchID,
+		arg.FirstWeek,
+		arg.LastWeek,
+		arg.Reason,
+		arg.CreatedAt,
+	)
+	var i Sanction
+	err := row.Scan(
+		&i.ID,
+		&i.CompetitionID,
+		&i.TeamID,
+		&i.Kind,
+		&i.Points,
+		&i.MatchID,
+		&i.FirstWeek,
+		&i.LastWeek,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createStanding = `-- name: CreateStanding :exec
 INSERT INTO standing (
-  team_id, season_id, points, wins, draws, losses, goal_diff
+  team_id, season_id, points, wins, draws, losses, goal_diff, goals_for, goals_against
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 `
 
 type CreateStandingParams struct {
-	TeamID   int64
-	SeasonID int64
-	Points   sql.NullInt64
-	Wins     sql.NullInt64
-	Draws    sql.NullInt64
-	Losses   sql.NullInt64
-	GoalDiff sql.NullInt64
+	TeamID       int64
+	SeasonID     int64
+	Points       sql.NullInt64
+	Wins         sql.NullInt64
+	Draws        sql.NullInt64
+	Losses       sql.NullInt64
+	GoalDiff     sql.NullInt64
+	GoalsFor     sql.NullInt64
+	GoalsAgainst sql.NullInt64
 }
 
 func (q *Queries) CreateStanding(ctx context.Context, arg CreateStandingParams) error {
@@ -89,6 +475,8 @@ func (q *Queries) CreateStanding(ctx context.Context, arg CreateStandingParams)
 		arg.Draws,
 		arg.Losses,
 		arg.GoalDiff,
+		arg.GoalsFor,
+		arg.GoalsAgainst,
 	)
 	return err
 }
@@ -120,145 +508,1512 @@ func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, e
 	return i, err
 }
 
-const deleteTeam = `-- name: DeleteTeam :exec
-DELETE FROM team
-WHERE id = ?
+const createTeamHistory = `-- name: CreateTeamHistory :exec
+INSERT INTO team_history (
+  team_id, season_id, last_season_standing, prize_money, strength_before, strength_after, budget_before, budget_after
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?
+)
 `
 
-func (q *Queries) DeleteTeam(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteTeam, id)
+type CreateTeamHistoryParams struct {
+	TeamID             int64
+	SeasonID           int64
+	LastSeasonStanding int64
+	PrizeMoney         int64
+	StrengthBefore     int64
+	StrengthAfter      int64
+	BudgetBefore       int64
+	BudgetAfter        int64
+}
+
+func (q *Queries) CreateTeamHistory(ctx context.Context, arg CreateTeamHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, createTeamHistory,
+		arg.TeamID,
+		arg.SeasonID,
+		arg.LastSeasonStanding,
+		arg.PrizeMoney,
+		arg.StrengthBefore,
+		arg.StrengthAfter,
+		arg.BudgetBefore,
+		arg.BudgetAfter,
+	)
 	return err
 }
 
-const getAllMatchesPlayedForWeek = `-- name: GetAllMatchesPlayedForWeek :one
-SELECT COUNT(*) = 0 as all_played FROM match WHERE week = ? AND played = FALSE AND season_id = ?
+const createTournament = `-- name: CreateTournament :one
+INSERT INTO tournament (
+  season_id, group_count, qualifiers, away_goals, separate_divisions
+) VALUES (
+  ?, ?, ?, ?, ?
+)
+RETURNING id, season_id, group_count, qualifiers, away_goals, separate_divisions
 `
 
-type GetAllMatchesPlayedForWeekParams struct {
-	Week     int64
-	SeasonID int64
-}
-
-func (q *Queries) GetAllMatchesPlayedForWeek(ctx context.Context, arg GetAllMatchesPlayedForWeekParams) (bool, error) {
-	row := q.db.QueryRowContext(ctx, getAllMatchesPlayedForWeek, arg.Week, arg.SeasonID)
-	var all_played bool
-	err := row.Scan(&all_played)
-	return all_played, err
+type CreateTournamentParams struct {
+	SeasonID          int64
+	GroupCount        int64
+	Qualifiers        int64
+	AwayGoals         bool
+	SeparateDivisions bool
 }
 
-const getCurrentSeason = `-- name: GetCurrentSeason :one
-SELECT id, year, is_current, is_complete FROM season WHERE is_current = TRUE LIMIT 1
-`
-
-func (q *Queries) GetCurrentSeason(ctx context.Context) (Season, error) {
-	row := q.db.QueryRowContext(ctx, getCurrentSeason)
-	var i Season
+func (q *Queries) CreateTournament(ctx context.Context, arg CreateTournamentParams) (Tournament, error) {
+	row := q.db.QueryRowContext(ctx, createTournament,
+		arg.SeasonID,
+		arg.GroupCount,
+		arg.Qualifiers,
+		arg.AwayGoals,
+		arg.SeparateDivisions,
+	)
+	var i Tournament
 	err := row.Scan(
 		&i.ID,
-		&i.Year,
-		&i.IsCurrent,
-		&i.IsComplete,
+		&i.SeasonID,
+		&i.GroupCount,
+		&i.Qualifiers,
+		&i.AwayGoals,
+		&i.SeparateDivisions,
 	)
 	return i, err
 }
 
-const getCurrentWeek = `-- name: GetCurrentWeek :one
-SELECT current_week FROM game_state WHERE season_id = ? LIMIT 1
+const createTournamentMatch = `-- name: CreateTournamentMatch :exec
+INSERT INTO tournament_match (
+  tournament_id, round, slot, leg, week, home_id, guest_id
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?
+)
 `
 
-func (q *Queries) GetCurrentWeek(ctx context.Context, seasonID int64) (sql.NullInt64, error) {
-	row := q.db.QueryRowContext(ctx, getCurrentWeek, seasonID)
-	var current_week sql.NullInt64
-	err := row.Scan(&current_week)
-	return current_week, err
+type CreateTournamentMatchParams struct {
+	TournamentID int64
+	Round        int64
+	Slot         int64
+	Leg          int64
+	Week         int64
+	HomeID       sql.NullInt64
+	GuestID      sql.NullInt64
 }
 
-const getMatchResult = `-- name: GetMatchResult :one
-SELECT mr.id, mr.match_id, mr.home_score, mr.guest_score, mr.winner_id, 
-       ht.name as home_team_name, 
-       gt.name as guest_team_name
-FROM match_result mr
-JOIN match m ON mr.match_id = m.id
-JOIN team ht ON m.home_id = ht.id
-JOIN team gt ON m.guest_id = gt.id
-WHERE mr.match_id = ?
+func (q *Queries) CreateTournamentMatch(ctx context.Context, arg CreateTournamentMatchParams) error {
+	_, err := q.db.ExecContext(ctx, createTournamentMatch,
+		arg.TournamentID,
+		arg.Round,
+		arg.Slot,
+		arg.Leg,
+		arg.Week,
+		arg.HomeID,
+		arg.GuestID,
+	)
+	return err
+}
+
+const createTournamentTeam = `-- name: CreateTournamentTeam :exec
+INSERT INTO tournament_team (
+  tournament_id, team_id, pot, group_number
+) VALUES (
+  ?, ?, ?, ?
+)
 `
 
-type GetMatchResultRow struct {
-	ID            int64
-	MatchID       int64
-	HomeScore     int64
-	GuestScore    int64
-	WinnerID      sql.NullInt64
-	HomeTeamName  string
-	GuestTeamName string
+type CreateTournamentTeamParams struct {
+	TournamentID int64
+	TeamID       int64
+	Pot          int64
+	GroupNumber  int64
 }
 
-func (q *Queries) GetMatchResult(ctx context.Context, matchID int64) (GetMatchResultRow, error) {
-	row := q.db.QueryRowContext(ctx, getMatchResult, matchID)
-	var i GetMatchResultRow
-	err := row.Scan(
-		&i.ID,
-		&i.MatchID,
-		&i.HomeScore,
-		&i.GuestScore,
-		&i.WinnerID,
-		&i.HomeTeamName,
-		&i.GuestTeamName,
+func (q *Queries) CreateTournamentTeam(ctx context.Context, arg CreateTournamentTeamParams) error {
+	_, err := q.db.ExecContext(ctx, createTournamentTeam,
+		arg.TournamentID,
+		arg.TeamID,
+		arg.Pot,
+		arg.GroupNumber,
 	)
-	return i, err
+	return err
 }
 
-const getMatchesByWeek = `-- name: GetMatchesByWeek :many
-SELECT m.id, m.season_id, m.home_id, m.guest_id, m.played, m.week, 
-       ht.name as home_team_name, 
-       gt.name as guest_team_name,
-       ht.strength as home_team_strength,
-       gt.strength as guest_team_strength
-FROM match m
-JOIN team ht ON m.home_id = ht.id
-JOIN team gt ON m.guest_id = gt.id
-WHERE m.week = ? AND m.season_id = ?
+const deleteCompetitionTeams = `-- name: DeleteCompetitionTeams :exec
+DELETE FROM competition_team WHERE competition_id = ?
 `
 
-type GetMatchesByWeekParams struct {
+func (q *Queries) DeleteCompetitionTeams(ctx context.Context, competitionID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCompetitionTeams, competitionID)
+	return err
+}
+
+const deleteCup = `-- name: DeleteCup :exec
+DELETE FROM cup WHERE id = ?
+`
+
+func (q *Queries) DeleteCup(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCup, id)
+	return err
+}
+
+const deleteCupTies = `-- name: DeleteCupTies :exec
+DELETE FROM cup_tie WHERE cup_id = ?
+`
+
+func (q *Queries) DeleteCupTies(ctx context.Context, cupID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteCupTies, cupID)
+	return err
+}
+
+const deleteDivision = `-- name: DeleteDivision :exec
+DELETE FROM division WHERE id = ?
+`
+
+func (q *Queries) DeleteDivision(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteDivision, id)
+	return err
+}
+
+const deletePlayoffsBySeason = `-- name: DeletePlayoffsBySeason :exec
+DELETE FROM playoff WHERE season_id = ?
+`
+
+func (q *Queries) DeletePlayoffsBySeason(ctx context.Context, seasonID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePlayoffsBySeason, seasonID)
+	return err
+}
+
+const deleteResultsFromWeek = `-- name: DeleteResultsFromWeek :exec
+DELETE FROM match_result
+WHERE match_id IN (SELECT id FROM match WHERE season_id = ? AND week >= ?)
+`
+
+type DeleteResultsFromWeekParams struct {
+	SeasonID int64
+	Week     int64
+}
+
+func (q *Queries) DeleteResultsFromWeek(ctx context.Context, arg DeleteResultsFromWeekParams) error {
+	_, err := q.db.ExecContext(ctx, deleteResultsFromWeek, arg.SeasonID, arg.Week)
+	return err
+}
+
+const deleteSanction = `-- name: DeleteSanction :exec
+DELETE FROM sanction WHERE id = ?
+`
+
+func (q *Queries) DeleteSanction(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSanction, id)
+	return err
+}
+
+const deleteTeam = `-- name: DeleteTeam :exec
+DELETE FROM team
+WHERE id = ?
+`
+
+func (q *Queries) DeleteTeam(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTeam, id)
+	return err
+}
+
+const deleteTeamCompetitions = `-- name: DeleteTeamCompetitions :exec
+DELETE FROM competition_team WHERE team_id = ?
+`
+
+func (q *Queries) DeleteTeamCompetitions(ctx context.Context, teamID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTeamCompetitions, teamID)
+	return err
+}
+
+const deleteTeamDivisions = `-- name: DeleteTeamDivisions :exec
+DELETE FROM division_team WHERE team_id = ?
+`
+
+func (q *Queries) DeleteTeamDivisions(ctx context.Context, teamID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTeamDivisions, teamID)
+	return err
+}
+
+const deleteTeamSanctions = `-- name: DeleteTeamSanctions :exec
+DELETE FROM sanction WHERE team_id = ?
+`
+
+func (q *Queries) DeleteTeamSanctions(ctx context.Context, teamID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTeamSanctions, teamID)
+	return err
+}
+
+const deleteTeamStandings = `-- name: DeleteTeamStandings :exec
+DELETE FROM standing
+WHERE team_id = ?
+`
+
+func (q *Queries) DeleteTeamStandings(ctx context.Context, teamID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTeamStandings, teamID)
+	return err
+}
+
+const deleteTournament = `-- name: DeleteTournament :exec
+DELETE FROM tournament WHERE id = ?
+`
+
+func (q *Queries) DeleteTournament(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTournament, id)
+	return err
+}
+
+const deleteTournamentMatches = `-- name: DeleteTournamentMatches :exec
+DELETE FROM tournament_match WHERE tournament_id = ?
+`
+
+func (q *Queries) DeleteTournamentMatches(ctx context.Context, tournamentID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTournamentMatches, tournamentID)
+	return err
+}
+
+const deleteTournamentTeams = `-- name: DeleteTournamentTeams :exec
+DELETE FROM tournament_team WHERE tournament_id = ?
+`
+
+func (q *Queries) DeleteTournamentTeams(ctx context.Context, tournamentID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTournamentTeams, tournamentID)
+	return err
+}
+
+const deleteUnplayedMatchesBySeason = `-- name: DeleteUnplayedMatchesBySeason :exec
+DELETE FROM match WHERE season_id = ? AND played = FALSE
+`
+
+func (q *Queries) DeleteUnplayedMatchesBySeason(ctx context.Context, seasonID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUnplayedMatchesBySeason, seasonID)
+	return err
+}
+
+const getAllMatchesPlayedForWeek = `-- name: GetAllMatchesPlayedForWeek :one
+SELECT COUNT(*) = 0 as all_played FROM match WHERE week = ? AND played = FALSE AND season_id = ?
+`
+
+type GetAllMatchesPlayedForWeekParams struct {
 	Week     int64
 	SeasonID int64
 }
 
-type GetMatchesByWeekRow struct {
-	ID                int64
-	SeasonID          int64
-	HomeID            int64
-	GuestID           int64
-	Played            sql.NullBool
-	Week              int64
-	HomeTeamName      string
-	GuestTeamName     string
-	HomeTeamStrength  sql.NullInt64
-	GuestTeamStrength sql.NullInt64
+func (q *Queries) GetAllMatchesPlayedForWeek(ctx context.Context, arg GetAllMatchesPlayedForWeekParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getAllMatchesPlayedForWeek, arg.Week, arg.SeasonID)
+	var all_played bool
+	err := row.Scan(&all_played)
+	return all_played, err
 }
 
-func (q *Queries) GetMatchesByWeek(ctx context.Context, arg GetMatchesByWeekParams) ([]GetMatchesByWeekRow, error) {
-	rows, err := q.db.QueryContext(ctx, getMatchesByWeek, arg.Week, arg.SeasonID)
+const getCompetition = `-- name: GetCompetition :one
+SELECT id, season_id, format, name, legs, win_points, draw_points, loss_points, bonus_goals, bonus_points, ranking_rules FROM competition WHERE id = ?
+`
+
+func (q *Queries) GetCompetition(ctx context.Context, id int64) (Competition, error) {
+	row := q.db.QueryRowContext(ctx, getCompetition, id)
+	var i Competition
+	err := row.Scan(
+		&i.ID,
+		&i.SeasonID,
+		&i.Format,
+		&i.Name,
+		&i.Legs,
+		&i.WinPoints,
+		&i.DrawPoints,
+		&i.LossPoints,
+		&i.BonusGoals,
+		&i.BonusPoints,
+		&i.RankingRules,
+	)
+	return i, err
+}
+
+const getCompetitionBySeason = `-- name: GetCompetitionBySeason :one
+SELECT id, season_id, format, name, legs, win_points, draw_points, loss_points, bonus_goals, bonus_points, ranking_rules FROM competition WHERE season_id = ? AND format = ?
+`
+
+type GetCompetitionBySeasonParams struct {
+	SeasonID int64
+	Format   string
+}
+
+func (q *Queries) GetCompetitionBySeason(ctx context.Context, arg GetCompetitionBySeasonParams) (Competition, error) {
+	row := q.db.QueryRowContext(ctx, getCompetitionBySeason, arg.SeasonID, arg.Format)
+	var i Competition
+	err := row.Scan(
+		&i.ID,
+		&i.SeasonID,
+		&i.Format,
+		&i.Name,
+		&i.Legs,
+		&i.WinPoints,
+		&i.DrawPoints,
+		&i.LossPoints,
+		&i.BonusGoals,
+		&i.BonusPoints,
+		&i.RankingRules,
+	)
+	return i, err
+}
+
+const getCupBySeason = `-- name: GetCupBySeason :one
+SELECT id, season_id, seeded FROM cup WHERE season_id = ?
+`
+
+func (q *Queries) GetCupBySeason(ctx context.Context, seasonID int64) (Cup, error) {
+	row := q.db.QueryRowContext(ctx, getCupBySeason, seasonID)
+	var i Cup
+	err := row.Scan(&i.ID, &i.SeasonID, &i.Seeded)
+	return i, err
+}
+
+const getCurrentSeason = `-- name: GetCurrentSeason :one
+SELECT id, year, seed, is_current, is_complete FROM season WHERE is_current = TRUE LIMIT 1
+`
+
+func (q *Queries) GetCurrentSeason(ctx context.Context) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getCurrentSeason)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Year,
+		&i.Seed,
+		&i.IsCurrent,
+		&i.IsComplete,
+	)
+	return i, err
+}
+
+const getCurrentWeek = `-- name: GetCurrentWeek :one
+SELECT current_week FROM game_state WHERE season_id = ? LIMIT 1
+`
+
+func (q *Queries) GetCurrentWeek(ctx context.Context, seasonID int64) (sql.NullInt64, error) {
+	row := q.db.QueryRowContext(ctx, getCurrentWeek, seasonID)
+	var current_week sql.NullInt64
+	err := row.Scan(&current_week)
+	return current_week, err
+}
+
+const getDivision = `-- name: GetDivision :one
+SELECT id, season_id, name, tier, relegation_places, playoff FROM division WHERE id = ?
+`
+
+func (q *Queries) GetDivision(ctx context.Context, id int64) (Division, error) {
+	row := q.db.QueryRowContext(ctx, getDivision, id)
+	var i Division
+	err := row.Scan(
+		&i.ID,
+		&i.SeasonID,
+		&i.Name,
+		&i.Tier,
+		&i.RelegationPlaces,
+		&i.Playoff,
+	)
+	return i, err
+}
+
+const getMatchResult = `-- name: GetMatchResult :one
+SELECT mr.id, mr.match_id, mr.home_score, mr.guest_score, mr.winner_id, 
+       ht.name as home_team_name, 
+       gt.name as guest_team_name
+FROM match_result mr
+JOIN match m ON mr.match_id = m.id
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+WHERE mr.match_id = ?
+`
+
+type GetMatchResultRow struct {
+	ID            int64
+	MatchID       int64
+	HomeScore     int64
+	GuestScore    int64
+	WinnerID      sql.NullInt64
+	HomeTeamName  string
+	GuestTeamName string
+}
+
+func (q *Queries) GetMatchResult(ctx context.Context, matchID int64) (GetMatchResultRow, error) {
+	row := q.db.QueryRowContext(ctx, getMatchResult, matchID)
+	var i GetMatchResultRow
+	err := row.Scan(
+		&i.ID,
+		&i.MatchID,
+		&i.HomeScore,
+		&i.GuestScore,
+		&i.WinnerID,
+		&i.HomeTeamName,
+		&i.GuestTeamName,
+	)
+	return i, err
+}
+
+const getMatchesBySeason = `-- name: GetMatchesBySeason :many
+SELECT m.id, m.season_id, m.home_id, m.guest_id, m.played, m.week, m.division_id,
+       ht.name as home_team_name,
+       gt.name as guest_team_name,
+       mr.home_score,
+       mr.guest_score
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+LEFT JOIN match_result mr ON mr.match_id = m.id
+WHERE m.season_id = ?
+ORDER BY m.week, m.id
+`
+
+type GetMatchesBySeasonRow struct {
+	ID            int64
+	SeasonID      int64
+	HomeID        int64
+	GuestID       int64
+	Played        sql.NullBool
+	Week          int64
+	DivisionID    sql.NullInt64
+	HomeTeamName  string
+	GuestTeamName string
+	HomeScore     sql.NullInt64
+	GuestScore    sql.NullInt64
+}
+
+func (q *Queries) GetMatchesBySeason(ctx context.Context, seasonID int64) ([]GetMatchesBySeasonRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMatchesBySeason, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMatchesBySeasonRow
+	for rows.Next() {
+		var i GetMatchesBySeasonRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.HomeID,
+			&i.GuestID,
+			&i.Played,
+			&i.Week,
+			&i.DivisionID,
+			&i.HomeTeamName,
+			&i.GuestTeamName,
+			&i.HomeScore,
+			&i.GuestScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMatchesByTeam = `-- name: GetMatchesByTeam :many
+SELECT m.id, m.season_id, m.home_id, m.guest_id, m.played, m.week, m.division_id,
+       ht.name as home_team_name,
+       gt.name as guest_team_name,
+       mr.home_score,
+       mr.guest_score
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+LEFT JOIN match_result mr ON mr.match_id = m.id
+WHERE m.season_id = ?1 AND (m.home_id = ?2 OR m.guest_id = ?2)
+ORDER BY m.week, m.id
+`
+
+type GetMatchesByTeamParams struct {
+	SeasonID int64
+	TeamID   int64
+}
+
+type GetMatchesByTeamRow struct {
+	ID            int64
+	SeasonID      int64
+	HomeID        int64
+	GuestID       int64
+	Played        sql.NullBool
+	Week          int64
+	DivisionID    sql.NullInt64
+	HomeTeamName  string
+	GuestTeamName string
+	HomeScore     sql.NullInt64
+	GuestScore    sql.NullInt64
+}
+
+func (q *Queries) GetMatchesByTeam(ctx context.Context, arg GetMatchesByTeamParams) ([]GetMatchesByTeamRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMatchesByTeam, arg.SeasonID, arg.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMatchesByTeamRow
+	for rows.Next() {
+		var i GetMatchesByTeamRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.HomeID,
+			&i.GuestID,
+			&i.Played,
+			&i.Week,
+			&i.DivisionID,
+			&i.HomeTeamName,
+			&i.GuestTeamName,
+			&i.HomeScore,
+			&i.GuestScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMatchesByWeek = `-- name: GetMatchesByWeek :many
+SELECT m.id, m.season_id, m.home_id, m.guest_id, m.played, m.week, m.division_id, 
+       ht.name as home_team_name, 
+       gt.name as guest_team_name,
+       ht.strength as home_team_strength,
+       gt.strength as guest_team_strength
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+WHERE m.week = ? AND m.season_id = ?
+`
+
+type GetMatchesByWeekParams struct {
+	Week     int64
+	SeasonID int64
+}
+
+type GetMatchesByWeekRow struct {
+	ID                int64
+	SeasonID          int64
+	HomeID            int64
+	GuestID           int64
+	Played            sql.NullBool
+	Week              int64
+	DivisionID        sql.NullInt64
+	HomeTeamName      string
+	GuestTeamName     string
+	HomeTeamStrength  sql.NullInt64
+	GuestTeamStrength sql.NullInt64
+}
+
+func (q *Queries) GetMatchesByWeek(ctx context.Context, arg GetMatchesByWeekParams) ([]GetMatchesByWeekRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMatchesByWeek, arg.Week, arg.SeasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMatchesByWeekRow
+	for rows.Next() {
+		var i GetMatchesByWeekRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.HomeID,
+			&i.GuestID,
+			&i.Played,
+			&i.Week,
+			&i.DivisionID,
+			&i.HomeTeamName,
+			&i.GuestTeamName,
+			&i.HomeTeamStrength,
+			&i.GuestTeamStrength,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getResultsBySeason = `-- name: GetResultsBySeason :many
+SELECT m.id, m.week, m.home_id, m.guest_id, mr.home_score, mr.guest_score
+FROM match m
+JOIN match_result mr ON mr.match_id = m.id
+WHERE m.season_id = ? AND m.played = TRUE
+ORDER BY m.week, m.id
+`
+
+type GetResultsBySeasonRow struct {
+	ID         int64
+	Week       int64
+	HomeID     int64
+	GuestID    int64
+	HomeScore  int64
+	GuestScore int64
+}
+
+func (q *Queries) GetResultsBySeason(ctx context.Context, seasonID int64) ([]GetResultsBySeasonRow, error) {
+	rows, err := q.db.QueryContext(ctx, getResultsBySeason, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetResultsBySeasonRow
+	for rows.Next() {
+		var i GetResultsBySeasonRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Week,
+			&i.HomeID,
+			&i.GuestID,
+			&i.HomeScore,
+			&i.GuestScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSanction = `-- name: GetSanction :one
+SELECT id, competition_id, team_id, kind, points, match_id, first_week, last_week, reason, created_at FROM sanction WHERE id = ?
+`
+
+func (q *Queries) GetSanction(ctx context.Context, id int64) (Sanction, error) {
+	row := q.db.QueryRowContext(ctx, getSanction, id)
+	var i Sanction
+	err := row.Scan(
+		&i.ID,
+		&i.CompetitionID,
+		&i.TeamID,
+		&i.Kind,
+		&i.Points,
+		&i.MatchID,
+		&i.FirstWeek,
+		&i.LastWeek,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSeason = `-- name: GetSeason :one
+SELECT id, year, seed, is_current, is_complete FROM season WHERE id = ?
+`
+
+func (q *Queries) GetSeason(ctx context.Context, id int64) (Season, error) {
+	row := q.db.QueryRowContext(ctx, getSeason, id)
+	var i Season
+	err := row.Scan(
+		&i.ID,
+		&i.Year,
+		&i.Seed,
+		&i.IsCurrent,
+		&i.IsComplete,
+	)
+	return i, err
+}
+
+const getSeasonWeeks = `-- name: GetSeasonWeeks :one
+SELECT CAST(COALESCE(MAX(week), 0) AS INTEGER) AS weeks FROM match WHERE season_id = ?
+`
+
+func (q *Queries) GetSeasonWeeks(ctx context.Context, seasonID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getSeasonWeeks, seasonID)
+	var weeks int64
+	err := row.Scan(&weeks)
+	return weeks, err
+}
+
+const getStanding = `-- name: GetStanding :one
+SELECT id, team_id, season_id, points, wins, draws, losses, goal_diff, goals_for, goals_against FROM standing
+WHERE team_id = ? AND season_id = ?
+LIMIT 1
+`
+
+type GetStandingParams struct {
+	TeamID   int64
+	SeasonID int64
+}
+
+func (q *Queries) GetStanding(ctx context.Context, arg GetStandingParams) (Standing, error) {
+	row := q.db.QueryRowContext(ctx, getStanding, arg.TeamID, arg.SeasonID)
+	var i Standing
+	err := row.Scan(
+		&i.ID,
+		&i.TeamID,
+		&i.SeasonID,
+		&i.Points,
+		&i.Wins,
+		&i.Draws,
+		&i.Losses,
+		&i.GoalDiff,
+		&i.GoalsFor,
+		&i.GoalsAgainst,
+	)
+	return i, err
+}
+
+const getTeam = `-- name: GetTeam :one
+SELECT id, name, strength, budget FROM team
+WHERE id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetTeam(ctx context.Context, id int64) (Team, error) {
+	row := q.db.QueryRowContext(ctx, getTeam, id)
+	var i Team
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Strength,
+		&i.Budget,
+	)
+	return i, err
+}
+
+const getTeamByName = `-- name: GetTeamByName :one
+SELECT id, name, strength, budget FROM team
+WHERE name = ?
+LIMIT 1
+`
+
+func (q *Queries) GetTeamByName(ctx context.Context, name string) (Team, error) {
+	row := q.db.QueryRowContext(ctx, getTeamByName, name)
+	var i Team
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Strength,
+		&i.Budget,
+	)
+	return i, err
+}
+
+const getTournamentBySeason = `-- name: GetTournamentBySeason :one
+SELECT id, season_id, group_count, qualifiers, away_goals, separate_divisions FROM tournament WHERE season_id = ?
+`
+
+func (q *Queries) GetTournamentBySeason(ctx context.Context, seasonID int64) (Tournament, error) {
+	row := q.db.QueryRowContext(ctx, getTournamentBySeason, seasonID)
+	var i Tournament
+	err := row.Scan(
+		&i.ID,
+		&i.SeasonID,
+		&i.GroupCount,
+		&i.Qualifiers,
+		&i.AwayGoals,
+		&i.SeparateDivisions,
+	)
+	return i, err
+}
+
+const getUnplayedMatchesBySeason = `-- name: GetUnplayedMatchesBySeason :many
+SELECT id, season_id, home_id, guest_id, played, week, division_id FROM match
+WHERE played = FALSE AND season_id = ?
+ORDER BY week, id
+`
+
+func (q *Queries) GetUnplayedMatchesBySeason(ctx context.Context, seasonID int64) ([]Match, error) {
+	rows, err := q.db.QueryContext(ctx, getUnplayedMatchesBySeason, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Match
+	for rows.Next() {
+		var i Match
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.HomeID,
+			&i.GuestID,
+			&i.Played,
+			&i.Week,
+			&i.DivisionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnplayedMatchesByWeek = `-- name: GetUnplayedMatchesByWeek :many
+SELECT m.id, m.season_id, m.home_id, m.guest_id, m.played, m.week, m.division_id, 
+       ht.name as home_team_name, 
+       gt.name as guest_team_name,
+       ht.strength as home_team_strength,
+       gt.strength as guest_team_strength
+FROM match m
+JOIN team ht ON m.home_id = ht.id
+JOIN team gt ON m.guest_id = gt.id
+WHERE m.week = ? AND m.played = FALSE AND m.season_id = ?
+`
+
+type GetUnplayedMatchesByWeekParams struct {
+	Week     int64
+	SeasonID int64
+}
+
+type GetUnplayedMatchesByWeekRow struct {
+	ID                int64
+	SeasonID          int64
+	HomeID            int64
+	GuestID           int64
+	Played            sql.NullBool
+	Week              int64
+	DivisionID        sql.NullInt64
+	HomeTeamName      string
+	GuestTeamName     string
+	HomeTeamStrength  sql.NullInt64
+	GuestTeamStrength sql.NullInt64
+}
+
+func (q *Queries) GetUnplayedMatchesByWeek(ctx context.Context, arg GetUnplayedMatchesByWeekParams) ([]GetUnplayedMatchesByWeekRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUnplayedMatchesByWeek, arg.Week, arg.SeasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUnplayedMatchesByWeekRow
+	for rows.Next() {
+		var i GetUnplayedMatchesByWeekRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.HomeID,
+			&i.GuestID,
+			&i.Played,
+			&i.Week,
+			&i.DivisionID,
+			&i.HomeTeamName,
+			&i.GuestTeamName,
+			&i.HomeTeamStrength,
+			&i.GuestTeamStrength,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementWeek = `-- name: IncrementWeek :exec
+UPDATE game_state SET current_week = current_week + 1 WHERE season_id = ?
+`
+
+func (q *Queries) IncrementWeek(ctx context.Context, seasonID int64) error {
+	_, err := q.db.ExecContext(ctx, incrementWeek, seasonID)
+	return err
+}
+
+const initializeGameState = `-- name: InitializeGameState :exec
+INSERT INTO game_state (current_week, season_id) VALUES (1, ?)
+`
+
+func (q *Queries) InitializeGameState(ctx context.Context, seasonID int64) error {
+	_, err := q.db.ExecContext(ctx, initializeGameState, seasonID)
+	return err
+}
+
+const listCompetitionTeams = `-- name: ListCompetitionTeams :many
+SELECT ct.competition_id, ct.team_id, t.name as team_name
+FROM competition_team ct
+JOIN team t ON ct.team_id = t.id
+WHERE ct.competition_id = ?
+ORDER BY t.name
+`
+
+type ListCompetitionTeamsRow struct {
+	CompetitionID int64
+	TeamID        int64
+	TeamName      string
+}
+
+func (q *Queries) ListCompetitionTeams(ctx context.Context, competitionID int64) ([]ListCompetitionTeamsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCompetitionTeams, competitionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCompetitionTeamsRow
+	for rows.Next() {
+		var i ListCompetitionTeamsRow
+		if err := rows.Scan(
+			&i.CompetitionID,
+			&i.TeamID,
+			&i.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCompetitions = `-- name: ListCompetitions :many
+SELECT id, season_id, format, name, legs, win_points, draw_points, loss_points, bonus_goals, bonus_points, ranking_rules FROM competition WHERE season_id = ? ORDER BY id
+`
+
+func (q *Queries) ListCompetitions(ctx context.Context, seasonID int64) ([]Competition, error) {
+	rows, err := q.db.QueryContext(ctx, listCompetitions, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Competition
+	for rows.Next() {
+		var i Competition
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.Format,
+			&i.Name,
+			&i.Legs,
+			&i.WinPoints,
+			&i.DrawPoints,
+			&i.LossPoints,
+			&i.BonusGoals,
+			&i.BonusPoints,
+			&i.RankingRules,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCupTies = `-- name: ListCupTies :many
+SELECT ct.id, ct.cup_id, ct.round, ct.slot, ct.week, ct.home_id, ct.guest_id, ct.home_score, ct.guest_score, ct.extra_time, ct.home_penalties, ct.guest_penalties, ct.winner_id, ct.played,
+       ht.name as home_team_name,
+       gt.name as guest_team_name
+FROM cup_tie ct
+LEFT JOIN team ht ON ct.home_id = ht.id
+LEFT JOIN team gt ON ct.guest_id = gt.id
+WHERE ct.cup_id = ?
+ORDER BY ct.round, ct.slot
+`
+
+type ListCupTiesRow struct {
+	ID             int64
+	CupID          int64
+	Round          int64
+	Slot           int64
+	Week           int64
+	HomeID         sql.NullInt64
+	GuestID        sql.NullInt64
+	HomeScore      sql.NullInt64
+	GuestScore     sql.NullInt64
+	ExtraTime      bool
+	HomePenalties  sql.NullInt64
+	GuestPenalties sql.NullInt64
+	WinnerID       sql.NullInt64
+	Played         bool
+	HomeTeamName   sql.NullString
+	GuestTeamName  sql.NullString
+}
+
+func (q *Queries) ListCupTies(ctx context.Context, cupID int64) ([]ListCupTiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCupTies, cupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCupTiesRow
+	for rows.Next() {
+		var i ListCupTiesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CupID,
+			&i.Round,
+			&i.Slot,
+			&i.Week,
+			&i.HomeID,
+			&i.GuestID,
+			&i.HomeScore,
+			&i.GuestScore,
+			&i.ExtraTime,
+			&i.HomePenalties,
+			&i.GuestPenalties,
+			&i.WinnerID,
+			&i.Played,
+			&i.HomeTeamName,
+			&i.GuestTeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDivisionTeams = `-- name: ListDivisionTeams :many
+SELECT dt.division_id, dt.team_id
+FROM division_team dt
+JOIN division d ON d.id = dt.division_id
+WHERE d.season_id = ?
+ORDER BY d.tier, dt.team_id
+`
+
+func (q *Queries) ListDivisionTeams(ctx context.Context, seasonID int64) ([]DivisionTeam, error) {
+	rows, err := q.db.QueryContext(ctx, listDivisionTeams, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DivisionTeam
+	for rows.Next() {
+		var i DivisionTeam
+		if err := rows.Scan(&i.DivisionID, &i.TeamID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDivisions = `-- name: ListDivisions :many
+SELECT id, season_id, name, tier, relegation_places, playoff FROM division WHERE season_id = ? ORDER BY tier, id
+`
+
+func (q *Queries) ListDivisions(ctx context.Context, seasonID int64) ([]Division, error) {
+	rows, err := q.db.QueryContext(ctx, listDivisions, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Division
+	for rows.Next() {
+		var i Division
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.Name,
+			&i.Tier,
+			&i.RelegationPlaces,
+			&i.Playoff,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHeadToHeadMatches = `-- name: ListHeadToHeadMatches :many
+SELECT m.id, m.season_id, s.year AS season_year, m.week, m.home_id, m.guest_id, mr.home_score, mr.guest_score
+FROM match m
+JOIN match_result mr ON mr.match_id = m.id
+JOIN season s ON s.id = m.season_id
+WHERE m.played = TRUE
+  AND ((m.home_id = ?1 AND m.guest_id = ?2)
+    OR (m.home_id = ?2 AND m.guest_id = ?1))
+ORDER BY s.year DESC, m.week DESC, m.id DESC
+`
+
+type ListHeadToHeadMatchesParams struct {
+	TeamID     int64
+	OpponentID int64
+}
+
+type ListHeadToHeadMatchesRow struct {
+	ID         int64
+	SeasonID   int64
+	SeasonYear int64
+	Week       int64
+	HomeID     int64
+	GuestID    int64
+	HomeScore  int64
+	GuestScore int64
+}
+
+func (q *Queries) ListHeadToHeadMatches(ctx context.Context, arg ListHeadToHeadMatchesParams) ([]ListHeadToHeadMatchesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listHeadToHeadMatches, arg.TeamID, arg.OpponentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListHeadToHeadMatchesRow
+	for rows.Next() {
+		var i ListHeadToHeadMatchesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.SeasonYear,
+			&i.Week,
+			&i.HomeID,
+			&i.GuestID,
+			&i.HomeScore,
+			&i.GuestScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHeadToHeadSeasons = `-- name: ListHeadToHeadSeasons :many
+SELECT s.id AS season_id, s.year AS season_year,
+       CAST(COUNT(*) AS INTEGER) AS played,
+       CAST(SUM(h.goals_for > h.goals_against) AS INTEGER) AS wins,
+       CAST(SUM(h.goals_for = h.goals_against) AS INTEGER) AS draws,
+       CAST(SUM(h.goals_for < h.goals_against) AS INTEGER) AS losses,
+       CAST(SUM(h.goals_for) AS INTEGER) AS goals_for,
+       CAST(SUM(h.goals_against) AS INTEGER) AS goals_against
+FROM (
+    SELECT m.season_id, mr.home_score AS goals_for, mr.guest_score AS goals_against
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.home_id = ?1 AND m.guest_id = ?2 AND m.played = TRUE
+    UNION ALL
+    SELECT m.season_id, mr.guest_score AS goals_for, mr.home_score AS goals_against
+    FROM match m
+    JOIN match_result mr ON mr.match_id = m.id
+    WHERE m.home_id = ?2 AND m.guest_id = ?1 AND m.played = TRUE
+) h
+JOIN season s ON s.id = h.season_id
+GROUP BY s.id, s.year
+ORDER BY s.year
+`
+
+type ListHeadToHeadSeasonsParams struct {
+	TeamID     int64
+	OpponentID int64
+}
+
+type ListHeadToHeadSeasonsRow struct {
+	SeasonID     int64
+	SeasonYear   int64
+	Played       int64
+	Wins         int64
+	Draws        int64
+	Losses       int64
+	GoalsFor     int64
+	GoalsAgainst int64
+}
+
+func (q *Queries) ListHeadToHeadSeasons(ctx context.Context, arg ListHeadToHeadSeasonsParams) ([]ListHeadToHeadSeasonsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listHeadToHeadSeasons, arg.TeamID, arg.OpponentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListHeadToHeadSeasonsRow
+	for rows.Next() {
+		var i ListHeadToHeadSeasonsRow
+		if err := rows.Scan(
+			&i.SeasonID,
+			&i.SeasonYear,
+			&i.Played,
+			&i.Wins,
+			&i.Draws,
+			&i.Losses,
+			&i.GoalsFor,
+			&i.GoalsAgainst,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlayoffsBySeason = `-- name: ListPlayoffsBySeason :many
+SELECT p.id, p.season_id, p.division_id, p.upper_team_id, p.lower_team_id, p.upper_score, p.lower_score, p.winner_id,
+       ut.name as upper_team_name,
+       lt.name as lower_team_name
+FROM playoff p
+JOIN team ut ON p.upper_team_id = ut.id
+JOIN team lt ON p.lower_team_id = lt.id
+WHERE p.season_id = ?
+ORDER BY p.id
+`
+
+type ListPlayoffsBySeasonRow struct {
+	ID            int64
+	SeasonID      int64
+	DivisionID    int64
+	UpperTeamID   int64
+	LowerTeamID   int64
+	UpperScore    int64
+	LowerScore    int64
+	WinnerID      int64
+	UpperTeamName string
+	LowerTeamName string
+}
+
+func (q *Queries) ListPlayoffsBySeason(ctx context.Context, seasonID int64) ([]ListPlayoffsBySeasonRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPlayoffsBySeason, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPlayoffsBySeasonRow
+	for rows.Next() {
+		var i ListPlayoffsBySeasonRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeasonID,
+			&i.DivisionID,
+			&i.UpperTeamID,
+			&i.LowerTeamID,
+			&i.UpperScore,
+			&i.LowerScore,
+			&i.WinnerID,
+			&i.UpperTeamName,
+			&i.LowerTeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSanctions = `-- name: ListSanctions :many
+SELECT s.id, s.competition_id, s.team_id, s.kind, s.points, s.match_id, s.first_week, s.last_week, s.reason, s.created_at, c.format, t.name as team_name
+FROM sanction s
+JOIN competition c ON s.competition_id = c.id
+JOIN team t ON s.team_id = t.id
+WHERE c.season_id = ?
+ORDER BY s.created_at, s.id
+`
+
+type ListSanctionsRow struct {
+	ID            int64
+	CompetitionID int64
+	TeamID        int64
+	Kind          string
+	Points        int64
+	MatchID       sql.NullInt64
+	FirstWeek     int64
+	LastWeek      int64
+	Reason        string
+	CreatedAt     time.Time
+	Format        string
+	TeamName      string
+}
+
+func (q *Queries) ListSanctions(ctx context.Context, seasonID int64) ([]ListSanctionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSanctions, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSanctionsRow
+	for rows.Next() {
+		var i ListSanctionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CompetitionID,
+			&i.TeamID,
+			&i.Kind,
+			&i.Points,
+			&i.MatchID,
+			&i.FirstWeek,
+			&i.LastWeek,
+			&i.Reason,
+			&i.CreatedAt,
+			&i.Format,
+			&i.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSeasons = `-- name: ListSeasons :many
+SELECT id, year, seed, is_current, is_complete FROM season ORDER BY year, id
+`
+
+func (q *Queries) ListSeasons(ctx context.Context) ([]Season, error) {
+	rows, err := q.db.QueryContext(ctx, listSeasons)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Season
+	for rows.Next() {
+		var i Season
+		if err := rows.Scan(
+			&i.ID,
+			&i.Year,
+			&i.Seed,
+			&i.IsCurrent,
+			&i.IsComplete,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeamHistory = `-- name: ListTeamHistory :many
+SELECT th.id, th.team_id, th.season_id, th.last_season_standing, th.prize_money, th.strength_before, th.strength_after, th.budget_before, th.budget_after, s.year AS season_year
+FROM team_history th
+JOIN season s ON s.id = th.season_id
+ORDER BY th.team_id, s.year, th.id
+`
+
+type ListTeamHistoryRow struct {
+	ID                 int64
+	TeamID             int64
+	SeasonID           int64
+	LastSeasonStanding int64
+	PrizeMoney         int64
+	StrengthBefore     int64
+	StrengthAfter      int64
+	BudgetBefore       int64
+	BudgetAfter        int64
+	SeasonYear         int64
+}
+
+func (q *Queries) ListTeamHistory(ctx context.Context) ([]ListTeamHistoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTeamHistory)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTeamHistoryRow
+	for rows.Next() {
+		var i ListTeamHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamID,
+			&i.SeasonID,
+			&i.LastSeasonStanding,
+			&i.PrizeMoney,
+			&i.StrengthBefore,
+			&i.StrengthAfter,
+			&i.BudgetBefore,
+			&i.BudgetAfter,
+			&i.SeasonYear,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTeams = `-- name: ListTeams :many
+SELECT id, name, strength, budget FROM team
+ORDER BY name
+`
+
+func (q *Queries) ListTeams(ctx context.Context) ([]Team, error) {
+	rows, err := q.db.QueryContext(ctx, listTeams)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetMatchesByWeekRow
+	var items []Team
 	for rows.Next() {
-		var i GetMatchesByWeekRow
+		var i Team
 		if err := rows.Scan(
 			&i.ID,
-			&i.SeasonID,
-			&i.HomeID,
-			&i.GuestID,
-			&i.Played,
-			&i.Week,
-			&i.HomeTeamName,
-			&i.GuestTeamName,
-			&i.HomeTeamStrength,
-			&i.GuestTeamStrength,
+			&i.Name,
+			&i.Strength,
+			&i.Budget,
 		); err != nil {
 			return nil, err
 		}
@@ -273,119 +2028,64 @@ func (q *Queries) GetMatchesByWeek(ctx context.Context, arg GetMatchesByWeekPara
 	return items, nil
 }
 
-const getStanding = `-- name: GetStanding :one
-SELECT id, team_id, season_id, points, wins, draws, losses, goal_diff FROM standing
-WHERE team_id = ? AND season_id = ?
-LIMIT 1
-`
-
-type GetStandingParams struct {
-	TeamID   int64
-	SeasonID int64
-}
-
-func (q *Queries) GetStanding(ctx context.Context, arg GetStandingParams) (Standing, error) {
-	row := q.db.QueryRowContext(ctx, getStanding, arg.TeamID, arg.SeasonID)
-	var i Standing
-	err := row.Scan(
-		&i.ID,
-		&i.TeamID,
-		&i.SeasonID,
-		&i.Points,
-		&i.Wins,
-		&i.Draws,
-		&i.Losses,
-		&i.GoalDiff,
-	)
-	return i, err
-}
-
-const getTeam = `-- name: GetTeam :one
-SELECT id, name, strength, budget FROM team
-WHERE id = ?
-LIMIT 1
-`
-
-func (q *Queries) GetTeam(ctx context.Context, id int64) (Team, error) {
-	row := q.db.QueryRowContext(ctx, getTeam, id)
-	var i Team
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Strength,
-		&i.Budget,
-	)
-	return i, err
-}
-
-const getTeamByName = `-- name: GetTeamByName :one
-SELECT id, name, strength, budget FROM team
-WHERE name = ?
-LIMIT 1
-`
-
-func (q *Queries) GetTeamByName(ctx context.Context, name string) (Team, error) {
-	row := q.db.QueryRowContext(ctx, getTeamByName, name)
-	var i Team
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Strength,
-		&i.Budget,
-	)
-	return i, err
-}
-
-const getUnplayedMatchesByWeek = `-- name: GetUnplayedMatchesByWeek :many
-SELECT m.id, m.season_id, m.home_id, m.guest_id, m.played, m.week, 
-       ht.name as home_team_name, 
-       gt.name as guest_team_name,
-       ht.strength as home_team_strength,
-       gt.strength as guest_team_strength
-FROM match m
-JOIN team ht ON m.home_id = ht.id
-JOIN team gt ON m.guest_id = gt.id
-WHERE m.week = ? AND m.played = FALSE AND m.season_id = ?
+const listTournamentMatches = `-- name: ListTournamentMatches :many
+SELECT tm.id, tm.tournament_id, tm.round, tm.slot, tm.leg, tm.week, tm.home_id, tm.guest_id, tm.home_score, tm.guest_score, tm.extra_time, tm.home_penalties, tm.guest_penalties, tm.winner_id, tm.played,
+       ht.name as home_team_name,
+       gt.name as guest_team_name
+FROM tournament_match tm
+LEFT JOIN team ht ON tm.home_id = ht.id
+LEFT JOIN team gt ON tm.guest_id = gt.id
+WHERE tm.tournament_id = ?
+ORDER BY tm.round, tm.slot, tm.leg, tm.id
 `
 
-type GetUnplayedMatchesByWeekParams struct {
-	Week     int64
-	SeasonID int64
-}
-
-type GetUnplayedMatchesByWeekRow struct {
-	ID                int64
-	SeasonID          int64
-	HomeID            int64
-	GuestID           int64
-	Played            sql.NullBool
-	Week              int64
-	HomeTeamName      string
-	GuestTeamName     string
-	HomeTeamStrength  sql.NullInt64
-	GuestTeamStrength sql.NullInt64
+type ListTournamentMatchesRow struct {
+	ID             int64
+	TournamentID   int64
+	Round          int64
+	Slot           int64
+	Leg            int64
+	Week           int64
+	HomeID         sql.NullInt64
+	GuestID        sql.NullInt64
+	HomeScore      sql.NullInt64
+	GuestScore     sql.NullInt64
+	ExtraTime      bool
+	HomePenalties  sql.NullInt64
+	GuestPenalties sql.NullInt64
+	WinnerID       sql.NullInt64
+	Played         bool
+	HomeTeamName   sql.NullString
+	GuestTeamName  sql.NullString
 }
 
-func (q *Queries) GetUnplayedMatchesByWeek(ctx context.Context, arg GetUnplayedMatchesByWeekParams) ([]GetUnplayedMatchesByWeekRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUnplayedMatchesByWeek, arg.Week, arg.SeasonID)
+func (q *Queries) ListTournamentMatches(ctx context.Context, tournamentID int64) ([]ListTournamentMatchesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTournamentMatches, tournamentID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUnplayedMatchesByWeekRow
+	var items []ListTournamentMatchesRow
 	for rows.Next() {
-		var i GetUnplayedMatchesByWeekRow
+		var i ListTournamentMatchesRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.SeasonID,
+			&i.TournamentID,
+			&i.Round,
+			&i.Slot,
+			&i.Leg,
+			&i.Week,
 			&i.HomeID,
 			&i.GuestID,
+			&i.HomeScore,
+			&i.GuestScore,
+			&i.ExtraTime,
+			&i.HomePenalties,
+			&i.GuestPenalties,
+			&i.WinnerID,
 			&i.Played,
-			&i.Week,
 			&i.HomeTeamName,
 			&i.GuestTeamName,
-			&i.HomeTeamStrength,
-			&i.GuestTeamStrength,
 		); err != nil {
 			return nil, err
 		}
@@ -400,34 +2100,37 @@ func (q *Queries) GetUnplayedMatchesByWeek(ctx context.Context, arg GetUnplayedM
 	return items, nil
 }
 
-const incrementWeek = `-- name: IncrementWeek :exec
-UPDATE game_state SET current_week = current_week + 1 WHERE season_id = ?
+const listTournamentTeams = `-- name: ListTournamentTeams :many
+SELECT tt.tournament_id, tt.team_id, tt.pot, tt.group_number, t.name as team_name
+FROM tournament_team tt
+JOIN team t ON tt.team_id = t.id
+WHERE tt.tournament_id = ?
+ORDER BY tt.group_number, tt.pot
 `
 
-func (q *Queries) IncrementWeek(ctx context.Context, seasonID int64) error {
-	_, err := q.db.ExecContext(ctx, incrementWeek, seasonID)
-	return err
+type ListTournamentTeamsRow struct {
+	TournamentID int64
+	TeamID       int64
+	Pot          int64
+	GroupNumber  int64
+	TeamName     string
 }
 
-const listTeams = `-- name: ListTeams :many
-SELECT id, name, strength, budget FROM team
-ORDER BY name
-`
-
-func (q *Queries) ListTeams(ctx context.Context) ([]Team, error) {
-	rows, err := q.db.QueryContext(ctx, listTeams)
+func (q *Queries) ListTournamentTeams(ctx context.Context, tournamentID int64) ([]ListTournamentTeamsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTournamentTeams, tournamentID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Team
+	var items []ListTournamentTeamsRow
 	for rows.Next() {
-		var i Team
+		var i ListTournamentTeamsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Strength,
-			&i.Budget,
+			&i.TournamentID,
+			&i.TeamID,
+			&i.Pot,
+			&i.GroupNumber,
+			&i.TeamName,
 		); err != nil {
 			return nil, err
 		}
@@ -451,12 +2154,106 @@ func (q *Queries) MarkMatchAsPlayed(ctx context.Context, id int64) error {
 	return err
 }
 
-const resetToYear = `-- name: ResetToYear :exec
-DELETE FROM match_result
+const removeDivisionTeam = `-- name: RemoveDivisionTeam :exec
+DELETE FROM division_team
+WHERE team_id = ? AND division_id IN (SELECT id FROM division WHERE season_id = ?)
+`
+
+type RemoveDivisionTeamParams struct {
+	TeamID   int64
+	SeasonID int64
+}
+
+func (q *Queries) RemoveDivisionTeam(ctx context.Context, arg RemoveDivisionTeamParams) error {
+	_, err := q.db.ExecContext(ctx, removeDivisionTeam, arg.TeamID, arg.SeasonID)
+	return err
+}
+
+const reopenSeason = `-- name: ReopenSeason :exec
+UPDATE season SET is_complete = FALSE WHERE id = ?
+`
+
+func (q *Queries) ReopenSeason(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, reopenSeason, id)
+	return err
+}
+
+const resetCupTiesFromWeek = `-- name: ResetCupTiesFromWeek :exec
+UPDATE cup_tie
+SET home_score = NULL,
+    guest_score = NULL,
+    extra_time = FALSE,
+    home_penalties = NULL,
+    guest_penalties = NULL,
+    winner_id = NULL,
+    played = FALSE
+WHERE week >= ?1 AND cup_id IN (SELECT id FROM cup WHERE season_id = ?2)
+`
+
+type ResetCupTiesFromWeekParams struct {
+	Week     int64
+	SeasonID int64
+}
+
+func (q *Queries) ResetCupTiesFromWeek(ctx context.Context, arg ResetCupTiesFromWeekParams) error {
+	_, err := q.db.ExecContext(ctx, resetCupTiesFromWeek, arg.Week, arg.SeasonID)
+	return err
+}
+
+const resetTournamentMatchesFromWeek = `-- name: ResetTournamentMatchesFromWeek :exec
+UPDATE tournament_match
+SET home_score = NULL,
+    guest_score = NULL,
+    extra_time = FALSE,
+    home_penalties = NULL,
+    guest_penalties = NULL,
+    winner_id = NULL,
+    played = FALSE
+WHERE week >= ?1 AND tournament_id IN (SELECT id FROM tournament WHERE season_id = ?2)
+`
+
+type ResetTournamentMatchesFromWeekParams struct {
+	Week     int64
+	SeasonID int64
+}
+
+func (q *Queries) ResetTournamentMatchesFromWeek(ctx context.Context, arg ResetTournamentMatchesFromWeekParams) error {
+	_, err := q.db.ExecContext(ctx, resetTournamentMatchesFromWeek, arg.Week, arg.SeasonID)
+	return err
+}
+
+const saveCupTieResult = `-- name: SaveCupTieResult :exec
+UPDATE cup_tie
+SET home_score = ?,
+    guest_score = ?,
+    extra_time = ?,
+    home_penalties = ?,
+    guest_penalties = ?,
+    winner_id = ?,
+    played = TRUE
+WHERE id = ?
 `
 
-func (q *Queries) ResetToYear(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, resetToYear)
+type SaveCupTieResultParams struct {
+	HomeScore      sql.NullInt64
+	GuestScore     sql.NullInt64
+	ExtraTime      bool
+	HomePenalties  sql.NullInt64
+	GuestPenalties sql.NullInt64
+	WinnerID       sql.NullInt64
+	ID             int64
+}
+
+func (q *Queries) SaveCupTieResult(ctx context.Context, arg SaveCupTieResultParams) error {
+	_, err := q.db.ExecContext(ctx, saveCupTieResult,
+		arg.HomeScore,
+		arg.GuestScore,
+		arg.ExtraTime,
+		arg.HomePenalties,
+		arg.GuestPenalties,
+		arg.WinnerID,
+		arg.ID,
+	)
 	return err
 }
 
@@ -489,6 +2286,70 @@ func (q *Queries) SaveResult(ctx context.Context, arg SaveResultParams) error {
 	return err
 }
 
+const saveTournamentMatchResult = `-- name: SaveTournamentMatchResult :exec
+UPDATE tournament_match
+SET home_score = ?,
+    guest_score = ?,
+    extra_time = ?,
+    home_penalties = ?,
+    guest_penalties = ?,
+    winner_id = ?,
+    played = TRUE
+WHERE id = ?
+`
+
+type SaveTournamentMatchResultParams struct {
+	HomeScore      sql.NullInt64
+	GuestScore     sql.NullInt64
+	ExtraTime      bool
+	HomePenalties  sql.NullInt64
+	GuestPenalties sql.NullInt64
+	WinnerID       sql.NullInt64
+	ID             int64
+}
+
+func (q *Queries) SaveTournamentMatchResult(ctx context.Context, arg SaveTournamentMatchResultParams) error {
+	_, err := q.db.ExecContext(ctx, saveTournamentMatchResult,
+		arg.HomeScore,
+		arg.GuestScore,
+		arg.ExtraTime,
+		arg.HomePenalties,
+		arg.GuestPenalties,
+		arg.WinnerID,
+		arg.ID,
+	)
+	return err
+}
+
+const setCompetitionRankingRules = `-- name: SetCompetitionRankingRules :exec
+UPDATE competition SET ranking_rules = ? WHERE id = ?
+`
+
+type SetCompetitionRankingRulesParams struct {
+	RankingRules string
+	ID           int64
+}
+
+func (q *Queries) SetCompetitionRankingRules(ctx context.Context, arg SetCompetitionRankingRulesParams) error {
+	_, err := q.db.ExecContext(ctx, setCompetitionRankingRules, arg.RankingRules, arg.ID)
+	return err
+}
+
+const setCupTieTeams = `-- name: SetCupTieTeams :exec
+UPDATE cup_tie SET home_id = ?, guest_id = ? WHERE id = ?
+`
+
+type SetCupTieTeamsParams struct {
+	HomeID  sql.NullInt64
+	GuestID sql.NullInt64
+	ID      int64
+}
+
+func (q *Queries) SetCupTieTeams(ctx context.Context, arg SetCupTieTeamsParams) error {
+	_, err := q.db.ExecContext(ctx, setCupTieTeams, arg.HomeID, arg.GuestID, arg.ID)
+	return err
+}
+
 const setCurrentSeason = `-- name: SetCurrentSeason :exec
 UPDATE season SET is_current = (season.id = ?) WHERE season.id IN (SELECT id FROM season)
 `
@@ -498,24 +2359,133 @@ func (q *Queries) SetCurrentSeason(ctx context.Context, id int64) error {
 	return err
 }
 
+const setCurrentWeek = `-- name: SetCurrentWeek :exec
+UPDATE game_state SET current_week = ? WHERE season_id = ?
+`
+
+type SetCurrentWeekParams struct {
+	CurrentWeek sql.NullInt64
+	SeasonID    int64
+}
+
+func (q *Queries) SetCurrentWeek(ctx context.Context, arg SetCurrentWeekParams) error {
+	_, err := q.db.ExecContext(ctx, setCurrentWeek, arg.CurrentWeek, arg.SeasonID)
+	return err
+}
+
+const setTournamentMatchTeams = `-- name: SetTournamentMatchTeams :exec
+UPDATE tournament_match SET home_id = ?, guest_id = ? WHERE id = ?
+`
+
+type SetTournamentMatchTeamsParams struct {
+	HomeID  sql.NullInt64
+	GuestID sql.NullInt64
+	ID      int64
+}
+
+func (q *Queries) SetTournamentMatchTeams(ctx context.Context, arg SetTournamentMatchTeamsParams) error {
+	_, err := q.db.ExecContext(ctx, setTournamentMatchTeams, arg.HomeID, arg.GuestID, arg.ID)
+	return err
+}
+
+const unplayMatchesFromWeek = `-- name: UnplayMatchesFromWeek :exec
+UPDATE match SET played = FALSE WHERE season_id = ? AND week >= ?
+`
+
+type UnplayMatchesFromWeekParams struct {
+	SeasonID int64
+	Week     int64
+}
+
+func (q *Queries) UnplayMatchesFromWeek(ctx context.Context, arg UnplayMatchesFromWeekParams) error {
+	_, err := q.db.ExecContext(ctx, unplayMatchesFromWeek, arg.SeasonID, arg.Week)
+	return err
+}
+
+const updateCompetitionRules = `-- name: UpdateCompetitionRules :exec
+UPDATE competition
+SET legs = ?,
+    win_points = ?,
+    draw_points = ?,
+    loss_points = ?,
+    bonus_goals = ?,
+    bonus_points = ?,
+    ranking_rules = ?
+WHERE id = ?
+`
+
+type UpdateCompetitionRulesParams struct {
+	Legs         int64
+	WinPoints    int64
+	DrawPoints   int64
+	LossPoints   int64
+	BonusGoals   int64
+	BonusPoints  int64
+	RankingRules string
+	ID           int64
+}
+
+func (q *Queries) UpdateCompetitionRules(ctx context.Context, arg UpdateCompetitionRulesParams) error {
+	_, err := q.db.ExecContext(ctx, updateCompetitionRules,
+		arg.Legs,
+		arg.WinPoints,
+		arg.DrawPoints,
+		arg.LossPoints,
+		arg.BonusGoals,
+		arg.BonusPoints,
+		arg.RankingRules,
+		arg.ID,
+	)
+	return err
+}
+
+const updateDivision = `-- name: UpdateDivision :exec
+UPDATE division
+SET name = ?, tier = ?, relegation_places = ?, playoff = ?
+WHERE id = ?
+`
+
+type UpdateDivisionParams struct {
+	Name             string
+	Tier             int64
+	RelegationPlaces int64
+	Playoff          bool
+	ID               int64
+}
+
+func (q *Queries) UpdateDivision(ctx context.Context, arg UpdateDivisionParams) error {
+	_, err := q.db.ExecContext(ctx, updateDivision,
+		arg.Name,
+		arg.Tier,
+		arg.RelegationPlaces,
+		arg.Playoff,
+		arg.ID,
+	)
+	return err
+}
+
 const updateStanding = `-- name: UpdateStanding :exec
 UPDATE standing
 SET points = ?,
     wins = ?,
     draws = ?,
     losses = ?,
-    goal_diff = ?
+    goal_diff = ?,
+    goals_for = ?,
+    goals_against = ?
 WHERE team_id = ? AND season_id = ?
 `
 
 type UpdateStandingParams struct {
-	Points   sql.NullInt64
-	Wins     sql.NullInt64
-	Draws    sql.NullInt64
-	Losses   sql.NullInt64
-	GoalDiff sql.NullInt64
-	TeamID   int64
-	SeasonID int64
+	Points       sql.NullInt64
+	Wins         sql.NullInt64
+	Draws        sql.NullInt64
+	Losses       sql.NullInt64
+	GoalDiff     sql.NullInt64
+	GoalsFor     sql.NullInt64
+	GoalsAgainst sql.NullInt64
+	TeamID       int64
+	SeasonID     int64
 }
 
 func (q *Queries) UpdateStanding(ctx context.Context, arg UpdateStandingParams) error {
@@ -525,12 +2495,37 @@ func (q *Queries) UpdateStanding(ctx context.Context, arg UpdateStandingParams)
 		arg.Draws,
 		arg.Losses,
 		arg.GoalDiff,
+		arg.GoalsFor,
+		arg.GoalsAgainst,
 		arg.TeamID,
 		arg.SeasonID,
 	)
 	return err
 }
 
+const updateTeam = `-- name: UpdateTeam :exec
+UPDATE team
+SET name = ?, strength = ?, budget = ?
+WHERE id = ?
+`
+
+type UpdateTeamParams struct {
+	Name     string
+	Strength sql.NullInt64
+	Budget   sql.NullInt64
+	ID       int64
+}
+
+func (q *Queries) UpdateTeam(ctx context.Context, arg UpdateTeamParams) error {
+	_, err := q.db.ExecContext(ctx, updateTeam,
+		arg.Name,
+		arg.Strength,
+		arg.Budget,
+		arg.ID,
+	)
+	return err
+}
+
 const updateTeamStrength = `-- name: UpdateTeamStrength :exec
 UPDATE team
 SET strength = ?
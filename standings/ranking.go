@@ -0,0 +1,182 @@
+package standings
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/orhosko/go-backend/simulation"
+)
+
+// TieBreaker separates teams that finish level on points. Higher values of a
+// tie-breaker rank a team higher, except for fair play where fewer
+// disciplinary points are better.
+type TieBreaker string
+
+const (
+	GoalDifference           TieBreaker = "goal-difference"
+	GoalsScored              TieBreaker = "goals-scored"
+	HeadToHeadPoints         TieBreaker = "head-to-head-points"
+	HeadToHeadGoalDifference TieBreaker = "head-to-head-goal-difference"
+	AwayGoals                TieBreaker = "away-goals"
+	Wins                     TieBreaker = "wins"
+	FairPlay                 TieBreaker = "fair-play"
+	DrawingOfLots            TieBreaker = "drawing-of-lots"
+)
+
+// Rules is an ordered chain of tie-breakers applied after points.
+type Rules struct {
+	Name        string
+	Label       string
+	TieBreakers []TieBreaker
+}
+
+var (
+	// PremierLeague ranks level teams on overall goal difference first.
+	PremierLeague = Rules{
+		Name:        "premier-league",
+		Label:       "Premier League",
+		TieBreakers: []TieBreaker{GoalDifference, GoalsScored, HeadToHeadPoints, AwayGoals, DrawingOfLots},
+	}
+	// LaLiga ranks level teams on their head-to-head record first.
+	LaLiga = Rules{
+		Name:        "la-liga",
+		Label:       "La Liga",
+		TieBreakers: []TieBreaker{HeadToHeadPoints, HeadToHeadGoalDifference, GoalDifference, GoalsScored, FairPlay, DrawingOfLots},
+	}
+	// SerieA ranks level teams on their head-to-head record first.
+	SerieA = Rules{
+		Name:        "serie-a",
+		Label:       "Serie A",
+		TieBreakers: []TieBreaker{HeadToHeadPoints, HeadToHeadGoalDifference, GoalDifference, GoalsScored, Wins, DrawingOfLots},
+	}
+)
+
+// Presets lists the available rule sets in display order.
+var Presets = []Rules{PremierLeague, LaLiga, SerieA}
+
+// RulesByName returns the preset with the given name.
+func RulesByName(name string) (Rules, error) {
+	for _, rules := range Presets {
+		if rules.Name == name {
+			return rules, nil
+		}
+	}
+	return Rules{}, fmt.Errorf("unknown ranking rules %q", name)
+}
+
+// Result is the score of a played match, used for head-to-head tie-breakers.
+type Result struct {
+	HomeID     int64
+	GuestID    int64
+	HomeScore  int64
+	GuestScore int64
+}
+
+// Rank sorts entries by points and separates level teams with the rules'
+// tie-breakers. Head-to-head tie-breakers only count results between the
+// teams that are still level and are worth the given points, and seed makes
+// the drawing of lots repeatable.
+func Rank(entries []Entry, results []Result, rules Rules, points Points, seed int64) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Points > entries[j].Points
+	})
+
+	r := ranker{results: results, points: points, seed: seed}
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && entries[end].Points == entries[start].Points {
+			end++
+		}
+		r.breakTies(entries[start:end], rules.TieBreakers)
+		start = end
+	}
+}
+
+type ranker struct {
+	results []Result
+	points  Points
+	seed    int64
+}
+
+// breakTies orders a group of teams level on everything so far by the first
+// tie-breaker of chain, then recurses into each group still level.
+func (r ranker) breakTies(group []Entry, chain []TieBreaker) {
+	if len(group) < 2 || len(chain) == 0 {
+		return
+	}
+
+	keys := r.keys(chain[0], group)
+	sort.SliceStable(group, func(i, j int) bool {
+		return keys[group[i].Team.ID] > keys[group[j].Team.ID]
+	})
+
+	for start := 0; start < len(group); {
+		end := start + 1
+		for end < len(group) && keys[group[end].Team.ID] == keys[group[start].Team.ID] {
+			end++
+		}
+		r.breakTies(group[start:end], chain[1:])
+		start = end
+	}
+}
+
+// keys returns the value of a tie-breaker for every team in group.
+func (r ranker) keys(tb TieBreaker, group []Entry) map[int64]int64 {
+	keys := make(map[int64]int64, len(group))
+
+	switch tb {
+	case HeadToHeadPoints, HeadToHeadGoalDifference:
+		points, goalDiff := r.headToHead(group)
+		if tb == HeadToHeadPoints {
+			return points
+		}
+		return goalDiff
+	}
+
+	for _, e := range group {
+		switch tb {
+		case GoalDifference:
+			keys[e.Team.ID] = e.GoalDiff()
+		case GoalsScored:
+			keys[e.Team.ID] = e.GoalsFor
+		case AwayGoals:
+			keys[e.Team.ID] = e.AwayGoalsFor
+		case Wins:
+			keys[e.Team.ID] = e.Wins
+		case FairPlay:
+			keys[e.Team.ID] = -e.FairPlayPoints
+		case DrawingOfLots:
+			keys[e.Team.ID] = lot(r.seed, e.Team.ID)
+		}
+	}
+	return keys
+}
+
+// headToHead builds the mini-table of the results between teams in group.
+func (r ranker) headToHead(group []Entry) (points, goalDiff map[int64]int64) {
+	points = make(map[int64]int64, len(group))
+	goalDiff = make(map[int64]int64, len(group))
+
+	inGroup := make(map[int64]bool, len(group))
+	for _, e := range group {
+		inGroup[e.Team.ID] = true
+	}
+
+	for _, res := range r.results {
+		if !inGroup[res.HomeID] || !inGroup[res.GuestID] {
+			continue
+		}
+
+		goalDiff[res.HomeID] += res.HomeScore - res.GuestScore
+		goalDiff[res.GuestID] += res.GuestScore - res.HomeScore
+		points[res.HomeID] += r.points.For(res.HomeScore, res.GuestScore)
+		points[res.GuestID] += r.points.For(res.GuestScore, res.HomeScore)
+	}
+
+	return points, goalDiff
+}
+
+// lot draws a repeatable pseudo-random number for a team.
+func lot(seed, teamID int64) int64 {
+	return int64(simulation.Mix(uint64(seed), uint64(teamID)) >> 1)
+}
@@ -0,0 +1,196 @@
+package standings
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+// entry returns a table entry of a team with the given points and goals.
+func entry(id, points, goalsFor, goalsAgainst int64) Entry {
+	return Entry{
+		Team:         sqlc.Team{ID: id},
+		Points:       points,
+		GoalsFor:     goalsFor,
+		GoalsAgainst: goalsAgainst,
+	}
+}
+
+// order returns the team IDs of a ranked table.
+func order(entries []Entry) []int64 {
+	ids := make([]int64, len(entries))
+	for i, e := range entries {
+		ids[i] = e.Team.ID
+	}
+	return ids
+}
+
+func TestRank(t *testing.T) {
+	// Teams 1 and 2 are level on everything the Premier League looks at
+	// before head-to-head, which team 2 won.
+	headToHead := []Entry{entry(1, 10, 8, 4), entry(2, 10, 8, 4), entry(3, 4, 3, 9)}
+	headToHeadResults := []Result{
+		{HomeID: 1, GuestID: 2, HomeScore: 0, GuestScore: 1},
+		{HomeID: 2, GuestID: 1, HomeScore: 2, GuestScore: 2},
+	}
+
+	// Team 1 has the better goal difference, team 2 won their match
+	goalDifference := []Entry{entry(1, 10, 9, 4), entry(2, 10, 8, 5)}
+	goalDifferenceResults := []Result{{HomeID: 2, GuestID: 1, HomeScore: 1, GuestScore: 0}}
+
+	// Teams 1, 2 and 3 are level on points. Team 1 beat both others, which
+	// won a match each against one another. Between just teams 2 and 3,
+	// team 2 has the better goal difference, but team 3 has the better one
+	// between all three and overall.
+	threeWay := []Entry{entry(1, 10, 6, 6), entry(2, 10, 5, 7), entry(3, 10, 8, 5), entry(4, 12, 4, 2)}
+	threeWayResults := []Result{
+		{HomeID: 1, GuestID: 2, HomeScore: 5, GuestScore: 0},
+		{HomeID: 1, GuestID: 3, HomeScore: 1, GuestScore: 0},
+		{HomeID: 2, GuestID: 3, HomeScore: 3, GuestScore: 1},
+		{HomeID: 3, GuestID: 2, HomeScore: 1, GuestScore: 0},
+	}
+
+	// Teams 1 and 2 drew both their matches and are level on goals. Team 1
+	// has more wins, team 2 more away goals and fewer disciplinary points.
+	later := []Entry{entry(1, 9, 7, 7), entry(2, 9, 7, 7)}
+	later[0].Wins, later[1].Wins = 3, 2
+	later[0].AwayGoalsFor, later[1].AwayGoalsFor = 2, 4
+	later[0].FairPlayPoints, later[1].FairPlayPoints = 5, 3
+	laterResults := []Result{
+		{HomeID: 1, GuestID: 2, HomeScore: 1, GuestScore: 1},
+		{HomeID: 2, GuestID: 1, HomeScore: 0, GuestScore: 0},
+	}
+
+	tests := []struct {
+		name    string
+		entries []Entry
+		results []Result
+		want    map[string][]int64 // by preset name
+	}{
+		{
+			name:    "two teams separated by head-to-head",
+			entries: headToHead,
+			results: headToHeadResults,
+			want: map[string][]int64{
+				PremierLeague.Name: {2, 1, 3},
+				LaLiga.Name:        {2, 1, 3},
+				SerieA.Name:        {2, 1, 3},
+			},
+		},
+		{
+			name:    "goal difference before head-to-head",
+			entries: goalDifference,
+			results: goalDifferenceResults,
+			want: map[string][]int64{
+				PremierLeague.Name: {1, 2},
+				LaLiga.Name:        {2, 1},
+				SerieA.Name:        {2, 1},
+			},
+		},
+		{
+			name:    "three teams split by a mini-table",
+			entries: threeWay,
+			results: threeWayResults,
+			want: map[string][]int64{
+				PremierLeague.Name: {4, 3, 1, 2},
+				LaLiga.Name:        {4, 1, 2, 3},
+				SerieA.Name:        {4, 1, 2, 3},
+			},
+		},
+		{
+			name:    "level until the preset's own tie-breaker",
+			entries: later,
+			results: laterResults,
+			want: map[string][]int64{
+				PremierLeague.Name: {2, 1}, // away goals
+				LaLiga.Name:        {2, 1}, // fair play
+				SerieA.Name:        {1, 2}, // wins
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, rules := range Presets {
+			t.Run(tt.name+"/"+rules.Name, func(t *testing.T) {
+				entries := slices.Clone(tt.entries)
+				Rank(entries, tt.results, rules, DefaultPoints, 1)
+				if got := order(entries); !slices.Equal(got, tt.want[rules.Name]) {
+					t.Errorf("Rank = %v, want %v", got, tt.want[rules.Name])
+				}
+			})
+		}
+	}
+}
+
+func TestRankHeadToHeadPoints(t *testing.T) {
+	// Team 2 drew both its matches against the others and team 1 drew one
+	// and won one, so the mini-table depends on what a draw is worth
+	results := []Result{
+		{HomeID: 1, GuestID: 2, HomeScore: 1, GuestScore: 1},
+		{HomeID: 2, GuestID: 3, HomeScore: 0, GuestScore: 0},
+		{HomeID: 1, GuestID: 3, HomeScore: 2, GuestScore: 0},
+	}
+
+	for _, tt := range []struct {
+		points Points
+		want   []int64
+	}{
+		{DefaultPoints, []int64{1, 2, 3}},
+		{Points{Win: 1, Draw: 2}, []int64{2, 1, 3}},
+	} {
+		entries := []Entry{entry(3, 5, 3, 3), entry(2, 5, 3, 3), entry(1, 5, 3, 3)}
+		Rank(entries, results, LaLiga, tt.points, 1)
+		if got := order(entries); !slices.Equal(got, tt.want) {
+			t.Errorf("Rank with %+v = %v, want %v", tt.points, got, tt.want)
+		}
+	}
+}
+
+func TestRankDrawingOfLots(t *testing.T) {
+	level := func() []Entry {
+		return []Entry{entry(1, 6, 4, 4), entry(2, 6, 4, 4), entry(3, 6, 4, 4), entry(4, 6, 4, 4), entry(5, 6, 4, 4)}
+	}
+
+	for _, rules := range Presets {
+		t.Run(rules.Name, func(t *testing.T) {
+			winners := map[int64]bool{}
+			for seed := int64(1); seed <= 20; seed++ {
+				entries := level()
+				Rank(entries, nil, rules, DefaultPoints, seed)
+				want := order(entries)
+				winners[want[0]] = true
+
+				// The same seed draws the same lots whatever the order the
+				// teams come in
+				for i := 0; i < 5; i++ {
+					shuffled := level()
+					rand.New(rand.NewSource(int64(i))).Shuffle(len(shuffled), func(a, b int) {
+						shuffled[a], shuffled[b] = shuffled[b], shuffled[a]
+					})
+					Rank(shuffled, nil, rules, DefaultPoints, seed)
+					if got := order(shuffled); !slices.Equal(got, want) {
+						t.Fatalf("seed %d: Rank = %v for shuffled teams, want %v", seed, got, want)
+					}
+				}
+			}
+
+			if len(winners) < 2 {
+				t.Errorf("20 seeds drew the same winner %v every time", winners)
+			}
+		})
+	}
+}
+
+func TestRulesByName(t *testing.T) {
+	for _, rules := range Presets {
+		got, err := RulesByName(rules.Name)
+		if err != nil || got.Name != rules.Name {
+			t.Errorf("RulesByName(%q) = %+v, %v", rules.Name, got, err)
+		}
+	}
+	if _, err := RulesByName("bundesliga"); err == nil {
+		t.Error("RulesByName accepted an unknown preset")
+	}
+}
@@ -0,0 +1,398 @@
+package standings
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+// Repository is the subset of repository.Repository the standings service uses.
+type Repository interface {
+	ComputeStandings(ctx context.Context, seasonID int64) ([]sqlc.ComputeStandingsRow, error)
+	GetResultsBySeason(ctx context.Context, seasonID int64) ([]sqlc.GetResultsBySeasonRow, error)
+	GetStanding(ctx context.Context, teamID int64, seasonID int64) (sqlc.Standing, error)
+	CreateStanding(ctx context.Context, arg sqlc.CreateStandingParams) error
+	UpdateStanding(ctx context.Context, arg sqlc.UpdateStandingParams) error
+	GetCompetitionBySeason(ctx context.Context, arg sqlc.GetCompetitionBySeasonParams) (sqlc.Competition, error)
+	ListSanctions(ctx context.Context, seasonID int64) ([]sqlc.ListSanctionsRow, error)
+}
+
+// LeagueFormat is the format of a season's league competition.
+const LeagueFormat = "league"
+
+// Kinds of sanction that rank differently. A deduction takes points from a
+// team and a ban covers a range of weeks, other sanctions change the result
+// of a single match.
+const (
+	DeductionKind = "deduction"
+	BanKind       = "ban"
+)
+
+// Points are what a win, a draw and a loss are worth in a competition. A
+// team scoring at least BonusGoals in a match earns Bonus more, unless
+// BonusGoals is 0.
+type Points struct {
+	Win        int64
+	Draw       int64
+	Loss       int64
+	BonusGoals int64
+	Bonus      int64
+}
+
+// DefaultPoints are three points for a win and one for a draw.
+var DefaultPoints = Points{Win: 3, Draw: 1}
+
+// CompetitionPoints returns what a result is worth in a competition.
+func CompetitionPoints(competition sqlc.Competition) Points {
+	return Points{
+		Win:        competition.WinPoints,
+		Draw:       competition.DrawPoints,
+		Loss:       competition.LossPoints,
+		BonusGoals: competition.BonusGoals,
+		Bonus:      competition.BonusPoints,
+	}
+}
+
+// For returns the points a team earns with a result, bonus included.
+func (p Points) For(goalsFor, goalsAgainst int64) int64 {
+	var points int64
+	switch {
+	case goalsFor > goalsAgainst:
+		points = p.Win
+	case goalsFor == goalsAgainst:
+		points = p.Draw
+	default:
+		points = p.Loss
+	}
+	if p.BonusGoals > 0 && goalsFor >= p.BonusGoals {
+		points += p.Bonus
+	}
+	return points
+}
+
+// Record counts a result in a table entry.
+func (p Points) Record(entry *Entry, goalsFor, goalsAgainst int64, away bool) {
+	entry.Played++
+	entry.GoalsFor += goalsFor
+	entry.GoalsAgainst += goalsAgainst
+	if away {
+		entry.AwayGoalsFor += goalsFor
+	}
+	switch {
+	case goalsFor > goalsAgainst:
+		entry.Wins++
+	case goalsFor == goalsAgainst:
+		entry.Draws++
+	default:
+		entry.Losses++
+	}
+	entry.Points += p.For(goalsFor, goalsAgainst)
+}
+
+// CompetitionRules returns the tie-breaker rules a competition is ranked by.
+func CompetitionRules(competition sqlc.Competition) (Rules, error) {
+	return RulesByName(competition.RankingRules)
+}
+
+// LeaguePoints returns the points of a season's league. Seasons without a
+// league competition use the default points.
+func LeaguePoints(ctx context.Context, repo Repository, seasonID int64) (Points, error) {
+	competition, err := league(ctx, repo, seasonID)
+	if err != nil {
+		return Points{}, err
+	}
+	return CompetitionPoints(competition), nil
+}
+
+// LeagueRules returns the tie-breaker rules of a season's league. Seasons
+// without a league competition use the Premier League rules.
+func LeagueRules(ctx context.Context, repo Repository, seasonID int64) (Rules, error) {
+	competition, err := league(ctx, repo, seasonID)
+	if err != nil {
+		return Rules{}, err
+	}
+	return CompetitionRules(competition)
+}
+
+// league returns the league competition of a season, or one with the
+// default points and rules and no ID if the season has none.
+func league(ctx context.Context, repo Repository, seasonID int64) (sqlc.Competition, error) {
+	competition, err := repo.GetCompetitionBySeason(ctx, sqlc.GetCompetitionBySeasonParams{
+		SeasonID: seasonID,
+		Format:   LeagueFormat,
+	})
+	if err == sql.ErrNoRows {
+		return sqlc.Competition{
+			SeasonID:     seasonID,
+			Format:       LeagueFormat,
+			WinPoints:    DefaultPoints.Win,
+			DrawPoints:   DefaultPoints.Draw,
+			LossPoints:   DefaultPoints.Loss,
+			RankingRules: PremierLeague.Name,
+		}, nil
+	}
+	if err != nil {
+		return sqlc.Competition{}, fmt.Errorf("failed to fetch league: %w", err)
+	}
+	return competition, nil
+}
+
+// Penalties are what the sanctions taken against a team in a competition
+// cost it.
+type Penalties struct {
+	// Deducted are the points taken from the team.
+	Deducted int64
+	// FairPlayPoints count one for every sanction, a ban one for every week
+	// it covers.
+	FairPlayPoints int64
+}
+
+// TeamPenalties returns the penalties of each team of a competition of a
+// season.
+func TeamPenalties(ctx context.Context, repo Repository, seasonID, competitionID int64) (map[int64]Penalties, error) {
+	penalties := map[int64]Penalties{}
+	if competitionID == 0 {
+		return penalties, nil
+	}
+
+	rows, err := repo.ListSanctions(ctx, seasonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sanctions: %w", err)
+	}
+	for _, row := range rows {
+		if row.CompetitionID != competitionID {
+			continue
+		}
+		p := penalties[row.TeamID]
+		switch row.Kind {
+		case DeductionKind:
+			p.Deducted += row.Points
+			p.FairPlayPoints++
+		case BanKind:
+			p.FairPlayPoints += row.LastWeek - row.FirstWeek + 1
+		default:
+			p.FairPlayPoints++
+		}
+		penalties[row.TeamID] = p
+	}
+	return penalties, nil
+}
+
+// Entry is a team's line in the league table.
+type Entry struct {
+	Team         sqlc.Team
+	Played       int64
+	Points       int64
+	Wins         int64
+	Draws        int64
+	Losses       int64
+	GoalsFor     int64
+	GoalsAgainst int64
+	AwayGoalsFor int64
+
+	// Deducted are the points taken from the team by an admin, already
+	// subtracted from Points.
+	Deducted int64
+
+	// FairPlayPoints are the disciplinary points of the team's sanctions,
+	// fewer is better.
+	FairPlayPoints int64
+}
+
+// GoalDiff returns the team's goal difference.
+func (e Entry) GoalDiff() int64 {
+	return e.GoalsFor - e.GoalsAgainst
+}
+
+// Standing converts the entry into the row stored in the standing table.
+func (e Entry) Standing(seasonID int64) sqlc.Standing {
+	return sqlc.Standing{
+		TeamID:       e.Team.ID,
+		SeasonID:     seasonID,
+		Points:       sql.NullInt64{Int64: e.Points, Valid: true},
+		Wins:         sql.NullInt64{Int64: e.Wins, Valid: true},
+		Draws:        sql.NullInt64{Int64: e.Draws, Valid: true},
+		Losses:       sql.NullInt64{Int64: e.Losses, Valid: true},
+		GoalDiff:     sql.NullInt64{Int64: e.GoalDiff(), Valid: true},
+		GoalsFor:     sql.NullInt64{Int64: e.GoalsFor, Valid: true},
+		GoalsAgainst: sql.NullInt64{Int64: e.GoalsAgainst, Valid: true},
+	}
+}
+
+// Table computes the league table of a season directly from its played
+// matches and returns it ranked by the rules of the season's league.
+func Table(ctx context.Context, repo Repository, season sqlc.Season) ([]Entry, error) {
+	entries, competition, err := compute(ctx, repo, season.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := CompetitionRules(competition)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := Results(ctx, repo, season.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	Rank(entries, results, rules, CompetitionPoints(competition), season.Seed)
+	return entries, nil
+}
+
+// Results returns every played result of a season.
+func Results(ctx context.Context, repo Repository, seasonID int64) ([]Result, error) {
+	rows, err := repo.GetResultsBySeason(ctx, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, Result{
+			HomeID:     row.HomeID,
+			GuestID:    row.GuestID,
+			HomeScore:  row.HomeScore,
+			GuestScore: row.GuestScore,
+		})
+	}
+	return results, nil
+}
+
+// compute aggregates the played matches of a season into one unsorted entry
+// per team, worth the points of the season's league less the points deducted
+// from the team, and returns them with the league, whose rules rank them.
+func compute(ctx context.Context, repo Repository, seasonID int64) ([]Entry, sqlc.Competition, error) {
+	competition, err := league(ctx, repo, seasonID)
+	if err != nil {
+		return nil, sqlc.Competition{}, err
+	}
+	points := CompetitionPoints(competition)
+
+	rows, err := repo.ComputeStandings(ctx, seasonID)
+	if err != nil {
+		return nil, sqlc.Competition{}, err
+	}
+
+	penalties, err := TeamPenalties(ctx, repo, seasonID, competition.ID)
+	if err != nil {
+		return nil, sqlc.Competition{}, err
+	}
+
+	// Bonus points depend on the score of each match, not just its outcome
+	bonus := map[int64]int64{}
+	if points.BonusGoals > 0 {
+		results, err := Results(ctx, repo, seasonID)
+		if err != nil {
+			return nil, sqlc.Competition{}, err
+		}
+		for _, result := range results {
+			if result.HomeScore >= points.BonusGoals {
+				bonus[result.HomeID] += points.Bonus
+			}
+			if result.GuestScore >= points.BonusGoals {
+				bonus[result.GuestID] += points.Bonus
+			}
+		}
+	}
+
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		p := penalties[row.Team.ID]
+		entries = append(entries, Entry{
+			Team:           row.Team,
+			Played:         row.Played,
+			Points:         row.Wins*points.Win + row.Draws*points.Draw + row.Losses*points.Loss + bonus[row.Team.ID] - p.Deducted,
+			Wins:           row.Wins,
+			Draws:          row.Draws,
+			Losses:         row.Losses,
+			GoalsFor:       row.GoalsFor,
+			GoalsAgainst:   row.GoalsAgainst,
+			AwayGoalsFor:   row.AwayGoalsFor,
+			Deducted:       p.Deducted,
+			FairPlayPoints: p.FairPlayPoints,
+		})
+	}
+
+	return entries, competition, nil
+}
+
+// Refresh rewrites the cached standing rows of a season from its match
+// results. Call it in the same transaction that changes the results.
+func Refresh(ctx context.Context, repo Repository, seasonID int64) error {
+	entries, _, err := compute(ctx, repo, seasonID)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		standing := entry.Standing(seasonID)
+
+		_, err := repo.GetStanding(ctx, entry.Team.ID, seasonID)
+		if err == sql.ErrNoRows {
+			err = repo.CreateStanding(ctx, sqlc.CreateStandingParams{
+				TeamID:       standing.TeamID,
+				SeasonID:     standing.SeasonID,
+				Points:       standing.Points,
+				Wins:         standing.Wins,
+				Draws:        standing.Draws,
+				Losses:       standing.Losses,
+				GoalDiff:     standing.GoalDiff,
+				GoalsFor:     standing.GoalsFor,
+				GoalsAgainst: standing.GoalsAgainst,
+			})
+		} else if err == nil {
+			err = repo.UpdateStanding(ctx, sqlc.UpdateStandingParams{
+				TeamID:       standing.TeamID,
+				SeasonID:     standing.SeasonID,
+				Points:       standing.Points,
+				Wins:         standing.Wins,
+				Draws:        standing.Draws,
+				Losses:       standing.Losses,
+				GoalDiff:     standing.GoalDiff,
+				GoalsFor:     standing.GoalsFor,
+				GoalsAgainst: standing.GoalsAgainst,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store standing for team %d: %w", entry.Team.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Verify compares the cached standing rows of a season with the table
+// computed from its match results and returns the IDs of teams whose cached
+// row is missing or out of date.
+func Verify(ctx context.Context, repo Repository, seasonID int64) ([]int64, error) {
+	entries, _, err := compute(ctx, repo, seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifted []int64
+	for _, entry := range entries {
+		cached, err := repo.GetStanding(ctx, entry.Team.ID, seasonID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		want := entry.Standing(seasonID)
+		if err == sql.ErrNoRows ||
+			cached.Points != want.Points ||
+			cached.Wins != want.Wins ||
+			cached.Draws != want.Draws ||
+			cached.Losses != want.Losses ||
+			cached.GoalDiff != want.GoalDiff ||
+			cached.GoalsFor != want.GoalsFor ||
+			cached.GoalsAgainst != want.GoalsAgainst {
+			drifted = append(drifted, entry.Team.ID)
+		}
+	}
+
+	return drifted, nil
+}
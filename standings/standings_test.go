@@ -0,0 +1,53 @@
+package standings
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/orhosko/go-backend/sqlc"
+)
+
+// sanctionRepo serves a fixed list of sanctions, the only method
+// TeamPenalties needs.
+type sanctionRepo struct {
+	Repository
+	sanctions []sqlc.ListSanctionsRow
+}
+
+func (r sanctionRepo) ListSanctions(context.Context, int64) ([]sqlc.ListSanctionsRow, error) {
+	return r.sanctions, nil
+}
+
+func TestTeamPenalties(t *testing.T) {
+	repo := sanctionRepo{sanctions: []sqlc.ListSanctionsRow{
+		{TeamID: 1, CompetitionID: 10, Kind: DeductionKind, Points: 3},
+		{TeamID: 1, CompetitionID: 10, Kind: DeductionKind, Points: 2},
+		{TeamID: 2, CompetitionID: 10, Kind: "forfeit", MatchID: sql.NullInt64{Int64: 5, Valid: true}},
+		{TeamID: 3, CompetitionID: 10, Kind: BanKind, FirstWeek: 2, LastWeek: 4},
+		{TeamID: 4, CompetitionID: 11, Kind: DeductionKind, Points: 6}, // another competition
+	}}
+
+	got, err := TeamPenalties(context.Background(), repo, 1, 10)
+	if err != nil {
+		t.Fatalf("TeamPenalties: %v", err)
+	}
+	want := map[int64]Penalties{
+		1: {Deducted: 5, FairPlayPoints: 2},
+		2: {FairPlayPoints: 1},
+		3: {FairPlayPoints: 3},
+	}
+	if len(got) != len(want) {
+		t.Errorf("TeamPenalties = %+v, want %+v", got, want)
+	}
+	for team, p := range want {
+		if got[team] != p {
+			t.Errorf("penalties of team %d = %+v, want %+v", team, got[team], p)
+		}
+	}
+
+	// A season without the competition stored yet has no sanctions in it
+	if got, err := TeamPenalties(context.Background(), repo, 1, 0); err != nil || len(got) != 0 {
+		t.Errorf("TeamPenalties without a competition = %+v, %v, want none", got, err)
+	}
+}
@@ -62,7 +62,7 @@ func Layout(meta PageMeta) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</title><link rel=\"stylesheet\" href=\"/static/css/main.css\"></head><body><div class=\"container\"><nav class=\"navigation\"><a href=\"/\" class=\"nav-button\">Standings</a> <a href=\"/teams\" class=\"nav-button\">Teams</a> <a href=\"/matches\" class=\"nav-button\">Matches</a></nav>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</title><link rel=\"stylesheet\" href=\"/static/css/main.css\"></head><body><div class=\"container\"><nav class=\"navigation\"><a href=\"/\" class=\"nav-button\">Standings</a> <a href=\"/teams\" class=\"nav-button\">Teams</a> <a href=\"/matches\" class=\"nav-button\">Matches</a> <a href=\"/divisions\" class=\"nav-button\">Divisions</a> <a href=\"/competitions\" class=\"nav-button\">Competitions</a> <a href=\"/sanctions\" class=\"nav-button\">Sanctions</a> <a href=\"/cup\" class=\"nav-button\">Cup</a> <a href=\"/tournament\" class=\"nav-button\">Tournament</a> <a href=\"/seasons\" class=\"nav-button\">Seasons</a></nav>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
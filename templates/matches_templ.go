@@ -26,6 +26,7 @@ type MatchesPageData struct {
 	Matches       map[int][]MatchData // Map of week number to matches
 	CurrentSeason sqlc.Season
 	CurrentWeek   int
+	ReadOnly      bool // an archived season, whose results cannot be edited
 }
 
 // Matches is the main template for displaying match information by week
@@ -62,14 +63,20 @@ func Matches(data MatchesPageData) templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"page-header\"><h1>League Matches - Season ")
+			if data.ReadOnly {
+				templ_7745c5c3_Err = ArchivedSeason(int(data.CurrentSeason.Year), "/matches").Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, " <div class=\"page-header\"><h1>League Matches - Season ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var3 string
 			templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", data.CurrentSeason.Year))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 27, Col: 75}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 31, Col: 75}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 			if templ_7745c5c3_Err != nil {
@@ -82,7 +89,7 @@ func Matches(data MatchesPageData) templ.Component {
 			var templ_7745c5c3_Var4 string
 			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", data.CurrentWeek))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 28, Col: 71}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 32, Col: 71}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 			if templ_7745c5c3_Err != nil {
@@ -101,7 +108,7 @@ func Matches(data MatchesPageData) templ.Component {
 					var templ_7745c5c3_Var5 string
 					templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", week))
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 36, Col: 41}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 40, Col: 41}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 					if templ_7745c5c3_Err != nil {
@@ -119,7 +126,7 @@ func Matches(data MatchesPageData) templ.Component {
 						var templ_7745c5c3_Var6 string
 						templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("match-%d", match.Match.ID))
 						if templ_7745c5c3_Err != nil {
-							return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 40, Col: 76}
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 44, Col: 76}
 						}
 						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 						if templ_7745c5c3_Err != nil {
@@ -132,7 +139,7 @@ func Matches(data MatchesPageData) templ.Component {
 						var templ_7745c5c3_Var7 string
 						templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(match.HomeTeamName)
 						if templ_7745c5c3_Err != nil {
-							return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 42, Col: 54}
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 46, Col: 54}
 						}
 						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 						if templ_7745c5c3_Err != nil {
@@ -145,7 +152,7 @@ func Matches(data MatchesPageData) templ.Component {
 						var templ_7745c5c3_Var8 string
 						templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(match.GuestTeamName)
 						if templ_7745c5c3_Err != nil {
-							return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 44, Col: 55}
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 48, Col: 55}
 						}
 						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 						if templ_7745c5c3_Err != nil {
@@ -155,90 +162,121 @@ func Matches(data MatchesPageData) templ.Component {
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						if match.Result != nil {
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<div class=\"match-result\"><form method=\"POST\" action=\"")
+						if match.Result != nil && data.ReadOnly {
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<div class=\"match-result\"><div class=\"score-container\"><span>")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
-							var templ_7745c5c3_Var9 templ.SafeURL = templ.SafeURL(fmt.Sprintf("/matches/%d/edit", match.Match.ID))
-							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(string(templ_7745c5c3_Var9)))
+							var templ_7745c5c3_Var9 string
+							templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", match.Result.HomeScore))
+							if templ_7745c5c3_Err != nil {
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 53, Col: 61}
+							}
+							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "\" class=\"score-form\"><div class=\"score-container\"><input type=\"number\" name=\"home_score\" value=\"")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</span> <span class=\"score-separator\">-</span> <span>")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 							var templ_7745c5c3_Var10 string
-							templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", match.Result.HomeScore))
+							templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", match.Result.GuestScore))
 							if templ_7745c5c3_Err != nil {
-								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 52, Col: 63}
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 55, Col: 62}
 							}
 							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\" class=\"score-input\" disabled min=\"0\"> <span class=\"score-separator\">-</span> <input type=\"number\" name=\"guest_score\" value=\"")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "</span></div></div>")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
-							var templ_7745c5c3_Var11 string
-							templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", match.Result.GuestScore))
+						} else if match.Result != nil {
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<div class=\"match-result\"><form method=\"POST\" action=\"")
 							if templ_7745c5c3_Err != nil {
-								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 60, Col: 64}
+								return templ_7745c5c3_Err
 							}
-							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+							var templ_7745c5c3_Var11 templ.SafeURL = templ.SafeURL(fmt.Sprintf("/matches/%d/edit", match.Match.ID))
+							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(string(templ_7745c5c3_Var11)))
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "\" class=\"score-input\" disabled min=\"0\"></div><div class=\"match-actions\"><button type=\"button\" class=\"btn btn-secondary edit-btn\" data-match-id=\"")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\" class=\"score-form\"><div class=\"score-container\"><input type=\"number\" name=\"home_score\" value=\"")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 							var templ_7745c5c3_Var12 string
-							templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(match.Match.ID))
+							templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", match.Result.HomeScore))
 							if templ_7745c5c3_Err != nil {
-								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 70, Col: 56}
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 64, Col: 63}
 							}
 							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\" onclick=\"toggleEdit(this.dataset.matchId)\">Edit</button> <button type=\"submit\" class=\"btn btn-primary save-btn\" style=\"display: none;\">Save</button> <button type=\"button\" class=\"btn btn-secondary cancel-btn\" style=\"display: none;\" data-match-id=\"")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\" class=\"score-input\" disabled min=\"0\"> <span class=\"score-separator\">-</span> <input type=\"number\" name=\"guest_score\" value=\"")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 							var templ_7745c5c3_Var13 string
-							templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(match.Match.ID))
+							templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", match.Result.GuestScore))
 							if templ_7745c5c3_Err != nil {
-								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 86, Col: 56}
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 72, Col: 64}
 							}
 							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "\" onclick=\"cancelEdit(this.dataset.matchId)\">Cancel</button></div></form></div>")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\" class=\"score-input\" disabled min=\"0\"></div><div class=\"match-actions\"><button type=\"button\" class=\"btn btn-secondary edit-btn\" data-match-id=\"")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							var templ_7745c5c3_Var14 string
+							templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(match.Match.ID))
+							if templ_7745c5c3_Err != nil {
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 82, Col: 56}
+							}
+							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\" onclick=\"toggleEdit(this.dataset.matchId)\">Edit</button> <button type=\"submit\" class=\"btn btn-primary save-btn\" style=\"display: none;\">Save</button> <button type=\"button\" class=\"btn btn-secondary cancel-btn\" style=\"display: none;\" data-match-id=\"")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							var templ_7745c5c3_Var15 string
+							templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(match.Match.ID))
+							if templ_7745c5c3_Err != nil {
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/matches.templ`, Line: 98, Col: 56}
+							}
+							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "\" onclick=\"cancelEdit(this.dataset.matchId)\">Cancel</button></div></form></div>")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 						} else {
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "<div class=\"match-status\"><span class=\"pending\">Not Played</span></div>")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "<div class=\"match-status\"><span class=\"pending\">Not Played</span></div>")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "</div>")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "</div>")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "</div></div>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "</div></div>")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "</div><script>\n\t\t\tfunction toggleEdit(matchId) {\n\t\t\t\tconst matchCard = document.getElementById(`match-${matchId}`);\n\t\t\t\tconst form = matchCard.querySelector('.score-form');\n\t\t\t\tconst inputs = form.querySelectorAll('.score-input');\n\t\t\t\tconst editBtn = form.querySelector('.edit-btn');\n\t\t\t\tconst saveBtn = form.querySelector('.save-btn');\n\t\t\t\tconst cancelBtn = form.querySelector('.cancel-btn');\n\n\t\t\t\t// Store original values for cancel\n\t\t\t\tinputs.forEach(input => {\n\t\t\t\t\tinput.dataset.originalValue = input.value;\n\t\t\t\t\tinput.disabled = false;\n\t\t\t\t});\n\n\t\t\t\teditBtn.style.display = 'none';\n\t\t\t\tsaveBtn.style.display = 'inline-block';\n\t\t\t\tcancelBtn.style.display = 'inline-block';\n\t\t\t}\n\n\t\t\tfunction cancelEdit(matchId) {\n\t\t\t\tconst matchCard = document.getElementById(`match-${matchId}`);\n\t\t\t\tconst form = matchCard.querySelector('.score-form');\n\t\t\t\tconst inputs = form.querySelectorAll('.score-input');\n\t\t\t\tconst editBtn = form.querySelector('.edit-btn');\n\t\t\t\tconst saveBtn = form.querySelector('.save-btn');\n\t\t\t\tconst cancelBtn = form.querySelector('.cancel-btn');\n\n\t\t\t\t// Restore original values\n\t\t\t\tinputs.forEach(input => {\n\t\t\t\t\tinput.value = input.dataset.originalValue;\n\t\t\t\t\tinput.disabled = true;\n\t\t\t\t});\n\n\t\t\t\teditBtn.style.display = 'inline-block';\n\t\t\t\tsaveBtn.style.display = 'none';\n\t\t\t\tcancelBtn.style.display = 'none';\n\t\t\t}\n\t\t</script> <style>\n\t\t\t.matches-container {\n\t\t\t\tmax-width: 1200px;\n\t\t\t\tmargin: 0 auto;\n\t\t\t\tpadding: 20px;\n\t\t\t}\n\n\t\t\t.page-header {\n\t\t\t\tdisplay: flex;\n\t\t\t\tjustify-content: space-between;\n\t\t\t\talign-items: center;\n\t\t\t\tmargin-bottom: 20px;\n\t\t\t\tflex-wrap: wrap;\n\t\t\t\tgap: 15px;\n\t\t\t}\n\n\t\t\t.page-header h1 {\n\t\t\t\tmargin: 0;\n\t\t\t}\n\n\t\t\t.current-week {\n\t\t\t\tfont-size: 1.2rem;\n\t\t\t\tcolor: var(--secondary-color);\n\t\t\t}\n\n\t\t\t.week-section {\n\t\t\t\tmargin-bottom: 30px;\n\t\t\t\tbackground-color: var(--card-background);\n\t\t\t\tborder-radius: 8px;\n\t\t\t\tpadding: 20px;\n\t\t\t\tbox-shadow: 0 2px 4px rgba(0, 0, 0, 0.1);\n\t\t\t}\n\n\t\t\t.week-header {\n\t\t\t\tmargin-bottom: 15px;\n\t\t\t\tpadding-bottom: 10px;\n\t\t\t\tborder-bottom: 2px solid var(--border-color);\n\t\t\t}\n\n\t\t\t.week-header h2 {\n\t\t\t\tcolor: var(--primary-color);\n\t\t\t\tmargin: 0;\n\t\t\t}\n\n\t\t\t.matches-grid {\n\t\t\t\tdisplay: grid;\n\t\t\t\tgrid-template-columns: repeat(auto-fill, minmax(300px, 1fr));\n\t\t\t\tgap: 15px;\n\t\t\t}\n\n\t\t\t.match-card {\n\t\t\t\tbackground-color: white;\n\t\t\t\tborder-radius: 6px;\n\t\t\t\tpadding: 15px;\n\t\t\t\tbox-shadow: 0 1px 3px rgba(0, 0, 0, 0.1);\n\t\t\t\tmin-height: 120px;\n\t\t\t\tdisplay: flex;\n\t\t\t\tflex-direction: column;\n\t\t\t}\n\n\t\t\t.match-teams {\n\t\t\t\tdisplay: flex;\n\t\t\t\tjustify-content: space-between;\n\t\t\t\talign-items: center;\n\t\t\t\tmargin-bottom: 10px;\n\t\t\t\tflex-shrink: 0;\n\t\t\t}\n\n\t\t\t.team {\n\t\t\t\tflex: 1;\n\t\t\t\tfont-weight: 600;\n\t\t\t\tmin-width: 0;\n\t\t\t\toverflow: hidden;\n\t\t\t\ttext-overflow: ellipsis;\n\t\t\t\twhite-space: nowrap;\n\t\t\t}\n\n\t\t\t.home {\n\t\t\t\ttext-align: right;\n\t\t\t\tpadding-right: 10px;\n\t\t\t}\n\n\t\t\t.away {\n\t\t\t\ttext-align: left;\n\t\t\t\tpadding-left: 10px;\n\t\t\t}\n\n\t\t\t.vs {\n\t\t\t\tcolor: var(--text-color);\n\t\t\t\topacity: 0.6;\n\t\t\t\tpadding: 0 10px;\n\t\t\t\tflex-shrink: 0;\n\t\t\t}\n\n\t\t\t.match-result {\n\t\t\t\ttext-align: center;\n\t\t\t\tfont-size: 1.2rem;\n\t\t\t\tfont-weight: 700;\n\t\t\t\tcolor: var(--secondary-color);\n\t\t\t\tflex-grow: 1;\n\t\t\t\tdisplay: flex;\n\t\t\t\tflex-direction: column;\n\t\t\t\tjustify-content: center;\n\t\t\t}\n\n\t\t\t.score-container {\n\t\t\t\tdisplay: flex;\n\t\t\t\talign-items: center;\n\t\t\t\tgap: 10px;\n\t\t\t\tjustify-content: center;\n\t\t\t\tmargin-bottom: 10px;\n\t\t\t}\n\n\t\t\t.score-input {\n\t\t\t\twidth: 50px;\n\t\t\t\ttext-align: center;\n\t\t\t\tpadding: 5px;\n\t\t\t\tborder: 1px solid var(--border-color);\n\t\t\t\tborder-radius: 4px;\n\t\t\t\tfont-size: 1.1rem;\n\t\t\t\tfont-weight: 600;\n\t\t\t\t-moz-appearance: textfield;\n\t\t\t}\n\n\t\t\t.score-input::-webkit-outer-spin-button,\n\t\t\t.score-input::-webkit-inner-spin-button {\n\t\t\t\t-webkit-appearance: none;\n\t\t\t\tmargin: 0;\n\t\t\t}\n\n\t\t\t.score-input:disabled {\n\t\t\t\tbackground-color: transparent;\n\t\t\t\tborder-color: transparent;\n\t\t\t\tcolor: var(--secondary-color);\n\t\t\t}\n\n\t\t\t.score-separator {\n\t\t\t\tfont-size: 1.2rem;\n\t\t\t\tcolor: var(--text-color);\n\t\t\t\topacity: 0.6;\n\t\t\t\tflex-shrink: 0;\n\t\t\t}\n\n\t\t\t.match-status {\n\t\t\t\ttext-align: center;\n\t\t\t\tflex-grow: 1;\n\t\t\t\tdisplay: flex;\n\t\t\t\talign-items: center;\n\t\t\t\tjustify-content: center;\n\t\t\t}\n\n\t\t\t.pending {\n\t\t\t\tcolor: var(--text-color);\n\t\t\t\topacity: 0.6;\n\t\t\t\tfont-size: 0.9rem;\n\t\t\t}\n\n\t\t\t.score-form {\n\t\t\t\tdisplay: flex;\n\t\t\t\tflex-direction: column;\n\t\t\t\talign-items: center;\n\t\t\t\twidth: 100%;\n\t\t\t}\n\n\t\t\t.match-actions {\n\t\t\t\tdisplay: flex;\n\t\t\t\tgap: 10px;\n\t\t\t\tmargin-top: 10px;\n\t\t\t}\n\n\t\t\t.btn {\n\t\t\t\tpadding: 5px 15px;\n\t\t\t\tborder-radius: 4px;\n\t\t\t\tcursor: pointer;\n\t\t\t\tfont-size: 0.9rem;\n\t\t\t\ttransition: all 0.2s;\n\t\t\t}\n\n\t\t\t.btn-secondary {\n\t\t\t\tbackground-color: var(--secondary-color);\n\t\t\t\tcolor: white;\n\t\t\t\tborder: none;\n\t\t\t}\n\n\t\t\t.btn-primary {\n\t\t\t\tbackground-color: var(--primary-color);\n\t\t\t\tcolor: white;\n\t\t\t\tborder: none;\n\t\t\t}\n\n\t\t\t.btn:hover {\n\t\t\t\topacity: 0.9;\n\t\t\t}\n\n\t\t\t@media (max-width: 768px) {\n\t\t\t\t.page-header {\n\t\t\t\t\tflex-direction: column;\n\t\t\t\t\ttext-align: center;\n\t\t\t\t}\n\n\t\t\t\t.matches-grid {\n\t\t\t\t\tgrid-template-columns: 1fr;\n\t\t\t\t}\n\n\t\t\t\t.match-card {\n\t\t\t\t\tmargin-bottom: 10px;\n\t\t\t\t}\n\n\t\t\t\t.match-actions {\n\t\t\t\t\tflex-direction: column;\n\t\t\t\t\twidth: 100%;\n\t\t\t\t}\n\n\t\t\t\t.btn {\n\t\t\t\t\twidth: 100%;\n\t\t\t\t}\n\t\t\t}\n\t\t</style>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "</div><script>\n\t\t\tfunction toggleEdit(matchId) {\n\t\t\t\tconst matchCard = document.getElementById(`match-${matchId}`);\n\t\t\t\tconst form = matchCard.querySelector('.score-form');\n\t\t\t\tconst inputs = form.querySelectorAll('.score-input');\n\t\t\t\tconst editBtn = form.querySelector('.edit-btn');\n\t\t\t\tconst saveBtn = form.querySelector('.save-btn');\n\t\t\t\tconst cancelBtn = form.querySelector('.cancel-btn');\n\n\t\t\t\t// Store original values for cancel\n\t\t\t\tinputs.forEach(input => {\n\t\t\t\t\tinput.dataset.originalValue = input.value;\n\t\t\t\t\tinput.disabled = false;\n\t\t\t\t});\n\n\t\t\t\teditBtn.style.display = 'none';\n\t\t\t\tsaveBtn.style.display = 'inline-block';\n\t\t\t\tcancelBtn.style.display = 'inline-block';\n\t\t\t}\n\n\t\t\tfunction cancelEdit(matchId) {\n\t\t\t\tconst matchCard = document.getElementById(`match-${matchId}`);\n\t\t\t\tconst form = matchCard.querySelector('.score-form');\n\t\t\t\tconst inputs = form.querySelectorAll('.score-input');\n\t\t\t\tconst editBtn = form.querySelector('.edit-btn');\n\t\t\t\tconst saveBtn = form.querySelector('.save-btn');\n\t\t\t\tconst cancelBtn = form.querySelector('.cancel-btn');\n\n\t\t\t\t// Restore original values\n\t\t\t\tinputs.forEach(input => {\n\t\t\t\t\tinput.value = input.dataset.originalValue;\n\t\t\t\t\tinput.disabled = true;\n\t\t\t\t});\n\n\t\t\t\teditBtn.style.display = 'inline-block';\n\t\t\t\tsaveBtn.style.display = 'none';\n\t\t\t\tcancelBtn.style.display = 'none';\n\t\t\t}\n\t\t</script> <style>\n\t\t\t.matches-container {\n\t\t\t\tmax-width: 1200px;\n\t\t\t\tmargin: 0 auto;\n\t\t\t\tpadding: 20px;\n\t\t\t}\n\n\t\t\t.page-header {\n\t\t\t\tdisplay: flex;\n\t\t\t\tjustify-content: space-between;\n\t\t\t\talign-items: center;\n\t\t\t\tmargin-bottom: 20px;\n\t\t\t\tflex-wrap: wrap;\n\t\t\t\tgap: 15px;\n\t\t\t}\n\n\t\t\t.page-header h1 {\n\t\t\t\tmargin: 0;\n\t\t\t}\n\n\t\t\t.current-week {\n\t\t\t\tfont-size: 1.2rem;\n\t\t\t\tcolor: var(--secondary-color);\n\t\t\t}\n\n\t\t\t.week-section {\n\t\t\t\tmargin-bottom: 30px;\n\t\t\t\tbackground-color: var(--card-background);\n\t\t\t\tborder-radius: 8px;\n\t\t\t\tpadding: 20px;\n\t\t\t\tbox-shadow: 0 2px 4px rgba(0, 0, 0, 0.1);\n\t\t\t}\n\n\t\t\t.week-header {\n\t\t\t\tmargin-bottom: 15px;\n\t\t\t\tpadding-bottom: 10px;\n\t\t\t\tborder-bottom: 2px solid var(--border-color);\n\t\t\t}\n\n\t\t\t.week-header h2 {\n\t\t\t\tcolor: var(--primary-color);\n\t\t\t\tmargin: 0;\n\t\t\t}\n\n\t\t\t.matches-grid {\n\t\t\t\tdisplay: grid;\n\t\t\t\tgrid-template-columns: repeat(auto-fill, minmax(300px, 1fr));\n\t\t\t\tgap: 15px;\n\t\t\t}\n\n\t\t\t.match-card {\n\t\t\t\tbackground-color: white;\n\t\t\t\tborder-radius: 6px;\n\t\t\t\tpadding: 15px;\n\t\t\t\tbox-shadow: 0 1px 3px rgba(0, 0, 0, 0.1);\n\t\t\t\tmin-height: 120px;\n\t\t\t\tdisplay: flex;\n\t\t\t\tflex-direction: column;\n\t\t\t}\n\n\t\t\t.match-teams {\n\t\t\t\tdisplay: flex;\n\t\t\t\tjustify-content: space-between;\n\t\t\t\talign-items: center;\n\t\t\t\tmargin-bottom: 10px;\n\t\t\t\tflex-shrink: 0;\n\t\t\t}\n\n\t\t\t.team {\n\t\t\t\tflex: 1;\n\t\t\t\tfont-weight: 600;\n\t\t\t\tmin-width: 0;\n\t\t\t\toverflow: hidden;\n\t\t\t\ttext-overflow: ellipsis;\n\t\t\t\twhite-space: nowrap;\n\t\t\t}\n\n\t\t\t.home {\n\t\t\t\ttext-align: right;\n\t\t\t\tpadding-right: 10px;\n\t\t\t}\n\n\t\t\t.away {\n\t\t\t\ttext-align: left;\n\t\t\t\tpadding-left: 10px;\n\t\t\t}\n\n\t\t\t.vs {\n\t\t\t\tcolor: var(--text-color);\n\t\t\t\topacity: 0.6;\n\t\t\t\tpadding: 0 10px;\n\t\t\t\tflex-shrink: 0;\n\t\t\t}\n\n\t\t\t.match-result {\n\t\t\t\ttext-align: center;\n\t\t\t\tfont-size: 1.2rem;\n\t\t\t\tfont-weight: 700;\n\t\t\t\tcolor: var(--secondary-color);\n\t\t\t\tflex-grow: 1;\n\t\t\t\tdisplay: flex;\n\t\t\t\tflex-direction: column;\n\t\t\t\tjustify-content: center;\n\t\t\t}\n\n\t\t\t.score-container {\n\t\t\t\tdisplay: flex;\n\t\t\t\talign-items: center;\n\t\t\t\tgap: 10px;\n\t\t\t\tjustify-content: center;\n\t\t\t\tmargin-bottom: 10px;\n\t\t\t}\n\n\t\t\t.score-input {\n\t\t\t\twidth: 50px;\n\t\t\t\ttext-align: center;\n\t\t\t\tpadding: 5px;\n\t\t\t\tborder: 1px solid var(--border-color);\n\t\t\t\tborder-radius: 4px;\n\t\t\t\tfont-size: 1.1rem;\n\t\t\t\tfont-weight: 600;\n\t\t\t\t-moz-appearance: textfield;\n\t\t\t}\n\n\t\t\t.score-input::-webkit-outer-spin-button,\n\t\t\t.score-input::-webkit-inner-spin-button {\n\t\t\t\t-webkit-appearance: none;\n\t\t\t\tmargin: 0;\n\t\t\t}\n\n\t\t\t.score-input:disabled {\n\t\t\t\tbackground-color: transparent;\n\t\t\t\tborder-color: transparent;\n\t\t\t\tcolor: var(--secondary-color);\n\t\t\t}\n\n\t\t\t.score-separator {\n\t\t\t\tfont-size: 1.2rem;\n\t\t\t\tcolor: var(--text-color);\n\t\t\t\topacity: 0.6;\n\t\t\t\tflex-shrink: 0;\n\t\t\t}\n\n\t\t\t.match-status {\n\t\t\t\ttext-align: center;\n\t\t\t\tflex-grow: 1;\n\t\t\t\tdisplay: flex;\n\t\t\t\talign-items: center;\n\t\t\t\tjustify-content: center;\n\t\t\t}\n\n\t\t\t.pending {\n\t\t\t\tcolor: var(--text-color);\n\t\t\t\topacity: 0.6;\n\t\t\t\tfont-size: 0.9rem;\n\t\t\t}\n\n\t\t\t.score-form {\n\t\t\t\tdisplay: flex;\n\t\t\t\tflex-direction: column;\n\t\t\t\talign-items: center;\n\t\t\t\twidth: 100%;\n\t\t\t}\n\n\t\t\t.match-actions {\n\t\t\t\tdisplay: flex;\n\t\t\t\tgap: 10px;\n\t\t\t\tmargin-top: 10px;\n\t\t\t}\n\n\t\t\t.btn {\n\t\t\t\tpadding: 5px 15px;\n\t\t\t\tborder-radius: 4px;\n\t\t\t\tcursor: pointer;\n\t\t\t\tfont-size: 0.9rem;\n\t\t\t\ttransition: all 0.2s;\n\t\t\t}\n\n\t\t\t.btn-secondary {\n\t\t\t\tbackground-color: var(--secondary-color);\n\t\t\t\tcolor: white;\n\t\t\t\tborder: none;\n\t\t\t}\n\n\t\t\t.btn-primary {\n\t\t\t\tbackground-color: var(--primary-color);\n\t\t\t\tcolor: white;\n\t\t\t\tborder: none;\n\t\t\t}\n\n\t\t\t.btn:hover {\n\t\t\t\topacity: 0.9;\n\t\t\t}\n\n\t\t\t@media (max-width: 768px) {\n\t\t\t\t.page-header {\n\t\t\t\t\tflex-direction: column;\n\t\t\t\t\ttext-align: center;\n\t\t\t\t}\n\n\t\t\t\t.matches-grid {\n\t\t\t\t\tgrid-template-columns: 1fr;\n\t\t\t\t}\n\n\t\t\t\t.match-card {\n\t\t\t\t\tmargin-bottom: 10px;\n\t\t\t\t}\n\n\t\t\t\t.match-actions {\n\t\t\t\t\tflex-direction: column;\n\t\t\t\t\twidth: 100%;\n\t\t\t\t}\n\n\t\t\t\t.btn {\n\t\t\t\t\twidth: 100%;\n\t\t\t\t}\n\t\t\t}\n\t\t</style>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}